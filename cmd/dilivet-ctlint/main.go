@@ -0,0 +1,121 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Command dilivet-ctlint flags branches, indexing, map lookups and
+// divisions that depend on values annotated //dilivet:secret.
+//
+//	dilivet-ctlint [-format text|json|sarif] [packages]
+//
+// Packages are directories, with "/..." for a whole tree; the default is
+// "./...". The exit status is 1 when anything is found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/codethor0/dilivet/code/ctlint"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+var version = "dev"
+
+const name = "dilivet-ctlint"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "output format: text (go vet style), json (go vet -json style) or sarif")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s [-format text|json|sarif] [packages]\n", name)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	switch *format {
+	case "text", "json", "sarif":
+	default:
+		fmt.Fprintf(stderr, "%s: unknown format %q\n", name, *format)
+		return 2
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	dirs, err := ctlint.Dirs(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	findings, err := ctlint.Check(dirs)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	for i := range findings {
+		findings[i].Pos.Filename = ctlint.RelPath(findings[i].Pos.Filename)
+	}
+
+	switch *format {
+	case "text":
+		for _, f := range findings {
+			fmt.Fprintln(stdout, f)
+		}
+	case "json":
+		err = writeJSON(stdout, findings)
+	case "sarif":
+		err = writeSARIF(stdout, findings)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeJSON mirrors go vet -json: package path, then analyzer (here the
+// rule), then the diagnostics.
+func writeJSON(w io.Writer, findings []ctlint.Finding) error {
+	type diagnostic struct {
+		Posn    string `json:"posn"`
+		Message string `json:"message"`
+	}
+	tree := map[string]map[string][]diagnostic{}
+	for _, f := range findings {
+		if tree[f.Package] == nil {
+			tree[f.Package] = map[string][]diagnostic{}
+		}
+		tree[f.Package][f.Rule] = append(tree[f.Package][f.Rule], diagnostic{f.Pos.String(), f.Message})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(tree)
+}
+
+func writeSARIF(w io.Writer, findings []ctlint.Finding) error {
+	rules := make([]diag.SourceRule, len(ctlint.Rules))
+	for i, r := range ctlint.Rules {
+		rules[i] = diag.SourceRule{ID: r.ID, Description: r.Description, Help: r.Help}
+	}
+	results := make([]diag.SourceResult, len(findings))
+	for i, f := range findings {
+		results[i] = diag.SourceResult{RuleID: f.Rule, Message: f.Message, File: f.Pos.Filename, Line: f.Pos.Line, Column: f.Pos.Column}
+	}
+	tool := diag.Tool{Name: name, Version: version, URI: "https://github.com/codethor0/dilivet"}
+	return diag.WriteSARIFSource(w, tool, rules, results)
+}
@@ -7,12 +7,15 @@ import (
 	"io"
 	"os"
 
+	"github.com/codethor0/dilivet/code/adapter/execsign"
 	"github.com/codethor0/dilivet/code/cli"
 )
 
 var version = "dev"
 
 func main() {
+	// Re-execs started for -max-memory, -max-cpu and -max-files stop here.
+	execsign.LimitsMain()
 	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
 
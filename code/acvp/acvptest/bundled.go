@@ -0,0 +1,102 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvptest
+
+import (
+	"encoding/json"
+
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// answerFields are the test case fields a prompt must not reveal.
+var answerFields = map[string][]string{
+	"keyGen": {"pk", "sk"},
+	"sigGen": {"pk", "signature"},
+	"sigVer": {"testPassed", "reason"},
+}
+
+// loadBundled turns the bundled internalProjection files into prompts and
+// expected values, keyed by mode.
+func loadBundled() (map[string]vectorSet, error) {
+	out := map[string]vectorSet{}
+
+	keyGen, err := kats.LoadKeyGenVectors("")
+	if err != nil {
+		return nil, err
+	}
+	expected := map[[2]int]kats.ResponseCase{}
+	for _, tg := range keyGen.TestGroups {
+		for _, tc := range tg.Tests {
+			expected[[2]int{tg.TargetGroupID, tc.CaseID}] = kats.ResponseCase{CaseID: tc.CaseID, Public: tc.Public, Secret: tc.Secret}
+		}
+	}
+	if out["keyGen"], err = newVectorSet(keyGen, "keyGen", expected); err != nil {
+		return nil, err
+	}
+
+	sigGen, err := kats.LoadSigGenVectors("")
+	if err != nil {
+		return nil, err
+	}
+	expected = map[[2]int]kats.ResponseCase{}
+	for _, tg := range sigGen.TestGroups {
+		for _, tc := range tg.Tests {
+			expected[[2]int{tg.TargetGroupID, tc.CaseID}] = kats.ResponseCase{CaseID: tc.CaseID, Signature: tc.Signature}
+		}
+	}
+	if out["sigGen"], err = newVectorSet(sigGen, "sigGen", expected); err != nil {
+		return nil, err
+	}
+
+	sigVer, err := kats.LoadSigVerVectors("")
+	if err != nil {
+		return nil, err
+	}
+	expected = map[[2]int]kats.ResponseCase{}
+	for _, tg := range sigVer.TestGroups {
+		for _, tc := range tg.Tests {
+			passed := tc.TestPassed
+			expected[[2]int{tg.TargetGroupID, tc.CaseID}] = kats.ResponseCase{CaseID: tc.CaseID, TestPassed: &passed}
+		}
+	}
+	if out["sigVer"], err = newVectorSet(sigVer, "sigVer", expected); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// newVectorSet converts loaded vectors into a generic prompt, dropping the
+// answer fields and the empty strings the loader structs always marshal.
+func newVectorSet(vectors any, mode string, expected map[[2]int]kats.ResponseCase) (vectorSet, error) {
+	data, err := json.Marshal(vectors)
+	if err != nil {
+		return vectorSet{}, err
+	}
+	var prompt map[string]any
+	if err := json.Unmarshal(data, &prompt); err != nil {
+		return vectorSet{}, err
+	}
+	groups, _ := prompt["testGroups"].([]any)
+	for _, g := range groups {
+		group, _ := g.(map[string]any)
+		dropEmpty(group)
+		tests, _ := group["tests"].([]any)
+		for _, t := range tests {
+			tc, _ := t.(map[string]any)
+			for _, field := range answerFields[mode] {
+				delete(tc, field)
+			}
+			dropEmpty(tc)
+		}
+	}
+	return vectorSet{prompt: prompt, expected: expected}, nil
+}
+
+func dropEmpty(m map[string]any) {
+	for k, v := range m {
+		if v == "" {
+			delete(m, k)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package acvptest provides a mock ACVP server for end-to-end tests of the
+// acvp client, in the spirit of net/http/httptest.
+//
+// The server speaks the login, testSessions, vectorSets and results parts
+// of the protocol. Each registered ML-DSA mode is answered with the bundled
+// KAT vector set (expected values stripped from the prompt), and uploaded
+// responses are graded against those expected values. Every bundled sigGen
+// case is reproducible (deterministic or with a published rnd), so grading
+// is an exact comparison.
+package acvptest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codethor0/dilivet/code/acvp"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// Server is a running mock ACVP server.
+type Server struct {
+	*httptest.Server
+
+	// TOTPSeed, when set, makes login require the current TOTP password.
+	TOTPSeed []byte
+	// TokenLifetime is the validity of issued tokens (default 30 minutes).
+	TokenLifetime time.Duration
+	// Retries is how many times each prompt answers {"retry": 0}, and each
+	// submitted vector set reports "incomplete", before the real reply.
+	Retries int
+
+	mu       sync.Mutex
+	tokens   map[string]tokenInfo
+	sessions []*session
+	prompts  map[string]vectorSet
+}
+
+type tokenInfo struct {
+	session int // 0 for login tokens
+	expires time.Time
+}
+
+type session struct {
+	id       int
+	isSample bool
+	sets     []*vectorSetState
+}
+
+type vectorSetState struct {
+	id      int
+	url     string
+	set     vectorSet
+	fetches int
+	polls   int
+	status  string // unreceived, passed or fail
+}
+
+// vectorSet pairs the prompt served for a mode with its expected values.
+type vectorSet struct {
+	prompt   map[string]any
+	expected map[[2]int]kats.ResponseCase
+}
+
+// NewServer starts a mock server backed by the bundled vectors. Callers
+// should Close it when done.
+func NewServer() (*Server, error) {
+	prompts, err := loadBundled()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		TokenLifetime: 30 * time.Minute,
+		tokens:        map[string]tokenInfo{},
+		prompts:       prompts,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+acvp.LoginPath, s.handleLogin)
+	mux.HandleFunc("POST /acvp/v1/testSessions", s.handleCreateSession)
+	mux.HandleFunc("GET /acvp/v1/testSessions/{session}/results", s.handleSessionResults)
+	mux.HandleFunc("GET /acvp/v1/testSessions/{session}/vectorSets/{vs}", s.handleVectorSet)
+	mux.HandleFunc("POST /acvp/v1/testSessions/{session}/vectorSets/{vs}/results", s.handleSubmit)
+	s.Server = httptest.NewServer(mux)
+	return s, nil
+}
+
+// ExpireTokens invalidates every issued token, forcing clients to renew.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for tok, info := range s.tokens {
+		info.expires = time.Time{}
+		s.tokens[tok] = info
+	}
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Password    string `json:"password"`
+		AccessToken string `json:"accessToken"`
+	}
+	if err := readMessage(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if s.TOTPSeed != nil && req.Password != acvp.TOTP(s.TOTPSeed, time.Now()) {
+		writeError(w, http.StatusUnauthorized, "invalid password")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessionID := 0
+	if req.AccessToken != "" {
+		// Renewal keeps the scope of the old token, even an expired one.
+		old, ok := s.tokens[req.AccessToken]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "unknown access token")
+			return
+		}
+		sessionID = old.session
+	}
+	writeMessage(w, http.StatusOK, map[string]any{"accessToken": s.issue(sessionID)})
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IsSample   bool              `json:"isSample"`
+		Algorithms []json.RawMessage `json:"algorithms"`
+	}
+	if err := readMessage(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.authorize(w, r, 0); !ok {
+		return
+	}
+	sess := &session{id: len(s.sessions) + 1, isSample: req.IsSample}
+	var urls []string
+	for _, raw := range req.Algorithms {
+		var reg struct {
+			Algorithm string `json:"algorithm"`
+			Mode      string `json:"mode"`
+			Revision  string `json:"revision"`
+		}
+		_ = json.Unmarshal(raw, &reg)
+		set, ok := s.prompts[reg.Mode]
+		if reg.Algorithm != kats.SupportedAlgorithm || reg.Revision != kats.SupportedRevision || !ok {
+			writeError(w, http.StatusBadRequest,
+				fmt.Sprintf("unsupported registration %s/%s/%s", reg.Algorithm, reg.Mode, reg.Revision))
+			return
+		}
+		id := sess.id*100 + len(sess.sets) + 1
+		url := fmt.Sprintf("/acvp/v1/testSessions/%d/vectorSets/%d", sess.id, id)
+		sess.sets = append(sess.sets, &vectorSetState{id: id, url: url, set: set, status: "unreceived"})
+		urls = append(urls, url)
+	}
+	if len(urls) == 0 {
+		writeError(w, http.StatusBadRequest, "no algorithms registered")
+		return
+	}
+	s.sessions = append(s.sessions, sess)
+	writeMessage(w, http.StatusOK, map[string]any{
+		"url":           fmt.Sprintf("/acvp/v1/testSessions/%d", sess.id),
+		"accessToken":   s.issue(sess.id),
+		"isSample":      sess.isSample,
+		"vectorSetUrls": urls,
+	})
+}
+
+func (s *Server) handleVectorSet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, vs, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if vs.fetches++; vs.fetches <= s.Retries {
+		writeMessage(w, http.StatusOK, map[string]any{"retry": 0})
+		return
+	}
+	prompt := make(map[string]any, len(vs.set.prompt)+2)
+	for k, v := range vs.set.prompt {
+		prompt[k] = v
+	}
+	prompt["vsId"] = vs.id
+	prompt["isSample"] = sess.isSample
+	writeMessage(w, http.StatusOK, prompt)
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var resp kats.Response
+	if err := readMessage(r, &resp); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, vs, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if resp.VectorSetID != vs.id {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("vsId %d does not match %d", resp.VectorSetID, vs.id))
+		return
+	}
+	vs.status = "fail"
+	if grade(&resp, vs.set.expected) {
+		vs.status = "passed"
+	}
+	vs.polls = 0
+	writeMessage(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) handleSessionResults(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.session(w, r)
+	if !ok {
+		return
+	}
+	passed := true
+	results := []acvp.VectorSetResult{}
+	for _, vs := range sess.sets {
+		status := vs.status
+		if status != "unreceived" {
+			if vs.polls++; vs.polls <= s.Retries {
+				status = "incomplete"
+			}
+		}
+		passed = passed && status == "passed"
+		results = append(results, acvp.VectorSetResult{VectorSetURL: vs.url, Status: status})
+	}
+	writeMessage(w, http.StatusOK, acvp.SessionResults{Passed: passed, Results: results})
+}
+
+// session resolves the {session} path value and checks the bearer token
+// belongs to it. s.mu must be held.
+func (s *Server) session(w http.ResponseWriter, r *http.Request) (*session, bool) {
+	id, err := strconv.Atoi(r.PathValue("session"))
+	if err != nil || id < 1 || id > len(s.sessions) {
+		writeError(w, http.StatusNotFound, "unknown test session")
+		return nil, false
+	}
+	if _, ok := s.authorize(w, r, id); !ok {
+		return nil, false
+	}
+	return s.sessions[id-1], true
+}
+
+// lookup resolves both the session and the {vs} path value. s.mu must be held.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*session, *vectorSetState, bool) {
+	sess, ok := s.session(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	id, _ := strconv.Atoi(r.PathValue("vs"))
+	for _, vs := range sess.sets {
+		if vs.id == id {
+			return sess, vs, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "unknown vector set")
+	return nil, nil, false
+}
+
+// authorize checks the bearer token is live and scoped to sessionID (0 for
+// login tokens). s.mu must be held.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, sessionID int) (tokenInfo, bool) {
+	tok, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	info, ok := s.tokens[tok]
+	switch {
+	case !ok:
+		writeError(w, http.StatusUnauthorized, "missing or unknown access token")
+	case time.Now().After(info.expires):
+		writeError(w, http.StatusUnauthorized, "access token expired")
+	case info.session != sessionID:
+		writeError(w, http.StatusForbidden, "access token not valid for this resource")
+	default:
+		return info, true
+	}
+	return tokenInfo{}, false
+}
+
+// issue mints an unsigned JWT carrying exp, so clients can schedule renewal.
+// s.mu must be held.
+func (s *Server) issue(sessionID int) string {
+	expires := time.Now().Add(s.TokenLifetime)
+	nonce := make([]byte, 12)
+	_, _ = rand.Read(nonce)
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]any{"exp": expires.Unix(), "jti": hex.EncodeToString(nonce)})
+	tok := header + "." + enc.EncodeToString(claims) + "." + enc.EncodeToString(nonce)
+	s.tokens[tok] = tokenInfo{session: sessionID, expires: expires}
+	return tok
+}
+
+// grade compares a response with the expected values case by case.
+func grade(resp *kats.Response, expected map[[2]int]kats.ResponseCase) bool {
+	seen := 0
+	for _, tg := range resp.TestGroups {
+		for _, tc := range tg.Tests {
+			want, ok := expected[[2]int{tg.TargetGroupID, tc.CaseID}]
+			if !ok {
+				return false
+			}
+			seen++
+			switch {
+			case !strings.EqualFold(tc.Public, want.Public),
+				!strings.EqualFold(tc.Secret, want.Secret),
+				!strings.EqualFold(tc.Signature, want.Signature),
+				(tc.TestPassed == nil) != (want.TestPassed == nil),
+				tc.TestPassed != nil && *tc.TestPassed != *want.TestPassed:
+				return false
+			}
+		}
+	}
+	return seen == len(expected)
+}
+
+func readMessage(r *http.Request, v any) error {
+	var elems []json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 64<<20)).Decode(&elems); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+	if len(elems) != 2 {
+		return errors.New("message must be [{\"acvVersion\"}, body]")
+	}
+	var version struct {
+		ACVVersion string `json:"acvVersion"`
+	}
+	if err := json.Unmarshal(elems[0], &version); err != nil || version.ACVVersion != kats.ACVPVersion {
+		return fmt.Errorf("unsupported acvVersion %q", version.ACVVersion)
+	}
+	return json.Unmarshal(elems[1], v)
+}
+
+func writeMessage(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode([]any{map[string]string{"acvVersion": kats.ACVPVersion}, body})
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeMessage(w, status, map[string]string{"error": msg})
+}
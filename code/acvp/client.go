@@ -0,0 +1,337 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// LoginPath is the endpoint that issues and renews access tokens.
+const LoginPath = "/acvp/v1/login"
+
+// sessionsPath is the endpoint that registers test sessions.
+const sessionsPath = "/acvp/v1/testSessions"
+
+// tokenMargin is how long before expiry a JWT is renewed.
+const tokenMargin = 30 * time.Second
+
+// Errors returned by the client.
+var (
+	ErrUnauthorized = errors.New("acvp: unauthorized")
+	ErrProtocol     = errors.New("acvp: malformed server message")
+)
+
+// Client talks to an ACVP server. The zero value is not usable; set BaseURL.
+type Client struct {
+	// BaseURL is the server root, e.g. https://demo.acvts.nist.gov.
+	BaseURL string
+	// HTTP performs requests; nil means http.DefaultClient. Mutual TLS is
+	// configured on its transport.
+	HTTP *http.Client
+	// Password returns the login password (normally a TOTP); nil sends none.
+	Password func() (string, error)
+	// Now is the clock used for token expiry; nil means time.Now.
+	Now func() time.Time
+
+	token string
+}
+
+// Session is a registered test session.
+type Session struct {
+	URL           string   `json:"url"`
+	AccessToken   string   `json:"accessToken"`
+	IsSample      bool     `json:"isSample"`
+	VectorSetURLs []string `json:"vectorSetUrls"`
+}
+
+// VectorSetResult is the server's verdict for one vector set.
+type VectorSetResult struct {
+	VectorSetURL string `json:"vectorSetUrl"`
+	Status       string `json:"status"`
+}
+
+// SessionResults summarises a test session.
+type SessionResults struct {
+	Passed  bool              `json:"passed"`
+	Results []VectorSetResult `json:"results"`
+}
+
+// Pending reports whether any vector set still awaits a verdict.
+func (r *SessionResults) Pending() bool {
+	for _, vs := range r.Results {
+		switch vs.Status {
+		case "unreceived", "incomplete", "processing":
+			return true
+		}
+	}
+	return false
+}
+
+// retryReply is sent instead of a payload while the server is still working.
+type retryReply struct {
+	Retry *int `json:"retry"`
+}
+
+// Login obtains an access token, renewing the current one if present.
+func (c *Client) Login(ctx context.Context) error {
+	token, err := c.renew(ctx, c.token)
+	if err != nil {
+		return err
+	}
+	c.token = token
+	return nil
+}
+
+// CreateSession registers a test session for the given algorithm
+// registrations (passed through verbatim).
+func (c *Client) CreateSession(ctx context.Context, isSample bool, algorithms []json.RawMessage) (*Session, error) {
+	body := map[string]any{"isSample": isSample, "algorithms": algorithms}
+	var s Session
+	if err := c.do(ctx, http.MethodPost, sessionsPath, &c.token, body, &s); err != nil {
+		return nil, fmt.Errorf("acvp: create test session: %w", err)
+	}
+	if s.URL == "" || len(s.VectorSetURLs) == 0 {
+		return nil, fmt.Errorf("acvp: create test session: %w: no vector sets", ErrProtocol)
+	}
+	return &s, nil
+}
+
+// VectorSet downloads the prompt at url, waiting while the server asks the
+// client to retry. The returned JSON is a single vector set object.
+func (c *Client) VectorSet(ctx context.Context, s *Session, url string) (json.RawMessage, error) {
+	for {
+		var raw json.RawMessage
+		if err := c.do(ctx, http.MethodGet, url, &s.AccessToken, nil, &raw); err != nil {
+			return nil, fmt.Errorf("acvp: fetch %s: %w", url, err)
+		}
+		var retry retryReply
+		if err := json.Unmarshal(raw, &retry); err == nil && retry.Retry != nil {
+			if err := sleep(ctx, time.Duration(*retry.Retry)*time.Second); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return raw, nil
+	}
+}
+
+// Submit uploads the response for the vector set at url.
+func (c *Client) Submit(ctx context.Context, s *Session, url string, resp *kats.Response) error {
+	if err := c.do(ctx, http.MethodPost, url+"/results", &s.AccessToken, resp, nil); err != nil {
+		return fmt.Errorf("acvp: submit %s: %w", url, err)
+	}
+	return nil
+}
+
+// Results fetches the current session verdict once.
+func (c *Client) Results(ctx context.Context, s *Session) (*SessionResults, error) {
+	var r SessionResults
+	if err := c.do(ctx, http.MethodGet, s.URL+"/results", &s.AccessToken, nil, &r); err != nil {
+		return nil, fmt.Errorf("acvp: session results: %w", err)
+	}
+	return &r, nil
+}
+
+// WaitResults polls the session verdict every interval until no vector set
+// is pending or ctx is done.
+func (c *Client) WaitResults(ctx context.Context, s *Session, interval time.Duration) (*SessionResults, error) {
+	for {
+		r, err := c.Results(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if !r.Pending() {
+			return r, nil
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return r, err
+		}
+	}
+}
+
+// renew posts to the login endpoint. An existing token is sent along so the
+// server can refresh it (or a session token) rather than issue a new one.
+func (c *Client) renew(ctx context.Context, old string) (string, error) {
+	body := map[string]string{}
+	if c.Password != nil {
+		pw, err := c.Password()
+		if err != nil {
+			return "", fmt.Errorf("acvp: login password: %w", err)
+		}
+		body["password"] = pw
+	}
+	if old != "" {
+		body["accessToken"] = old
+	}
+	var reply struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := c.do(ctx, http.MethodPost, LoginPath, nil, body, &reply); err != nil {
+		return "", fmt.Errorf("acvp: login: %w", err)
+	}
+	if reply.AccessToken == "" {
+		return "", fmt.Errorf("acvp: login: %w: no access token", ErrProtocol)
+	}
+	return reply.AccessToken, nil
+}
+
+// do sends one ACVP message. Bodies are wrapped as [{"acvVersion"}, body]
+// and replies unwrapped the same way. When token is non-nil it is sent as a
+// bearer token, renewed shortly before it expires and once more if the
+// server rejects it.
+func (c *Client) do(ctx context.Context, method, path string, token *string, body, out any) error {
+	if token != nil && *token != "" && c.expiresSoon(*token) {
+		fresh, err := c.renew(ctx, *token)
+		if err != nil {
+			return err
+		}
+		*token = fresh
+	}
+	err := c.send(ctx, method, path, token, body, out)
+	if errors.Is(err, ErrUnauthorized) && token != nil && *token != "" {
+		fresh, rerr := c.renew(ctx, *token)
+		if rerr != nil {
+			return err
+		}
+		*token = fresh
+		err = c.send(ctx, method, path, token, body, out)
+	}
+	return err
+}
+
+func (c *Client) send(ctx context.Context, method, path string, token *string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal([]any{map[string]string{"acvVersion": kats.ACVPVersion}, body})
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != nil && *token != "" {
+		req.Header.Set("Authorization", "Bearer "+*token)
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, 64<<20))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", ErrUnauthorized, serverError(data))
+	case res.StatusCode >= 300:
+		return fmt.Errorf("acvp: %s %s: HTTP %d: %s", method, path, res.StatusCode, serverError(data))
+	case out == nil:
+		return nil
+	}
+	payload, err := unwrap(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+	return nil
+}
+
+func (c *Client) url(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimRight(c.BaseURL, "/") + path
+}
+
+// expiresSoon reports whether the JWT's exp claim falls within tokenMargin.
+// Tokens that cannot be decoded are assumed valid and left to the server.
+func (c *Client) expiresSoon(token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return false
+	}
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	return now().Add(tokenMargin).After(time.Unix(claims.Exp, 0))
+}
+
+// unwrap returns the payload of an [{"acvVersion"}, payload] message. A bare
+// object is accepted as well.
+func unwrap(data []byte) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		return data, nil
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+	if len(elems) != 2 {
+		return nil, fmt.Errorf("%w: %d array elements, want 2", ErrProtocol, len(elems))
+	}
+	return elems[1], nil
+}
+
+// serverError extracts the "error" field of a failure reply, falling back to
+// the raw body.
+func serverError(data []byte) string {
+	if payload, err := unwrap(data); err == nil {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(payload, &e) == nil && e.Error != "" {
+			return e.Error
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestExpiresSoon(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := &Client{Now: func() time.Time { return now }}
+	jwt := func(claims string) string {
+		enc := base64.RawURLEncoding
+		return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".sig"
+	}
+
+	tests := map[string]struct {
+		token string
+		want  bool
+	}{
+		"valid":         {jwt(`{"exp":1700003600}`), false},
+		"within margin": {jwt(`{"exp":1700000010}`), true},
+		"expired":       {jwt(`{"exp":1699990000}`), true},
+		"no exp":        {jwt(`{}`), false},
+		"opaque":        {"not-a-jwt", false},
+	}
+	for name, tt := range tests {
+		if got := c.expiresSoon(tt.token); got != tt.want {
+			t.Errorf("%s: expiresSoon = %t, want %t", name, got, tt.want)
+		}
+	}
+}
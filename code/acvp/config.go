@@ -0,0 +1,142 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig reports an unusable run configuration.
+var ErrInvalidConfig = errors.New("acvp: invalid config")
+
+// Config describes one `dilivet acvp run`, normally read from acvp.json.
+type Config struct {
+	// ServerURL is the ACVP server root, e.g. https://demo.acvts.nist.gov.
+	ServerURL string `json:"serverUrl"`
+	// CertFile and KeyFile hold the PEM client certificate for mutual TLS.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// TOTPSeedFile holds the base64 TOTP seed issued with the certificate.
+	// Without it, login sends no password.
+	TOTPSeedFile string `json:"totpSeedFile"`
+	// IsSample asks the server to make expected results available.
+	IsSample bool `json:"isSample"`
+	// Algorithms are ACVP capability registrations, sent verbatim. When
+	// empty, ML-DSA keyGen, sigGen and sigVer are registered.
+	Algorithms []json.RawMessage `json:"algorithms"`
+	// Impl is an external implementation binary; empty uses the built-in one.
+	Impl string `json:"impl"`
+	// Timeout bounds each call to Impl.
+	Timeout Duration `json:"timeout"`
+	// PollInterval is the wait between result polls.
+	PollInterval Duration `json:"pollInterval"`
+	// WorkDir receives every prompt and response; empty uses a temporary
+	// directory.
+	WorkDir string `json:"workDir"`
+}
+
+// Duration is a time.Duration written as a Go duration string ("5s").
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// defaultAlgorithms registers the three ML-DSA modes the kats package answers.
+var defaultAlgorithms = []string{
+	`{"algorithm":"ML-DSA","mode":"keyGen","revision":"FIPS204","parameterSets":["ML-DSA-44","ML-DSA-65","ML-DSA-87"]}`,
+	`{"algorithm":"ML-DSA","mode":"sigGen","revision":"FIPS204"}`,
+	`{"algorithm":"ML-DSA","mode":"sigVer","revision":"FIPS204"}`,
+}
+
+// ReadConfig reads a JSON run configuration as written, without checking
+// it; callers that override fields (such as the server) call Fill after.
+func ReadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("acvp: read config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+	return &cfg, nil
+}
+
+// Fill checks cfg and fills in defaults.
+func (cfg *Config) Fill() error {
+	if cfg.ServerURL == "" {
+		return fmt.Errorf("%w: serverUrl is required", ErrInvalidConfig)
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return fmt.Errorf("%w: certFile and keyFile go together", ErrInvalidConfig)
+	}
+	if len(cfg.Algorithms) == 0 {
+		for _, a := range defaultAlgorithms {
+			cfg.Algorithms = append(cfg.Algorithms, json.RawMessage(a))
+		}
+	}
+	if cfg.Timeout.Duration <= 0 {
+		cfg.Timeout.Duration = 5 * time.Second
+	}
+	if cfg.PollInterval.Duration <= 0 {
+		cfg.PollInterval.Duration = 5 * time.Second
+	}
+	return nil
+}
+
+// NewClient builds a Client for cfg, loading the TLS certificate and TOTP
+// seed it names.
+func NewClient(cfg *Config) (*Client, error) {
+	c := &Client{BaseURL: cfg.ServerURL}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("acvp: load client certificate: %w", err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		c.HTTP = &http.Client{Transport: transport, Timeout: time.Minute}
+	}
+	if cfg.TOTPSeedFile != "" {
+		data, err := os.ReadFile(cfg.TOTPSeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("acvp: read TOTP seed: %w", err)
+		}
+		seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("acvp: decode TOTP seed: %w", err)
+		}
+		c.Password = func() (string, error) { return TOTP(seed, time.Now()), nil }
+	}
+	return c, nil
+}
@@ -0,0 +1,12 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package acvp is a client for the NIST ACVP protocol, limited to what is
+// needed to validate ML-DSA: logging in (TOTP password and JWT access
+// tokens), registering a test session, downloading vector sets, uploading
+// responses built by the kats package and polling for the verdict.
+//
+// Package acvptest provides an in-process server speaking the same protocol
+// over the bundled test vectors, so the full exchange can be exercised
+// without network access.
+package acvp
@@ -0,0 +1,117 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// Report summarises a complete ACVP run.
+type Report struct {
+	SessionURL string            `json:"sessionUrl"`
+	WorkDir    string            `json:"workDir"`
+	Passed     bool              `json:"passed"`
+	VectorSets []VectorSetReport `json:"vectorSets"`
+}
+
+// VectorSetReport describes one vector set of the session.
+type VectorSetReport struct {
+	URL          string `json:"url"`
+	VectorSetID  int    `json:"vsId"`
+	Mode         string `json:"mode"`
+	Status       string `json:"status"`
+	PromptFile   string `json:"promptFile"`
+	ResponseFile string `json:"responseFile"`
+}
+
+// Run performs a full session: log in, register cfg.Algorithms, answer every
+// vector set with impl, upload the responses and wait for the verdict.
+// Prompts and responses are kept under cfg.WorkDir.
+func Run(ctx context.Context, c *Client, cfg *Config, impl kats.Implementation) (*Report, error) {
+	workDir := cfg.WorkDir
+	if workDir == "" {
+		dir, err := os.MkdirTemp("", "dilivet-acvp-")
+		if err != nil {
+			return nil, err
+		}
+		workDir = dir
+	} else if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	if err := c.Login(ctx); err != nil {
+		return nil, err
+	}
+	session, err := c.CreateSession(ctx, cfg.IsSample, cfg.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{SessionURL: session.URL, WorkDir: workDir}
+	for _, url := range session.VectorSetURLs {
+		vs, err := answerVectorSet(ctx, c, session, url, workDir, impl)
+		if err != nil {
+			return report, err
+		}
+		report.VectorSets = append(report.VectorSets, *vs)
+	}
+
+	results, err := c.WaitResults(ctx, session, cfg.PollInterval.Duration)
+	if err != nil {
+		return report, err
+	}
+	report.Passed = results.Passed
+	status := map[string]string{}
+	for _, r := range results.Results {
+		status[r.VectorSetURL] = r.Status
+	}
+	for i := range report.VectorSets {
+		report.VectorSets[i].Status = status[report.VectorSets[i].URL]
+	}
+	return report, nil
+}
+
+// answerVectorSet downloads one prompt, answers it and uploads the response.
+func answerVectorSet(ctx context.Context, c *Client, s *Session, url, workDir string, impl kats.Implementation) (*VectorSetReport, error) {
+	prompt, err := c.VectorSet(ctx, s, url)
+	if err != nil {
+		return nil, err
+	}
+	vs := &VectorSetReport{URL: url}
+	vs.PromptFile = filepath.Join(workDir, "vs-"+path.Base(url)+"-prompt.json")
+	if err := os.WriteFile(vs.PromptFile, prompt, 0o644); err != nil {
+		return nil, err
+	}
+
+	responses, err := kats.RespondFile(vs.PromptFile, impl)
+	if err != nil {
+		return nil, fmt.Errorf("acvp: answer %s: %w", url, err)
+	}
+	if len(responses) != 1 {
+		return nil, fmt.Errorf("acvp: %s: %w: %d vector sets in prompt", url, ErrProtocol, len(responses))
+	}
+	resp := responses[0]
+	vs.VectorSetID, vs.Mode = resp.VectorSetID, resp.Mode
+
+	data, err := json.MarshalIndent([]any{map[string]string{"acvVersion": kats.ACVPVersion}, resp}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	vs.ResponseFile = filepath.Join(workDir, "vs-"+path.Base(url)+"-response.json")
+	if err := os.WriteFile(vs.ResponseFile, append(data, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+
+	if err := c.Submit(ctx, s, url, resp); err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
@@ -0,0 +1,149 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/acvp"
+	"github.com/codethor0/dilivet/code/acvp/acvptest"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+func newMock(t *testing.T) *acvptest.Server {
+	t.Helper()
+	srv, err := acvptest.NewServer()
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testConfig(t *testing.T, srv *acvptest.Server) *acvp.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "acvp.json")
+	content := `{"serverUrl":"` + srv.URL + `","isSample":true,"pollInterval":"1ms","workDir":"` + filepath.Join(t.TempDir(), "work") + `"}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := acvp.ReadConfig(path)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	if err := cfg.Fill(); err != nil {
+		t.Fatalf("Fill: %v", err)
+	}
+	return cfg
+}
+
+func TestRunAgainstMock(t *testing.T) {
+	srv := newMock(t)
+	srv.Retries = 2
+	srv.TOTPSeed = []byte("0123456789abcdef0123456789abcdef")
+
+	cfg := testConfig(t, srv)
+	cfg.TOTPSeedFile = filepath.Join(t.TempDir(), "seed")
+	if err := os.WriteFile(cfg.TOTPSeedFile, []byte(base64.StdEncoding.EncodeToString(srv.TOTPSeed)), 0o600); err != nil {
+		t.Fatalf("write seed: %v", err)
+	}
+	c, err := acvp.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	report, err := acvp.Run(context.Background(), c, cfg, kats.Builtin{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.Passed || len(report.VectorSets) != 3 {
+		t.Fatalf("report = %+v, want three passing vector sets", report)
+	}
+	for _, vs := range report.VectorSets {
+		if vs.Status != "passed" {
+			t.Errorf("%s (%s): status %q", vs.URL, vs.Mode, vs.Status)
+		}
+		for _, f := range []string{vs.PromptFile, vs.ResponseFile} {
+			if _, err := os.Stat(f); err != nil {
+				t.Errorf("work file: %v", err)
+			}
+		}
+	}
+}
+
+// brokenKeyGen returns key pairs with the first public key byte flipped.
+type brokenKeyGen struct{ kats.Builtin }
+
+func (b brokenKeyGen) KeyGen(parameterSet string, seed []byte) ([]byte, []byte, error) {
+	pk, sk, err := b.Builtin.KeyGen(parameterSet, seed)
+	if err == nil {
+		pk[0] ^= 1
+	}
+	return pk, sk, err
+}
+
+func TestRunReportsFailedVectorSet(t *testing.T) {
+	srv := newMock(t)
+	cfg := testConfig(t, srv)
+	c, err := acvp.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	report, err := acvp.Run(context.Background(), c, cfg, brokenKeyGen{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Passed {
+		t.Fatal("session passed with a broken keyGen")
+	}
+	for _, vs := range report.VectorSets {
+		want := "passed"
+		if vs.Mode == "keyGen" {
+			want = "fail"
+		}
+		if vs.Status != want {
+			t.Errorf("%s: status %q, want %q", vs.Mode, vs.Status, want)
+		}
+	}
+}
+
+func TestClientRenewsExpiredToken(t *testing.T) {
+	srv := newMock(t)
+	c := &acvp.Client{BaseURL: srv.URL}
+	ctx := context.Background()
+	if err := c.Login(ctx); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	srv.ExpireTokens()
+	session, err := c.CreateSession(ctx, true, nil)
+	if err == nil || errors.Is(err, acvp.ErrUnauthorized) {
+		t.Fatalf("empty registration: err = %v, want a rejection after renewal", err)
+	}
+
+	cfg := testConfig(t, srv)
+	if session, err = c.CreateSession(ctx, true, cfg.Algorithms); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	srv.ExpireTokens()
+	if _, err := c.Results(ctx, session); err != nil {
+		t.Fatalf("Results after expiry: %v", err)
+	}
+
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	srv := newMock(t)
+	srv.TOTPSeed = []byte("seed")
+	c := &acvp.Client{BaseURL: srv.URL, Password: func() (string, error) { return "00000000", nil }}
+	if err := c.Login(context.Background()); !errors.Is(err, acvp.ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,33 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// TOTP parameters used by the NIST ACVP servers: HMAC-SHA256, 30 second
+// steps and 8 digit passwords (RFC 6238).
+const (
+	TOTPStep   = 30 * time.Second
+	TOTPDigits = 8
+)
+
+// TOTP returns the one-time password for seed at time t.
+func TOTP(seed []byte, t time.Time) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(TOTPStep/time.Second)))
+
+	mac := hmac.New(sha256.New, seed)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	off := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, code%100000000)
+}
@@ -0,0 +1,30 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package acvp
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B, SHA-256 column.
+func TestTOTPRFC6238(t *testing.T) {
+	seed := []byte("12345678901234567890123456789012")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "46119246"},
+		{1111111109, "68084774"},
+		{1111111111, "67062674"},
+		{1234567890, "91819424"},
+		{2000000000, "90698825"},
+		{20000000000, "77737706"},
+	}
+	for _, tt := range tests {
+		if got := TOTP(seed, time.Unix(tt.unix, 0)); got != tt.want {
+			t.Errorf("TOTP(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,12 +20,34 @@ type Bin struct {
 	Timeout time.Duration
 	Env     []string
 	Dir     string
+
+	// Limits caps the resources of each target process (Linux only).
+	Limits Limits
+
+	// StderrLimit bounds how much trailing stderr is kept for error
+	// messages; zero keeps 2 KiB.
+	StderrLimit int
+
+	// Usage, when set, accumulates the resource usage of every call.
+	Usage *UsageLog
 }
 
 // Run executes the binary with the supplied input and returns trimmed stdout.
 func (b Bin) Run(ctx context.Context, input []byte) ([]byte, error) {
+	out, _, err := b.RunUsage(ctx, input)
+	return out, err
+}
+
+// RunUsage is Run that also reports the resource usage of the call. Usage
+// is filled in whenever the process was started, including on failure. A
+// call that runs out of time wraps ErrTimeout and a non-zero exit wraps
+// ErrCrashed.
+//
+// The target runs in its own process group, which is killed as a whole on
+// timeout so helpers it spawned do not outlive it.
+func (b Bin) RunUsage(ctx context.Context, input []byte) ([]byte, Usage, error) {
 	if b.Path == "" {
-		return nil, errors.New("execsign: empty binary path")
+		return nil, Usage{}, errors.New("execsign: empty binary path")
 	}
 	if b.Timeout <= 0 {
 		b.Timeout = 5 * time.Second
@@ -38,21 +62,77 @@ func (b Bin) Run(ctx context.Context, input []byte) ([]byte, error) {
 	if b.Dir != "" {
 		cmd.Dir = b.Dir
 	}
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killGroup(cmd) }
+	cmd.WaitDelay = time.Second
 
-	var (
-		stdout bytes.Buffer
-		stderr bytes.Buffer
-	)
+	var stdout bytes.Buffer
+	stderr := newTailBuffer(b.StderrLimit)
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Stderr = stderr
 	cmd.Stdin = bytes.NewReader(input)
+	if err := wrapLimits(cmd, b.Limits); err != nil {
+		return nil, Usage{}, err
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, Usage{}, fmt.Errorf("execsign: %w", err)
+	}
+	err := cmd.Wait()
+	usage := processUsage(cmd.ProcessState, time.Since(start))
+	b.Usage.add(usage, true)
 
-	if err := cmd.Run(); err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("execsign: %s timed out after %s", b.Path, b.Timeout)
+	if err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return nil, usage, fmt.Errorf("%w: %s after %s", ErrTimeout, b.Path, b.Timeout)
+		case errors.As(err, &exitErr):
+			return nil, usage, fmt.Errorf("%w: %s: %v%s", ErrCrashed, b.Path, err, stderr.suffix())
 		}
-		return nil, fmt.Errorf("execsign: %w (stderr: %s)", err, stderr.String())
+		return nil, usage, fmt.Errorf("execsign: %w%s", err, stderr.suffix())
+	}
+
+	return bytes.TrimSpace(stdout.Bytes()), usage, nil
+}
+
+// tailBuffer keeps the last bytes a target wrote to stderr.
+type tailBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func newTailBuffer(limit int) *tailBuffer {
+	if limit <= 0 {
+		limit = 2048
+	}
+	return &tailBuffer{limit: limit}
+}
+
+func (t *tailBuffer) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, b...)
+	if len(t.buf) > t.limit {
+		t.buf = append(t.buf[:0], t.buf[len(t.buf)-t.limit:]...)
+		t.truncated = true
 	}
+	return len(b), nil
+}
 
-	return bytes.TrimSpace(stdout.Bytes()), nil
+// suffix renders the kept stderr for an error message, or "" if empty.
+func (t *tailBuffer) suffix() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := strings.TrimSpace(string(t.buf))
+	if s == "" {
+		return ""
+	}
+	if t.truncated {
+		s = "..." + s
+	}
+	return " (stderr: " + s + ")"
 }
@@ -0,0 +1,122 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// printLimits prints the soft RLIMIT_AS and RLIMIT_NOFILE of the process.
+func printLimits() {
+	var as, nofile syscall.Rlimit
+	syscall.Getrlimit(syscall.RLIMIT_AS, &as)
+	syscall.Getrlimit(syscall.RLIMIT_NOFILE, &nofile)
+	fmt.Printf("as=%d nofile=%d\n", as.Cur, nofile.Cur)
+}
+
+func TestRunAddressSpaceLimit(t *testing.T) {
+	b := helperBin(t, "oneshot-alloc")
+	if out, err := b.Run(context.Background(), nil); err != nil {
+		t.Fatalf("unlimited run failed: %v (%s)", err, out)
+	}
+	b.Limits = Limits{AddressSpace: 64 << 20}
+	if _, err := b.Run(context.Background(), nil); err == nil {
+		t.Fatal("256 MiB allocation succeeded under a 64 MiB address-space limit")
+	}
+}
+
+// TestRunLimitsFromStart checks that the limits hold in the target and in
+// a child it forks straight away, with no window before they apply.
+func TestRunLimitsFromStart(t *testing.T) {
+	want := "as=1073741824 nofile=64"
+	for _, mode := range []string{"oneshot-limits", "oneshot-spawn-limits"} {
+		b := helperBin(t, mode)
+		b.Limits = Limits{AddressSpace: 1 << 30, OpenFiles: 64}
+		out, err := b.Run(context.Background(), nil)
+		if err != nil || string(out) != want {
+			t.Errorf("%s: got %q, %v; want %q", mode, out, err, want)
+		}
+	}
+
+	// Sessions start through the same wrapper.
+	s, err := StartSession(context.Background(), Bin{Path: helperBin(t, "").Path,
+		Env: append(os.Environ(), helperEnv+"=echo"), Limits: Limits{OpenFiles: 64}})
+	if err != nil {
+		t.Fatalf("StartSession under limits: %v", err)
+	}
+	defer s.Close()
+	echo(t, s, "limited")
+}
+
+// A binary that never called LimitsMain cannot act as the wrapper, so
+// limits are refused rather than silently re-running it.
+func TestRunLimitsNeedLimitsMain(t *testing.T) {
+	limitsMain.Store(false)
+	defer limitsMain.Store(true)
+	b := helperBin(t, "oneshot-limits")
+	b.Limits = Limits{OpenFiles: 64}
+	if _, err := b.Run(context.Background(), nil); !errors.Is(err, ErrNoLimitsMain) {
+		t.Fatalf("got %v, want ErrNoLimitsMain", err)
+	}
+}
+
+func TestRunCPULimit(t *testing.T) {
+	b := helperBin(t, "oneshot-burn")
+	b.Limits = Limits{CPUSeconds: 1}
+	if _, err := b.Run(context.Background(), nil); err != nil {
+		t.Fatalf("200ms of CPU should fit in 1s: %v", err)
+	}
+}
+
+func TestRunKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	b := helperBin(t, "oneshot-spawn-hang")
+	b.Timeout = 500 * time.Millisecond
+	_, err := b.Run(context.Background(), []byte(pidFile))
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v, want a timeout", err)
+	}
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("helper did not record its child: %v", err)
+	}
+	pid, _ := strconv.Atoi(string(data))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if gone(pid) {
+			return
+		}
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("child %d survived the timeout", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// gone reports whether pid has exited. The orphaned child is reaped by
+// whatever init the system runs, so a zombie counts as gone too.
+func gone(pid int) bool {
+	if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+		return true
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true
+	}
+	// The state follows the parenthesized command name.
+	if i := strings.LastIndexByte(string(stat), ')'); i >= 0 && i+2 < len(stat) {
+		return stat[i+2] == 'Z'
+	}
+	return false
+}
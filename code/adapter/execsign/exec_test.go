@@ -0,0 +1,126 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// oneShotHelper implements the one-shot modes: echo copies stdin, burn
+// spins the CPU for 200ms, noisy floods stderr and fails, alloc touches
+// 256 MiB, spawn-hang starts a sleeping child, records its pid in the
+// file named on stdin and hangs, limits prints its resource limits and
+// spawn-limits prints those of a child it starts.
+func oneShotHelper(mode string) int {
+	switch mode {
+	case "oneshot-echo":
+		io.Copy(os.Stdout, os.Stdin)
+	case "oneshot-burn":
+		deadline := time.Now().Add(200 * time.Millisecond)
+		x := 0
+		for time.Now().Before(deadline) {
+			x++
+		}
+		fmt.Println(x > 0)
+	case "oneshot-noisy":
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(os.Stderr, "line %d of noise\n", i)
+		}
+		fmt.Fprint(os.Stderr, "last words")
+		return 1
+	case "oneshot-alloc":
+		buf := make([]byte, 256<<20)
+		for i := range buf {
+			buf[i] = 1
+		}
+		fmt.Println(len(buf))
+	case "oneshot-spawn-hang":
+		pidFile, _ := io.ReadAll(os.Stdin)
+		return spawnAndHang(strings.TrimSpace(string(pidFile)))
+	case "oneshot-sleep":
+		time.Sleep(time.Minute)
+	case "oneshot-limits":
+		printLimits()
+	case "oneshot-spawn-limits":
+		exe, _ := os.Executable()
+		child := exec.Command(exe)
+		child.Env = append(os.Environ(), helperEnv+"=oneshot-limits")
+		child.Stdout = os.Stdout
+		if err := child.Run(); err != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+func helperBin(t *testing.T, mode string) Bin {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	return Bin{Path: exe, Env: append(os.Environ(), helperEnv+"="+mode)}
+}
+
+func TestRunUsage(t *testing.T) {
+	b := helperBin(t, "oneshot-burn")
+	b.Usage = &UsageLog{}
+	out, u, err := b.RunUsage(context.Background(), nil)
+	if err != nil || string(out) != "true" {
+		t.Fatalf("RunUsage = %q, %v", out, err)
+	}
+	if u.Wall < 200*time.Millisecond || u.User+u.System < 100*time.Millisecond {
+		t.Errorf("usage %+v does not reflect 200ms of CPU", u)
+	}
+	if _, err := b.Run(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	s := b.Usage.Summary()
+	if s.Calls != 2 || s.Processes != 2 || s.Wall < 400*time.Millisecond || s.MeanWall() < 200*time.Millisecond {
+		t.Errorf("summary %+v", s)
+	}
+}
+
+func TestRunBoundsStderr(t *testing.T) {
+	b := helperBin(t, "oneshot-noisy")
+	b.StderrLimit = 64
+	_, err := b.Run(context.Background(), nil)
+	if !errors.Is(err, ErrCrashed) {
+		t.Fatalf("got %v, want ErrCrashed", err)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "last words") || strings.Contains(msg, "line 1 of noise") || len(msg) > 200 {
+		t.Errorf("error not bounded to the stderr tail: %q", msg)
+	}
+}
+
+func TestRunEcho(t *testing.T) {
+	out, err := helperBin(t, "oneshot-echo").Run(context.Background(), []byte("op=keygen\n"))
+	if err != nil || string(out) != "op=keygen" {
+		t.Fatalf("Run = %q, %v", out, err)
+	}
+}
+
+// spawnAndHang starts a sleeping copy of the helper, writes its pid to
+// pidFile and blocks until killed.
+func spawnAndHang(pidFile string) int {
+	exe, _ := os.Executable()
+	child := exec.Command(exe)
+	child.Env = append(os.Environ(), helperEnv+"=oneshot-sleep")
+	if err := child.Start(); err != nil {
+		return 1
+	}
+	os.WriteFile(pidFile, []byte(strconv.Itoa(child.Process.Pid)), 0o644)
+	time.Sleep(time.Minute)
+	return 0
+}
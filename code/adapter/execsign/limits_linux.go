@@ -0,0 +1,95 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"syscall"
+)
+
+// limitsEnv hands Limits to the re-exec wrapper started by wrapLimits.
+const limitsEnv = "DILIVET_EXECSIGN_LIMITS"
+
+// limitsMain records that the executable calls LimitsMain, so a re-exec
+// of it reaches the wrapper.
+var limitsMain atomic.Bool
+
+// LimitsMain must open main in every binary that runs targets with Limits.
+// In the wrapper process wrapLimits starts it sets the limits and execs the
+// target, never returning; anywhere else it returns at once and enables
+// Limits for the process.
+func LimitsMain() {
+	if spec, ok := os.LookupEnv(limitsEnv); ok {
+		os.Exit(limitsWrapper(spec, os.Args[1:]))
+	}
+	limitsMain.Store(true)
+}
+
+// wrapLimits makes cmd start the target through a copy of the current
+// executable that sets l with setrlimit(2) and then execve(2)s the target.
+// The limits are thus in force from the target's first instruction and
+// inherited by every process it forks, while execve keeps the pid, process
+// group and rusage the caller relies on.
+func wrapLimits(cmd *exec.Cmd, l Limits) error {
+	if l.IsZero() || cmd.Err != nil {
+		return nil // a failed lookup is reported by Start
+	}
+	if !limitsMain.Load() {
+		return ErrNoLimitsMain
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("execsign: limits wrapper: %w", err)
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env[:len(env):len(env)],
+		fmt.Sprintf("%s=%d,%d,%d", limitsEnv, l.AddressSpace, l.CPUSeconds, l.OpenFiles))
+	cmd.Args = []string{self, cmd.Path}
+	cmd.Path = self
+	return nil
+}
+
+// limitsWrapper sets the limits encoded in spec and execs args[0]. It only
+// returns on failure, with the exit status a shell uses for exec errors.
+func limitsWrapper(spec string, args []string) int {
+	fail := func(err error) int {
+		fmt.Fprintf(os.Stderr, "execsign: limits wrapper: %v\n", err)
+		return 127
+	}
+	if len(args) != 1 {
+		return fail(errors.New("want exactly one target path"))
+	}
+	var l Limits
+	if _, err := fmt.Sscanf(spec, "%d,%d,%d", &l.AddressSpace, &l.CPUSeconds, &l.OpenFiles); err != nil {
+		return fail(fmt.Errorf("%s=%q: %w", limitsEnv, spec, err))
+	}
+	for _, r := range []struct {
+		name     string
+		resource int
+		value    uint64
+	}{
+		{"address space", syscall.RLIMIT_AS, l.AddressSpace},
+		{"CPU time", syscall.RLIMIT_CPU, l.CPUSeconds},
+		{"open files", syscall.RLIMIT_NOFILE, l.OpenFiles},
+	} {
+		if r.value == 0 {
+			continue
+		}
+		// syscall.Setrlimit, unlike a raw prlimit, also stops the runtime
+		// from restoring its saved RLIMIT_NOFILE on exec.
+		lim := syscall.Rlimit{Cur: r.value, Max: r.value}
+		if err := syscall.Setrlimit(r.resource, &lim); err != nil {
+			return fail(fmt.Errorf("limit %s: %w", r.name, err))
+		}
+	}
+	os.Unsetenv(limitsEnv)
+	return fail(syscall.Exec(args[0], args, os.Environ()))
+}
@@ -0,0 +1,18 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+//go:build !linux
+
+package execsign
+
+import "os/exec"
+
+// LimitsMain does nothing where Limits are unsupported.
+func LimitsMain() {}
+
+func wrapLimits(_ *exec.Cmd, l Limits) error {
+	if l.IsZero() {
+		return nil
+	}
+	return ErrLimitsUnsupported
+}
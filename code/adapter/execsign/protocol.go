@@ -0,0 +1,103 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Protocol identifies the line-delimited JSON protocol spoken over a
+// Session (docs/execsign-protocol.md). Every message is one JSON object on
+// one line; requests carry an id that the reply echoes, so replies may
+// arrive in any order.
+const (
+	ProtocolName    = "dilivet-execsign"
+	ProtocolVersion = 1
+
+	// OpHello is the handshake; it always uses id 0.
+	OpHello = "hello"
+)
+
+// Capabilities a target may announce in its hello reply.
+const (
+	CapKeyGen     = "keygen"
+	CapSign       = "sign"
+	CapVerify     = "verify"
+	CapPreHash    = "prehash"
+	CapExternalMu = "externalMu"
+	CapCtx        = "ctx"
+	CapFault      = "fault"
+)
+
+// Error codes a target may put in a failed reply. Other codes are passed
+// through in TargetError.
+const (
+	CodeUnsupported = "unsupported"
+	CodeBadRequest  = "bad-request"
+)
+
+// Session failure classes. Errors returned by Session.Call wrap exactly one
+// of them (or a *TargetError), so callers can tell a crashed target from a
+// slow one from one that broke the protocol. Bin.Run uses ErrTimeout and
+// ErrCrashed too.
+var (
+	ErrTimeout     = errors.New("execsign: request timed out")
+	ErrCrashed     = errors.New("execsign: target exited")
+	ErrProtocol    = errors.New("execsign: protocol error")
+	ErrAborted     = errors.New("execsign: request aborted by target restart")
+	ErrUnsupported = errors.New("execsign: operation not supported by target")
+	ErrClosed      = errors.New("execsign: session closed")
+)
+
+// Request is one line sent to the target.
+type Request struct {
+	ID   uint64            `json:"id"`
+	Op   string            `json:"op"`
+	Args map[string]string `json:"args,omitempty"`
+
+	// Handshake only.
+	Protocol string `json:"protocol,omitempty"`
+	Version  int    `json:"version,omitempty"`
+}
+
+// Response is one line read from the target.
+type Response struct {
+	ID     uint64            `json:"id"`
+	OK     bool              `json:"ok"`
+	Result map[string]string `json:"result,omitempty"`
+	Error  *ResponseError    `json:"error,omitempty"`
+
+	// Handshake only.
+	Protocol     string   `json:"protocol,omitempty"`
+	Version      int      `json:"version,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// ResponseError is the error object of a failed reply.
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// TargetError is a failure the target reported for one request; the
+// target itself is still healthy.
+type TargetError struct {
+	Op      string
+	Code    string
+	Message string
+}
+
+func (e *TargetError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("execsign: %s: %s", e.Op, e.Code)
+	}
+	return fmt.Sprintf("execsign: %s: %s: %s", e.Op, e.Code, e.Message)
+}
+
+// Is matches ErrUnsupported for the unsupported code.
+func (e *TargetError) Is(target error) bool {
+	return target == ErrUnsupported && e.Code == CodeUnsupported
+}
@@ -0,0 +1,400 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session keeps one target process running and exchanges Protocol messages
+// with it. Calls may be issued from several goroutines; they are written
+// in order and matched to replies by id, so requests pipeline.
+//
+// When the target exits, breaks the protocol or misses a deadline, the
+// process is killed and the next Call starts a fresh one, up to
+// MaxRestarts times.
+type Session struct {
+	Bin Bin
+
+	// MaxRestarts bounds how often a failed target is restarted; zero
+	// uses 3, a negative value disables restarts.
+	MaxRestarts int
+
+	mu       sync.Mutex
+	proc     *process
+	hello    Response
+	nextID   uint64
+	restarts int
+	closed   bool
+}
+
+// StartSession launches b and performs the handshake.
+func StartSession(ctx context.Context, b Bin) (*Session, error) {
+	s := &Session{Bin: b}
+	if _, err := s.current(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Name is the implementation name from the handshake.
+func (s *Session) Name() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hello.Name
+}
+
+// Capabilities lists what the target announced in the handshake.
+func (s *Session) Capabilities() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.hello.Capabilities...)
+}
+
+// Has reports whether the target announced every capability in caps.
+func (s *Session) Has(caps ...string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range caps {
+		found := false
+		for _, have := range s.hello.Capabilities {
+			found = found || have == c
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Restarts reports how often the target has been restarted.
+func (s *Session) Restarts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.restarts
+}
+
+// Call sends op with args and waits for the reply or the per-request
+// timeout (Bin.Timeout, default 5s). A reply with ok=false is returned as a
+// *TargetError; every other failure wraps ErrTimeout, ErrCrashed,
+// ErrProtocol or ErrAborted.
+func (s *Session) Call(ctx context.Context, op string, args map[string]string) (map[string]string, error) {
+	p, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+
+	resp, err := p.roundTrip(ctx, Request{ID: id, Op: op, Args: args}, s.timeout())
+	if err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		te := &TargetError{Op: op, Code: "error"}
+		if resp.Error != nil {
+			te.Code, te.Message = resp.Error.Code, resp.Error.Message
+		}
+		return nil, te
+	}
+	return resp.Result, nil
+}
+
+// Close stops the target: stdin is closed so it can exit on its own, and it
+// is killed if it is still running after a short grace period.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	p := s.proc
+	s.proc, s.closed = nil, true
+	s.mu.Unlock()
+	if p == nil {
+		return nil
+	}
+	p.stdin.Close()
+	select {
+	case <-p.done:
+	case <-time.After(time.Second):
+		p.fail(ErrClosed)
+		<-p.done
+	}
+	return nil
+}
+
+func (s *Session) timeout() time.Duration {
+	if s.Bin.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return s.Bin.Timeout
+}
+
+// current returns the live process, starting or restarting it as needed.
+func (s *Session) current(ctx context.Context) (*process, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.proc != nil {
+		if !s.proc.failed() {
+			return s.proc, nil
+		}
+		limit := s.MaxRestarts
+		if limit == 0 {
+			limit = 3
+		}
+		if s.restarts >= limit {
+			cause := s.proc.cause()
+			return nil, fmt.Errorf("%w: not restarted after %d restarts (last failure: %v)", failureClass(cause), s.restarts, cause)
+		}
+		s.restarts++
+		s.proc = nil
+	}
+
+	p, err := startProcess(s.Bin)
+	if err != nil {
+		return nil, err
+	}
+	hello, err := p.roundTrip(ctx, Request{ID: 0, Op: OpHello, Protocol: ProtocolName, Version: ProtocolVersion}, s.timeout())
+	if err == nil {
+		err = checkHello(hello)
+	}
+	if err != nil {
+		// Keep the failed process so the next Call counts as a restart.
+		p.fail(err)
+		s.proc = p
+		return nil, fmt.Errorf("execsign: %s handshake: %w", s.Bin.Path, err)
+	}
+	s.proc, s.hello = p, hello
+	return p, nil
+}
+
+// failureClass is the sentinel of the last failure of a process: ErrTimeout
+// when it was killed for a timed-out request (ErrAborted), ErrProtocol for
+// a garbled reply and ErrCrashed otherwise, so a target that keeps hanging
+// is not reported as crashing.
+func failureClass(cause error) error {
+	switch {
+	case errors.Is(cause, ErrAborted), errors.Is(cause, ErrTimeout):
+		return ErrTimeout
+	case errors.Is(cause, ErrProtocol):
+		return ErrProtocol
+	}
+	return ErrCrashed
+}
+
+func checkHello(r Response) error {
+	switch {
+	case !r.OK:
+		msg := "rejected"
+		if r.Error != nil {
+			msg = r.Error.Code + ": " + r.Error.Message
+		}
+		return fmt.Errorf("%w: hello %s", ErrProtocol, msg)
+	case r.Protocol != ProtocolName:
+		return fmt.Errorf("%w: protocol %q, want %q", ErrProtocol, r.Protocol, ProtocolName)
+	case r.Version != ProtocolVersion:
+		return fmt.Errorf("%w: version %d, want %d", ErrProtocol, r.Version, ProtocolVersion)
+	}
+	return nil
+}
+
+// process is one running target and its reply dispatcher.
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *tailBuffer
+	usage  *UsageLog
+
+	writeMu sync.Mutex
+
+	mu        sync.Mutex
+	pending   map[uint64]chan reply
+	abandoned map[uint64]bool // cancelled requests whose reply may still come
+	err       error           // set once the process is failed
+	done      chan struct{}
+	once      sync.Once
+}
+
+type reply struct {
+	resp Response
+	err  error
+}
+
+func startProcess(b Bin) (*process, error) {
+	if b.Path == "" {
+		return nil, errors.New("execsign: empty binary path")
+	}
+	cmd := exec.Command(b.Path)
+	cmd.Env = append(cmd.Env, b.Env...)
+	cmd.Dir = b.Dir
+	setProcessGroup(cmd)
+	p := &process{
+		cmd:       cmd,
+		stderr:    newTailBuffer(b.StderrLimit),
+		usage:     b.Usage,
+		pending:   map[uint64]chan reply{},
+		abandoned: map[uint64]bool{},
+		done:      make(chan struct{}),
+	}
+	cmd.Stderr = p.stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("execsign: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("execsign: %w", err)
+	}
+	if err := wrapLimits(cmd, b.Limits); err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("execsign: %w", err)
+	}
+	p.stdin = stdin
+	go p.read(stdout)
+	return p, nil
+}
+
+// read dispatches reply lines until stdout closes, then reaps the process.
+func (p *process) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var resp Response
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			p.fail(fmt.Errorf("%w: malformed reply %.80q: %v", ErrProtocol, line, err))
+			break
+		}
+		p.mu.Lock()
+		ch, ok := p.pending[resp.ID]
+		late := p.abandoned[resp.ID]
+		delete(p.pending, resp.ID)
+		delete(p.abandoned, resp.ID)
+		p.mu.Unlock()
+		if late {
+			continue
+		}
+		if !ok {
+			p.fail(fmt.Errorf("%w: reply for unknown id %d", ErrProtocol, resp.ID))
+			break
+		}
+		ch <- reply{resp: resp}
+	}
+	if err := scanner.Err(); err != nil {
+		p.fail(fmt.Errorf("%w: read reply: %v", ErrProtocol, err))
+	}
+	werr := p.cmd.Wait()
+	// Wall time is accounted per call; the process adds CPU time and RSS.
+	u := processUsage(p.cmd.ProcessState, 0)
+	p.usage.add(u, true)
+	p.fail(fmt.Errorf("%w: %v%s", ErrCrashed, exitStatus(werr), p.stderr.suffix()))
+	close(p.done)
+}
+
+// fail marks the process dead with cause, kills it and fails every
+// pending request. Only the first cause is kept.
+func (p *process) fail(cause error) {
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.err = cause
+		pending := p.pending
+		p.pending = map[uint64]chan reply{}
+		p.mu.Unlock()
+		for _, ch := range pending {
+			ch <- reply{err: cause}
+		}
+		killGroup(p.cmd)
+	})
+}
+
+func (p *process) failed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err != nil
+}
+
+func (p *process) cause() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// roundTrip writes req and waits for its reply. On timeout the process is
+// killed: the late request gets ErrTimeout and other in-flight requests
+// ErrAborted.
+func (p *process) roundTrip(ctx context.Context, req Request, timeout time.Duration) (Response, error) {
+	ch := make(chan reply, 1)
+	p.mu.Lock()
+	if p.err != nil {
+		p.mu.Unlock()
+		return Response{}, p.err
+	}
+	p.pending[req.ID] = ch
+	p.mu.Unlock()
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		p.forget(req.ID)
+		return Response{}, fmt.Errorf("execsign: encode request: %w", err)
+	}
+	p.writeMu.Lock()
+	_, werr := p.stdin.Write(append(line, '\n'))
+	p.writeMu.Unlock()
+	if werr != nil {
+		// The reader reports why the target went away; wait for it.
+		<-p.done
+		return Response{}, p.cause()
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-ch:
+		p.usage.add(Usage{Wall: time.Since(start)}, false)
+		return r.resp, r.err
+	case <-timer.C:
+		p.forget(req.ID)
+		p.fail(fmt.Errorf("%w: request %d (%s) timed out", ErrAborted, req.ID, req.Op))
+		return Response{}, fmt.Errorf("%w: %s after %s", ErrTimeout, req.Op, timeout)
+	case <-ctx.Done():
+		p.mu.Lock()
+		if _, ok := p.pending[req.ID]; ok {
+			delete(p.pending, req.ID)
+			p.abandoned[req.ID] = true
+		}
+		p.mu.Unlock()
+		return Response{}, ctx.Err()
+	}
+}
+
+func (p *process) forget(id uint64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+func exitStatus(err error) string {
+	if err == nil {
+		return "exit status 0"
+	}
+	return err.Error()
+}
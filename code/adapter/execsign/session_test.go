@@ -0,0 +1,224 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const helperEnv = "DILIVET_EXECSIGN_HELPER"
+
+// TestMain lets the test binary act as a scripted target: a Bin in the
+// oneshot-* modes (exec_test.go) and a protocol peer otherwise.
+func TestMain(m *testing.M) {
+	LimitsMain()
+	mode := os.Getenv(helperEnv)
+	switch {
+	case strings.HasPrefix(mode, "oneshot-"):
+		os.Exit(oneShotHelper(mode))
+	case mode != "":
+		os.Exit(sessionHelper(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// sessionHelper answers hello, then: echo returns args.value, delay replies
+// after args.ms from a goroutine, crash exits, hang never replies, garbage
+// prints a non-JSON line and unsupported fails with that code. Mode
+// "old-version" announces protocol version 99.
+func sessionHelper(mode string) int {
+	var mu sync.Mutex
+	send := func(r Response) {
+		line, _ := json.Marshal(r)
+		mu.Lock()
+		fmt.Printf("%s\n", line)
+		mu.Unlock()
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return 2
+		}
+		switch req.Op {
+		case OpHello:
+			version := ProtocolVersion
+			if mode == "old-version" {
+				version = 99
+			}
+			send(Response{ID: req.ID, OK: true, Protocol: ProtocolName, Version: version,
+				Name: "helper", Capabilities: []string{CapKeyGen, CapSign, CapVerify}})
+		case "echo":
+			send(Response{ID: req.ID, OK: true, Result: map[string]string{"value": req.Args["value"]}})
+		case "delay":
+			ms, _ := strconv.Atoi(req.Args["ms"])
+			go func(id uint64) {
+				time.Sleep(time.Duration(ms) * time.Millisecond)
+				send(Response{ID: id, OK: true})
+			}(req.ID)
+		case "crash":
+			fmt.Fprintln(os.Stderr, "boom")
+			return 3
+		case "hang":
+		case "garbage":
+			mu.Lock()
+			fmt.Println("not json")
+			mu.Unlock()
+		default:
+			send(Response{ID: req.ID, Error: &ResponseError{Code: CodeUnsupported, Message: req.Op}})
+		}
+	}
+	return 0
+}
+
+func startHelper(t *testing.T, mode string, timeout time.Duration) *Session {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	s, err := StartSession(context.Background(), Bin{Path: exe, Timeout: timeout, Env: append(os.Environ(), helperEnv+"="+mode)})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func echo(t *testing.T, s *Session, value string) {
+	t.Helper()
+	got, err := s.Call(context.Background(), "echo", map[string]string{"value": value})
+	if err != nil || got["value"] != value {
+		t.Fatalf("echo %q = %v, %v", value, got, err)
+	}
+}
+
+func TestSessionHandshake(t *testing.T) {
+	s := startHelper(t, "ok", 0)
+	if s.Name() != "helper" || !s.Has(CapSign, CapVerify) || s.Has(CapPreHash) {
+		t.Fatalf("name %q, capabilities %v", s.Name(), s.Capabilities())
+	}
+	echo(t, s, "hi")
+
+	_, err := s.Call(context.Background(), "nope", nil)
+	var te *TargetError
+	if !errors.Is(err, ErrUnsupported) || !errors.As(err, &te) || te.Message != "nope" {
+		t.Fatalf("got %v, want an unsupported TargetError", err)
+	}
+	echo(t, s, "still alive")
+}
+
+func TestSessionPipelines(t *testing.T) {
+	s := startHelper(t, "ok", 0)
+	slow := make(chan error, 1)
+	go func() {
+		_, err := s.Call(context.Background(), "delay", map[string]string{"ms": "300"})
+		slow <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	echo(t, s, "fast")
+	if d := time.Since(start); d > 200*time.Millisecond {
+		t.Errorf("echo waited %s behind the slow request", d)
+	}
+	if err := <-slow; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionCrashRestarts(t *testing.T) {
+	s := startHelper(t, "ok", 0)
+	_, err := s.Call(context.Background(), "crash", nil)
+	if !errors.Is(err, ErrCrashed) || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("got %v, want ErrCrashed with stderr", err)
+	}
+	echo(t, s, "after crash")
+	if s.Restarts() != 1 {
+		t.Errorf("restarts = %d, want 1", s.Restarts())
+	}
+}
+
+func TestSessionTimeout(t *testing.T) {
+	s := startHelper(t, "ok", 300*time.Millisecond)
+	late := make(chan error, 1)
+	go func() {
+		_, err := s.Call(context.Background(), "hang", nil)
+		late <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Still in flight when the hang times out and the target is killed.
+	_, err := s.Call(context.Background(), "delay", map[string]string{"ms": "5000"})
+	if !errors.Is(err, ErrAborted) {
+		t.Fatalf("in-flight request got %v, want ErrAborted", err)
+	}
+	if err := <-late; !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v, want ErrTimeout", err)
+	}
+	echo(t, s, "after timeout")
+}
+
+func TestSessionProtocolError(t *testing.T) {
+	s := startHelper(t, "ok", 0)
+	_, err := s.Call(context.Background(), "garbage", nil)
+	if !errors.Is(err, ErrProtocol) || errors.Is(err, ErrCrashed) {
+		t.Fatalf("got %v, want ErrProtocol only", err)
+	}
+	echo(t, s, "after garbage")
+}
+
+func TestSessionRestartLimit(t *testing.T) {
+	s := startHelper(t, "ok", 0)
+	s.MaxRestarts = -1
+	if _, err := s.Call(context.Background(), "crash", nil); !errors.Is(err, ErrCrashed) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := s.Call(context.Background(), "echo", nil); !errors.Is(err, ErrCrashed) {
+		t.Fatalf("got %v, want no restart", err)
+	}
+}
+
+// Once restarts run out, later calls report the class of the last
+// failure rather than a crash.
+func TestSessionRestartLimitKeepsClass(t *testing.T) {
+	s := startHelper(t, "ok", 100*time.Millisecond)
+	s.MaxRestarts = -1
+	if _, err := s.Call(context.Background(), "hang", nil); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v", err)
+	}
+	_, err := s.Call(context.Background(), "echo", nil)
+	if !errors.Is(err, ErrTimeout) || errors.Is(err, ErrCrashed) {
+		t.Fatalf("got %v, want ErrTimeout only", err)
+	}
+
+	s = startHelper(t, "ok", 0)
+	s.MaxRestarts = -1
+	if _, err := s.Call(context.Background(), "garbage", nil); !errors.Is(err, ErrProtocol) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := s.Call(context.Background(), "echo", nil); !errors.Is(err, ErrProtocol) || errors.Is(err, ErrCrashed) {
+		t.Fatalf("got %v, want ErrProtocol only", err)
+	}
+}
+
+func TestSessionVersionMismatch(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	_, err = StartSession(context.Background(), Bin{Path: exe, Env: append(os.Environ(), helperEnv+"=old-version")})
+	if !errors.Is(err, ErrProtocol) {
+		t.Fatalf("got %v, want ErrProtocol", err)
+	}
+}
@@ -0,0 +1,112 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package execsign
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Limits are per-process resource limits (setrlimit). Zero fields are left
+// at the inherited value. The target is started through a small re-exec
+// wrapper that sets them before execve(2), so they hold from the target's
+// first instruction and bind every child it forks. CPU time and peak RSS
+// include the wrapper's brief Go startup.
+type Limits struct {
+	AddressSpace uint64 // RLIMIT_AS, bytes
+	CPUSeconds   uint64 // RLIMIT_CPU
+	OpenFiles    uint64 // RLIMIT_NOFILE
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// ErrLimitsUnsupported is returned when Limits are set on a platform other
+// than Linux.
+var ErrLimitsUnsupported = errors.New("execsign: resource limits are only supported on Linux")
+
+// ErrNoLimitsMain is returned when Limits are set in a binary whose main
+// does not call LimitsMain.
+var ErrNoLimitsMain = errors.New("execsign: resource limits need execsign.LimitsMain at the start of main")
+
+// Usage is the resource usage of one call. For one-shot Bin calls it covers
+// the whole process; for a Session only Wall is per call.
+type Usage struct {
+	Wall   time.Duration `json:"wallNs"`
+	User   time.Duration `json:"userNs"`
+	System time.Duration `json:"systemNs"`
+	MaxRSS int64         `json:"maxRssBytes"` // 0 where the platform does not report it
+}
+
+// UsageLog accumulates Usage across calls and processes. It is safe for
+// concurrent use; a nil *UsageLog ignores records.
+type UsageLog struct {
+	mu sync.Mutex
+	s  UsageSummary
+}
+
+// UsageSummary totals a UsageLog.
+type UsageSummary struct {
+	Calls     int           `json:"calls"`
+	Processes int           `json:"processes"`
+	Wall      time.Duration `json:"wallNs"`
+	User      time.Duration `json:"userNs"`
+	System    time.Duration `json:"systemNs"`
+	MaxRSS    int64         `json:"maxRssBytes"` // peak over all processes
+}
+
+// MeanWall is the average wall time per call.
+func (s UsageSummary) MeanWall() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Wall / time.Duration(s.Calls)
+}
+
+// Summary returns the totals so far.
+func (l *UsageLog) Summary() UsageSummary {
+	if l == nil {
+		return UsageSummary{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s
+}
+
+// add records u. A non-zero Wall counts as one call; with process set, the
+// CPU time and RSS count as one finished process.
+func (l *UsageLog) add(u Usage, process bool) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if u.Wall > 0 {
+		l.s.Calls++
+		l.s.Wall += u.Wall
+	}
+	if process {
+		l.s.Processes++
+		l.s.User += u.User
+		l.s.System += u.System
+		if u.MaxRSS > l.s.MaxRSS {
+			l.s.MaxRSS = u.MaxRSS
+		}
+	}
+}
+
+// processUsage reads the rusage of an exited process.
+func processUsage(ps *os.ProcessState, wall time.Duration) Usage {
+	u := Usage{Wall: wall}
+	if ps == nil {
+		return u
+	}
+	u.User, u.System = ps.UserTime(), ps.SystemTime()
+	u.MaxRSS = maxRSS(ps)
+	return u
+}
@@ -0,0 +1,277 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/pack"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// polyBytes returns the packed size of one polynomial at the given bit width.
+func polyBytes(bits int) int {
+	return poly.N * bits / 8
+}
+
+// unpackPublicKey implements FIPS 204 Algorithm 23 (pkDecode).
+func unpackPublicKey(pk []byte, params *Params) (rho []byte, t1 *poly.Vec, err error) {
+	if len(pk) != params.PKBytes {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	rho = pk[:SeedBytes]
+	t1 = poly.NewVec(params.K)
+	step := polyBytes(params.DuBits)
+	for i := 0; i < params.K; i++ {
+		off := SeedBytes + i*step
+		vals, err := pack.UnpackBits(pk[off:off+step], params.DuBits, poly.N)
+		if err != nil {
+			return nil, nil, fmt.Errorf("mldsa: unpack t1[%d]: %w", i, err)
+		}
+		copy(t1.Polys()[i].Coeffs[:], vals)
+	}
+	return rho, t1, nil
+}
+
+// unpackSignature implements FIPS 204 Algorithm 27 (sigDecode).
+// The hint is returned as a k×n bitmap; a nil error guarantees it was
+// canonically encoded (Algorithm 21, HintBitUnpack).
+func unpackSignature(sig []byte, params *Params) (ctilde []byte, z *poly.Vec, h [][]bool, err error) {
+	if len(sig) != params.SigBytes {
+		return nil, nil, nil, ErrInvalidSignature
+	}
+	cb := params.CTildeBytes()
+	ctilde = sig[:cb]
+
+	z = poly.NewVec(params.L)
+	step := polyBytes(params.Gamma1Bits)
+	for i := 0; i < params.L; i++ {
+		off := cb + i*step
+		vals, err := pack.UnpackBits(sig[off:off+step], params.Gamma1Bits, poly.N)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("mldsa: unpack z[%d]: %w", i, err)
+		}
+		// BitUnpack(·, γ₁-1, γ₁) stores γ₁ - z.
+		for j, v := range vals {
+			z.Polys()[i].Coeffs[j] = poly.ModQ(uint32(params.Gamma1) + poly.Q - v)
+		}
+	}
+
+	h, err = unpackHint(sig[cb+params.L*step:], params)
+	if err != nil {
+		// c̃ and z decoded fine; callers tracing a rejection still see them.
+		return ctilde, z, nil, err
+	}
+	return ctilde, z, h, nil
+}
+
+// unpackHint implements FIPS 204 Algorithm 21 (HintBitUnpack), rejecting
+// non-canonical encodings: unsorted or repeated indices, counters that
+// decrease or exceed ω, and non-zero padding.
+func unpackHint(y []byte, params *Params) ([][]bool, error) {
+	if len(y) != params.Omega+params.K {
+		return nil, ErrMalformedHint
+	}
+	h := make([][]bool, params.K)
+	idx := 0
+	for i := 0; i < params.K; i++ {
+		h[i] = make([]bool, poly.N)
+		limit := int(y[params.Omega+i])
+		if limit < idx || limit > params.Omega {
+			return nil, fmt.Errorf("%w: hint counter %d out of range", ErrMalformedHint, i)
+		}
+		first := idx
+		for idx < limit {
+			if idx > first && y[idx-1] >= y[idx] {
+				return nil, fmt.Errorf("%w: hint indices not strictly increasing", ErrMalformedHint)
+			}
+			h[i][y[idx]] = true
+			idx++
+		}
+	}
+	for i := idx; i < params.Omega; i++ {
+		if y[i] != 0 {
+			return nil, fmt.Errorf("%w: non-zero hint padding", ErrMalformedHint)
+		}
+	}
+	return h, nil
+}
+
+// Component names one field of an encoded key or signature and its byte range.
+type Component struct {
+	Name   string
+	Offset int
+	Size   int
+}
+
+// PublicKeyLayout returns the fields of pkEncode (FIPS 204 Algorithm 22) in order.
+func PublicKeyLayout(params *Params) []Component {
+	return withOffsets([]Component{
+		{Name: "rho", Size: SeedBytes},
+		{Name: "t1", Size: params.K * polyBytes(params.DuBits)},
+	})
+}
+
+// SecretKeyLayout returns the fields of skEncode (FIPS 204 Algorithm 24) in order.
+func SecretKeyLayout(params *Params) []Component {
+	return withOffsets([]Component{
+		{Name: "rho", Size: SeedBytes},
+		{Name: "K", Size: SeedBytes},
+		{Name: "tr", Size: CRHBytes},
+		{Name: "s1", Size: params.L * polyBytes(params.EtaBits)},
+		{Name: "s2", Size: params.K * polyBytes(params.EtaBits)},
+		{Name: "t0", Size: params.K * polyBytes(d)},
+	})
+}
+
+// SignatureLayout returns the fields of sigEncode (FIPS 204 Algorithm 26) in order.
+func SignatureLayout(params *Params) []Component {
+	return withOffsets([]Component{
+		{Name: "c_tilde", Size: params.CTildeBytes()},
+		{Name: "z", Size: params.L * polyBytes(params.Gamma1Bits)},
+		{Name: "h", Size: params.Omega + params.K},
+	})
+}
+
+// withOffsets lays the components out back to back.
+func withOffsets(components []Component) []Component {
+	off := 0
+	for i := range components {
+		components[i].Offset = off
+		off += components[i].Size
+	}
+	return components
+}
+
+// ComponentAt returns the name of the component containing byte offset off,
+// or "" if off lies outside the layout.
+func ComponentAt(components []Component, off int) string {
+	for _, c := range components {
+		if off >= c.Offset && off < c.Offset+c.Size {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// packPublicKey implements FIPS 204 Algorithm 22 (pkEncode).
+func packPublicKey(rho []byte, t1 *poly.Vec, params *Params) ([]byte, error) {
+	pk := make([]byte, 0, params.PKBytes)
+	pk = append(pk, rho...)
+	for i, p := range t1.Polys() {
+		b, err := pack.PackPolyCoeffs(p, params.DuBits)
+		if err != nil {
+			return nil, fmt.Errorf("mldsa: pack t1[%d]: %w", i, err)
+		}
+		pk = append(pk, b...)
+	}
+	return pk, nil
+}
+
+// packSecretKey implements FIPS 204 Algorithm 24 (skEncode). s₁ and s₂ are
+// stored as η − s and t₀ as 2^(d-1) − t₀.
+func packSecretKey(rho, key, tr []byte, s1, s2, t0 *poly.Vec, params *Params) ([]byte, error) {
+	sk := make([]byte, 0, params.SKBytes)
+	sk = append(sk, rho...)
+	sk = append(sk, key...)
+	sk = append(sk, tr...)
+
+	packOffset := func(v *poly.Vec, offset uint32, bits int, name string) error {
+		var tmp poly.Poly
+		for i, p := range v.Polys() {
+			for j, c := range p.Coeffs {
+				tmp.Coeffs[j] = poly.ModQ(offset + q - c)
+			}
+			b, err := pack.PackPolyCoeffs(&tmp, bits)
+			if err != nil {
+				return fmt.Errorf("mldsa: pack %s[%d]: %w", name, i, err)
+			}
+			sk = append(sk, b...)
+		}
+		return nil
+	}
+	if err := packOffset(s1, uint32(params.Eta), params.EtaBits, "s1"); err != nil {
+		return nil, err
+	}
+	if err := packOffset(s2, uint32(params.Eta), params.EtaBits, "s2"); err != nil {
+		return nil, err
+	}
+	if err := packOffset(t0, 1<<(d-1), d, "t0"); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// unpackSecretKey implements FIPS 204 Algorithm 25 (skDecode). s₁, s₂ and
+// t₀ are returned with coefficients modulo q.
+func unpackSecretKey(sk []byte, params *Params) (rho, key, tr []byte, s1, s2, t0 *poly.Vec, err error) {
+	if len(sk) != params.SKBytes {
+		return nil, nil, nil, nil, nil, nil, ErrInvalidSecretKey
+	}
+	rho = sk[:SeedBytes]
+	key = sk[SeedBytes : 2*SeedBytes]
+	tr = sk[2*SeedBytes : 2*SeedBytes+CRHBytes]
+	off := 2*SeedBytes + CRHBytes
+
+	unpackOffset := func(n int, offset uint32, bits int, name string) (*poly.Vec, error) {
+		v := poly.NewVec(n)
+		step := polyBytes(bits)
+		for i := 0; i < n; i++ {
+			vals, err := pack.UnpackBits(sk[off:off+step], bits, poly.N)
+			if err != nil {
+				return nil, fmt.Errorf("mldsa: unpack %s[%d]: %w", name, i, err)
+			}
+			for j, c := range vals {
+				v.Polys()[i].Coeffs[j] = poly.ModQ(offset + q - c)
+			}
+			off += step
+		}
+		return v, nil
+	}
+	if s1, err = unpackOffset(params.L, uint32(params.Eta), params.EtaBits, "s1"); err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	if s2, err = unpackOffset(params.K, uint32(params.Eta), params.EtaBits, "s2"); err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	if t0, err = unpackOffset(params.K, 1<<(d-1), d, "t0"); err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	return rho, key, tr, s1, s2, t0, nil
+}
+
+// packSignature implements FIPS 204 Algorithm 26 (sigEncode), including
+// HintBitPack (Algorithm 20). z is stored as γ₁ − z.
+func packSignature(ctilde []byte, z *poly.Vec, h [][]bool, params *Params) ([]byte, error) {
+	sig := make([]byte, 0, params.SigBytes)
+	sig = append(sig, ctilde...)
+
+	var tmp poly.Poly
+	for i, p := range z.Polys() {
+		for j, c := range p.Coeffs {
+			tmp.Coeffs[j] = poly.ModQ(uint32(params.Gamma1) + q - c)
+		}
+		b, err := pack.PackPolyCoeffs(&tmp, params.Gamma1Bits)
+		if err != nil {
+			return nil, fmt.Errorf("mldsa: pack z[%d]: %w", i, err)
+		}
+		sig = append(sig, b...)
+	}
+
+	y := make([]byte, params.Omega+params.K)
+	idx := 0
+	for i := range h {
+		for j, set := range h[i] {
+			if set {
+				if idx == params.Omega {
+					return nil, fmt.Errorf("mldsa: more than %d hint bits", params.Omega)
+				}
+				y[idx] = byte(j)
+				idx++
+			}
+		}
+		y[params.Omega+i] = byte(idx)
+	}
+	return append(sig, y...), nil
+}
@@ -0,0 +1,97 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import "errors"
+
+// MaxContextBytes is the longest context string FIPS 204 permits.
+const MaxContextBytes = 255
+
+// Errors specific to the external signing and verification interfaces.
+var (
+	ErrContextTooLong = errors.New("mldsa: context string longer than 255 bytes")
+	ErrInvalidMu      = errors.New("mldsa: external mu must be 64 bytes")
+)
+
+// Domain separators prefixed to M′ by the external interfaces.
+const (
+	domainPure    = 0x00
+	domainPreHash = 0x01
+)
+
+// formatMessage builds M′ = domain || |ctx| || ctx || payload as used by
+// FIPS 204 Algorithms 2–5.
+func formatMessage(domain byte, ctx, payload []byte) ([]byte, error) {
+	if len(ctx) > MaxContextBytes {
+		return nil, ErrContextTooLong
+	}
+	out := make([]byte, 0, 2+len(ctx)+len(payload))
+	out = append(out, domain, byte(len(ctx)))
+	out = append(out, ctx...)
+	return append(out, payload...), nil
+}
+
+// verifyParams performs the length checks shared by the external verify
+// entry points and returns the parameter set implied by pk.
+func verifyParams(pk, sig []byte) (*Params, error) {
+	if len(pk) == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	if len(sig) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	params, err := FromPublicKeyLength(len(pk))
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if len(sig) != params.SigBytes {
+		return nil, ErrInvalidSignature
+	}
+	return params, nil
+}
+
+// VerifyWithContext implements ML-DSA.Verify (FIPS 204 Algorithm 3), the
+// external "pure" interface. ctx may be empty and is at most 255 bytes.
+func VerifyWithContext(pk, msg, ctx, sig []byte) (bool, error) {
+	params, err := verifyParams(pk, sig)
+	if err != nil {
+		return false, err
+	}
+	mPrime, err := formatMessage(domainPure, ctx, msg)
+	if err != nil {
+		return false, err
+	}
+	return verifyFull(pk, mPrime, nil, sig, params, nil)
+}
+
+// VerifyPreHash implements HashML-DSA.Verify (FIPS 204 Algorithm 5). msg is
+// hashed with hashAlg (an ACVP name such as "SHA2-256" or "SHAKE-128").
+func VerifyPreHash(pk, msg, ctx []byte, hashAlg string, sig []byte) (bool, error) {
+	ph, err := preHashMessage(hashAlg, msg)
+	if err != nil {
+		return false, err
+	}
+	params, err := verifyParams(pk, sig)
+	if err != nil {
+		return false, err
+	}
+	mPrime, err := formatMessage(domainPreHash, ctx, ph)
+	if err != nil {
+		return false, err
+	}
+	return verifyFull(pk, mPrime, nil, sig, params, nil)
+}
+
+// VerifyExternalMu runs ML-DSA.Verify_internal with a caller-supplied
+// message representative μ, as exercised by ACVP "externalMu" groups.
+func VerifyExternalMu(pk, mu, sig []byte) (bool, error) {
+	params, err := verifyParams(pk, sig)
+	if err != nil {
+		return false, err
+	}
+	if len(mu) != CRHBytes {
+		return false, ErrInvalidMu
+	}
+	return verifyFull(pk, nil, mu, sig, params, nil)
+}
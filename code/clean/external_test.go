@@ -0,0 +1,69 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestFormatMessage(t *testing.T) {
+	got, err := formatMessage(domainPure, []byte("ctx"), []byte("msg"))
+	if err != nil {
+		t.Fatalf("formatMessage: %v", err)
+	}
+	want := []byte{0x00, 0x03, 'c', 't', 'x', 'm', 's', 'g'}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("formatMessage = %x, want %x", got, want)
+	}
+
+	if _, err := formatMessage(domainPure, make([]byte, MaxContextBytes+1), nil); !errors.Is(err, ErrContextTooLong) {
+		t.Fatalf("expected ErrContextTooLong, got %v", err)
+	}
+}
+
+func TestPreHashMessage(t *testing.T) {
+	for _, name := range PreHashAlgorithms() {
+		out, err := preHashMessage(name, []byte("abc"))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// DER OID header for 2.16.840.1.101.3.4.2.x
+		if len(out) < 11 || out[0] != 0x06 || out[1] != 0x09 {
+			t.Fatalf("%s: malformed OID prefix %x", name, out)
+		}
+	}
+
+	// SHA2-256("abc") with its OID suffix 0x01.
+	out, err := preHashMessage("SHA2-256", []byte("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[10] != 0x01 || out[11] != 0xba || out[len(out)-1] != 0xad {
+		t.Fatalf("unexpected SHA2-256 encoding %x", out)
+	}
+
+	if _, err := preHashMessage("MD5", nil); !errors.Is(err, ErrUnsupportedHash) {
+		t.Fatalf("expected ErrUnsupportedHash, got %v", err)
+	}
+}
+
+func TestVerifyExternalMu_InvalidMu(t *testing.T) {
+	pk := make([]byte, ParamsMLDSA44.PKBytes)
+	sig := make([]byte, ParamsMLDSA44.SigBytes)
+	if _, err := VerifyExternalMu(pk, []byte{1, 2, 3}, sig); !errors.Is(err, ErrInvalidMu) {
+		t.Fatalf("expected ErrInvalidMu, got %v", err)
+	}
+}
+
+func TestVerifyWithContext_LengthChecks(t *testing.T) {
+	pk := make([]byte, ParamsMLDSA65.PKBytes)
+	if _, err := VerifyWithContext(pk, []byte("m"), nil, make([]byte, 10)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if _, err := VerifyWithContext(pk[:5], []byte("m"), nil, make([]byte, 10)); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Fatalf("expected ErrInvalidPublicKey, got %v", err)
+	}
+}
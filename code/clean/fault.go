@@ -0,0 +1,108 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// FaultKind names a fault the built-in signer can simulate.
+type FaultKind string
+
+// Simulated faults. All but FaultReuseRnd are transient: they hit one
+// iteration of the rejection loop, chosen by FaultPlan.Attempt, and leave
+// the others alone.
+const (
+	// FaultSkipNormCheck skips the ‖z‖∞ < γ₁−β and r₀/ct₀ rejection
+	// checks, so the iteration's z is output whatever its size.
+	FaultSkipNormCheck FaultKind = "skip-norm-check"
+	// FaultFlipY adds Delta to one coefficient of y before w = Ay, giving
+	// a valid signature over a mask the attacker knows the offset of.
+	FaultFlipY FaultKind = "flip-y"
+	// FaultCorruptC flips one bit of c̃ after it is hashed, so c and the
+	// signature use a challenge that does not match w₁.
+	FaultCorruptC FaultKind = "corrupt-c"
+	// FaultReuseRnd replaces the caller's rnd with Rnd on every call, as a
+	// stuck random number generator would.
+	FaultReuseRnd FaultKind = "reuse-rnd"
+)
+
+// FaultKinds lists every simulated fault.
+var FaultKinds = []FaultKind{FaultSkipNormCheck, FaultFlipY, FaultCorruptC, FaultReuseRnd}
+
+// ErrFaultPlan reports a fault plan the signer cannot apply.
+var ErrFaultPlan = errors.New("mldsa: invalid fault plan")
+
+// FaultPlan configures the fault SignFaulty injects. The zero value of
+// each field selects the first iteration, polynomial, coefficient or bit.
+type FaultPlan struct {
+	Kind    FaultKind `json:"kind"`
+	Attempt int       `json:"attempt"`         // rejection-loop iteration a transient fault hits
+	Poly    int       `json:"poly,omitempty"`  // flip-y: index of the polynomial of y
+	Coeff   int       `json:"coeff,omitempty"` // flip-y: index of the coefficient
+	Delta   int32     `json:"delta,omitempty"` // flip-y: offset added to the coefficient; 0 means 1
+	Bit     int       `json:"bit,omitempty"`   // corrupt-c: bit of c̃ to flip, 0 being the low bit of c̃[0]
+	Rnd     []byte    `json:"rnd,omitempty"`   // reuse-rnd: the stuck randomness; nil means 32 zero bytes
+}
+
+// YDelta returns the offset FaultFlipY adds to its coefficient.
+func (f *FaultPlan) YDelta() int32 {
+	if f.Delta == 0 {
+		return 1
+	}
+	return f.Delta
+}
+
+func (f *FaultPlan) validate(params *Params) error {
+	switch f.Kind {
+	case FaultSkipNormCheck, FaultCorruptC, FaultFlipY, FaultReuseRnd:
+	default:
+		return fmt.Errorf("%w: unknown fault %q", ErrFaultPlan, f.Kind)
+	}
+	switch {
+	case f.Attempt < 0:
+		return fmt.Errorf("%w: attempt %d", ErrFaultPlan, f.Attempt)
+	case f.Kind == FaultFlipY && (f.Poly < 0 || f.Poly >= params.L || f.Coeff < 0 || f.Coeff >= poly.N):
+		return fmt.Errorf("%w: y[%d][%d] outside l=%d", ErrFaultPlan, f.Poly, f.Coeff, params.L)
+	case f.Kind == FaultFlipY && (f.Delta <= -q || f.Delta >= q):
+		return fmt.Errorf("%w: delta %d outside (-q, q)", ErrFaultPlan, f.Delta)
+	case f.Kind == FaultCorruptC && (f.Bit < 0 || f.Bit >= 8*params.CTildeBytes()):
+		return fmt.Errorf("%w: bit %d outside c̃", ErrFaultPlan, f.Bit)
+	case f.Kind == FaultReuseRnd && f.Rnd != nil && len(f.Rnd) != RndBytes:
+		return fmt.Errorf("%w: %w", ErrFaultPlan, ErrInvalidRandomness)
+	}
+	return nil
+}
+
+// SignFaulty is Sign with the fault described by plan injected, for
+// assessing fault attacks and countermeasures. It must never be used to
+// produce real signatures. A nil plan signs normally.
+func SignFaulty(sk, msg, rnd []byte, plan *FaultPlan) ([]byte, error) {
+	params, err := signParams(sk, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		if err := plan.validate(params); err != nil {
+			return nil, err
+		}
+	}
+	return signFull(sk, msg, nil, rnd, params, plan, nil)
+}
+
+// hits reports whether a transient fault of kind strikes iteration attempt.
+func (f *FaultPlan) hits(kind FaultKind, attempt int) bool {
+	return f != nil && f.Kind == kind && f.Attempt == attempt
+}
+
+// stuckRnd returns the randomness FaultReuseRnd signs with.
+func (f *FaultPlan) stuckRnd() []byte {
+	if f.Rnd != nil {
+		return f.Rnd
+	}
+	return make([]byte, RndBytes)
+}
@@ -0,0 +1,87 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSignFaulty(t *testing.T) {
+	pk, sk, err := KeyGen(ParamsMLDSA44, bytes.Repeat([]byte{0x21}, SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("fault injection")
+	rnd := make([]byte, RndBytes)
+	want, err := Sign(sk, msg, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := SignFaulty(sk, msg, rnd, nil); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("nil plan: err=%v, same=%v", err, bytes.Equal(got, want))
+	}
+
+	// Sweep the iterations: a transient fault only shows where it hits the
+	// iteration that produces the signature (or makes a rejected one pass).
+	changed := map[FaultKind]int{}
+	for _, kind := range []FaultKind{FaultCorruptC, FaultFlipY, FaultSkipNormCheck} {
+		for attempt := 0; attempt < 10; attempt++ {
+			plan := &FaultPlan{Kind: kind, Attempt: attempt, Poly: 1, Coeff: 7, Bit: 5}
+			sig, err := SignFaulty(sk, msg, rnd, plan)
+			if err != nil {
+				continue // skip-norm-check can produce an unencodable z
+			}
+			if bytes.Equal(sig, want) {
+				continue
+			}
+			changed[kind]++
+			ok, _ := Verify(pk, msg, sig)
+			switch kind {
+			case FaultCorruptC:
+				if ok {
+					t.Errorf("corrupt-c at attempt %d verifies", attempt)
+				}
+			case FaultFlipY:
+				if !ok {
+					t.Errorf("flip-y at attempt %d does not verify", attempt)
+				}
+			}
+		}
+		if changed[kind] == 0 {
+			t.Errorf("%s never changed the signature", kind)
+		}
+	}
+
+	stuck := bytes.Repeat([]byte{0x5a}, RndBytes)
+	a, err := SignFaulty(sk, msg, bytes.Repeat([]byte{1}, RndBytes), &FaultPlan{Kind: FaultReuseRnd, Rnd: stuck})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Sign(sk, msg, stuck)
+	if err != nil || !bytes.Equal(a, b) {
+		t.Errorf("reuse-rnd did not sign with the stuck rnd: err=%v", err)
+	}
+}
+
+func TestSignFaultyPlanValidation(t *testing.T) {
+	_, sk, err := KeyGen(ParamsMLDSA44, make([]byte, SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rnd := make([]byte, RndBytes)
+	for _, plan := range []*FaultPlan{
+		{Kind: "melt"},
+		{Kind: FaultCorruptC, Attempt: -1},
+		{Kind: FaultFlipY, Poly: ParamsMLDSA44.L},
+		{Kind: FaultFlipY, Delta: q},
+		{Kind: FaultCorruptC, Bit: 8 * ParamsMLDSA44.CTildeBytes()},
+		{Kind: FaultReuseRnd, Rnd: []byte{1}},
+	} {
+		if _, err := SignFaulty(sk, []byte("m"), rnd, plan); !errors.Is(err, ErrFaultPlan) {
+			t.Errorf("%+v: err = %v", plan, err)
+		}
+	}
+}
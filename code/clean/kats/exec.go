@@ -0,0 +1,230 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+)
+
+// Exec runs test cases against an external implementation through
+// execsign. Each operation starts the binary once, writes a request of
+// key=value lines to stdin and reads key=value lines from stdout:
+//
+//	op=keygen            ->  pk=<hex>
+//	parameterSet=<name>      sk=<hex>
+//	seed=<hex>
+//
+// Signing uses op=sign-internal, sign-external, sign-prehash or sign-mu with
+// the fields sk, rnd and message (or mu), plus context and hashAlg where
+// the interface needs them; the reply is signature=<hex>. Verification uses
+// op=verify-internal, verify-external, verify-prehash or verify-mu with pk
+// and signature in place of sk and rnd; the reply is valid=true|false.
+//
+// The binary may print error=<text> to report a failure, or
+// error=unsupported for operations it does not implement.
+//
+// With Session set, the same operations and fields travel as JSON-lines
+// requests to one long-lived process instead (docs/execsign-protocol.md), and
+// operations outside the capabilities announced in the handshake are
+// reported as ErrUnsupported without a round trip.
+type Exec struct {
+	Bin     execsign.Bin
+	Session *execsign.Session
+}
+
+// opCapabilities lists the handshake capabilities each operation needs.
+var opCapabilities = map[string][]string{
+	"keygen":          {execsign.CapKeyGen},
+	"sign-internal":   {execsign.CapSign},
+	"sign-external":   {execsign.CapSign, execsign.CapCtx},
+	"sign-prehash":    {execsign.CapSign, execsign.CapCtx, execsign.CapPreHash},
+	"sign-mu":         {execsign.CapSign, execsign.CapExternalMu},
+	"verify-internal": {execsign.CapVerify},
+	"verify-external": {execsign.CapVerify, execsign.CapCtx},
+	"verify-prehash":  {execsign.CapVerify, execsign.CapCtx, execsign.CapPreHash},
+	"verify-mu":       {execsign.CapVerify, execsign.CapExternalMu},
+	"sign-fault":      {execsign.CapSign, execsign.CapFault},
+}
+
+// KeyGen implements KeyGenerator.
+func (e Exec) KeyGen(parameterSet string, seed []byte) ([]byte, []byte, error) {
+	out, err := e.call("keygen",
+		"parameterSet", parameterSet,
+		"seed", hex.EncodeToString(seed))
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, err := out.hex("pk")
+	if err != nil {
+		return nil, nil, err
+	}
+	sk, err := out.hex("sk")
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, sk, nil
+}
+
+// SignInternal implements Signer.
+func (e Exec) SignInternal(sk, msg, rnd []byte) ([]byte, error) {
+	return e.sign("sign-internal", "sk", hex.EncodeToString(sk),
+		"message", hex.EncodeToString(msg),
+		"rnd", hex.EncodeToString(rnd))
+}
+
+// SignExternal implements Signer.
+func (e Exec) SignExternal(sk, msg, ctx, rnd []byte) ([]byte, error) {
+	return e.sign("sign-external", "sk", hex.EncodeToString(sk),
+		"message", hex.EncodeToString(msg),
+		"context", hex.EncodeToString(ctx),
+		"rnd", hex.EncodeToString(rnd))
+}
+
+// SignPreHash implements Signer.
+func (e Exec) SignPreHash(sk, msg, ctx []byte, hashAlg string, rnd []byte) ([]byte, error) {
+	return e.sign("sign-prehash", "sk", hex.EncodeToString(sk),
+		"message", hex.EncodeToString(msg),
+		"context", hex.EncodeToString(ctx),
+		"hashAlg", hashAlg,
+		"rnd", hex.EncodeToString(rnd))
+}
+
+// SignExternalMu implements Signer.
+func (e Exec) SignExternalMu(sk, mu, rnd []byte) ([]byte, error) {
+	return e.sign("sign-mu", "sk", hex.EncodeToString(sk),
+		"mu", hex.EncodeToString(mu),
+		"rnd", hex.EncodeToString(rnd))
+}
+
+func (e Exec) sign(op string, kv ...string) ([]byte, error) {
+	out, err := e.call(op, kv...)
+	if err != nil {
+		return nil, err
+	}
+	return out.hex("signature")
+}
+
+// VerifyInternal implements Verifier.
+func (e Exec) VerifyInternal(pk, msg, sig []byte) (bool, error) {
+	return e.verify("verify-internal", "pk", hex.EncodeToString(pk),
+		"message", hex.EncodeToString(msg),
+		"signature", hex.EncodeToString(sig))
+}
+
+// VerifyExternal implements Verifier.
+func (e Exec) VerifyExternal(pk, msg, ctx, sig []byte) (bool, error) {
+	return e.verify("verify-external", "pk", hex.EncodeToString(pk),
+		"message", hex.EncodeToString(msg),
+		"context", hex.EncodeToString(ctx),
+		"signature", hex.EncodeToString(sig))
+}
+
+// VerifyPreHash implements Verifier.
+func (e Exec) VerifyPreHash(pk, msg, ctx []byte, hashAlg string, sig []byte) (bool, error) {
+	return e.verify("verify-prehash", "pk", hex.EncodeToString(pk),
+		"message", hex.EncodeToString(msg),
+		"context", hex.EncodeToString(ctx),
+		"hashAlg", hashAlg,
+		"signature", hex.EncodeToString(sig))
+}
+
+// VerifyExternalMu implements Verifier.
+func (e Exec) VerifyExternalMu(pk, mu, sig []byte) (bool, error) {
+	return e.verify("verify-mu", "pk", hex.EncodeToString(pk),
+		"mu", hex.EncodeToString(mu),
+		"signature", hex.EncodeToString(sig))
+}
+
+func (e Exec) verify(op string, kv ...string) (bool, error) {
+	out, err := e.call(op, kv...)
+	if err != nil {
+		return false, err
+	}
+	switch v := out["valid"]; v {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("kats: exec reply valid=%q, want true or false", v)
+	}
+}
+
+// execReply holds the key=value pairs printed by the external binary.
+type execReply map[string]string
+
+func (r execReply) hex(key string) ([]byte, error) {
+	v, ok := r[key]
+	if !ok {
+		return nil, fmt.Errorf("kats: exec reply missing %q", key)
+	}
+	b, err := hex.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("kats: exec reply %q: %w", key, err)
+	}
+	return b, nil
+}
+
+// call sends op followed by alternating key/value arguments.
+func (e Exec) call(op string, kv ...string) (execReply, error) {
+	if e.Session != nil {
+		return e.callSession(op, kv...)
+	}
+	var req strings.Builder
+	fmt.Fprintf(&req, "op=%s\n", op)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&req, "%s=%s\n", kv[i], kv[i+1])
+	}
+
+	out, err := e.Bin.Run(context.Background(), []byte(req.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	reply := execReply{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if ok {
+			reply[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("kats: read exec reply: %w", err)
+	}
+	if msg, ok := reply["error"]; ok {
+		if msg == "unsupported" {
+			return nil, fmt.Errorf("%s via %s: %w", op, e.Bin.Path, ErrUnsupported)
+		}
+		return nil, fmt.Errorf("kats: %s via %s: %s", op, e.Bin.Path, msg)
+	}
+	return reply, nil
+}
+
+func (e Exec) callSession(op string, kv ...string) (execReply, error) {
+	if !e.Session.Has(opCapabilities[op]...) {
+		return nil, fmt.Errorf("%s via %s: %w", op, e.Session.Bin.Path, ErrUnsupported)
+	}
+	args := make(map[string]string, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		args[kv[i]] = kv[i+1]
+	}
+	result, err := e.Session.Call(context.Background(), op, args)
+	if errors.Is(err, execsign.ErrUnsupported) {
+		return nil, fmt.Errorf("%s via %s: %w", op, e.Session.Bin.Path, ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kats: %s via %s: %w", op, e.Session.Bin.Path, err)
+	}
+	return execReply(result), nil
+}
@@ -0,0 +1,91 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+)
+
+// FaultSigner signs with a simulated fault injected (mldsa.SignFaulty),
+// for fault-injection assessments. msg is used as M′.
+type FaultSigner interface {
+	SignFaulty(sk, msg, rnd []byte, plan *mldsa.FaultPlan) ([]byte, error)
+}
+
+// SignFaulty implements FaultSigner.
+func (Builtin) SignFaulty(sk, msg, rnd []byte, plan *mldsa.FaultPlan) ([]byte, error) {
+	return mldsa.SignFaulty(sk, msg, rnd, plan)
+}
+
+// SignFaulty implements FaultSigner with op=sign-fault: the sign-internal
+// fields plus the plan as fault, attempt, poly, coeff, delta and bit, and
+// faultRnd (hex) when the plan sets Rnd. Sessions need the fault
+// capability; targets without a fault-injection build should answer
+// unsupported.
+func (e Exec) SignFaulty(sk, msg, rnd []byte, plan *mldsa.FaultPlan) ([]byte, error) {
+	kv := []string{
+		"sk", hex.EncodeToString(sk),
+		"message", hex.EncodeToString(msg),
+		"rnd", hex.EncodeToString(rnd),
+	}
+	if plan != nil {
+		kv = append(kv, FaultArgs(plan)...)
+	}
+	return e.sign("sign-fault", kv...)
+}
+
+// FaultArgs encodes plan as the alternating key/value fields of a
+// sign-fault request.
+func FaultArgs(plan *mldsa.FaultPlan) []string {
+	kv := []string{
+		"fault", string(plan.Kind),
+		"attempt", strconv.Itoa(plan.Attempt),
+		"poly", strconv.Itoa(plan.Poly),
+		"coeff", strconv.Itoa(plan.Coeff),
+		"delta", strconv.Itoa(int(plan.Delta)),
+		"bit", strconv.Itoa(plan.Bit),
+	}
+	if plan.Rnd != nil {
+		kv = append(kv, "faultRnd", hex.EncodeToString(plan.Rnd))
+	}
+	return kv
+}
+
+// ParseFaultArgs decodes the plan of a sign-fault request, for targets
+// that implement the operation; a missing fault field yields nil.
+func ParseFaultArgs(args map[string]string) (*mldsa.FaultPlan, error) {
+	kind, ok := args["fault"]
+	if !ok {
+		return nil, nil
+	}
+	plan := &mldsa.FaultPlan{Kind: mldsa.FaultKind(kind)}
+	for key, dst := range map[string]*int{"attempt": &plan.Attempt, "poly": &plan.Poly, "coeff": &plan.Coeff, "bit": &plan.Bit} {
+		if v, ok := args[key]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("kats: sign-fault %s: %w", key, err)
+			}
+			*dst = n
+		}
+	}
+	if v, ok := args["delta"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("kats: sign-fault delta: %w", err)
+		}
+		plan.Delta = int32(n)
+	}
+	if v, ok := args["faultRnd"]; ok {
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("kats: sign-fault faultRnd: %w", err)
+		}
+		plan.Rnd = b
+	}
+	return plan, nil
+}
@@ -0,0 +1,37 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+)
+
+func TestFaultArgsRoundTrip(t *testing.T) {
+	for _, plan := range []*mldsa.FaultPlan{
+		{Kind: mldsa.FaultFlipY, Attempt: 2, Poly: 3, Coeff: 200, Delta: -5},
+		{Kind: mldsa.FaultCorruptC, Bit: 17},
+		{Kind: mldsa.FaultReuseRnd, Rnd: bytes.Repeat([]byte{9}, mldsa.RndBytes)},
+	} {
+		kv := FaultArgs(plan)
+		args := map[string]string{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			args[kv[i]] = kv[i+1]
+		}
+		got, err := ParseFaultArgs(args)
+		if err != nil || !reflect.DeepEqual(got, plan) {
+			t.Errorf("round trip of %+v: got %+v, err %v", plan, got, err)
+		}
+	}
+
+	if plan, err := ParseFaultArgs(map[string]string{"sk": "00"}); plan != nil || err != nil {
+		t.Errorf("no fault field: %+v, %v", plan, err)
+	}
+	if _, err := ParseFaultArgs(map[string]string{"fault": "flip-y", "coeff": "x"}); err == nil {
+		t.Error("bad coeff accepted")
+	}
+}
@@ -0,0 +1,95 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// KeyGenerator derives ML-DSA key pairs for ACVP keyGen groups.
+type KeyGenerator interface {
+	// KeyGen runs ML-DSA.KeyGen_internal for the named parameter set.
+	KeyGen(parameterSet string, seed []byte) (pk, sk []byte, err error)
+}
+
+// KeyGen implements KeyGenerator.
+func (Builtin) KeyGen(parameterSet string, seed []byte) ([]byte, []byte, error) {
+	params, err := mldsa.FromName(parameterSet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parameter set %q: %w", parameterSet, ErrUnsupported)
+	}
+	return mldsa.KeyGen(params, seed)
+}
+
+// RunKeyGen derives a key pair from every test case seed with g and compares
+// the encoded pk and sk with the expected values byte for byte.
+func RunKeyGen(vectors *KeyGenVectors, g KeyGenerator) *Result {
+	res := &Result{}
+	for _, tg := range vectors.TestGroups {
+		params, paramsErr := mldsa.FromName(tg.ParameterSet)
+		res.beginGroup(GroupResult{GroupID: tg.TargetGroupID, ParameterSet: tg.ParameterSet})
+		for _, tc := range tg.Tests {
+			c := CaseResult{
+				GroupID:      tg.TargetGroupID,
+				CaseID:       tc.CaseID,
+				ParameterSet: tg.ParameterSet,
+			}
+			if paramsErr != nil {
+				c.Outcome = OutcomeSkipped
+				c.Reason = fmt.Sprintf("unknown parameter set %q", tg.ParameterSet)
+				res.add(c)
+				continue
+			}
+			start := time.Now()
+			runKeyGenCase(&c, g, params, tc)
+			c.Duration = time.Since(start)
+			res.add(c)
+		}
+	}
+	return res.finish()
+}
+
+func runKeyGenCase(c *CaseResult, g KeyGenerator, params *mldsa.Params, tc KeyGenTestCase) {
+	seed, err := hex.DecodeString(tc.Seed)
+	if err != nil {
+		c.Outcome, c.Reason = OutcomeDecodeError, fmt.Sprintf("decode seed: %v", err)
+		return
+	}
+	wantPK, err := hex.DecodeString(tc.Public)
+	if err != nil {
+		c.Outcome, c.Reason = OutcomeDecodeError, fmt.Sprintf("decode pk: %v", err)
+		return
+	}
+	wantSK, err := hex.DecodeString(tc.Secret)
+	if err != nil {
+		c.Outcome, c.Reason = OutcomeDecodeError, fmt.Sprintf("decode sk: %v", err)
+		return
+	}
+
+	pk, sk, err := g.KeyGen(params.Name, seed)
+	switch {
+	case errors.Is(err, ErrUnsupported):
+		c.Outcome, c.Reason = OutcomeSkipped, err.Error()
+		return
+	case err != nil:
+		c.Outcome, c.Reason, c.RuleID = OutcomeFail, fmt.Sprintf("key generation failed: %v", err), callRule(err, diag.RuleKeyGenError)
+		return
+	}
+
+	if m := compareBytes("pk", pk, wantPK, mldsa.PublicKeyLayout(params)); m != nil {
+		c.Outcome, c.Reason, c.Mismatch, c.RuleID = OutcomeFail, m.String(), m, diag.RuleKeyGenMismatch
+		return
+	}
+	if m := compareBytes("sk", sk, wantSK, mldsa.SecretKeyLayout(params)); m != nil {
+		c.Outcome, c.Reason, c.Mismatch, c.RuleID = OutcomeFail, m.String(), m, diag.RuleKeyGenMismatch
+		return
+	}
+	c.Outcome = OutcomePass
+}
@@ -0,0 +1,129 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+)
+
+const execHelperEnv = "DILIVET_KATS_EXEC_HELPER"
+
+// TestMain lets the test binary double as an external implementation for
+// the Exec adapter tests.
+func TestMain(m *testing.M) {
+	if os.Getenv(execHelperEnv) == "1" {
+		os.Exit(execHelper())
+	}
+	os.Exit(m.Run())
+}
+
+func execHelper() int {
+	req := map[string]string{}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if k, v, ok := strings.Cut(scanner.Text(), "="); ok {
+			req[k] = v
+		}
+	}
+	switch req["op"] {
+	case "keygen":
+		seed, _ := hex.DecodeString(req["seed"])
+		pk, sk, err := Builtin{}.KeyGen(req["parameterSet"], seed)
+		if err != nil {
+			fmt.Printf("error=%v\n", err)
+			return 0
+		}
+		fmt.Printf("pk=%x\nsk=%x\n", pk, sk)
+	case "verify-internal":
+		pk, _ := hex.DecodeString(req["pk"])
+		msg, _ := hex.DecodeString(req["message"])
+		sig, _ := hex.DecodeString(req["signature"])
+		ok, _ := Builtin{}.VerifyInternal(pk, msg, sig)
+		fmt.Printf("valid=%t\n", ok)
+	default:
+		fmt.Println("error=unsupported")
+	}
+	return 0
+}
+
+// flipHexDigit changes the hex digit at index i of s to a different digit.
+func flipHexDigit(s string, i int) string {
+	b := []byte(s)
+	if b[i] == '0' {
+		b[i] = '1'
+	} else {
+		b[i] = '0'
+	}
+	return string(b)
+}
+
+func TestRunKeyGenBundled(t *testing.T) {
+	vectors, err := LoadKeyGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadKeyGenVectors: %v", err)
+	}
+	res := RunKeyGen(vectors, Builtin{})
+	if res.Report.TotalTests == 0 {
+		t.Fatal("no test cases executed")
+	}
+	for _, c := range res.Cases {
+		if c.Outcome != OutcomePass {
+			t.Errorf("tgId=%d tcId=%d: outcome %s (%s)", c.GroupID, c.CaseID, c.Outcome, c.Reason)
+		}
+	}
+}
+
+func TestRunKeyGenLocatesMismatch(t *testing.T) {
+	vectors, err := LoadKeyGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadKeyGenVectors: %v", err)
+	}
+	tg := vectors.TestGroups[0]
+	tg.Tests = tg.Tests[:2]
+
+	// Corrupt the first byte of tr in case 1 and truncate pk in case 2.
+	tg.Tests[0].Secret = flipHexDigit(tg.Tests[0].Secret, 2*64)
+	tg.Tests[1].Public = tg.Tests[1].Public[:2*40]
+	vectors.TestGroups = []KeyGenTestGroup{tg}
+
+	res := RunKeyGen(vectors, Builtin{})
+	want := []Mismatch{
+		{Field: "sk", Offset: 64, Component: "tr"},
+		{Field: "pk", Offset: 40, Component: "t1"},
+	}
+	for i, c := range res.Cases {
+		if c.Outcome != OutcomeFail || c.Mismatch == nil {
+			t.Fatalf("case %d: outcome %s, mismatch %v", i, c.Outcome, c.Mismatch)
+		}
+		if *c.Mismatch != want[i] {
+			t.Errorf("case %d: mismatch %+v, want %+v", i, *c.Mismatch, want[i])
+		}
+	}
+}
+
+func TestRunKeyGenExec(t *testing.T) {
+	vectors, err := LoadKeyGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadKeyGenVectors: %v", err)
+	}
+	for i := range vectors.TestGroups {
+		vectors.TestGroups[i].Tests = vectors.TestGroups[i].Tests[:1]
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+
+	res := RunKeyGen(vectors, Exec{Bin: execsign.Bin{Path: exe, Env: []string{execHelperEnv + "=1"}}})
+	if res.Failed() || res.Report.StrictPasses != len(vectors.TestGroups) {
+		t.Fatalf("unexpected result: %+v", res.Cases)
+	}
+}
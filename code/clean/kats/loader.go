@@ -4,7 +4,9 @@
 package kats
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,11 +28,12 @@ const (
 
 // KeyGenVectors captures the structure of ACVP ML-DSA key generation vectors.
 type KeyGenVectors struct {
-	Algorithm  string            `json:"algorithm"`
-	Mode       string            `json:"mode"`
-	Revision   string            `json:"revision"`
-	IsSample   bool              `json:"isSample"`
-	TestGroups []KeyGenTestGroup `json:"testGroups"`
+	VectorSetID int               `json:"vsId"`
+	Algorithm   string            `json:"algorithm"`
+	Mode        string            `json:"mode"`
+	Revision    string            `json:"revision"`
+	IsSample    bool              `json:"isSample"`
+	TestGroups  []KeyGenTestGroup `json:"testGroups"`
 }
 
 // KeyGenTestGroup contains a set of key generation test cases for a parameter set.
@@ -52,11 +55,12 @@ type KeyGenTestCase struct {
 
 // SigGenVectors captures the structure of ACVP ML-DSA signature generation vectors.
 type SigGenVectors struct {
-	Algorithm  string            `json:"algorithm"`
-	Mode       string            `json:"mode"`
-	Revision   string            `json:"revision"`
-	IsSample   bool              `json:"isSample"`
-	TestGroups []SigGenTestGroup `json:"testGroups"`
+	VectorSetID int               `json:"vsId"`
+	Algorithm   string            `json:"algorithm"`
+	Mode        string            `json:"mode"`
+	Revision    string            `json:"revision"`
+	IsSample    bool              `json:"isSample"`
+	TestGroups  []SigGenTestGroup `json:"testGroups"`
 }
 
 // SigGenTestGroup contains signature generation tests for a parameter set.
@@ -79,18 +83,21 @@ type SigGenTestCase struct {
 	Message   string `json:"message"`
 	Public    string `json:"pk"`
 	Secret    string `json:"sk"`
+	Mu        string `json:"mu"`
 	Context   string `json:"context"`
 	HashAlg   string `json:"hashAlg"`
+	Rnd       string `json:"rnd"`
 	Signature string `json:"signature"`
 }
 
 // SigVerVectors captures the structure of ACVP ML-DSA signature verification vectors.
 type SigVerVectors struct {
-	Algorithm  string            `json:"algorithm"`
-	Mode       string            `json:"mode"`
-	Revision   string            `json:"revision"`
-	IsSample   bool              `json:"isSample"`
-	TestGroups []SigVerTestGroup `json:"testGroups"`
+	VectorSetID int               `json:"vsId"`
+	Algorithm   string            `json:"algorithm"`
+	Mode        string            `json:"mode"`
+	Revision    string            `json:"revision"`
+	IsSample    bool              `json:"isSample"`
+	TestGroups  []SigVerTestGroup `json:"testGroups"`
 }
 
 // SigVerTestGroup contains signature verification tests for a parameter set.
@@ -101,6 +108,7 @@ type SigVerTestGroup struct {
 	SignatureInterface string           `json:"signatureInterface"`
 	PreHash            string           `json:"preHash"`
 	ExternalMu         bool             `json:"externalMu"`
+	Public             string           `json:"pk"` // group-level pk used by older vector sets
 	Tests              []SigVerTestCase `json:"tests"`
 }
 
@@ -112,83 +120,316 @@ type SigVerTestCase struct {
 	Public     string `json:"pk"`
 	Secret     string `json:"sk"`
 	Message    string `json:"message"`
+	Mu         string `json:"mu"`
 	Context    string `json:"context"`
 	HashAlg    string `json:"hashAlg"`
 	Signature  string `json:"signature"`
 	Reason     string `json:"reason"`
 }
 
+// Supported ACVP vector set identification.
+const (
+	SupportedAlgorithm = "ML-DSA"
+	SupportedRevision  = "FIPS204"
+)
+
+// ErrUnsupportedVectorSet is returned (wrapped) when a vector set names an
+// algorithm, mode or revision the loaders do not handle.
+var ErrUnsupportedVectorSet = errors.New("kats: unsupported vector set")
+
 // LoadKeyGenVectors loads key generation KAT vectors from disk.
+//
+// path may hold a single vector set or an ACVP registration array
+// ([{"acvVersion": ...}, {vector set}, ...]); see LoadKeyGenPair for
+// prompt files that come with a separate expectedResults file.
 func LoadKeyGenVectors(path string) (*KeyGenVectors, error) {
-	if path == "" {
-		path = filepath.Join(DefaultRoot, DefaultKeyGenVectors)
-	}
-	resolved, err := resolvePath(path)
-	if err != nil {
-		return nil, err
+	return LoadKeyGenPair(path, "")
+}
+
+// LoadKeyGenPair loads an ACVP keyGen prompt and joins it with the
+// expectedResults file by tgId/tcId. An empty expectedPath loads the prompt alone.
+func LoadKeyGenPair(promptPath, expectedPath string) (*KeyGenVectors, error) {
+	if promptPath == "" {
+		promptPath = filepath.Join(DefaultRoot, DefaultKeyGenVectors)
 	}
 	var vectors KeyGenVectors
-	if err := decodeJSON(resolved, &vectors); err != nil {
+	if err := loadVectorSet(promptPath, expectedPath, "keyGen", &vectors); err != nil {
 		return nil, err
 	}
-	if err := expectMode(vectors.Mode, "keyGen"); err != nil {
+	if err := checkVectorSet(vectors.Algorithm, vectors.Mode, vectors.Revision, "keyGen"); err != nil {
 		return nil, err
 	}
 	return &vectors, nil
 }
 
 // LoadSigGenVectors loads signature generation KAT vectors from disk.
+// Registration arrays are accepted as for LoadKeyGenVectors.
 func LoadSigGenVectors(path string) (*SigGenVectors, error) {
-	if path == "" {
-		path = filepath.Join(DefaultRoot, DefaultSigGenVectors)
-	}
-	resolved, err := resolvePath(path)
-	if err != nil {
-		return nil, err
+	return LoadSigGenPair(path, "")
+}
+
+// LoadSigGenPair loads an ACVP sigGen prompt and joins it with the
+// expectedResults file by tgId/tcId. An empty expectedPath loads the prompt alone.
+func LoadSigGenPair(promptPath, expectedPath string) (*SigGenVectors, error) {
+	if promptPath == "" {
+		promptPath = filepath.Join(DefaultRoot, DefaultSigGenVectors)
 	}
 	var vectors SigGenVectors
-	if err := decodeJSON(resolved, &vectors); err != nil {
+	if err := loadVectorSet(promptPath, expectedPath, "sigGen", &vectors); err != nil {
 		return nil, err
 	}
-	if err := expectMode(vectors.Mode, "sigGen"); err != nil {
+	if err := checkVectorSet(vectors.Algorithm, vectors.Mode, vectors.Revision, "sigGen"); err != nil {
 		return nil, err
 	}
 	return &vectors, nil
 }
 
 // LoadSigVerVectors loads signature verification KAT vectors from disk.
+// Registration arrays are accepted as for LoadKeyGenVectors.
 func LoadSigVerVectors(path string) (*SigVerVectors, error) {
-	if path == "" {
-		path = filepath.Join(DefaultRoot, DefaultSigVerVectors)
-	}
-	resolved, err := resolvePath(path)
-	if err != nil {
-		return nil, err
+	return LoadSigVerPair(path, "")
+}
+
+// LoadSigVerPair loads an ACVP sigVer prompt and joins it with the
+// expectedResults file by tgId/tcId. An empty expectedPath loads the prompt
+// alone. A group-level pk is copied into test cases that lack one.
+func LoadSigVerPair(promptPath, expectedPath string) (*SigVerVectors, error) {
+	if promptPath == "" {
+		promptPath = filepath.Join(DefaultRoot, DefaultSigVerVectors)
 	}
 	var vectors SigVerVectors
-	if err := decodeJSON(resolved, &vectors); err != nil {
+	if err := loadVectorSet(promptPath, expectedPath, "sigVer", &vectors); err != nil {
 		return nil, err
 	}
-	if err := expectMode(vectors.Mode, "sigVer"); err != nil {
+	if err := checkVectorSet(vectors.Algorithm, vectors.Mode, vectors.Revision, "sigVer"); err != nil {
 		return nil, err
 	}
+	for i := range vectors.TestGroups {
+		tg := &vectors.TestGroups[i]
+		for j := range tg.Tests {
+			if tg.Tests[j].Public == "" {
+				tg.Tests[j].Public = tg.Public
+			}
+		}
+	}
 	return &vectors, nil
 }
 
-func decodeJSON(path string, v any) error {
-	f, err := os.Open(path)
+// loadVectorSet decodes the vector set for mode from promptPath into v,
+// first merging in expectedPath when it is non-empty.
+func loadVectorSet(promptPath, expectedPath, mode string, v any) error {
+	prompt, err := readVectorSet(promptPath, mode, 0)
 	if err != nil {
-		return fmt.Errorf("kats: open %q: %w", path, err)
+		return err
 	}
-	defer f.Close()
-
-	if err := json.NewDecoder(f).Decode(v); err != nil {
-		return fmt.Errorf("kats: decode %q: %w", path, err)
+	if expectedPath != "" {
+		var id struct {
+			VectorSetID int `json:"vsId"`
+		}
+		_ = json.Unmarshal(prompt, &id)
+		expected, err := readVectorSet(expectedPath, mode, id.VectorSetID)
+		if err != nil {
+			return err
+		}
+		if prompt, err = joinExpected(prompt, expected); err != nil {
+			return fmt.Errorf("kats: join %q with %q: %w", promptPath, expectedPath, err)
+		}
+	}
+	if err := json.Unmarshal(prompt, v); err != nil {
+		return fmt.Errorf("kats: decode %q: %w", promptPath, err)
 	}
 	return nil
 }
 
-func resolvePath(path string) (string, error) {
+// readVectorSet returns the raw vector set stored in path. A registration
+// array is searched for the element whose mode matches (or, for
+// expectedResults files, which carry no mode, whose vsId matches).
+func readVectorSet(path, mode string, vsID int) (json.RawMessage, error) {
+	resolved, elems, wrapped, err := readElements(path)
+	if err != nil {
+		return nil, err
+	}
+	if !wrapped {
+		return elems[0], nil
+	}
+
+	var sets []json.RawMessage
+	for _, elem := range elems {
+		head, ok := vectorSetHead(elem)
+		if !ok {
+			continue
+		}
+		switch {
+		case head.Mode == mode:
+		case head.Mode == "" && (vsID == 0 || head.VectorSetID == vsID):
+		default:
+			continue
+		}
+		sets = append(sets, elem)
+	}
+	switch len(sets) {
+	case 0:
+		return nil, fmt.Errorf("kats: %q holds no %s vector set", resolved, mode)
+	case 1:
+		return sets[0], nil
+	default:
+		return nil, fmt.Errorf("kats: %q holds %d %s vector sets; split the file", resolved, len(sets), mode)
+	}
+}
+
+// VectorSetModes lists the modes of the vector sets stored in path, in file
+// order, so callers can dispatch a registration array holding several sets.
+func VectorSetModes(path string) ([]string, error) {
+	resolved, elems, _, err := readElements(path)
+	if err != nil {
+		return nil, err
+	}
+	var modes []string
+	for _, elem := range elems {
+		if head, ok := vectorSetHead(elem); ok {
+			modes = append(modes, head.Mode)
+		}
+	}
+	if len(modes) == 0 {
+		return nil, fmt.Errorf("kats: %q holds no vector set", resolved)
+	}
+	return modes, nil
+}
+
+// readElements reads path and returns its vector set candidates: the
+// elements of a registration array (wrapped is true), or the file itself.
+func readElements(path string) (resolved string, elems []json.RawMessage, wrapped bool, err error) {
+	resolved, err = ResolvePath(path)
+	if err != nil {
+		return "", nil, false, err
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("kats: open %q: %w", resolved, err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		return resolved, []json.RawMessage{data}, false, nil
+	}
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return "", nil, false, fmt.Errorf("kats: decode %q: %w", resolved, err)
+	}
+	return resolved, elems, true, nil
+}
+
+type vectorSetHeader struct {
+	VectorSetID int             `json:"vsId"`
+	Mode        string          `json:"mode"`
+	TestGroups  json.RawMessage `json:"testGroups"`
+}
+
+// vectorSetHead reports whether elem is a vector set (rather than a
+// registration header such as {"acvVersion": ...}) and returns its identity.
+func vectorSetHead(elem json.RawMessage) (vectorSetHeader, bool) {
+	var head vectorSetHeader
+	if json.Unmarshal(elem, &head) != nil || head.TestGroups == nil {
+		return head, false
+	}
+	return head, true
+}
+
+// joinExpected copies the fields of every expectedResults group and test
+// case onto the prompt entry with the same tgId/tcId.
+func joinExpected(prompt, expected json.RawMessage) (json.RawMessage, error) {
+	type object = map[string]json.RawMessage
+
+	var p, e object
+	if err := json.Unmarshal(prompt, &p); err != nil {
+		return nil, fmt.Errorf("prompt: %w", err)
+	}
+	if err := json.Unmarshal(expected, &e); err != nil {
+		return nil, fmt.Errorf("expectedResults: %w", err)
+	}
+	if pid, eid := rawInt(p["vsId"]), rawInt(e["vsId"]); pid != 0 && eid != 0 && pid != eid {
+		return nil, fmt.Errorf("vsId %d does not match expectedResults vsId %d", pid, eid)
+	}
+
+	var pGroups, eGroups []object
+	if err := json.Unmarshal(p["testGroups"], &pGroups); err != nil {
+		return nil, fmt.Errorf("prompt testGroups: %w", err)
+	}
+	if err := json.Unmarshal(e["testGroups"], &eGroups); err != nil {
+		return nil, fmt.Errorf("expectedResults testGroups: %w", err)
+	}
+
+	groupIndex := make(map[int]object, len(pGroups))
+	caseIndex := make(map[[2]int]object)
+	groupCases := make(map[int][]object, len(pGroups))
+	for _, g := range pGroups {
+		tgID := rawInt(g["tgId"])
+		groupIndex[tgID] = g
+		var tests []object
+		if err := json.Unmarshal(g["tests"], &tests); err != nil {
+			return nil, fmt.Errorf("prompt tgId=%d tests: %w", tgID, err)
+		}
+		for _, tc := range tests {
+			caseIndex[[2]int{tgID, rawInt(tc["tcId"])}] = tc
+		}
+		groupCases[tgID] = tests
+	}
+
+	matched := 0
+	for _, eg := range eGroups {
+		tgID := rawInt(eg["tgId"])
+		g, ok := groupIndex[tgID]
+		if !ok {
+			return nil, fmt.Errorf("expectedResults tgId=%d has no prompt group", tgID)
+		}
+		var tests []object
+		if err := json.Unmarshal(eg["tests"], &tests); err != nil {
+			return nil, fmt.Errorf("expectedResults tgId=%d tests: %w", tgID, err)
+		}
+		for k, val := range eg {
+			if k != "tests" {
+				g[k] = val
+			}
+		}
+		for _, et := range tests {
+			tcID := rawInt(et["tcId"])
+			tc, ok := caseIndex[[2]int{tgID, tcID}]
+			if !ok {
+				return nil, fmt.Errorf("expectedResults tgId=%d tcId=%d has no prompt case", tgID, tcID)
+			}
+			for k, val := range et {
+				tc[k] = val
+			}
+			matched++
+		}
+	}
+	if matched != len(caseIndex) {
+		return nil, fmt.Errorf("expectedResults covers %d of %d prompt cases", matched, len(caseIndex))
+	}
+
+	for _, g := range pGroups {
+		tests, err := json.Marshal(groupCases[rawInt(g["tgId"])])
+		if err != nil {
+			return nil, err
+		}
+		g["tests"] = tests
+	}
+	groups, err := json.Marshal(pGroups)
+	if err != nil {
+		return nil, err
+	}
+	p["testGroups"] = groups
+	return json.Marshal(p)
+}
+
+// rawInt decodes a JSON number, returning 0 if raw is absent or not an integer.
+func rawInt(raw json.RawMessage) int {
+	var n int
+	_ = json.Unmarshal(raw, &n)
+	return n
+}
+
+// ResolvePath returns path unchanged when absolute and otherwise joins it to
+// the module root, so repository-relative defaults work from any directory.
+func ResolvePath(path string) (string, error) {
 	if filepath.IsAbs(path) {
 		return path, nil
 	}
@@ -199,6 +440,18 @@ func resolvePath(path string) (string, error) {
 	return filepath.Join(root, path), nil
 }
 
+// RelPath names path for reports: relative to the module root and
+// slash-separated when it lies below the root, and by its base name
+// otherwise, so no host path leaks into shared output.
+func RelPath(path string) string {
+	if root, err := moduleRoot(); err == nil {
+		if rel, err := filepath.Rel(root, path); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(path)
+}
+
 func moduleRoot() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -216,9 +469,17 @@ func moduleRoot() (string, error) {
 	}
 }
 
-func expectMode(actual, expected string) error {
-	if actual != expected {
-		return fmt.Errorf("kats: expected mode %q, got %q", expected, actual)
+// checkVectorSet verifies that a decoded vector set is one the loaders
+// support. The revision may be omitted by hand-written fixtures.
+func checkVectorSet(algorithm, mode, revision, wantMode string) error {
+	if mode != wantMode {
+		return fmt.Errorf("kats: expected mode %q, got %q", wantMode, mode)
+	}
+	if algorithm != SupportedAlgorithm {
+		return fmt.Errorf("%w: algorithm %q (want %q)", ErrUnsupportedVectorSet, algorithm, SupportedAlgorithm)
+	}
+	if revision != "" && revision != SupportedRevision {
+		return fmt.Errorf("%w: revision %q (want %q)", ErrUnsupportedVectorSet, revision, SupportedRevision)
 	}
 	return nil
 }
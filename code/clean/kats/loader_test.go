@@ -4,7 +4,11 @@
 package kats
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -93,6 +97,149 @@ func TestLoadCustomPath(t *testing.T) {
 	}
 }
 
-func TestVerifyVectorsTODO(t *testing.T) {
-	t.Skip("TODO: integrate ML-DSA verification with KAT vectors")
+// splitSigVer turns the bundled sigVer vectors into a registration-wrapped
+// prompt with group-level pk and a separate expectedResults file, as
+// downloaded from an ACVP server.
+func splitSigVer(t *testing.T, dir string) (promptPath, expectedPath string, total int) {
+	t.Helper()
+	vectors, err := LoadSigVerVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigVerVectors: %v", err)
+	}
+
+	type object = map[string]any
+	var promptGroups, expectedGroups []object
+	for _, tg := range vectors.TestGroups {
+		var pTests, eTests []object
+		for _, tc := range tg.Tests {
+			pTests = append(pTests, object{
+				"tcId": tc.CaseID, "message": tc.Message, "mu": tc.Mu,
+				"context": tc.Context, "hashAlg": tc.HashAlg, "signature": tc.Signature,
+			})
+			eTests = append(eTests, object{"tcId": tc.CaseID, "testPassed": tc.TestPassed})
+			total++
+		}
+		group := object{
+			"tgId": tg.TargetGroupID, "testType": tg.TestType, "parameterSet": tg.ParameterSet,
+			"signatureInterface": tg.SignatureInterface, "preHash": tg.PreHash,
+			"externalMu": tg.ExternalMu, "tests": pTests,
+		}
+		group["pk"] = tg.Tests[0].Public // every bundled group shares one key
+		promptGroups = append(promptGroups, group)
+		expectedGroups = append(expectedGroups, object{"tgId": tg.TargetGroupID, "tests": eTests})
+	}
+
+	prompt := []object{
+		{"acvVersion": "1.0"},
+		{"vsId": 7, "algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS204", "testGroups": []object{}},
+		{"vsId": 8, "algorithm": "ML-DSA", "mode": "sigVer", "revision": "FIPS204", "testGroups": promptGroups},
+	}
+	expected := []object{
+		{"acvVersion": "1.0"},
+		{"vsId": 8, "algorithm": "ML-DSA", "testGroups": expectedGroups},
+	}
+	promptPath = filepath.Join(dir, "prompt.json")
+	expectedPath = filepath.Join(dir, "expectedResults.json")
+	writeJSON(t, promptPath, prompt)
+	writeJSON(t, expectedPath, expected)
+	return promptPath, expectedPath, total
+}
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoadSigVerPair(t *testing.T) {
+	promptPath, expectedPath, total := splitSigVer(t, t.TempDir())
+
+	vectors, err := LoadSigVerPair(promptPath, expectedPath)
+	if err != nil {
+		t.Fatalf("LoadSigVerPair: %v", err)
+	}
+	if vectors.VectorSetID != 8 {
+		t.Fatalf("vsId = %d, want the sigVer set", vectors.VectorSetID)
+	}
+	res := RunSigVer(vectors, Builtin{})
+	if res.Report.TotalTests != total || res.Report.StrictPasses != total {
+		t.Fatalf("joined vectors: %d/%d passed, want %d", res.Report.StrictPasses, res.Report.TotalTests, total)
+	}
+
+	// Without expectedResults every testPassed is false, so valid cases fail.
+	vectors, err = LoadSigVerVectors(promptPath)
+	if err != nil {
+		t.Fatalf("LoadSigVerVectors(prompt): %v", err)
+	}
+	if !RunSigVer(vectors, Builtin{}).Failed() {
+		t.Fatal("prompt alone should not reproduce expected verdicts")
+	}
+}
+
+func TestLoadPairRejectsMismatchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	promptPath, _, _ := splitSigVer(t, dir)
+
+	tests := map[string]any{
+		"unknown case": map[string]any{"vsId": 8, "testGroups": []any{
+			map[string]any{"tgId": 1, "tests": []any{map[string]any{"tcId": 9999, "testPassed": true}}},
+		}},
+		"other vsId": map[string]any{"vsId": 9, "testGroups": []any{}},
+		"incomplete": map[string]any{"vsId": 8, "testGroups": []any{}},
+	}
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "expected.json")
+			writeJSON(t, path, expected)
+			if _, err := LoadSigVerPair(promptPath, path); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUnsupportedVectorSet(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"algorithm":   `{"algorithm":"ML-KEM","mode":"sigVer","revision":"FIPS204","testGroups":[]}`,
+		"revision":    `{"algorithm":"ML-DSA","mode":"sigVer","revision":"FIPS205","testGroups":[]}`,
+		"wrapped set": `[{"acvVersion":"1.0"},{"algorithm":"ML-DSA","mode":"sigVer","revision":"draft","testGroups":[]}]`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "vectors.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if _, err := LoadSigVerVectors(path); !errors.Is(err, ErrUnsupportedVectorSet) {
+				t.Fatalf("err = %v, want ErrUnsupportedVectorSet", err)
+			}
+		})
+	}
+
+	path := filepath.Join(dir, "keygen-only.json")
+	if err := os.WriteFile(path, []byte(`[{"acvVersion":"1.0"},{"algorithm":"ML-DSA","mode":"keyGen","testGroups":[]}]`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := LoadSigVerVectors(path); err == nil || !strings.Contains(err.Error(), "no sigVer vector set") {
+		t.Fatalf("err = %v, want missing sigVer vector set", err)
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	bundled, err := ResolvePath("code/clean/testdata/wycheproof/mldsa-wycheproof-alpha1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RelPath(bundled); got != "code/clean/testdata/wycheproof/mldsa-wycheproof-alpha1.json" {
+		t.Errorf("bundled: %q", got)
+	}
+	if got := RelPath(filepath.Join(t.TempDir(), "suite.json")); got != "suite.json" {
+		t.Errorf("outside the module: %q", got)
+	}
 }
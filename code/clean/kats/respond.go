@@ -0,0 +1,185 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ACVPVersion is the protocol version written in response headers.
+const ACVPVersion = "1.0"
+
+// Implementation provides every entry point needed to answer ACVP ML-DSA
+// keyGen, sigGen and sigVer prompts.
+type Implementation interface {
+	KeyGenerator
+	Signer
+	Verifier
+}
+
+// Response is the ACVP response for one vector set.
+type Response struct {
+	VectorSetID int             `json:"vsId"`
+	Algorithm   string          `json:"algorithm"`
+	Mode        string          `json:"mode"`
+	Revision    string          `json:"revision"`
+	IsSample    bool            `json:"isSample"`
+	TestGroups  []ResponseGroup `json:"testGroups"`
+}
+
+// ResponseGroup holds the responses for one test group.
+type ResponseGroup struct {
+	TargetGroupID int            `json:"tgId"`
+	Tests         []ResponseCase `json:"tests"`
+}
+
+// ResponseCase carries the fields the server expects for the vector set mode:
+// pk and sk for keyGen, signature for sigGen and testPassed for sigVer.
+type ResponseCase struct {
+	CaseID     int    `json:"tcId"`
+	Public     string `json:"pk,omitempty"`
+	Secret     string `json:"sk,omitempty"`
+	Signature  string `json:"signature,omitempty"`
+	TestPassed *bool  `json:"testPassed,omitempty"`
+}
+
+// RespondFile answers every vector set in the prompt file at path, in file
+// order. The result is ready to be wrapped with an acvVersion header.
+func RespondFile(path string, impl Implementation) ([]*Response, error) {
+	modes, err := VectorSetModes(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []*Response
+	for _, mode := range modes {
+		var (
+			resp *Response
+			err  error
+		)
+		switch mode {
+		case "keyGen":
+			var v *KeyGenVectors
+			if v, err = LoadKeyGenVectors(path); err == nil {
+				resp, err = RespondKeyGen(v, impl)
+			}
+		case "sigGen":
+			var v *SigGenVectors
+			if v, err = LoadSigGenVectors(path); err == nil {
+				resp, err = RespondSigGen(v, impl)
+			}
+		case "sigVer":
+			var v *SigVerVectors
+			if v, err = LoadSigVerVectors(path); err == nil {
+				resp, err = RespondSigVer(v, impl)
+			}
+		default:
+			err = fmt.Errorf("%w: mode %q", ErrUnsupportedVectorSet, mode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resp)
+	}
+	return out, nil
+}
+
+// RespondKeyGen derives the key pair for every keyGen test case.
+func RespondKeyGen(vectors *KeyGenVectors, g KeyGenerator) (*Response, error) {
+	resp := newResponse(vectors.VectorSetID, vectors.Algorithm, vectors.Mode, vectors.Revision, vectors.IsSample)
+	for _, tg := range vectors.TestGroups {
+		group := ResponseGroup{TargetGroupID: tg.TargetGroupID}
+		for _, tc := range tg.Tests {
+			seed, err := hex.DecodeString(tc.Seed)
+			if err != nil {
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, fmt.Errorf("decode seed: %w", err))
+			}
+			pk, sk, err := g.KeyGen(tg.ParameterSet, seed)
+			if err != nil {
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, err)
+			}
+			group.Tests = append(group.Tests, ResponseCase{CaseID: tc.CaseID, Public: hexUpper(pk), Secret: hexUpper(sk)})
+		}
+		resp.TestGroups = append(resp.TestGroups, group)
+	}
+	return resp, nil
+}
+
+// RespondSigGen signs every sigGen test case. Deterministic groups use
+// rnd = 0^32; hedged cases use the prompt's rnd, or fresh randomness when
+// the prompt carries none.
+func RespondSigGen(vectors *SigGenVectors, s Signer) (*Response, error) {
+	resp := newResponse(vectors.VectorSetID, vectors.Algorithm, vectors.Mode, vectors.Revision, vectors.IsSample)
+	for _, tg := range vectors.TestGroups {
+		route, err := routeGroup(tg.SignatureInterface, tg.PreHash, tg.ExternalMu)
+		if err != nil {
+			return nil, fmt.Errorf("kats: tgId=%d: %w", tg.TargetGroupID, err)
+		}
+		group := ResponseGroup{TargetGroupID: tg.TargetGroupID}
+		for _, tc := range tg.Tests {
+			in, err := decodeSigGenCase(tc, route)
+			if err != nil {
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, err)
+			}
+			if in.rnd, _, err = sigGenRnd(tg.Deterministic, in.rnd); err != nil {
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, err)
+			}
+			sig, err := signRoute(s, route, in, tc.HashAlg)
+			if err != nil {
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, err)
+			}
+			group.Tests = append(group.Tests, ResponseCase{CaseID: tc.CaseID, Signature: hexUpper(sig)})
+		}
+		resp.TestGroups = append(resp.TestGroups, group)
+	}
+	return resp, nil
+}
+
+// RespondSigVer records the verdict of v for every sigVer test case.
+// Verification errors other than ErrUnsupported count as testPassed=false.
+func RespondSigVer(vectors *SigVerVectors, v Verifier) (*Response, error) {
+	resp := newResponse(vectors.VectorSetID, vectors.Algorithm, vectors.Mode, vectors.Revision, vectors.IsSample)
+	for _, tg := range vectors.TestGroups {
+		route, err := routeGroup(tg.SignatureInterface, tg.PreHash, tg.ExternalMu)
+		if err != nil {
+			return nil, fmt.Errorf("kats: tgId=%d: %w", tg.TargetGroupID, err)
+		}
+		group := ResponseGroup{TargetGroupID: tg.TargetGroupID}
+		for _, tc := range tg.Tests {
+			ok, verr, decodeErr := runSigVerCase(v, route, tc)
+			switch {
+			case decodeErr != nil:
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, decodeErr)
+			case errors.Is(verr, ErrUnsupported):
+				return nil, caseError(tg.TargetGroupID, tc.CaseID, verr)
+			}
+			passed := ok && verr == nil
+			group.Tests = append(group.Tests, ResponseCase{CaseID: tc.CaseID, TestPassed: &passed})
+		}
+		resp.TestGroups = append(resp.TestGroups, group)
+	}
+	return resp, nil
+}
+
+func newResponse(vsID int, algorithm, mode, revision string, isSample bool) *Response {
+	return &Response{
+		VectorSetID: vsID,
+		Algorithm:   algorithm,
+		Mode:        mode,
+		Revision:    revision,
+		IsSample:    isSample,
+		TestGroups:  []ResponseGroup{},
+	}
+}
+
+func caseError(tgID, tcID int, err error) error {
+	return fmt.Errorf("kats: tgId=%d tcId=%d: %w", tgID, tcID, err)
+}
+
+// hexUpper encodes b the way ACVP servers do.
+func hexUpper(b []byte) string {
+	return strings.ToUpper(hex.EncodeToString(b))
+}
@@ -0,0 +1,126 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+)
+
+var (
+	_ Implementation = Builtin{}
+	_ Implementation = Exec{}
+)
+
+func TestRespondKeyGenMatchesExpected(t *testing.T) {
+	vectors, err := LoadKeyGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadKeyGenVectors: %v", err)
+	}
+	resp, err := RespondKeyGen(vectors, Builtin{})
+	if err != nil {
+		t.Fatalf("RespondKeyGen: %v", err)
+	}
+	if resp.VectorSetID != vectors.VectorSetID || resp.Mode != "keyGen" {
+		t.Fatalf("header = vsId %d mode %q", resp.VectorSetID, resp.Mode)
+	}
+	for i, tg := range vectors.TestGroups {
+		for j, tc := range tg.Tests {
+			got := resp.TestGroups[i].Tests[j]
+			if got.CaseID != tc.CaseID || !strings.EqualFold(got.Public, tc.Public) || !strings.EqualFold(got.Secret, tc.Secret) {
+				t.Fatalf("tgId=%d tcId=%d: response does not match expected key pair", tg.TargetGroupID, tc.CaseID)
+			}
+		}
+	}
+}
+
+func TestRespondSigGenMatchesExpected(t *testing.T) {
+	vectors, err := LoadSigGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigGenVectors: %v", err)
+	}
+	resp, err := RespondSigGen(vectors, Builtin{})
+	if err != nil {
+		t.Fatalf("RespondSigGen: %v", err)
+	}
+	for i, tg := range vectors.TestGroups {
+		for j, tc := range tg.Tests {
+			got := resp.TestGroups[i].Tests[j]
+			if got.Signature == "" || got.Public != "" || got.TestPassed != nil {
+				t.Fatalf("tgId=%d tcId=%d: response carries the wrong fields: %+v", tg.TargetGroupID, tc.CaseID, got)
+			}
+			// Only reproducible cases can be compared byte for byte.
+			if (tg.Deterministic || tc.Rnd != "") && !strings.EqualFold(got.Signature, tc.Signature) {
+				t.Errorf("tgId=%d tcId=%d: signature differs from expected", tg.TargetGroupID, tc.CaseID)
+			}
+		}
+	}
+}
+
+func TestRespondFileRegistration(t *testing.T) {
+	promptPath, expectedPath, total := splitSigVer(t, t.TempDir())
+	responses, err := RespondFile(promptPath, Builtin{})
+	if err != nil {
+		t.Fatalf("RespondFile: %v", err)
+	}
+	if len(responses) != 2 || responses[0].Mode != "keyGen" || responses[1].Mode != "sigVer" {
+		t.Fatalf("got %d responses, want keyGen then sigVer", len(responses))
+	}
+
+	expected, err := LoadSigVerPair(promptPath, expectedPath)
+	if err != nil {
+		t.Fatalf("LoadSigVerPair: %v", err)
+	}
+	resp := responses[1]
+	if resp.VectorSetID != 8 {
+		t.Fatalf("vsId = %d, want 8", resp.VectorSetID)
+	}
+	n := 0
+	for i, tg := range expected.TestGroups {
+		for j, tc := range tg.Tests {
+			got := resp.TestGroups[i].Tests[j]
+			if got.TestPassed == nil || *got.TestPassed != tc.TestPassed {
+				t.Errorf("tgId=%d tcId=%d: testPassed = %v, want %t", tg.TargetGroupID, tc.CaseID, got.TestPassed, tc.TestPassed)
+			}
+			n++
+		}
+	}
+	if n != total {
+		t.Fatalf("compared %d cases, want %d", n, total)
+	}
+}
+
+func TestRespondSigVerExec(t *testing.T) {
+	vectors, err := LoadSigVerVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigVerVectors: %v", err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	impl := Exec{Bin: execsign.Bin{Path: exe, Env: []string{execHelperEnv + "=1"}}}
+
+	// The helper only verifies through the internal interface; external
+	// groups must abort the response rather than report testPassed=false.
+	if _, err := RespondSigVer(vectors, impl); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("err = %v, want ErrUnsupported", err)
+	}
+
+	vectors.TestGroups = vectors.TestGroups[:1]
+	vectors.TestGroups[0].Tests = vectors.TestGroups[0].Tests[:4]
+	resp, err := RespondSigVer(vectors, impl)
+	if err != nil {
+		t.Fatalf("RespondSigVer: %v", err)
+	}
+	for i, tc := range vectors.TestGroups[0].Tests {
+		if got := resp.TestGroups[0].Tests[i].TestPassed; *got != tc.TestPassed {
+			t.Errorf("tcId=%d: testPassed = %t, want %t", tc.CaseID, *got, tc.TestPassed)
+		}
+	}
+}
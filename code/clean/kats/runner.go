@@ -0,0 +1,410 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// ACVP signatureInterface and preHash values for ML-DSA test groups.
+const (
+	InterfaceInternal = "internal"
+	InterfaceExternal = "external"
+	PreHashPure       = "pure"
+	PreHashPreHash    = "preHash"
+)
+
+// ErrUnsupported is returned (possibly wrapped) by a Verifier that does not
+// implement the requested entry point. The runner reports such cases as
+// skipped rather than failed.
+var ErrUnsupported = errors.New("kats: unsupported by implementation")
+
+// Verifier exposes the ML-DSA verification entry points an ACVP sigVer
+// group can be routed to.
+type Verifier interface {
+	// VerifyInternal runs ML-DSA.Verify_internal with msg as M′.
+	VerifyInternal(pk, msg, sig []byte) (bool, error)
+	// VerifyExternal runs ML-DSA.Verify with a context string.
+	VerifyExternal(pk, msg, ctx, sig []byte) (bool, error)
+	// VerifyPreHash runs HashML-DSA.Verify with the named hash function.
+	VerifyPreHash(pk, msg, ctx []byte, hashAlg string, sig []byte) (bool, error)
+	// VerifyExternalMu runs ML-DSA.Verify_internal on a precomputed μ.
+	VerifyExternalMu(pk, mu, sig []byte) (bool, error)
+}
+
+// Builtin routes every entry point to the in-process implementation in code/clean.
+type Builtin struct{}
+
+// VerifyInternal implements Verifier.
+func (Builtin) VerifyInternal(pk, msg, sig []byte) (bool, error) {
+	return mldsa.Verify(pk, msg, sig)
+}
+
+// VerifyExternal implements Verifier.
+func (Builtin) VerifyExternal(pk, msg, ctx, sig []byte) (bool, error) {
+	return mldsa.VerifyWithContext(pk, msg, ctx, sig)
+}
+
+// VerifyPreHash implements Verifier.
+func (Builtin) VerifyPreHash(pk, msg, ctx []byte, hashAlg string, sig []byte) (bool, error) {
+	return mldsa.VerifyPreHash(pk, msg, ctx, hashAlg, sig)
+}
+
+// VerifyExternalMu implements Verifier.
+func (Builtin) VerifyExternalMu(pk, mu, sig []byte) (bool, error) {
+	return mldsa.VerifyExternalMu(pk, mu, sig)
+}
+
+// Outcome classifies the result of a single test case.
+type Outcome string
+
+// Possible test case outcomes.
+const (
+	OutcomePass        Outcome = "pass"
+	OutcomeFail        Outcome = "fail"
+	OutcomeDecodeError Outcome = "decode-error"
+	OutcomeSkipped     Outcome = "skipped"
+)
+
+// CaseResult records the outcome of one ACVP test case.
+type CaseResult struct {
+	GroupID      int           `json:"tgId"`
+	CaseID       int           `json:"tcId"`
+	ParameterSet string        `json:"parameterSet"`
+	Outcome      Outcome       `json:"outcome"`
+	Reason       string        `json:"reason,omitempty"`
+	Stage        string        `json:"stage,omitempty"`   // sigVer: kat.VerifyStage of the verifier's answer
+	Warning      bool          `json:"warning,omitempty"` // passed without a byte-exact comparison
+	RuleID       string        `json:"ruleId,omitempty"`  // failures: the diag rule broken
+	Mismatch     *Mismatch     `json:"mismatch,omitempty"`
+	Duration     time.Duration `json:"durationNs,omitempty"`
+}
+
+// Mismatch locates the first byte at which an output differs from the
+// expected value.
+type Mismatch struct {
+	Field     string `json:"field"`               // "pk", "sk" or "signature"
+	Offset    int    `json:"offset"`              // first differing byte, or the shorter length
+	Component string `json:"component,omitempty"` // encoded component containing Offset
+}
+
+func (m *Mismatch) String() string {
+	if m.Component == "" {
+		return fmt.Sprintf("%s differs at byte %d", m.Field, m.Offset)
+	}
+	return fmt.Sprintf("%s differs at byte %d (%s)", m.Field, m.Offset, m.Component)
+}
+
+// compareBytes returns nil if got equals want, otherwise the first
+// differing offset attributed to a component of layout (which may be nil).
+func compareBytes(field string, got, want []byte, layout []mldsa.Component) *Mismatch {
+	n := len(got)
+	if len(want) < n {
+		n = len(want)
+	}
+	off := n
+	for i := 0; i < n; i++ {
+		if got[i] != want[i] {
+			off = i
+			break
+		}
+	}
+	if off == n && len(got) == len(want) {
+		return nil
+	}
+	return &Mismatch{Field: field, Offset: off, Component: mldsa.ComponentAt(layout, off)}
+}
+
+// GroupResult summarises the outcomes of one ACVP test group.
+type GroupResult struct {
+	GroupID      int    `json:"tgId"`
+	ParameterSet string `json:"parameterSet"`
+	Mode         string `json:"mode,omitempty"`
+	CornerCase   string `json:"cornerCase,omitempty"`
+	Total        int    `json:"total"`
+	Passed       int    `json:"passed"`
+	Failed       int    `json:"failed"`
+	Skipped      int    `json:"skipped"`
+}
+
+// Result aggregates per-case outcomes with the summary counters.
+type Result struct {
+	Report diag.Report   `json:"report"`
+	Groups []GroupResult `json:"groups,omitempty"`
+	Cases  []CaseResult  `json:"cases"`
+}
+
+// beginGroup starts a group summary; subsequent cases are counted in it.
+func (r *Result) beginGroup(g GroupResult) {
+	r.Groups = append(r.Groups, g)
+}
+
+func (r *Result) add(c CaseResult) {
+	var g *GroupResult
+	if n := len(r.Groups); n > 0 {
+		g = &r.Groups[n-1]
+	} else {
+		g = &GroupResult{}
+	}
+	rec := diag.Record{
+		ParameterSet: c.ParameterSet,
+		Group:        fmt.Sprintf("tgId=%d", c.GroupID),
+		Warning:      c.Warning,
+		Stage:        c.Stage,
+		Duration:     c.Duration,
+	}
+	if rec.Stage == "" && c.Mismatch != nil {
+		rec.Stage = c.Mismatch.Field + "-mismatch"
+	}
+	g.Total++
+	switch c.Outcome {
+	case OutcomePass:
+		rec.Status = diag.StatusPass
+		g.Passed++
+	case OutcomeFail:
+		rec.Status = diag.StatusFail
+		g.Failed++
+	case OutcomeDecodeError:
+		rec.Status = diag.StatusError
+		g.Failed++
+		if c.RuleID == "" {
+			c.RuleID = diag.RuleVectorDecode
+		}
+	case OutcomeSkipped:
+		rec.Status = diag.StatusSkipped
+		g.Skipped++
+	}
+	r.Report.Add(rec)
+	r.Cases = append(r.Cases, c)
+}
+
+// finish completes the report once every case has been added.
+func (r *Result) finish() *Result {
+	r.Report.Finish()
+	return r
+}
+
+// Failed reports whether any case failed or could not be decoded.
+func (r *Result) Failed() bool {
+	return r.Report.StructuralFailures > 0 || r.Report.DecodeFailures > 0
+}
+
+// Findings aggregates the failed and undecodable cases by rule.
+func (r *Result) Findings() []diag.Finding {
+	findings := []diag.Finding{}
+	for _, c := range r.Cases {
+		if c.RuleID != "" {
+			findings = diag.AddFinding(findings, c.RuleID, fmt.Sprintf("tgId=%d tcId=%d: %s", c.GroupID, c.CaseID, c.Reason))
+		}
+	}
+	diag.SortFindings(findings)
+	return findings
+}
+
+// callRule names the rule broken by an implementation call that failed
+// with err: a crash, a timeout or else fallback.
+func callRule(err error, fallback string) string {
+	switch {
+	case errors.Is(err, execsign.ErrCrashed):
+		return diag.RuleCrash
+	case errors.Is(err, execsign.ErrTimeout):
+		return diag.RuleTimeout
+	}
+	return fallback
+}
+
+// DiagCases returns the cases for diag.WriteJUnit and diag.WriteSARIF,
+// one suite per test group.
+func (r *Result) DiagCases() []diag.Case {
+	suites := map[int]string{}
+	for _, g := range r.Groups {
+		name := fmt.Sprintf("tgId=%d %s", g.GroupID, g.ParameterSet)
+		if g.Mode != "" {
+			name += " " + g.Mode
+		}
+		suites[g.GroupID] = name
+	}
+	cases := make([]diag.Case, 0, len(r.Cases))
+	for _, c := range r.Cases {
+		suite, ok := suites[c.GroupID]
+		if !ok {
+			suite = fmt.Sprintf("tgId=%d %s", c.GroupID, c.ParameterSet)
+		}
+		dc := diag.Case{Suite: suite, Name: fmt.Sprintf("tcId=%d", c.CaseID), Stage: c.Stage, RuleID: c.RuleID, Message: c.Reason}
+		switch c.Outcome {
+		case OutcomePass:
+			dc.Status = diag.StatusPass
+		case OutcomeFail:
+			dc.Status = diag.StatusFail
+		case OutcomeDecodeError:
+			dc.Status = diag.StatusError
+		default:
+			dc.Status = diag.StatusSkipped
+		}
+		cases = append(cases, dc)
+	}
+	return cases
+}
+
+// groupRoute identifies which entry point a sigGen or sigVer test group maps to.
+type groupRoute int
+
+const (
+	routeInternal groupRoute = iota
+	routeExternalMu
+	routePure
+	routePreHash
+)
+
+func (r groupRoute) String() string {
+	switch r {
+	case routeExternalMu:
+		return "internal/externalMu"
+	case routePure:
+		return "external/pure"
+	case routePreHash:
+		return "external/preHash"
+	default:
+		return "internal"
+	}
+}
+
+// routeGroup decides which entry point serves a test group with the given
+// properties, or explains why the combination is not supported.
+func routeGroup(signatureInterface, preHash string, externalMu bool) (groupRoute, error) {
+	switch signatureInterface {
+	case "", InterfaceInternal:
+		// Vector sets predating the signatureInterface field exercise the
+		// internal interface.
+		if preHash != "" && preHash != PreHashPure {
+			return 0, fmt.Errorf("preHash %q is not defined for the internal interface", preHash)
+		}
+		if externalMu {
+			return routeExternalMu, nil
+		}
+		return routeInternal, nil
+	case InterfaceExternal:
+		if externalMu {
+			return 0, errors.New("externalMu is only defined for the internal interface")
+		}
+		switch preHash {
+		case "", PreHashPure:
+			return routePure, nil
+		case PreHashPreHash:
+			return routePreHash, nil
+		default:
+			return 0, fmt.Errorf("unknown preHash mode %q", preHash)
+		}
+	default:
+		return 0, fmt.Errorf("unknown signatureInterface %q", signatureInterface)
+	}
+}
+
+// RunSigVer verifies every test case in vectors with v and compares the
+// verdict with the expected testPassed value.
+func RunSigVer(vectors *SigVerVectors, v Verifier) *Result {
+	res := &Result{}
+	for _, tg := range vectors.TestGroups {
+		route, routeErr := routeGroup(tg.SignatureInterface, tg.PreHash, tg.ExternalMu)
+		group := GroupResult{GroupID: tg.TargetGroupID, ParameterSet: tg.ParameterSet}
+		if routeErr == nil {
+			group.Mode = route.String()
+		}
+		res.beginGroup(group)
+		for _, tc := range tg.Tests {
+			c := CaseResult{
+				GroupID:      tg.TargetGroupID,
+				CaseID:       tc.CaseID,
+				ParameterSet: tg.ParameterSet,
+			}
+			if routeErr != nil {
+				c.Outcome = OutcomeSkipped
+				c.Reason = routeErr.Error()
+				res.add(c)
+				continue
+			}
+			start := time.Now()
+			ok, verr, decodeErr := runSigVerCase(v, route, tc)
+			c.Duration = time.Since(start)
+			switch {
+			case decodeErr != nil:
+				c.Outcome = OutcomeDecodeError
+				c.Reason = decodeErr.Error()
+			case verr != nil && (errors.Is(verr, ErrUnsupported) || errors.Is(verr, mldsa.ErrUnsupportedHash)):
+				c.Outcome = OutcomeSkipped
+				c.Reason = verr.Error()
+			case ok == tc.TestPassed:
+				c.Outcome = OutcomePass
+				c.Stage = kat.VerifyStage(ok, verr)
+			default:
+				c.Stage = kat.VerifyStage(ok, verr)
+				c.Outcome = OutcomeFail
+				c.Reason = fmt.Sprintf("expected testPassed=%v, verifier returned %v", tc.TestPassed, ok)
+				if verr != nil {
+					c.Reason += ": " + verr.Error()
+				}
+				c.RuleID = sigVerRule(route, tc, verr)
+			}
+			res.add(c)
+		}
+	}
+	return res.finish()
+}
+
+// sigVerRule names the rule a failed sigVer case breaks. For an accepted
+// invalid signature that is the check the built-in verifier rejects it at.
+func sigVerRule(route groupRoute, tc SigVerTestCase, verr error) string {
+	if tc.TestPassed {
+		return callRule(verr, diag.RuleRejectValid)
+	}
+	ok, refErr, _ := runSigVerCase(Builtin{}, route, tc)
+	return kat.StageRule(kat.VerifyStage(ok, refErr))
+}
+
+func runSigVerCase(v Verifier, route groupRoute, tc SigVerTestCase) (ok bool, verr, decodeErr error) {
+	pk, err := hex.DecodeString(tc.Public)
+	if err != nil {
+		return false, nil, fmt.Errorf("decode pk: %w", err)
+	}
+	sig, err := hex.DecodeString(tc.Signature)
+	if err != nil {
+		return false, nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	if route == routeExternalMu {
+		mu, err := hex.DecodeString(tc.Mu)
+		if err != nil {
+			return false, nil, fmt.Errorf("decode mu: %w", err)
+		}
+		ok, verr = v.VerifyExternalMu(pk, mu, sig)
+		return ok, verr, nil
+	}
+
+	msg, err := hex.DecodeString(tc.Message)
+	if err != nil {
+		return false, nil, fmt.Errorf("decode message: %w", err)
+	}
+	ctx, err := hex.DecodeString(tc.Context)
+	if err != nil {
+		return false, nil, fmt.Errorf("decode context: %w", err)
+	}
+
+	switch route {
+	case routePure:
+		ok, verr = v.VerifyExternal(pk, msg, ctx, sig)
+	case routePreHash:
+		ok, verr = v.VerifyPreHash(pk, msg, ctx, tc.HashAlg, sig)
+	default:
+		ok, verr = v.VerifyInternal(pk, msg, sig)
+	}
+	return ok, verr, nil
+}
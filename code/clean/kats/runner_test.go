@@ -0,0 +1,147 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+func TestRunSigVerBundled(t *testing.T) {
+	vectors, err := LoadSigVerVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigVerVectors: %v", err)
+	}
+	res := RunSigVer(vectors, Builtin{})
+	if res.Report.TotalTests == 0 {
+		t.Fatal("no test cases executed")
+	}
+	for _, c := range res.Cases {
+		if c.Outcome != OutcomePass {
+			t.Errorf("tgId=%d tcId=%d: outcome %s (%s)", c.GroupID, c.CaseID, c.Outcome, c.Reason)
+		}
+	}
+
+	routes := map[groupRoute]bool{}
+	for _, tg := range vectors.TestGroups {
+		route, err := routeGroup(tg.SignatureInterface, tg.PreHash, tg.ExternalMu)
+		if err != nil {
+			t.Fatalf("tgId=%d: %v", tg.TargetGroupID, err)
+		}
+		routes[route] = true
+	}
+	for _, want := range []groupRoute{routeInternal, routeExternalMu, routePure} {
+		if !routes[want] {
+			t.Errorf("bundled vectors do not exercise route %s", want)
+		}
+	}
+}
+
+func TestRunSigVerDetectsWrongVerdict(t *testing.T) {
+	vectors, err := LoadSigVerVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigVerVectors: %v", err)
+	}
+	tc := &vectors.TestGroups[0].Tests[0]
+	tc.TestPassed = !tc.TestPassed
+
+	res := RunSigVer(vectors, Builtin{})
+	if !res.Failed() {
+		t.Fatal("expected the flipped case to fail")
+	}
+	if got := res.Cases[0].Outcome; got != OutcomeFail {
+		t.Fatalf("outcome = %s, want %s", got, OutcomeFail)
+	}
+	if res.Cases[0].Stage == "" {
+		t.Error("failed case has no verify stage")
+	}
+	// A valid signature now expected to fail is one the reference accepts,
+	// so no finer rule than DV-ACCEPT-INVALID applies.
+	want := diag.RuleRejectValid
+	if !tc.TestPassed {
+		want = diag.RuleAcceptInvalid
+	}
+	if got := res.Cases[0].RuleID; got != want {
+		t.Errorf("rule = %q, want %q", got, want)
+	}
+	if f := res.Findings(); len(f) != 1 || f[0].Count != 1 || f[0].RuleID != res.Cases[0].RuleID {
+		t.Errorf("findings %+v", f)
+	}
+
+	cases := res.DiagCases()
+	if len(cases) != len(res.Cases) || cases[0].Status != diag.StatusFail || cases[0].Stage != res.Cases[0].Stage {
+		t.Fatalf("diag cases %+v", cases[0])
+	}
+	if want := fmt.Sprintf("tgId=%d %s", vectors.TestGroups[0].TargetGroupID, vectors.TestGroups[0].ParameterSet); !strings.HasPrefix(cases[0].Suite, want) {
+		t.Errorf("suite %q, want prefix %q", cases[0].Suite, want)
+	}
+}
+
+// internalOnly verifies nothing but the internal interface.
+type internalOnly struct{ Builtin }
+
+func (internalOnly) VerifyExternal(pk, msg, ctx, sig []byte) (bool, error) {
+	return false, fmt.Errorf("external interface: %w", ErrUnsupported)
+}
+
+func TestRunSigVerSkipsUnsupported(t *testing.T) {
+	vectors := &SigVerVectors{
+		Mode: "sigVer",
+		TestGroups: []SigVerTestGroup{
+			{TargetGroupID: 1, ParameterSet: "ML-DSA-44", SignatureInterface: "external", PreHash: "pure",
+				Tests: []SigVerTestCase{{CaseID: 1}}},
+			{TargetGroupID: 2, ParameterSet: "ML-DSA-44", SignatureInterface: "external", ExternalMu: true,
+				Tests: []SigVerTestCase{{CaseID: 2}}},
+			{TargetGroupID: 3, ParameterSet: "ML-DSA-44", SignatureInterface: "bogus",
+				Tests: []SigVerTestCase{{CaseID: 3}}},
+			{TargetGroupID: 4, ParameterSet: "ML-DSA-44", SignatureInterface: "external", PreHash: "preHash",
+				Tests: []SigVerTestCase{{CaseID: 4, HashAlg: "MD5"}}},
+		},
+	}
+
+	res := RunSigVer(vectors, internalOnly{})
+	if res.Failed() {
+		t.Fatalf("unsupported combinations must not fail: %+v", res.Cases)
+	}
+	if res.Report.Skipped != 4 {
+		t.Fatalf("Skipped = %d, want 4", res.Report.Skipped)
+	}
+	for _, c := range res.Cases {
+		if c.Reason == "" {
+			t.Errorf("tcId=%d skipped without explanation", c.CaseID)
+		}
+	}
+	if !strings.Contains(res.Cases[3].Reason, "MD5") {
+		t.Errorf("reason %q should name the hash", res.Cases[3].Reason)
+	}
+}
+
+// acceptAll is a verifier that accepts every signature.
+type acceptAll struct{ Builtin }
+
+func (acceptAll) VerifyInternal(pk, msg, sig []byte) (bool, error)      { return true, nil }
+func (acceptAll) VerifyExternal(pk, msg, ctx, sig []byte) (bool, error) { return true, nil }
+
+func TestRunSigVerClassifiesAcceptedInvalid(t *testing.T) {
+	vectors, err := LoadSigVerVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigVerVectors: %v", err)
+	}
+	res := RunSigVer(vectors, acceptAll{})
+	rules := map[string]int{}
+	for _, f := range res.Findings() {
+		rules[f.RuleID] = f.Count
+	}
+	if res.Report.StructuralFailures == 0 || rules[diag.RuleRejectValid] != 0 {
+		t.Fatalf("failures %d, findings %v", res.Report.StructuralFailures, rules)
+	}
+	// The bundled invalid signatures fail either on the hint encoding or on
+	// the challenge; each is attributed to the check that catches it.
+	if rules[diag.RuleHintMalformed] == 0 || rules[diag.RuleChallenge] == 0 {
+		t.Errorf("findings %v", rules)
+	}
+}
@@ -0,0 +1,227 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// Signer exposes the ML-DSA signing entry points an ACVP sigGen group can
+// be routed to. rnd is always 32 bytes; deterministic groups pass zeros.
+type Signer interface {
+	// SignInternal runs ML-DSA.Sign_internal with msg as M′.
+	SignInternal(sk, msg, rnd []byte) ([]byte, error)
+	// SignExternal runs ML-DSA.Sign with a context string.
+	SignExternal(sk, msg, ctx, rnd []byte) ([]byte, error)
+	// SignPreHash runs HashML-DSA.Sign with the named hash function.
+	SignPreHash(sk, msg, ctx []byte, hashAlg string, rnd []byte) ([]byte, error)
+	// SignExternalMu runs ML-DSA.Sign_internal on a precomputed μ.
+	SignExternalMu(sk, mu, rnd []byte) ([]byte, error)
+}
+
+// SignInternal implements Signer.
+func (Builtin) SignInternal(sk, msg, rnd []byte) ([]byte, error) {
+	return mldsa.Sign(sk, msg, rnd)
+}
+
+// SignExternal implements Signer.
+func (Builtin) SignExternal(sk, msg, ctx, rnd []byte) ([]byte, error) {
+	return mldsa.SignWithContext(sk, msg, ctx, rnd)
+}
+
+// SignPreHash implements Signer.
+func (Builtin) SignPreHash(sk, msg, ctx []byte, hashAlg string, rnd []byte) ([]byte, error) {
+	return mldsa.SignPreHash(sk, msg, ctx, hashAlg, rnd)
+}
+
+// SignExternalMu implements Signer.
+func (Builtin) SignExternalMu(sk, mu, rnd []byte) ([]byte, error) {
+	return mldsa.SignExternalMu(sk, mu, rnd)
+}
+
+// RunSigGen signs every test case in vectors with s. Deterministic groups,
+// and hedged cases that publish their rnd, are compared byte for byte with
+// the expected signature. Hedged cases without rnd are signed with fresh
+// randomness and the result is checked with the built-in verifier.
+func RunSigGen(vectors *SigGenVectors, s Signer) *Result {
+	res := &Result{}
+	for _, tg := range vectors.TestGroups {
+		route, routeErr := routeGroup(tg.SignatureInterface, tg.PreHash, tg.ExternalMu)
+		group := GroupResult{
+			GroupID:      tg.TargetGroupID,
+			ParameterSet: tg.ParameterSet,
+			CornerCase:   tg.CornerCase,
+		}
+		if routeErr == nil {
+			group.Mode = route.String() + " " + signingMode(tg.Deterministic)
+		}
+		res.beginGroup(group)
+
+		params, paramsErr := mldsa.FromName(tg.ParameterSet)
+		for _, tc := range tg.Tests {
+			c := CaseResult{
+				GroupID:      tg.TargetGroupID,
+				CaseID:       tc.CaseID,
+				ParameterSet: tg.ParameterSet,
+			}
+			switch {
+			case routeErr != nil:
+				c.Outcome, c.Reason = OutcomeSkipped, routeErr.Error()
+			case paramsErr != nil:
+				c.Outcome, c.Reason = OutcomeSkipped, fmt.Sprintf("unknown parameter set %q", tg.ParameterSet)
+			default:
+				start := time.Now()
+				runSigGenCase(&c, s, route, params, tg.Deterministic, tc)
+				c.Duration = time.Since(start)
+			}
+			res.add(c)
+		}
+	}
+	return res.finish()
+}
+
+func signingMode(deterministic bool) string {
+	if deterministic {
+		return "deterministic"
+	}
+	return "hedged"
+}
+
+// sigGenInputs holds the decoded fields of a sigGen test case.
+type sigGenInputs struct {
+	sk, msg, mu, ctx, rnd, want []byte
+}
+
+func decodeSigGenCase(tc SigGenTestCase, route groupRoute) (*sigGenInputs, error) {
+	in := &sigGenInputs{}
+	var err error
+	decode := func(dst *[]byte, name, value string) {
+		if err != nil {
+			return
+		}
+		if *dst, err = hex.DecodeString(value); err != nil {
+			err = fmt.Errorf("decode %s: %w", name, err)
+		}
+	}
+	decode(&in.sk, "sk", tc.Secret)
+	decode(&in.rnd, "rnd", tc.Rnd)
+	decode(&in.want, "signature", tc.Signature)
+	if route == routeExternalMu {
+		decode(&in.mu, "mu", tc.Mu)
+	} else {
+		decode(&in.msg, "message", tc.Message)
+		decode(&in.ctx, "context", tc.Context)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// sigGenRnd picks the signing randomness for a case: rnd = 0^32 when
+// deterministic, the published rnd when present, otherwise a fresh draw
+// (reported through fresh).
+func sigGenRnd(deterministic bool, published []byte) (rnd []byte, fresh bool, err error) {
+	switch {
+	case deterministic:
+		return make([]byte, mldsa.RndBytes), false, nil
+	case len(published) > 0:
+		return published, false, nil
+	}
+	rnd = make([]byte, mldsa.RndBytes)
+	if _, err := rand.Read(rnd); err != nil {
+		return nil, false, err
+	}
+	return rnd, true, nil
+}
+
+// signRoute calls the Signer entry point for route.
+func signRoute(s Signer, route groupRoute, in *sigGenInputs, hashAlg string) ([]byte, error) {
+	switch route {
+	case routeExternalMu:
+		return s.SignExternalMu(in.sk, in.mu, in.rnd)
+	case routePure:
+		return s.SignExternal(in.sk, in.msg, in.ctx, in.rnd)
+	case routePreHash:
+		return s.SignPreHash(in.sk, in.msg, in.ctx, hashAlg, in.rnd)
+	default:
+		return s.SignInternal(in.sk, in.msg, in.rnd)
+	}
+}
+
+func runSigGenCase(c *CaseResult, s Signer, route groupRoute, params *mldsa.Params, deterministic bool, tc SigGenTestCase) {
+	in, err := decodeSigGenCase(tc, route)
+	if err != nil {
+		c.Outcome, c.Reason = OutcomeDecodeError, err.Error()
+		return
+	}
+
+	rnd, fresh, err := sigGenRnd(deterministic, in.rnd)
+	if err != nil {
+		c.Outcome, c.Reason, c.RuleID = OutcomeFail, fmt.Sprintf("draw rnd: %v", err), diag.RuleSigGenError
+		return
+	}
+	in.rnd = rnd
+	compare := !fresh
+
+	sig, err := signRoute(s, route, in, tc.HashAlg)
+	switch {
+	case err != nil && (errors.Is(err, ErrUnsupported) || errors.Is(err, mldsa.ErrUnsupportedHash)):
+		c.Outcome, c.Reason = OutcomeSkipped, err.Error()
+		return
+	case err != nil:
+		c.Outcome, c.Reason, c.RuleID = OutcomeFail, fmt.Sprintf("signing failed: %v", err), callRule(err, diag.RuleSigGenError)
+		return
+	}
+
+	if compare {
+		if m := compareBytes("signature", sig, in.want, mldsa.SignatureLayout(params)); m != nil {
+			c.Outcome, c.Reason, c.Mismatch, c.RuleID = OutcomeFail, m.String(), m, diag.RuleSigGenMismatch
+			return
+		}
+		c.Outcome = OutcomePass
+		return
+	}
+
+	ok, err := verifyHedged(route, tc, in, sig)
+	if !ok {
+		c.Outcome, c.RuleID = OutcomeFail, diag.RuleSigGenError
+		c.Reason = "hedged signature does not verify"
+		if err != nil {
+			c.Reason += ": " + err.Error()
+		}
+		return
+	}
+	c.Outcome, c.Warning = OutcomePass, true
+	c.Reason = "hedged signature verified (no rnd published)"
+}
+
+// verifyHedged checks a freshly randomised signature with the built-in
+// verifier, deriving pk from sk when the vector does not carry one.
+func verifyHedged(route groupRoute, tc SigGenTestCase, in *sigGenInputs, sig []byte) (bool, error) {
+	pk, err := hex.DecodeString(tc.Public)
+	if err != nil || len(pk) == 0 {
+		if pk, err = mldsa.PublicKeyFromSecretKey(in.sk); err != nil {
+			return false, err
+		}
+	}
+	var v Builtin
+	switch route {
+	case routeExternalMu:
+		return v.VerifyExternalMu(pk, in.mu, sig)
+	case routePure:
+		return v.VerifyExternal(pk, in.msg, in.ctx, sig)
+	case routePreHash:
+		return v.VerifyPreHash(pk, in.msg, in.ctx, tc.HashAlg, sig)
+	default:
+		return v.VerifyInternal(pk, in.msg, sig)
+	}
+}
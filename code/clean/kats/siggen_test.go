@@ -0,0 +1,90 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kats
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunSigGenBundled(t *testing.T) {
+	vectors, err := LoadSigGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigGenVectors: %v", err)
+	}
+	res := RunSigGen(vectors, Builtin{})
+	if res.Report.TotalTests == 0 {
+		t.Fatal("no test cases executed")
+	}
+	for _, c := range res.Cases {
+		if c.Outcome != OutcomePass {
+			t.Errorf("tgId=%d tcId=%d: outcome %s (%s)", c.GroupID, c.CaseID, c.Outcome, c.Reason)
+		}
+	}
+	if len(res.Groups) != len(vectors.TestGroups) {
+		t.Fatalf("got %d group summaries, want %d", len(res.Groups), len(vectors.TestGroups))
+	}
+	modes := map[string]bool{}
+	for _, g := range res.Groups {
+		modes[g.Mode] = true
+	}
+	for _, want := range []string{"internal deterministic", "internal hedged", "external/pure deterministic", "internal/externalMu hedged"} {
+		if !modes[want] {
+			t.Errorf("bundled vectors do not exercise %q", want)
+		}
+	}
+}
+
+func TestRunSigGenHedgedWithoutRnd(t *testing.T) {
+	vectors, err := LoadSigGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigGenVectors: %v", err)
+	}
+	var groups []SigGenTestGroup
+	for _, tg := range vectors.TestGroups {
+		if tg.Deterministic {
+			continue
+		}
+		tg.Tests = tg.Tests[:1]
+		tg.Tests[0].Rnd = ""
+		tg.Tests[0].Public = "" // force pk derivation from sk
+		groups = append(groups, tg)
+	}
+	vectors.TestGroups = groups
+
+	res := RunSigGen(vectors, Builtin{})
+	if res.Failed() || res.Report.StructuralWarnings != len(groups) || res.Report.StrictPasses != 0 {
+		t.Fatalf("unexpected result: %+v", res.Cases)
+	}
+	for _, c := range res.Cases {
+		if !strings.Contains(c.Reason, "verified") {
+			t.Errorf("tcId=%d: reason %q should say the signature was verified", c.CaseID, c.Reason)
+		}
+	}
+}
+
+func TestRunSigGenKeepsCornerCaseLabel(t *testing.T) {
+	vectors, err := LoadSigGenVectors("")
+	if err != nil {
+		t.Fatalf("LoadSigGenVectors: %v", err)
+	}
+	tg := vectors.TestGroups[0]
+	tg.CornerCase = "largeHint"
+	tg.Tests = tg.Tests[:2]
+	// Corrupt the final hint counter.
+	tg.Tests[1].Signature = flipHexDigit(tg.Tests[1].Signature, len(tg.Tests[1].Signature)-1)
+	vectors.TestGroups = []SigGenTestGroup{tg}
+
+	res := RunSigGen(vectors, Builtin{})
+	if len(res.Groups) != 1 {
+		t.Fatalf("got %d groups", len(res.Groups))
+	}
+	g := res.Groups[0]
+	if g.CornerCase != "largeHint" || g.Passed != 1 || g.Failed != 1 {
+		t.Fatalf("unexpected group summary: %+v", g)
+	}
+	if m := res.Cases[1].Mismatch; m == nil || m.Field != "signature" || m.Component != "h" {
+		t.Fatalf("unexpected mismatch: %+v", m)
+	}
+}
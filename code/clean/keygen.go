@@ -0,0 +1,74 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/hash"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// ErrInvalidSeed is returned when a key generation seed is not 32 bytes.
+var ErrInvalidSeed = errors.New("mldsa: key generation seed must be 32 bytes")
+
+// KeyGen implements ML-DSA.KeyGen_internal (FIPS 204 Algorithm 6),
+// deterministically deriving an encoded key pair from the 32-byte seed ξ.
+func KeyGen(params *Params, seed []byte) (pk, sk []byte, err error) {
+	if err := params.ValidateParams(); err != nil {
+		return nil, nil, err
+	}
+	if len(seed) != SeedBytes {
+		return nil, nil, ErrInvalidSeed
+	}
+
+	// Step 1: (ρ, ρ′, K) = H(ξ || k || l, 128)
+	expanded := make([]byte, 2*SeedBytes+CRHBytes)
+	hash.SumShake256(expanded, seed, []byte{byte(params.K), byte(params.L)})
+	rho := expanded[:SeedBytes]
+	rhoPrime := expanded[SeedBytes : SeedBytes+CRHBytes]
+	key := expanded[SeedBytes+CRHBytes:]
+
+	// Steps 3–4: Â = ExpandA(ρ), (s₁, s₂) = ExpandS(ρ′)
+	a := expandA(rho, params)
+	s1, s2 := expandS(rhoPrime, params)
+
+	// Step 5: t = NTT⁻¹(Â∘NTT(s₁)) + s₂
+	s1Hat := poly.NewVec(params.L)
+	if err := s1Hat.CopyFrom(s1); err != nil {
+		return nil, nil, err
+	}
+	if err := s1Hat.NTT(); err != nil {
+		return nil, nil, fmt.Errorf("mldsa: NTT s1: %w", err)
+	}
+
+	// Step 6: (t₁, t₀) = Power2Round(t)
+	t1 := poly.NewVec(params.K)
+	t0 := poly.NewVec(params.K)
+	for i := 0; i < params.K; i++ {
+		t := &poly.Poly{}
+		poly.PointwiseAccMontgomery(t, a[i], s1Hat.Polys())
+		if err := poly.InvNTT(t); err != nil {
+			return nil, nil, fmt.Errorf("mldsa: InvNTT t[%d]: %w", i, err)
+		}
+		t.Add(t, s2.Polys()[i])
+		for j, c := range t.Coeffs {
+			t1.Polys()[i].Coeffs[j], t0.Polys()[i].Coeffs[j] = power2Round(c)
+		}
+	}
+
+	// Steps 8–10: pk = pkEncode(ρ, t₁), tr = H(pk, 64), sk = skEncode(...)
+	pk, err = packPublicKey(rho, t1, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr := make([]byte, CRHBytes)
+	hashPublicKey(tr, pk)
+	sk, err = packSecretKey(rho, key, tr, s1, s2, t0, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, sk, nil
+}
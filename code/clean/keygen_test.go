@@ -0,0 +1,51 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestKeyGenSizesAndLayout(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, SeedBytes)
+	for _, params := range []*Params{ParamsMLDSA44, ParamsMLDSA65, ParamsMLDSA87} {
+		t.Run(params.Name, func(t *testing.T) {
+			pk, sk, err := KeyGen(params, seed)
+			if err != nil {
+				t.Fatalf("KeyGen: %v", err)
+			}
+			if len(pk) != params.PKBytes || len(sk) != params.SKBytes {
+				t.Fatalf("sizes pk=%d sk=%d, want %d/%d", len(pk), len(sk), params.PKBytes, params.SKBytes)
+			}
+			for name, l := range map[string][]Component{"pk": PublicKeyLayout(params), "sk": SecretKeyLayout(params)} {
+				last := l[len(l)-1]
+				want := params.PKBytes
+				if name == "sk" {
+					want = params.SKBytes
+				}
+				if last.Offset+last.Size != want {
+					t.Errorf("%s layout covers %d bytes, want %d", name, last.Offset+last.Size, want)
+				}
+			}
+			if !bytes.Equal(pk[:SeedBytes], sk[:SeedBytes]) {
+				t.Error("rho differs between pk and sk")
+			}
+			pk2, sk2, _ := KeyGen(params, seed)
+			if !bytes.Equal(pk, pk2) || !bytes.Equal(sk, sk2) {
+				t.Error("KeyGen is not deterministic")
+			}
+		})
+	}
+}
+
+func TestKeyGenRejectsBadSeed(t *testing.T) {
+	if _, _, err := KeyGen(ParamsMLDSA44, make([]byte, 31)); !errors.Is(err, ErrInvalidSeed) {
+		t.Fatalf("err = %v, want ErrInvalidSeed", err)
+	}
+	if _, _, err := KeyGen(&Params{}, make([]byte, SeedBytes)); !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("err = %v, want ErrInvalidParams", err)
+	}
+}
@@ -7,8 +7,11 @@
 // of the Module Learning With Errors (M-LWE) problem. It provides
 // three security levels corresponding to NIST PQC security categories.
 //
-// This package implements ML-DSA (FIPS 204) signature verification.
-// Full verification algorithm is implemented according to FIPS 204 Algorithm 3.
+// This package implements ML-DSA (FIPS 204) key generation (Algorithm 6),
+// signing and verification through each of the interfaces exercised by
+// ACVP: the internal interface (Algorithms 7 and 8), the external pure
+// interface with context strings (Algorithms 2 and 3), HashML-DSA
+// pre-hashing (Algorithms 4 and 5) and external μ.
 //
 // For more information, see FIPS 204:
 // https://csrc.nist.gov/pubs/fips/204/final
@@ -34,6 +37,13 @@ var (
 	ErrEmptyMessage     = errors.New("mldsa: message cannot be empty")
 )
 
+// Finer-grained signature rejections. Both wrap ErrInvalidSignature, so
+// existing errors.Is checks keep matching.
+var (
+	ErrMalformedHint = fmt.Errorf("%w: malformed hint encoding", ErrInvalidSignature)
+	ErrZOutOfRange   = fmt.Errorf("%w: z coefficient outside ±(γ1-β)", ErrInvalidSignature)
+)
+
 // Verify checks whether sig is a valid ML-DSA signature for msg under pk.
 //
 // It returns true if and only if sig was produced by signing msg with the
@@ -50,7 +60,9 @@ var (
 //   - bool: true if signature is valid, false otherwise
 //   - error: validation error if inputs are malformed or verification fails
 //
-// This function implements the full FIPS 204 Algorithm 3 (Signature Verification).
+// This function implements ML-DSA.Verify_internal (FIPS 204 Algorithm 8): msg
+// is used directly as the formatted message M′. Use VerifyWithContext for
+// signatures produced by the external ML-DSA.Sign interface.
 // It performs complete cryptographic verification including matrix expansion,
 // polynomial arithmetic, hint application, and challenge reconstruction.
 func Verify(pk, msg, sig []byte) (bool, error) {
@@ -103,7 +115,7 @@ func Verify(pk, msg, sig []byte) (bool, error) {
 		return false, ErrInvalidSignature
 	}
 
-	// Phase 3: Full FIPS 204 Algorithm 3 (Signature Verification)
+	// Phase 3: Full FIPS 204 Algorithm 8 (ML-DSA.Verify_internal)
 	params, err := FromPublicKeyLength(len(pk))
 	if err != nil {
 		return false, ErrInvalidPublicKey
@@ -115,5 +127,5 @@ func Verify(pk, msg, sig []byte) (bool, error) {
 	}
 
 	// Perform full verification
-	return verifyFull(pk, msg, sig, params)
+	return verifyFull(pk, msg, nil, sig, params, nil)
 }
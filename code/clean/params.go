@@ -3,7 +3,10 @@
 
 package mldsa
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 // Params defines the cryptographic parameters for a specific ML-DSA security level.
 //
@@ -25,10 +28,12 @@ type Params struct {
 	Gamma2     int    // Hint generation bound γ₂ = (q-1)/(2*ω)
 	Tau        int    // Number of ±1 coefficients in challenge polynomial
 	Omega      int    // Maximum number of ones in hint h
+	Lambda     int    // Collision strength λ of c̃ in bits
 	Gamma1Bits int    // Number of bits used to encode gamma1-bound polys
 	Gamma2Bits int    // Number of bits used to encode gamma2-bound polys
 	DuBits     int    // Bit-width for t1 compression
 	DvBits     int    // Bit-width for w1 compression
+	EtaBits    int    // Bit-width for s1/s2 packing
 	ETA1       int    // eta1 (secret key)
 	ETA2       int    // eta2 (used in the expansion of secret key)
 	TauShort   int    // Tau' for recomputed challenge
@@ -54,10 +59,12 @@ var (
 		Gamma2:     (q - 1) / 88,
 		Tau:        39,
 		Omega:      80,
+		Lambda:     128,
 		Gamma1Bits: 18,
 		Gamma2Bits: 9,
-		DuBits:     9,
-		DvBits:     5,
+		DuBits:     10,
+		DvBits:     6,
+		EtaBits:    3,
 		ETA1:       2,
 		ETA2:       2,
 		TauShort:   39,
@@ -80,10 +87,12 @@ var (
 		Gamma2:     (q - 1) / 32,
 		Tau:        49,
 		Omega:      55,
-		Gamma1Bits: 19,
+		Lambda:     192,
+		Gamma1Bits: 20,
 		Gamma2Bits: 10,
 		DuBits:     10,
 		DvBits:     4,
+		EtaBits:    4,
 		ETA1:       4,
 		ETA2:       2,
 		TauShort:   49,
@@ -106,10 +115,12 @@ var (
 		Gamma2:     (q - 1) / 32,
 		Tau:        60,
 		Omega:      75,
-		Gamma1Bits: 19,
+		Lambda:     256,
+		Gamma1Bits: 20,
 		Gamma2Bits: 10,
 		DuBits:     10,
 		DvBits:     4,
+		EtaBits:    3,
 		ETA1:       2,
 		ETA2:       2,
 		TauShort:   60,
@@ -133,6 +144,40 @@ func FromPublicKeyLength(pkLen int) (*Params, error) {
 	}
 }
 
+// FromSecretKeyLength returns the Params for a given secret key length.
+func FromSecretKeyLength(skLen int) (*Params, error) {
+	switch skLen {
+	case ParamsMLDSA44.SKBytes:
+		return ParamsMLDSA44, nil
+	case ParamsMLDSA65.SKBytes:
+		return ParamsMLDSA65, nil
+	case ParamsMLDSA87.SKBytes:
+		return ParamsMLDSA87, nil
+	default:
+		return nil, ErrInvalidParams
+	}
+}
+
+// FromName returns the Params for a parameter set name such as "ML-DSA-65".
+// The comparison is case-insensitive to accept ACVP and CLI spellings alike.
+func FromName(name string) (*Params, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case ParamsMLDSA44.Name:
+		return ParamsMLDSA44, nil
+	case ParamsMLDSA65.Name:
+		return ParamsMLDSA65, nil
+	case ParamsMLDSA87.Name:
+		return ParamsMLDSA87, nil
+	default:
+		return nil, ErrInvalidParams
+	}
+}
+
+// CTildeBytes returns the length of the commitment hash c̃ (λ/4 bytes).
+func (p *Params) CTildeBytes() int {
+	return p.Lambda / 4
+}
+
 const (
 	q = 8380417 // ML-DSA modulus (prime)
 	n = 256     // Polynomial degree
@@ -0,0 +1,75 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrUnsupportedHash is returned when a HashML-DSA pre-hash function is unknown.
+var ErrUnsupportedHash = errors.New("mldsa: unsupported pre-hash function")
+
+// preHash describes one approved HashML-DSA pre-hash function: the DER
+// encoding of its OID and the digest computation.
+type preHash struct {
+	oid []byte
+	sum func(msg []byte) []byte
+}
+
+// nistHashOID returns the DER encoding of 2.16.840.1.101.3.4.2.<last>.
+func nistHashOID(last byte) []byte {
+	return []byte{0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, last}
+}
+
+// preHashes is keyed by the hash names used in ACVP ML-DSA test vectors.
+var preHashes = map[string]preHash{
+	"SHA2-224":     {nistHashOID(0x04), func(m []byte) []byte { s := sha256.Sum224(m); return s[:] }},
+	"SHA2-256":     {nistHashOID(0x01), func(m []byte) []byte { s := sha256.Sum256(m); return s[:] }},
+	"SHA2-384":     {nistHashOID(0x02), func(m []byte) []byte { s := sha512.Sum384(m); return s[:] }},
+	"SHA2-512":     {nistHashOID(0x03), func(m []byte) []byte { s := sha512.Sum512(m); return s[:] }},
+	"SHA2-512/224": {nistHashOID(0x05), func(m []byte) []byte { s := sha512.Sum512_224(m); return s[:] }},
+	"SHA2-512/256": {nistHashOID(0x06), func(m []byte) []byte { s := sha512.Sum512_256(m); return s[:] }},
+	"SHA3-224":     {nistHashOID(0x07), func(m []byte) []byte { s := sha3.Sum224(m); return s[:] }},
+	"SHA3-256":     {nistHashOID(0x08), func(m []byte) []byte { s := sha3.Sum256(m); return s[:] }},
+	"SHA3-384":     {nistHashOID(0x09), func(m []byte) []byte { s := sha3.Sum384(m); return s[:] }},
+	"SHA3-512":     {nistHashOID(0x0a), func(m []byte) []byte { s := sha3.Sum512(m); return s[:] }},
+	"SHAKE-128": {nistHashOID(0x0b), func(m []byte) []byte {
+		out := make([]byte, 32)
+		sha3.ShakeSum128(out, m)
+		return out
+	}},
+	"SHAKE-256": {nistHashOID(0x0c), func(m []byte) []byte {
+		out := make([]byte, 64)
+		sha3.ShakeSum256(out, m)
+		return out
+	}},
+}
+
+// PreHashAlgorithms lists the supported HashML-DSA pre-hash function names.
+func PreHashAlgorithms() []string {
+	names := make([]string, 0, len(preHashes))
+	for name := range preHashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// preHashMessage returns OID || PH(msg) for the named hash function.
+func preHashMessage(hashAlg string, msg []byte) ([]byte, error) {
+	ph, ok := preHashes[hashAlg]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedHash, hashAlg)
+	}
+	digest := ph.sum(msg)
+	out := make([]byte, 0, len(ph.oid)+len(digest))
+	out = append(out, ph.oid...)
+	return append(out, digest...), nil
+}
@@ -0,0 +1,74 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import "github.com/codethor0/dilivet/code/poly"
+
+// decomposeCoeff implements FIPS 204 Algorithm 36 (Decompose).
+// It returns (r1, r0) with r ≡ r1·2γ₂ + r0 (mod q) and r0 ∈ (-γ₂, γ₂],
+// folding the corner case r - r0 = q - 1 into r1 = 0.
+func decomposeCoeff(r uint32, gamma2 int) (uint32, int32) {
+	rp := int32(poly.ModQ(r))
+	alpha := int32(2 * gamma2)
+	r0 := rp % alpha
+	if r0 > alpha/2 {
+		r0 -= alpha
+	}
+	if rp-r0 == int32(q-1) {
+		return 0, r0 - 1
+	}
+	return uint32((rp - r0) / alpha), r0
+}
+
+// highBits implements FIPS 204 Algorithm 37 (HighBits).
+func highBits(r uint32, gamma2 int) uint32 {
+	r1, _ := decomposeCoeff(r, gamma2)
+	return r1
+}
+
+// applyHint implements FIPS 204 Algorithm 40 (UseHint) for one coefficient.
+func applyHint(hint bool, r uint32, gamma2 int) uint32 {
+	m := int32((q - 1) / (2 * gamma2))
+	r1, r0 := decomposeCoeff(r, gamma2)
+	if !hint {
+		return r1
+	}
+	if r0 > 0 {
+		return uint32((int32(r1) + 1) % m)
+	}
+	return uint32((int32(r1) - 1 + m) % m)
+}
+
+// infNorm returns |x| for a coefficient interpreted in (-q/2, q/2].
+func infNorm(x uint32) int32 {
+	c := poly.Canonical(x)
+	if c < 0 {
+		return -c
+	}
+	return c
+}
+
+// power2Round implements FIPS 204 Algorithm 35 (Power2Round).
+// It returns (r1, r0) with r ≡ r1·2^d + r0 (mod q) and r0 ∈ (-2^(d-1), 2^(d-1)];
+// r0 is returned modulo q.
+func power2Round(r uint32) (uint32, uint32) {
+	rp := int32(poly.ModQ(r))
+	r0 := rp & (1<<d - 1)
+	if r0 > 1<<(d-1) {
+		r0 -= 1 << d
+	}
+	return uint32((rp - r0) >> d), poly.ModQ(uint32(r0 + q))
+}
+
+// lowBits implements FIPS 204 Algorithm 38 (LowBits).
+func lowBits(r uint32, gamma2 int) int32 {
+	_, r0 := decomposeCoeff(r, gamma2)
+	return r0
+}
+
+// makeHint implements FIPS 204 Algorithm 39 (MakeHint): it reports whether
+// adding z to r changes the high bits.
+func makeHint(z, r uint32, gamma2 int) bool {
+	return highBits(r, gamma2) != highBits(r+z, gamma2)
+}
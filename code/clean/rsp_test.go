@@ -0,0 +1,102 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// rspKeyGen and rspSign drive ML-DSA the way the pq-crystals reference
+// PQCgenKAT_sign does: ξ comes from one randombytes(32) call and signing is
+// the external interface with an empty context and rnd = 0^32.
+func rspKeyGen(params *mldsa.Params) kat.RSPKeyGenFunc {
+	return func(rng io.Reader) ([]byte, []byte, error) {
+		xi := make([]byte, mldsa.SeedBytes)
+		if _, err := rng.Read(xi); err != nil {
+			return nil, nil, err
+		}
+		return mldsa.KeyGen(params, xi)
+	}
+}
+
+func rspSign(sk, msg []byte, _ io.Reader) ([]byte, error) {
+	return mldsa.SignWithContext(sk, msg, nil, make([]byte, mldsa.RndBytes))
+}
+
+// TestPQCgenKATSign regenerates the 100-case KAT files of the pq-crystals
+// reference implementation (deterministic signing) and compares their
+// SHA-256 with the published digests.
+func TestPQCgenKATSign(t *testing.T) {
+	tests := []struct {
+		params *mldsa.Params
+		scheme string
+		digest string
+	}{
+		{mldsa.ParamsMLDSA44, "Dilithium2", "14f92c48abc0d63ea263cce3c83183c8360c6ede7cbd5b65bd7c6f31e38f0ea5"},
+		{mldsa.ParamsMLDSA65, "Dilithium3", "595a8eff6988159c94eb5398294458c5d27d21c994fb64cadbee339173abcf63"},
+		{mldsa.ParamsMLDSA87, "Dilithium5", "35e2ce3d88b3311517bf8d41aa2cd24aa0fbda2bb8052ca8af4ad8d7c7344074"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.params.Name, func(t *testing.T) {
+			entropy := make([]byte, kat.DRBGSeedBytes)
+			for i := range entropy {
+				entropy[i] = byte(i)
+			}
+			req, err := kat.NewDRBG(entropy)
+			if err != nil {
+				t.Fatalf("NewDRBG: %v", err)
+			}
+
+			file := &kat.RSPFile{Scheme: tt.scheme}
+			for i := 0; i < 100; i++ {
+				c := kat.RSPCase{Count: i, Seed: make([]byte, kat.DRBGSeedBytes), MLen: 33 * (i + 1)}
+				c.Msg = make([]byte, c.MLen)
+				_, _ = req.Read(c.Seed)
+				_, _ = req.Read(c.Msg)
+
+				rng, err := kat.NewDRBG(c.Seed)
+				if err != nil {
+					t.Fatalf("NewDRBG: %v", err)
+				}
+				if c.PK, c.SK, err = rspKeyGen(tt.params)(rng); err != nil {
+					t.Fatalf("count %d: keygen: %v", i, err)
+				}
+				sig, err := rspSign(c.SK, c.Msg, rng)
+				if err != nil {
+					t.Fatalf("count %d: sign: %v", i, err)
+				}
+				c.SM = append(sig, c.Msg...)
+				c.SMLen = len(c.SM)
+				file.Cases = append(file.Cases, c)
+			}
+
+			var buf bytes.Buffer
+			if _, err := file.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo: %v", err)
+			}
+			sum := sha256.Sum256(buf.Bytes())
+			if got := hex.EncodeToString(sum[:]); got != tt.digest {
+				t.Fatalf("KAT digest = %s, want %s", got, tt.digest)
+			}
+
+			// The regenerated file must load strictly and replay cleanly.
+			parsed, err := kat.ParseRSP(&buf, tt.scheme+".rsp")
+			if err != nil {
+				t.Fatalf("ParseRSP: %v", err)
+			}
+			for _, r := range kat.CheckRSP(parsed, rspKeyGen(tt.params), rspSign) {
+				if r.Err != nil {
+					t.Fatalf("count %d (line %d): %v", r.Count, r.Line, r.Err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"github.com/codethor0/dilivet/code/poly"
+	"golang.org/x/crypto/sha3"
+)
+
+// expandA implements FIPS 204 Algorithm 32 (ExpandA).
+// The returned k×l matrix Â is already in the NTT domain.
+func expandA(rho []byte, params *Params) [][]*poly.Poly {
+	a := make([][]*poly.Poly, params.K)
+	for r := 0; r < params.K; r++ {
+		a[r] = make([]*poly.Poly, params.L)
+		for s := 0; s < params.L; s++ {
+			a[r][s] = rejNTTPoly(rho, byte(s), byte(r))
+		}
+	}
+	return a
+}
+
+// rejNTTPoly implements FIPS 204 Algorithm 30 (RejNTTPoly) seeded with ρ || s || r.
+func rejNTTPoly(rho []byte, s, r byte) *poly.Poly {
+	xof := sha3.NewShake128()
+	_, _ = xof.Write(rho)
+	_, _ = xof.Write([]byte{s, r})
+
+	p := &poly.Poly{}
+	var buf [168]byte // one SHAKE128 block
+	ctr := 0
+	for ctr < poly.N {
+		_, _ = xof.Read(buf[:])
+		for i := 0; i+3 <= len(buf) && ctr < poly.N; i += 3 {
+			// CoeffFromThreeBytes (Algorithm 14)
+			v := uint32(buf[i]) | uint32(buf[i+1])<<8 | uint32(buf[i+2]&0x7f)<<16
+			if v < q {
+				p.Coeffs[ctr] = v
+				ctr++
+			}
+		}
+	}
+	return p
+}
+
+// sampleChallenge implements FIPS 204 Algorithm 29 (SampleInBall).
+// It produces a polynomial with exactly tau coefficients set to ±1, derived
+// from the full commitment hash c̃.
+func sampleChallenge(c *poly.Poly, ctilde []byte, tau int) error {
+	xof := sha3.NewShake256()
+	if _, err := xof.Write(ctilde); err != nil {
+		return err
+	}
+
+	var signBytes [8]byte
+	if _, err := xof.Read(signBytes[:]); err != nil {
+		return err
+	}
+	var signs uint64
+	for i, b := range signBytes {
+		signs |= uint64(b) << (8 * i)
+	}
+
+	for i := range c.Coeffs {
+		c.Coeffs[i] = 0
+	}
+
+	var jb [1]byte
+	for i := poly.N - tau; i < poly.N; i++ {
+		for {
+			if _, err := xof.Read(jb[:]); err != nil {
+				return err
+			}
+			if int(jb[0]) <= i {
+				break
+			}
+		}
+		j := int(jb[0])
+		c.Coeffs[i] = c.Coeffs[j]
+		if signs&1 == 0 {
+			c.Coeffs[j] = 1
+		} else {
+			c.Coeffs[j] = poly.Q - 1 // -1 mod q
+		}
+		signs >>= 1
+	}
+	return nil
+}
+
+// expandS implements FIPS 204 Algorithm 33 (ExpandS), returning s₁ and s₂
+// with coefficients in [-η, η] stored modulo q.
+func expandS(rhoPrime []byte, params *Params) (s1, s2 *poly.Vec) {
+	s1 = poly.NewVec(params.L)
+	s2 = poly.NewVec(params.K)
+	for r := 0; r < params.L; r++ {
+		rejBoundedPoly(s1.Polys()[r], rhoPrime, uint16(r), params.Eta)
+	}
+	for r := 0; r < params.K; r++ {
+		rejBoundedPoly(s2.Polys()[r], rhoPrime, uint16(params.L+r), params.Eta)
+	}
+	return s1, s2
+}
+
+// rejBoundedPoly implements FIPS 204 Algorithm 31 (RejBoundedPoly) seeded
+// with ρ′ || IntegerToBytes(nonce, 2).
+func rejBoundedPoly(p *poly.Poly, rhoPrime []byte, nonce uint16, eta int) {
+	xof := sha3.NewShake256()
+	_, _ = xof.Write(rhoPrime)
+	_, _ = xof.Write([]byte{byte(nonce), byte(nonce >> 8)})
+
+	var buf [136]byte // one SHAKE256 block
+	ctr := 0
+	for ctr < poly.N {
+		_, _ = xof.Read(buf[:])
+		for _, b := range buf {
+			for _, half := range [2]uint32{uint32(b & 0x0f), uint32(b >> 4)} {
+				if ctr == poly.N {
+					break
+				}
+				// CoeffFromHalfByte (Algorithm 15)
+				switch {
+				case eta == 2 && half < 15:
+					p.Coeffs[ctr] = poly.ModQ(2 + q - half%5)
+					ctr++
+				case eta == 4 && half < 9:
+					p.Coeffs[ctr] = poly.ModQ(4 + q - half)
+					ctr++
+				}
+			}
+		}
+	}
+}
+
+// expandMask implements FIPS 204 Algorithm 34 (ExpandMask), returning y with
+// coefficients in (-γ₁, γ₁] stored modulo q.
+func expandMask(rhoPP []byte, kappa int, params *Params) *poly.Vec {
+	y := poly.NewVec(params.L)
+	buf := make([]byte, polyBytes(params.Gamma1Bits))
+	for r := 0; r < params.L; r++ {
+		nonce := uint16(kappa + r)
+		xof := sha3.NewShake256()
+		_, _ = xof.Write(rhoPP)
+		_, _ = xof.Write([]byte{byte(nonce), byte(nonce >> 8)})
+		_, _ = xof.Read(buf)
+
+		// BitUnpack(v, γ₁-1, γ₁) yields γ₁ - v.
+		acc, accBits, idx := uint64(0), 0, 0
+		mask := uint64(1)<<params.Gamma1Bits - 1
+		for j := 0; j < poly.N; j++ {
+			for accBits < params.Gamma1Bits {
+				acc |= uint64(buf[idx]) << accBits
+				accBits += 8
+				idx++
+			}
+			v := uint32(acc & mask)
+			acc >>= params.Gamma1Bits
+			accBits -= params.Gamma1Bits
+			y.Polys()[r].Coeffs[j] = poly.ModQ(uint32(params.Gamma1) + q - v)
+		}
+	}
+	return y
+}
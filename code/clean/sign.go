@@ -0,0 +1,315 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/hash"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// RndBytes is the length of the signing randomness rnd. Deterministic
+// signing uses 32 zero bytes.
+const RndBytes = 32
+
+// maxSignAttempts bounds the rejection-sampling loop. The expected number
+// of iterations is below 5 for every parameter set; hitting the bound
+// indicates a malformed secret key.
+const maxSignAttempts = 1000
+
+// Errors returned by the signing entry points.
+var (
+	ErrInvalidSecretKey  = errors.New("mldsa: invalid secret key format")
+	ErrInvalidRandomness = errors.New("mldsa: signing randomness must be 32 bytes")
+	ErrSignAttempts      = errors.New("mldsa: rejection sampling did not terminate")
+)
+
+// Sign implements ML-DSA.Sign_internal (FIPS 204 Algorithm 7) with msg used
+// directly as the formatted message M′. rnd must be 32 bytes; pass 32 zero
+// bytes for the deterministic variant.
+func Sign(sk, msg, rnd []byte) ([]byte, error) {
+	params, err := signParams(sk, rnd)
+	if err != nil {
+		return nil, err
+	}
+	return signFull(sk, msg, nil, rnd, params, nil, nil)
+}
+
+// SignWithContext implements ML-DSA.Sign (FIPS 204 Algorithm 2) with the
+// supplied randomness. ctx may be empty and is at most 255 bytes.
+func SignWithContext(sk, msg, ctx, rnd []byte) ([]byte, error) {
+	params, err := signParams(sk, rnd)
+	if err != nil {
+		return nil, err
+	}
+	mPrime, err := formatMessage(domainPure, ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return signFull(sk, mPrime, nil, rnd, params, nil, nil)
+}
+
+// SignPreHash implements HashML-DSA.Sign (FIPS 204 Algorithm 4) with the
+// supplied randomness and the named pre-hash function.
+func SignPreHash(sk, msg, ctx []byte, hashAlg string, rnd []byte) ([]byte, error) {
+	ph, err := preHashMessage(hashAlg, msg)
+	if err != nil {
+		return nil, err
+	}
+	params, err := signParams(sk, rnd)
+	if err != nil {
+		return nil, err
+	}
+	mPrime, err := formatMessage(domainPreHash, ctx, ph)
+	if err != nil {
+		return nil, err
+	}
+	return signFull(sk, mPrime, nil, rnd, params, nil, nil)
+}
+
+// SignExternalMu runs ML-DSA.Sign_internal on a caller-supplied message
+// representative μ, as exercised by ACVP "externalMu" groups.
+func SignExternalMu(sk, mu, rnd []byte) ([]byte, error) {
+	params, err := signParams(sk, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(mu) != CRHBytes {
+		return nil, ErrInvalidMu
+	}
+	return signFull(sk, nil, mu, rnd, params, nil, nil)
+}
+
+// PublicKeyFromSecretKey recomputes pk = pkEncode(ρ, t₁) from an encoded
+// secret key and checks it against the embedded tr = H(pk).
+func PublicKeyFromSecretKey(sk []byte) ([]byte, error) {
+	params, err := FromSecretKeyLength(len(sk))
+	if err != nil {
+		return nil, ErrInvalidSecretKey
+	}
+	rho, _, tr, s1, s2, _, err := unpackSecretKey(sk, params)
+	if err != nil {
+		return nil, err
+	}
+
+	a := expandA(rho, params)
+	if err := s1.NTT(); err != nil {
+		return nil, fmt.Errorf("mldsa: NTT s1: %w", err)
+	}
+	t1 := poly.NewVec(params.K)
+	for i := 0; i < params.K; i++ {
+		t := &poly.Poly{}
+		poly.PointwiseAccMontgomery(t, a[i], s1.Polys())
+		if err := poly.InvNTT(t); err != nil {
+			return nil, fmt.Errorf("mldsa: InvNTT t[%d]: %w", i, err)
+		}
+		t.Add(t, s2.Polys()[i])
+		for j, c := range t.Coeffs {
+			t1.Polys()[i].Coeffs[j], _ = power2Round(c)
+		}
+	}
+
+	pk, err := packPublicKey(rho, t1, params)
+	if err != nil {
+		return nil, err
+	}
+	check := make([]byte, CRHBytes)
+	hashPublicKey(check, pk)
+	for i := range check {
+		if check[i] != tr[i] {
+			return nil, fmt.Errorf("%w: tr does not match H(pk)", ErrInvalidSecretKey)
+		}
+	}
+	return pk, nil
+}
+
+// signParams performs the length checks shared by the signing entry points.
+func signParams(sk, rnd []byte) (*Params, error) {
+	params, err := FromSecretKeyLength(len(sk))
+	if err != nil {
+		return nil, ErrInvalidSecretKey
+	}
+	if len(rnd) != RndBytes {
+		return nil, ErrInvalidRandomness
+	}
+	return params, nil
+}
+
+// signFull implements ML-DSA.Sign_internal (FIPS 204 Algorithm 7).
+//
+// mPrime is the formatted message M′. When mu is non-nil it is taken as the
+// externally computed message representative μ and mPrime is ignored. A
+// non-nil fault injects a simulated fault (SignFaulty); a non-nil hook
+// receives the rejection-loop statistics (SignWithStats).
+func signFull(sk, mPrime, mu, rnd []byte, params *Params, fault *FaultPlan, hook StatsHook) ([]byte, error) {
+	// Step 1: (ρ, K, tr, s₁, s₂, t₀) = skDecode(sk)
+	rho, key, tr, s1, s2, t0, err := unpackSecretKey(sk, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Steps 2–4: ŝ₁, ŝ₂, t̂₀ and Â = ExpandA(ρ)
+	for _, v := range []*poly.Vec{s1, s2, t0} {
+		if err := v.NTT(); err != nil {
+			return nil, fmt.Errorf("mldsa: NTT secret key: %w", err)
+		}
+	}
+	a := expandA(rho, params)
+
+	// Step 6: μ = H(tr || M′, 64)
+	if mu == nil {
+		mu = make([]byte, CRHBytes)
+		hash.SumShake256(mu, tr, mPrime)
+	}
+
+	// Step 7: ρ″ = H(K || rnd || μ, 64)
+	if fault != nil && fault.Kind == FaultReuseRnd {
+		rnd = fault.stuckRnd()
+	}
+	rhoPP := make([]byte, CRHBytes)
+	hash.SumShake256(rhoPP, key, rnd, mu)
+
+	ctilde := make([]byte, params.CTildeBytes())
+	zBound := int32(params.Gamma1 - params.Beta)
+	r0Bound := int32(params.Gamma2 - params.Beta)
+	stats := newLoopStats(hook)
+
+	for attempt, kappa := 0, 0; attempt < maxSignAttempts; attempt, kappa = attempt+1, kappa+params.L {
+		// Steps 11–13: y = ExpandMask(ρ″, κ), w = NTT⁻¹(Â∘NTT(y)), w₁ = HighBits(w)
+		y := expandMask(rhoPP, kappa, params)
+		if fault.hits(FaultFlipY, attempt) {
+			yc := &y.Polys()[fault.Poly].Coeffs[fault.Coeff]
+			*yc = poly.ModQ(uint32(int64(*yc) + int64(fault.YDelta()) + q))
+		}
+		yHat := poly.NewVec(params.L)
+		_ = yHat.CopyFrom(y)
+		if err := yHat.NTT(); err != nil {
+			return nil, fmt.Errorf("mldsa: NTT y: %w", err)
+		}
+		w := poly.NewVec(params.K)
+		w1 := poly.NewVec(params.K)
+		for i := 0; i < params.K; i++ {
+			wi := w.Polys()[i]
+			poly.PointwiseAccMontgomery(wi, a[i], yHat.Polys())
+			if err := poly.InvNTT(wi); err != nil {
+				return nil, fmt.Errorf("mldsa: InvNTT w[%d]: %w", i, err)
+			}
+			for j, c := range wi.Coeffs {
+				w1.Polys()[i].Coeffs[j] = highBits(c, params.Gamma2)
+			}
+		}
+
+		// Steps 15–17: c̃ = H(μ || w1Encode(w₁), λ/4), c = SampleInBall(c̃)
+		hashChallenge(ctilde, mu, encodeW1(w1, params.DvBits), params.Tau)
+		if fault.hits(FaultCorruptC, attempt) {
+			ctilde[fault.Bit/8] ^= 1 << (fault.Bit % 8)
+		}
+		c := &poly.Poly{}
+		if err := sampleChallenge(c, ctilde, params.Tau); err != nil {
+			return nil, fmt.Errorf("mldsa: sample challenge: %w", err)
+		}
+		if err := poly.NTT(c); err != nil {
+			return nil, fmt.Errorf("mldsa: NTT c: %w", err)
+		}
+
+		// Steps 18–20: z = y + ⟨⟨c·s₁⟩⟩, reject if ||z||∞ ≥ γ₁ − β
+		z := poly.NewVec(params.L)
+		reject := false
+		skipChecks := fault.hits(FaultSkipNormCheck, attempt)
+		for i := 0; i < params.L && !reject; i++ {
+			zi := z.Polys()[i]
+			zi.PointwiseMontgomery(c, s1.Polys()[i])
+			if err := poly.InvNTT(zi); err != nil {
+				return nil, fmt.Errorf("mldsa: InvNTT cs1[%d]: %w", i, err)
+			}
+			zi.Add(zi, y.Polys()[i])
+			poly.Freeze(zi)
+			for _, coeff := range zi.Coeffs {
+				if infNorm(coeff) >= zBound && !skipChecks {
+					reject = true
+					break
+				}
+			}
+		}
+		if reject {
+			stats.reject(RejectZNorm)
+			continue
+		}
+
+		// Steps 19–23: r₀ = LowBits(w − ⟨⟨c·s₂⟩⟩) over all K polynomials
+		// before anything of ct₀, so a rejection is put down to the check
+		// FIPS 204 runs first.
+		r := make([]*poly.Poly, params.K)
+		for i := 0; i < params.K; i++ {
+			cs2 := &poly.Poly{}
+			cs2.PointwiseMontgomery(c, s2.Polys()[i])
+			if err := poly.InvNTT(cs2); err != nil {
+				return nil, fmt.Errorf("mldsa: InvNTT cs2[%d]: %w", i, err)
+			}
+			r[i] = &poly.Poly{}
+			r[i].Sub(w.Polys()[i], cs2)
+			poly.Freeze(r[i])
+			for _, coeff := range r[i].Coeffs {
+				if abs32(lowBits(coeff, params.Gamma2)) >= r0Bound && !skipChecks {
+					reject = true
+					break
+				}
+			}
+			if reject {
+				break
+			}
+		}
+		if reject {
+			stats.reject(RejectR0Norm)
+			continue
+		}
+
+		// Steps 25–28: ct₀ = ⟨⟨c·t₀⟩⟩ and h = MakeHint(−ct₀, w − cs₂ + ct₀)
+		h := make([][]bool, params.K)
+		hints := 0
+		for i := 0; i < params.K && !reject; i++ {
+			ct0 := &poly.Poly{}
+			ct0.PointwiseMontgomery(c, t0.Polys()[i])
+			if err := poly.InvNTT(ct0); err != nil {
+				return nil, fmt.Errorf("mldsa: InvNTT ct0[%d]: %w", i, err)
+			}
+			poly.Freeze(ct0)
+
+			h[i] = make([]bool, poly.N)
+			for j := 0; j < poly.N; j++ {
+				if infNorm(ct0.Coeffs[j]) >= int32(params.Gamma2) && !skipChecks {
+					reject = true
+					break
+				}
+				if makeHint(poly.ModQ(q-ct0.Coeffs[j]), poly.ModQ(r[i].Coeffs[j]+ct0.Coeffs[j]), params.Gamma2) {
+					h[i][j] = true
+					hints++
+				}
+			}
+		}
+		if reject {
+			stats.reject(RejectCT0Norm)
+			continue
+		}
+		if hints > params.Omega {
+			stats.reject(RejectHintCount)
+			continue
+		}
+
+		// Step 33: σ = sigEncode(c̃, z mod± q, h)
+		stats.done(attempt+1, true)
+		return packSignature(ctilde, z, h, params)
+	}
+	stats.done(maxSignAttempts, false)
+	return nil, ErrSignAttempts
+}
+
+func abs32(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
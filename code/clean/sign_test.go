@@ -0,0 +1,105 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x07}, SeedBytes)
+	rnd := bytes.Repeat([]byte{0x99}, RndBytes)
+	msg := []byte("round trip")
+	ctx := []byte("ctx")
+
+	for _, params := range []*Params{ParamsMLDSA44, ParamsMLDSA65, ParamsMLDSA87} {
+		t.Run(params.Name, func(t *testing.T) {
+			pk, sk, err := KeyGen(params, seed)
+			if err != nil {
+				t.Fatalf("KeyGen: %v", err)
+			}
+
+			sig, err := Sign(sk, msg, rnd)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if ok, err := Verify(pk, msg, sig); !ok || err != nil {
+				t.Fatalf("Verify internal: ok=%v err=%v", ok, err)
+			}
+
+			sig, err = SignWithContext(sk, msg, ctx, rnd)
+			if err != nil {
+				t.Fatalf("SignWithContext: %v", err)
+			}
+			if ok, err := VerifyWithContext(pk, msg, ctx, sig); !ok || err != nil {
+				t.Fatalf("VerifyWithContext: ok=%v err=%v", ok, err)
+			}
+			if ok, _ := VerifyWithContext(pk, msg, []byte("other"), sig); ok {
+				t.Fatal("signature verified under a different context")
+			}
+
+			sig, err = SignPreHash(sk, msg, ctx, "SHA2-512", rnd)
+			if err != nil {
+				t.Fatalf("SignPreHash: %v", err)
+			}
+			if ok, err := VerifyPreHash(pk, msg, ctx, "SHA2-512", sig); !ok || err != nil {
+				t.Fatalf("VerifyPreHash: ok=%v err=%v", ok, err)
+			}
+
+			mu := bytes.Repeat([]byte{0x5a}, CRHBytes)
+			sig, err = SignExternalMu(sk, mu, rnd)
+			if err != nil {
+				t.Fatalf("SignExternalMu: %v", err)
+			}
+			if ok, err := VerifyExternalMu(pk, mu, sig); !ok || err != nil {
+				t.Fatalf("VerifyExternalMu: ok=%v err=%v", ok, err)
+			}
+		})
+	}
+}
+
+func TestPublicKeyFromSecretKey(t *testing.T) {
+	pk, sk, err := KeyGen(ParamsMLDSA65, bytes.Repeat([]byte{0x01}, SeedBytes))
+	if err != nil {
+		t.Fatalf("KeyGen: %v", err)
+	}
+	got, err := PublicKeyFromSecretKey(sk)
+	if err != nil {
+		t.Fatalf("PublicKeyFromSecretKey: %v", err)
+	}
+	if !bytes.Equal(got, pk) {
+		t.Fatal("derived public key differs from KeyGen output")
+	}
+
+	sk[2*SeedBytes] ^= 0x01 // corrupt tr
+	if _, err := PublicKeyFromSecretKey(sk); !errors.Is(err, ErrInvalidSecretKey) {
+		t.Fatalf("err = %v, want ErrInvalidSecretKey", err)
+	}
+}
+
+func TestSignInputValidation(t *testing.T) {
+	_, sk, err := KeyGen(ParamsMLDSA44, make([]byte, SeedBytes))
+	if err != nil {
+		t.Fatalf("KeyGen: %v", err)
+	}
+	rnd := make([]byte, RndBytes)
+
+	if _, err := Sign(sk[:10], []byte("m"), rnd); !errors.Is(err, ErrInvalidSecretKey) {
+		t.Errorf("short sk: err = %v", err)
+	}
+	if _, err := Sign(sk, []byte("m"), rnd[:16]); !errors.Is(err, ErrInvalidRandomness) {
+		t.Errorf("short rnd: err = %v", err)
+	}
+	if _, err := SignWithContext(sk, []byte("m"), make([]byte, MaxContextBytes+1), rnd); !errors.Is(err, ErrContextTooLong) {
+		t.Errorf("long ctx: err = %v", err)
+	}
+	if _, err := SignPreHash(sk, []byte("m"), nil, "MD5", rnd); !errors.Is(err, ErrUnsupportedHash) {
+		t.Errorf("unknown hash: err = %v", err)
+	}
+	if _, err := SignExternalMu(sk, make([]byte, 32), rnd); !errors.Is(err, ErrInvalidMu) {
+		t.Errorf("short mu: err = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+// RejectReason names the check that rejected an iteration of the signing
+// loop (FIPS 204 Algorithm 7, steps 23 and 28).
+type RejectReason string
+
+// Rejection checks, in the order the signing loop runs them. Each check
+// covers every polynomial before the next one starts, so an iteration
+// failing several is put down to the first in this order, as in FIPS 204.
+const (
+	RejectZNorm     RejectReason = "z-norm"     // ‖z‖∞ ≥ γ₁ − β
+	RejectR0Norm    RejectReason = "r0-norm"    // ‖r₀‖∞ ≥ γ₂ − β
+	RejectCT0Norm   RejectReason = "ct0-norm"   // ‖ct₀‖∞ ≥ γ₂
+	RejectHintCount RejectReason = "hint-count" // more than ω hints
+)
+
+// RejectReasons lists the rejection checks in loop order.
+var RejectReasons = []RejectReason{RejectZNorm, RejectR0Norm, RejectCT0Norm, RejectHintCount}
+
+// SignStats is the rejection-loop record of one signing call.
+type SignStats struct {
+	Iterations int            // iterations run, the accepted one included
+	Rejections []RejectReason // why each rejected iteration was rejected, in order
+	Accepted   bool           // false if the loop gave up (ErrSignAttempts)
+}
+
+// StatsHook receives the SignStats of every signing call it is passed to.
+type StatsHook interface {
+	ObserveSign(s SignStats)
+}
+
+// SignWithStats is Sign reporting the rejection-loop statistics of the
+// call to hook.
+func SignWithStats(sk, msg, rnd []byte, hook StatsHook) ([]byte, error) {
+	params, err := signParams(sk, rnd)
+	if err != nil {
+		return nil, err
+	}
+	return signFull(sk, msg, nil, rnd, params, nil, hook)
+}
+
+// loopStats collects one call's SignStats; a nil *loopStats ignores
+// everything, so the loop needs no hook checks.
+type loopStats struct {
+	hook StatsHook
+	s    SignStats
+}
+
+func newLoopStats(hook StatsHook) *loopStats {
+	if hook == nil {
+		return nil
+	}
+	return &loopStats{hook: hook}
+}
+
+func (l *loopStats) reject(reason RejectReason) {
+	if l != nil {
+		l.s.Rejections = append(l.s.Rejections, reason)
+	}
+}
+
+// done reports the call to the hook after iterations iterations.
+func (l *loopStats) done(iterations int, accepted bool) {
+	if l != nil {
+		l.s.Iterations, l.s.Accepted = iterations, accepted
+		l.hook.ObserveSign(l.s)
+	}
+}
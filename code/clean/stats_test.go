@@ -0,0 +1,49 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"bytes"
+	"testing"
+)
+
+type statsLog []SignStats
+
+func (l *statsLog) ObserveSign(s SignStats) { *l = append(*l, s) }
+
+func TestSignWithStats(t *testing.T) {
+	for _, params := range []*Params{ParamsMLDSA44, ParamsMLDSA65, ParamsMLDSA87} {
+		_, sk, err := KeyGen(params, bytes.Repeat([]byte{0x50}, SeedBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var log statsLog
+		rejected := 0
+		for i := 0; i < 20; i++ {
+			msg := []byte{byte(i)}
+			rnd := bytes.Repeat([]byte{byte(i)}, RndBytes)
+			sig, err := SignWithStats(sk, msg, rnd, &log)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := Sign(sk, msg, rnd)
+			if err != nil || !bytes.Equal(sig, want) {
+				t.Fatalf("%s: SignWithStats differs from Sign: %v", params.Name, err)
+			}
+			s := log[len(log)-1]
+			if !s.Accepted || s.Iterations != len(s.Rejections)+1 {
+				t.Errorf("%s: %+v", params.Name, s)
+			}
+			for _, r := range s.Rejections {
+				if r != RejectZNorm && r != RejectR0Norm && r != RejectCT0Norm && r != RejectHintCount {
+					t.Errorf("%s: reason %q", params.Name, r)
+				}
+			}
+			rejected += len(s.Rejections)
+		}
+		if len(log) != 20 || rejected == 0 {
+			t.Errorf("%s: %d records, %d rejections", params.Name, len(log), rejected)
+		}
+	}
+}
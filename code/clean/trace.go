@@ -0,0 +1,121 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// TraceStep names an intermediate value of ML-DSA.Verify (FIPS 204
+// Algorithms 3 and 8).
+type TraceStep string
+
+// Trace steps, in the order verification produces them.
+const (
+	TraceMPrime      TraceStep = "m_prime"       // formatted message M′
+	TraceRho         TraceStep = "rho"           // ρ from pkDecode
+	TraceT1          TraceStep = "t1"            // t₁ from pkDecode
+	TraceCTilde      TraceStep = "c_tilde"       // c̃ from sigDecode
+	TraceZ           TraceStep = "z"             // z from sigDecode, centred
+	TraceHint        TraceStep = "h"             // h from sigDecode, 0/1
+	TraceAHat        TraceStep = "a_hat"         // Â = ExpandA(ρ), row-major k×l, NTT domain
+	TraceTr          TraceStep = "tr"            // tr = H(pk, 64)
+	TraceMu          TraceStep = "mu"            // μ = H(tr || M′, 64), or the external μ
+	TraceC           TraceStep = "c"             // c = SampleInBall(c̃), centred
+	TraceAz          TraceStep = "az"            // NTT⁻¹(Â∘NTT(z))
+	TraceCT1         TraceStep = "ct1_2d"        // NTT⁻¹(NTT(c)∘NTT(t₁·2^d))
+	TraceWApprox     TraceStep = "w_approx"      // w′approx = Az − ct₁·2^d
+	TraceW1          TraceStep = "w1"            // w′₁ = UseHint(h, w′approx)
+	TraceW1Encode    TraceStep = "w1_encode"     // w1Encode(w′₁)
+	TraceCTildePrime TraceStep = "c_tilde_prime" // c̃′ = H(μ || w1Encode(w′₁), λ/4)
+)
+
+// TraceSteps lists every step in the order verification reports them.
+var TraceSteps = []TraceStep{
+	TraceMPrime, TraceRho, TraceT1, TraceCTilde, TraceZ, TraceHint, TraceAHat, TraceTr, TraceMu,
+	TraceC, TraceAz, TraceCT1, TraceWApprox, TraceW1, TraceW1Encode, TraceCTildePrime,
+}
+
+// TraceValue is one traced value: a byte string in Bytes, or a vector of
+// polynomials in Polys with poly.N coefficients each. Coefficients are in
+// [0, q) unless the step is documented as centred or 0/1.
+type TraceValue struct {
+	Bytes []byte
+	Polys [][]int32
+}
+
+// Tracer receives the intermediate values of a verification in step
+// order. A verification that stops early (malformed hint, z out of range)
+// only reports the steps it reached. Values are copies and may be kept.
+type Tracer interface {
+	Trace(step TraceStep, v TraceValue)
+}
+
+// VerifyTraced is Verify on the full FIPS 204 path, reporting every
+// intermediate value to t. msg is used directly as M′; unlike Verify it
+// accepts an empty M′, as FIPS 204 does.
+func VerifyTraced(pk, msg, sig []byte, t Tracer) (bool, error) {
+	params, err := verifyParams(pk, sig)
+	if err != nil {
+		return false, err
+	}
+	return verifyFull(pk, msg, nil, sig, params, t)
+}
+
+// VerifyWithContextTraced is VerifyWithContext reporting every
+// intermediate value, M′ included, to t.
+func VerifyWithContextTraced(pk, msg, ctx, sig []byte, t Tracer) (bool, error) {
+	params, err := verifyParams(pk, sig)
+	if err != nil {
+		return false, err
+	}
+	mPrime, err := formatMessage(domainPure, ctx, msg)
+	if err != nil {
+		return false, err
+	}
+	return verifyFull(pk, mPrime, nil, sig, params, t)
+}
+
+func traceBytes(t Tracer, step TraceStep, b []byte) {
+	t.Trace(step, TraceValue{Bytes: append([]byte(nil), b...)})
+}
+
+// tracePolys reports ps with coefficients reduced to [0, q), or centred
+// around zero when centred is set.
+func tracePolys(t Tracer, step TraceStep, ps []*poly.Poly, centred bool) {
+	out := make([][]int32, len(ps))
+	for i, p := range ps {
+		out[i] = make([]int32, poly.N)
+		for j, c := range p.Coeffs {
+			if centred {
+				out[i][j] = poly.Canonical(c)
+			} else {
+				out[i][j] = int32(poly.ModQ(c))
+			}
+		}
+	}
+	t.Trace(step, TraceValue{Polys: out})
+}
+
+func traceHint(t Tracer, h [][]bool) {
+	out := make([][]int32, len(h))
+	for i, row := range h {
+		out[i] = make([]int32, len(row))
+		for j, set := range row {
+			if set {
+				out[i][j] = 1
+			}
+		}
+	}
+	t.Trace(TraceHint, TraceValue{Polys: out})
+}
+
+// invNTTCopy returns NTT⁻¹ of a copy of p, leaving p untouched.
+func invNTTCopy(p *poly.Poly) (*poly.Poly, error) {
+	cp := *p
+	if err := poly.InvNTT(&cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
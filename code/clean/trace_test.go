@@ -0,0 +1,136 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mldsa
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/hash"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+type recorder struct {
+	steps  []TraceStep
+	values map[TraceStep]TraceValue
+}
+
+func (r *recorder) Trace(step TraceStep, v TraceValue) {
+	if r.values == nil {
+		r.values = map[TraceStep]TraceValue{}
+	}
+	r.steps = append(r.steps, step)
+	r.values[step] = v
+}
+
+func TestVerifyTraced(t *testing.T) {
+	params := ParamsMLDSA44
+	pk, sk, err := KeyGen(params, bytes.Repeat([]byte{0x11}, SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, ctx := []byte("trace me"), []byte("ctx")
+	sig, err := SignWithContext(sk, msg, ctx, make([]byte, RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r recorder
+	if ok, err := VerifyWithContextTraced(pk, msg, ctx, sig, &r); !ok || err != nil {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	want := TraceSteps
+	if len(r.steps) != len(want) {
+		t.Fatalf("steps %v", r.steps)
+	}
+	for i := range want {
+		if r.steps[i] != want[i] {
+			t.Fatalf("step %d = %s, want %s", i, r.steps[i], want[i])
+		}
+	}
+
+	v := r.values
+	if !bytes.Equal(v[TraceCTildePrime].Bytes, v[TraceCTilde].Bytes) || !bytes.Equal(v[TraceRho].Bytes, pk[:SeedBytes]) {
+		t.Error("c̃′ or ρ mismatch")
+	}
+	tr := make([]byte, CRHBytes)
+	hash.SumShake256(tr, pk)
+	if !bytes.Equal(v[TraceTr].Bytes, tr) || len(v[TraceMu].Bytes) != CRHBytes {
+		t.Error("tr or μ mismatch")
+	}
+	if len(v[TraceW1Encode].Bytes) != params.K*poly.N*params.DvBits/8 {
+		t.Errorf("w1Encode length %d", len(v[TraceW1Encode].Bytes))
+	}
+
+	nonZero := 0
+	for _, c := range v[TraceC].Polys[0] {
+		if c != 0 {
+			nonZero++
+			if c != 1 && c != -1 {
+				t.Fatalf("challenge coefficient %d", c)
+			}
+		}
+	}
+	if nonZero != params.Tau {
+		t.Errorf("challenge weight %d, want %d", nonZero, params.Tau)
+	}
+
+	if len(v[TraceZ].Polys) != params.L || len(v[TraceAz].Polys) != params.K || len(v[TraceAHat].Polys) != params.K*params.L {
+		t.Fatalf("shapes z=%d Az=%d Â=%d", len(v[TraceZ].Polys), len(v[TraceAz].Polys), len(v[TraceAHat].Polys))
+	}
+	for i := 0; i < params.K; i++ {
+		for j := 0; j < poly.N; j++ {
+			diff := (v[TraceAz].Polys[i][j] - v[TraceCT1].Polys[i][j] + poly.Q) % poly.Q
+			if diff != v[TraceWApprox].Polys[i][j] {
+				t.Fatalf("Az − ct1·2^d ≠ w′approx at [%d][%d]", i, j)
+			}
+		}
+	}
+}
+
+// Verify refuses an empty message; the traced path follows FIPS 204 and
+// accepts a valid signature on one.
+func TestVerifyTracedEmptyMessage(t *testing.T) {
+	pk, sk, err := KeyGen(ParamsMLDSA44, bytes.Repeat([]byte{0x22}, SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := Sign(sk, nil, make([]byte, RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r recorder
+	if ok, err := VerifyTraced(pk, nil, sig, &r); !ok || err != nil {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if len(r.steps) != len(TraceSteps) {
+		t.Errorf("steps %v", r.steps)
+	}
+}
+
+func TestVerifyTracedStopsAtMalformedHint(t *testing.T) {
+	params := ParamsMLDSA44
+	pk, sk, err := KeyGen(params, bytes.Repeat([]byte{0x22}, SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("hint")
+	sig, err := Sign(sk, msg, make([]byte, RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[len(sig)-1] = byte(params.Omega + 1) // last hint counter beyond ω
+
+	var r recorder
+	if _, err := VerifyTraced(pk, msg, sig, &r); !errors.Is(err, ErrMalformedHint) {
+		t.Fatalf("err = %v", err)
+	}
+	if got := r.steps[len(r.steps)-1]; got != TraceZ {
+		t.Errorf("last step %s, want z", got)
+	}
+	if _, ok := r.values[TraceHint]; ok {
+		t.Error("malformed hint was traced")
+	}
+}
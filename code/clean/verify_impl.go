@@ -8,227 +8,156 @@ import (
 	"fmt"
 
 	"github.com/codethor0/dilivet/code/hash"
-	"github.com/codethor0/dilivet/code/pack"
 	"github.com/codethor0/dilivet/code/poly"
 	"golang.org/x/crypto/sha3"
 )
 
-// verifyFull implements full FIPS 204 Algorithm 3 (Signature Verification).
-func verifyFull(pk, msg, sig []byte, params *Params) (bool, error) {
-	// Step 1: Parse public key (ρ, t₁)
-	// Public key format: ρ (32 bytes) || t₁ (compressed, k * n * du bits)
-	if len(pk) < SeedBytes {
-		return false, ErrInvalidPublicKey
+// verifyFull implements ML-DSA.Verify_internal (FIPS 204 Algorithm 8).
+//
+// mPrime is the formatted message M′. When mu is non-nil it is taken as the
+// externally computed message representative μ and mPrime is ignored.
+// A non-nil t receives every intermediate value; with a nil t no copies
+// are made.
+func verifyFull(pk, mPrime, mu, sig []byte, params *Params, t Tracer) (bool, error) {
+	if t != nil && mu == nil {
+		traceBytes(t, TraceMPrime, mPrime)
 	}
-	rho := pk[:SeedBytes]
-	t1Bytes := pk[SeedBytes:]
 
-	// Step 2: Decompress signature (c̃, z, h)
-	// Signature format: c̃ (tau bytes) || z (k * n * gamma1Bits bits) || h (omega bytes + padding)
-	tauBytes := (params.Tau + 7) / 8
-	if len(sig) < tauBytes {
-		return false, ErrInvalidSignature
+	// Step 1: Parse public key (ρ, t₁)
+	rho, t1, err := unpackPublicKey(pk, params)
+	if err != nil {
+		return false, err
 	}
-	ctilde := sig[:tauBytes]
-
-	// Unpack z vector (k polynomials, each gamma1Bits per coefficient)
-	zStart := tauBytes
-	zBytes := (params.K*poly.N*params.Gamma1Bits + 7) / 8
-	if len(sig) < zStart+zBytes {
-		return false, ErrInvalidSignature
+	if t != nil {
+		traceBytes(t, TraceRho, rho)
+		tracePolys(t, TraceT1, t1.Polys(), false)
 	}
-	zData := sig[zStart : zStart+zBytes]
 
-	zVec := poly.NewVec(params.K)
-	for i := 0; i < params.K; i++ {
-		offset := (i*poly.N*params.Gamma1Bits + 7) / 8
-		if offset+((poly.N*params.Gamma1Bits+7)/8) > len(zData) {
-			return false, ErrInvalidSignature
+	// Step 2: Decode signature (c̃, z, h); a malformed hint is ⊥
+	ctilde, z, h, err := unpackSignature(sig, params)
+	if t != nil && ctilde != nil {
+		traceBytes(t, TraceCTilde, ctilde)
+		tracePolys(t, TraceZ, z.Polys(), true)
+		if h != nil {
+			traceHint(t, h)
 		}
-		p, err := pack.UnpackPolyLeGamma1(zData[offset:], params.Gamma1Bits)
-		if err != nil {
-			return false, fmt.Errorf("mldsa: unpack z[%d]: %w", i, err)
-		}
-		// Check ||z||∞ < γ₁
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Reject ||z||∞ ≥ γ₁ - β before doing any heavy arithmetic
+	bound := int32(params.Gamma1 - params.Beta)
+	for _, p := range z.Polys() {
 		for _, coeff := range p.Coeffs {
-			canon := poly.Canonical(coeff)
-			if canon < 0 {
-				canon = -canon
-			}
-			if int32(canon) >= int32(params.Gamma1) {
-				return false, ErrInvalidSignature
+			if infNorm(coeff) >= bound {
+				return false, ErrZOutOfRange
 			}
 		}
-		zVec.Polys()[i] = p
 	}
 
-	// Unpack hint h
-	hStart := zStart + zBytes
-	hData := sig[hStart:]
-	h, err := pack.UnpackHint(hData, params.Omega)
-	if err != nil {
-		return false, fmt.Errorf("mldsa: unpack hint: %w", err)
-	}
-	if len(h) > params.Omega {
-		return false, ErrInvalidSignature
+	// Step 3: Expand Â from ρ (already in the NTT domain)
+	a := expandA(rho, params)
+	if t != nil {
+		var rows []*poly.Poly
+		for _, row := range a {
+			rows = append(rows, row...)
+		}
+		tracePolys(t, TraceAHat, rows, false)
 	}
 
-	// Step 3: Expand matrix A from seed ρ
-	// A is a k×l matrix of polynomials
-	// For now, we'll expand it on-the-fly as needed
-	// TODO: Cache A if performance requires it
+	// Step 4: tr = H(pk, 64) and μ = H(tr || M′, 64)
+	if mu == nil {
+		tr := make([]byte, CRHBytes)
+		hashPublicKey(tr, pk)
+		mu = make([]byte, CRHBytes)
+		hash.SumShake256(mu, tr, mPrime)
+		if t != nil {
+			traceBytes(t, TraceTr, tr)
+		}
+	}
+	if t != nil {
+		traceBytes(t, TraceMu, mu)
+	}
 
-	// Step 4: Sample challenge polynomial c from c̃
+	// Step 5: c = SampleInBall(c̃)
 	c := &poly.Poly{}
 	if err := sampleChallenge(c, ctilde, params.Tau); err != nil {
 		return false, fmt.Errorf("mldsa: sample challenge: %w", err)
 	}
+	if t != nil {
+		tracePolys(t, TraceC, []*poly.Poly{c}, true)
+	}
+	if err := poly.NTT(c); err != nil {
+		return false, fmt.Errorf("mldsa: NTT c: %w", err)
+	}
 
-	// Step 5: Compute μ = CRH(tr || msg) where tr = H(pk)
-	tr := make([]byte, CRHBytes)
-	hashPublicKey(tr, pk)
-	mu := make([]byte, CRHBytes)
-	hash.SumShake256(mu, tr, msg)
-
-	// Step 6: Compute w'₁ = UseHint(h, Az - c·t₁·2^d, 2γ₂)
-	// First, compute Az
-	azVec := poly.NewVec(params.K)
+	// Step 6: w′approx = NTT⁻¹(Â∘NTT(z) − NTT(c)∘NTT(t₁·2^d))
+	if err := z.NTT(); err != nil {
+		return false, fmt.Errorf("mldsa: NTT z: %w", err)
+	}
+	w1Prime := poly.NewVec(params.K)
+	var az, ct1, wApprox []*poly.Poly
 	for i := 0; i < params.K; i++ {
-		azVec.Polys()[i] = &poly.Poly{}
+		w := &poly.Poly{}
+		prod := &poly.Poly{}
 		for j := 0; j < params.L; j++ {
-			// Expand A[i][j] from rho
-			aij := &poly.Poly{}
-			nonce := uint16(i*params.L + j)
-			if err := poly.SamplePolyUniform(aij, rho, nonce); err != nil {
-				return false, fmt.Errorf("mldsa: expand A[%d][%d]: %w", i, j, err)
-			}
-			// Convert to NTT domain
-			if err := poly.NTT(aij); err != nil {
-				return false, fmt.Errorf("mldsa: NTT A[%d][%d]: %w", i, j, err)
-			}
-
-			// z[j] in NTT
-			zjNTT := &poly.Poly{}
-			copy(zjNTT.Coeffs[:], zVec.Polys()[j].Coeffs[:])
-			if err := poly.NTT(zjNTT); err != nil {
-				return false, fmt.Errorf("mldsa: NTT z[%d]: %w", j, err)
-			}
-
-			// Multiply and accumulate: az[i] += A[i][j] * z[j]
-			prod := &poly.Poly{}
-			prod.PointwiseMontgomery(aij, zjNTT)
-			azVec.Polys()[i].Add(azVec.Polys()[i], prod)
+			prod.PointwiseMontgomery(a[i][j], z.Polys()[j])
+			w.Add(w, prod)
 		}
-		// Convert back from NTT
-		if err := poly.InvNTT(azVec.Polys()[i]); err != nil {
-			return false, fmt.Errorf("mldsa: InvNTT az[%d]: %w", i, err)
+		if t != nil {
+			azi, err := invNTTCopy(w)
+			if err != nil {
+				return false, fmt.Errorf("mldsa: InvNTT Az[%d]: %w", i, err)
+			}
+			az = append(az, azi)
 		}
-	}
 
-	// Compute c·t₁·2^d
-	// First, unpack t₁
-	t1Vec := poly.NewVec(params.K)
-	for i := 0; i < params.K; i++ {
-		offset := (i*poly.N*params.DuBits + 7) / 8
-		if offset+((poly.N*params.DuBits+7)/8) > len(t1Bytes) {
-			return false, ErrInvalidPublicKey
+		t1i := t1.Polys()[i]
+		for j := range t1i.Coeffs {
+			t1i.Coeffs[j] <<= d
 		}
-		t1i, err := pack.UnpackBits(t1Bytes[offset:], params.DuBits, poly.N)
-		if err != nil {
-			return false, fmt.Errorf("mldsa: unpack t1[%d]: %w", i, err)
+		if err := poly.NTT(t1i); err != nil {
+			return false, fmt.Errorf("mldsa: NTT t1[%d]: %w", i, err)
 		}
-		t1Poly := &poly.Poly{}
-		for j := 0; j < poly.N; j++ {
-			t1Poly.Coeffs[j] = t1i[j] << d // Multiply by 2^d
+		prod.PointwiseMontgomery(c, t1i)
+		w.Sub(w, prod)
+		if err := poly.InvNTT(w); err != nil {
+			return false, fmt.Errorf("mldsa: InvNTT w[%d]: %w", i, err)
 		}
-		t1Vec.Polys()[i] = t1Poly
-	}
-
-	// Convert c and t1Vec to NTT
-	cNTT := &poly.Poly{}
-	copy(cNTT.Coeffs[:], c.Coeffs[:])
-	if err := poly.NTT(cNTT); err != nil {
-		return false, fmt.Errorf("mldsa: NTT c: %w", err)
-	}
-
-	for i := 0; i < params.K; i++ {
-		if err := poly.NTT(t1Vec.Polys()[i]); err != nil {
-			return false, fmt.Errorf("mldsa: NTT t1[%d]: %w", i, err)
+		if t != nil {
+			ct1i, err := invNTTCopy(prod)
+			if err != nil {
+				return false, fmt.Errorf("mldsa: InvNTT ct1[%d]: %w", i, err)
+			}
+			ct1 = append(ct1, ct1i)
+			wApprox = append(wApprox, w)
 		}
-	}
 
-	// Compute c·t₁
-	ct1Vec := poly.NewVec(params.K)
-	for i := 0; i < params.K; i++ {
-		ct1Vec.Polys()[i] = &poly.Poly{}
-		ct1Vec.Polys()[i].PointwiseMontgomery(cNTT, t1Vec.Polys()[i])
-		if err := poly.InvNTT(ct1Vec.Polys()[i]); err != nil {
-			return false, fmt.Errorf("mldsa: InvNTT ct1[%d]: %w", i, err)
+		// Step 7: w′₁ = UseHint(h, w′approx)
+		for j := range w.Coeffs {
+			w1Prime.Polys()[i].Coeffs[j] = applyHint(h[i][j], w.Coeffs[j], params.Gamma2)
 		}
 	}
 
-	// Compute w = az - ct1
-	wVec := poly.NewVec(params.K)
-	for i := 0; i < params.K; i++ {
-		wVec.Polys()[i] = &poly.Poly{}
-		wVec.Polys()[i].Sub(azVec.Polys()[i], ct1Vec.Polys()[i])
-	}
-
-	// Apply hints to get w'₁
-	w1Prime := poly.NewVec(params.K)
-	for i := 0; i < params.K; i++ {
-		w1Prime.Polys()[i] = &poly.Poly{}
-		if err := useHint(w1Prime.Polys()[i], wVec.Polys()[i], h, i, params.Gamma2); err != nil {
-			return false, fmt.Errorf("mldsa: useHint w[%d]: %w", i, err)
-		}
+	if t != nil {
+		tracePolys(t, TraceAz, az, false)
+		tracePolys(t, TraceCT1, ct1, false)
+		tracePolys(t, TraceWApprox, wApprox, false)
+		tracePolys(t, TraceW1, w1Prime.Polys(), false)
 	}
 
-	// Step 7: Encode w'₁ and compute c' = H(μ || w₁Encode(w'₁))
+	// Step 8: c̃′ = H(μ || w1Encode(w′₁), λ/4)
 	w1Encoded := encodeW1(w1Prime, params.DvBits)
-	cPrime := make([]byte, tauBytes)
+	cPrime := make([]byte, len(ctilde))
 	hashChallenge(cPrime, mu, w1Encoded, params.Tau)
-
-	// Step 8: Constant-time comparison
-	return subtle.ConstantTimeCompare(ctilde, cPrime) == 1, nil
-}
-
-// sampleChallenge samples a challenge polynomial c with exactly tau coefficients set to ±1.
-func sampleChallenge(c *poly.Poly, ctilde []byte, tau int) error {
-	// Use SHAKE256 to expand ctilde into indices
-	xof := sha3.NewShake256()
-	if _, err := xof.Write(ctilde); err != nil {
-		return err
+	if t != nil {
+		traceBytes(t, TraceW1Encode, w1Encoded)
+		traceBytes(t, TraceCTildePrime, cPrime)
 	}
 
-	// Sample tau distinct indices in [0, 2n)
-	indices := make(map[uint16]bool)
-	buf := make([]byte, 2)
-	for len(indices) < tau {
-		if _, err := xof.Read(buf); err != nil {
-			return err
-		}
-		idx := uint16(buf[0]) | (uint16(buf[1]) << 8)
-		idx %= 2 * poly.N
-		if !indices[idx] {
-			indices[idx] = true
-		}
-	}
-
-	// Set coefficients: c[i] = 1 if i in indices and sign bit set, else -1
-	for i := range c.Coeffs {
-		c.Coeffs[i] = 0
-	}
-	for idx := range indices {
-		pos := idx % poly.N
-		sign := (idx / poly.N) & 1
-		if sign == 0 {
-			c.Coeffs[pos] = 1
-		} else {
-			c.Coeffs[pos] = poly.Q - 1 // -1 mod q
-		}
-	}
-
-	return nil
+	// Step 9: Constant-time comparison
+	return subtle.ConstantTimeCompare(ctilde, cPrime) == 1, nil
 }
 
 // hashPublicKey computes tr = H(pk) using SHAKE256.
@@ -236,28 +165,6 @@ func hashPublicKey(tr []byte, pk []byte) {
 	hash.SumShake256(tr, pk)
 }
 
-// useHint applies the hint h to reconstruct w'₁ from w.
-// The hint vector h contains indices of coefficients that need adjustment.
-// For each coefficient i, if i is in h, we adjust the decomposition.
-func useHint(w1Prime *poly.Poly, w *poly.Poly, h []uint8, vecIdx int, gamma2 int) error {
-	// Build a set of hint indices for O(1) lookup
-	hintSet := make(map[uint8]bool, len(h))
-	for _, idx := range h {
-		if int(idx) >= poly.N {
-			return fmt.Errorf("mldsa: invalid hint index %d (>= %d)", idx, poly.N)
-		}
-		hintSet[idx] = true
-	}
-
-	// Apply hint logic to each coefficient
-	for i := range w1Prime.Coeffs {
-		hasHint := hintSet[uint8(i)]
-		w1Prime.Coeffs[i] = useHintForCoeff(w.Coeffs[i], hasHint, gamma2)
-	}
-
-	return nil
-}
-
 // useHintForCoeff applies hint logic to a single coefficient.
 // According to FIPS 204, if hasHint is true, it means |r0| > gamma2 and we need to adjust r1.
 // The correct reconstruction: if r0 > gamma2, r1' = r1 + 1; if r0 < -gamma2, r1' = r1 - 1.
@@ -300,7 +207,6 @@ func useHintForCoeff(r uint32, hasHint bool, gamma2 int) uint32 {
 	return uint32(adjustedR1)
 }
 
-
 // encodeW1 encodes w'₁ into a byte string for hashing.
 func encodeW1(w1Prime *poly.Vec, dvBits int) []byte {
 	// Encode each polynomial in w1Prime using dvBits per coefficient
@@ -328,9 +234,9 @@ func encodeW1(w1Prime *poly.Vec, dvBits int) []byte {
 	return encoded
 }
 
-// hashChallenge computes c' = H(μ || w₁Encoded) and outputs tau bytes.
+// hashChallenge computes c' = H(μ || w₁Encoded) and fills cPrime (λ/4 bytes).
 func hashChallenge(cPrime []byte, mu []byte, w1Encoded []byte, tau int) {
-	// Use SHAKE256 and take first tau bytes
+	// Use SHAKE256 and take the first len(cPrime) bytes
 	xof := sha3.NewShake256()
 	_, _ = xof.Write(mu)
 	_, _ = xof.Write(w1Encoded)
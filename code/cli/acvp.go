@@ -0,0 +1,171 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/codethor0/dilivet/code/acvp"
+	"github.com/codethor0/dilivet/code/acvp/acvptest"
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+func (a *App) runACVPRespond(args []string) int {
+	fs := flag.NewFlagSet("acvp-respond", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	promptPath := fs.String("prompt", "", "ACVP prompt JSON (keyGen, sigGen or sigVer)")
+	outPath := fs.String("out", "", "write the response to this file (default: stdout)")
+	impl := fs.String("impl", "", "external implementation binary (default: built-in)")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-case timeout for -impl")
+	limitFlags := addImplFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "acvp-respond: unexpected positional arguments")
+		return 1
+	}
+	if *promptPath == "" {
+		fmt.Fprintln(a.Err, "acvp-respond: -prompt is required")
+		return 1
+	}
+
+	var target kats.Implementation = kats.Builtin{}
+	if *impl != "" {
+		target = kats.Exec{Bin: execsign.Bin{Path: *impl, Timeout: *timeout, Limits: limitFlags.limits()}}
+	}
+
+	responses, err := kats.RespondFile(filepath.Clean(*promptPath), target)
+	if err != nil {
+		fmt.Fprintf(a.Err, "acvp-respond: %v\n", err)
+		return 1
+	}
+
+	// ACVP exchanges are arrays led by a version object.
+	doc := []any{map[string]string{"acvVersion": kats.ACVPVersion}}
+	for _, r := range responses {
+		doc = append(doc, r)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fmt.Fprintf(a.Err, "acvp-respond: encode response: %v\n", err)
+		return 1
+	}
+	data = append(data, '\n')
+
+	if *outPath == "" {
+		_, _ = a.Out.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*outPath, data, 0o644); err != nil {
+		fmt.Fprintf(a.Err, "acvp-respond: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(a.Out, "acvp-respond: wrote %d vector set response(s) to %s\n", len(responses), *outPath)
+	return 0
+}
+
+func (a *App) runACVP(args []string) int {
+	if len(args) == 0 || args[0] != "run" {
+		fmt.Fprintln(a.Err, "acvp: expected subcommand \"run\"")
+		return 1
+	}
+
+	fs := flag.NewFlagSet("acvp run", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	configPath := fs.String("config", "acvp.json", "ACVP run configuration")
+	mock := fs.Bool("mock", false, "run against an in-process mock server serving the bundled vectors")
+	timeout := fs.Duration("timeout", time.Hour, "give up on the session after this long, including result polling")
+	limitFlags := addImplFlags(fs)
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON report")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "acvp run: unexpected positional arguments")
+		return 1
+	}
+
+	if *timeout <= 0 {
+		fmt.Fprintln(a.Err, "acvp run: -timeout must be positive")
+		return 1
+	}
+
+	cfg, err := acvp.ReadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "acvp run: %v\n", err)
+		return 1
+	}
+	if *mock {
+		srv, err := acvptest.NewServer()
+		if err != nil {
+			fmt.Fprintf(a.Err, "acvp run: start mock server: %v\n", err)
+			return 1
+		}
+		defer srv.Close()
+		cfg.ServerURL, cfg.CertFile, cfg.KeyFile, cfg.TOTPSeedFile = srv.URL, "", "", ""
+	}
+	// Checked only now, so that -mock needs no serverUrl.
+	if err := cfg.Fill(); err != nil {
+		fmt.Fprintf(a.Err, "acvp run: %v\n", err)
+		return 1
+	}
+
+	client, err := acvp.NewClient(cfg)
+	if err != nil {
+		fmt.Fprintf(a.Err, "acvp run: %v\n", err)
+		return 1
+	}
+	var target kats.Implementation = kats.Builtin{}
+	if cfg.Impl != "" {
+		target = kats.Exec{Bin: execsign.Bin{Path: cfg.Impl, Timeout: cfg.Timeout.Duration, Limits: limitFlags.limits()}}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	report, err := acvp.Run(ctx, client, cfg, target)
+	if err != nil {
+		fmt.Fprintf(a.Err, "acvp run: %v\n", err)
+		return 1
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(a.Err, "acvp run: encode report: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(a.Out, "ACVP session %s\n", report.SessionURL)
+		for _, vs := range report.VectorSets {
+			fmt.Fprintf(a.Out, "  vsId=%d %s: %s\n", vs.VectorSetID, vs.Mode, vs.Status)
+		}
+		fmt.Fprintf(a.Out, "Prompts and responses: %s\n", report.WorkDir)
+		verdict := "PASSED"
+		if !report.Passed {
+			verdict = "FAILED"
+		}
+		fmt.Fprintf(a.Out, "Session %s\n", verdict)
+	}
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
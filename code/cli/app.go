@@ -72,6 +72,40 @@ func (a *App) Run(args []string) int {
 			return a.runVerify(args)
 		case "kat-verify":
 			return a.runKATVerify(args)
+		case "kat-keygen":
+			return a.runKATKeyGen(args)
+		case "kat-siggen":
+			return a.runKATSigGen(args)
+		case "kat-rsp":
+			return a.runKATRSP(args)
+		case "wycheproof":
+			return a.runWycheproof(args)
+		case "explain":
+			return a.runExplain(args)
+		case "trace-diff":
+			return a.runTraceDiff(args)
+		case "mutate":
+			return a.runMutate(args)
+		case "diff-impl":
+			return a.runDiffImpl(args)
+		case "fuzz-target":
+			return a.runFuzzTarget(args)
+		case "corpus":
+			return a.runCorpus(args)
+		case "vet":
+			return a.runVet(args)
+		case "ct-test":
+			return a.runCTTest(args)
+		case "fault-sim":
+			return a.runFaultSim(args)
+		case "corpus-analyze":
+			return a.runCorpusAnalyze(args)
+		case "sign-stats":
+			return a.runSignStats(args)
+		case "acvp-respond":
+			return a.runACVPRespond(args)
+		case "acvp":
+			return a.runACVP(args)
 		default:
 			fmt.Fprintf(a.Err, "unknown command %q\n", cmd)
 			return 1
@@ -97,7 +131,27 @@ USAGE:
 
 COMMANDS:
     verify      Validate an ML-DSA signature against a public key
-    kat-verify  Dry-run ACVP sigVer KAT vectors through structural checks
+    kat-verify  Verify ACVP sigVer KAT vectors and compare with expected results
+    kat-keygen  Derive key pairs for ACVP keyGen vectors and compare bytes
+    kat-siggen  Sign ACVP sigGen vectors and compare or verify, per group
+    kat-rsp     Replay a NIST PQC .rsp KAT file (DRBG-seeded keygen and signing)
+    wycheproof  Run a Wycheproof-style adversarial suite against the verifier
+    explain     Trace a verification step by step (ρ, t₁, z, h, μ, c, w′₁, c̃′)
+    trace-diff  Compare two verification traces and find the first divergence
+    mutate      Derive labeled negative vectors from one valid signature
+    diff-impl   Differential-test implementations and print an interop matrix
+    fuzz-target Mutation-fuzz an external verifier against the built-in one
+    corpus export|minimize
+                Turn Go fuzz corpus entries into vectors, or shrink one
+    vet         Grade an implementation: ACVP, edge, mutation and timing checks
+    ct-test     dudect-style constant-time leakage test (Welch's t-test)
+    fault-sim   Inject signing faults and check for key-recovery leakage
+    corpus-analyze
+                Check signatures under one key for nonce reuse and skew
+    sign-stats  Compare signing-loop rejections with their theoretical rates
+    acvp-respond
+                Answer an ACVP prompt file and write the response JSON
+    acvp run    Run a full ACVP session (login, vector sets, submit, verdict)
 
 OPTIONS:
     -version    Print version and exit
@@ -111,7 +165,64 @@ EXAMPLES:
         Verify a signature using hex-encoded key/signature files
 
     %s kat-verify
-        Run structural checks across the bundled ACVP sigVer vectors
+        Verify the bundled ACVP sigVer vectors against their expected results
+
+    %s kat-verify -format junit > kat-verify.xml
+        Write per-case results as JUnit XML (or -format sarif) for CI
+
+    %s kat-keygen -impl ./my-keygen
+        Compare an external implementation against the ACVP keyGen vectors
+
+    %s kat-siggen
+        Sign the bundled ACVP sigGen vectors and report results per test group
+
+    %s kat-rsp -rsp PQCsignKAT_Dilithium2.rsp
+        Check keygen and signing against a pq-crystals style KAT file
+
+    %s wycheproof -suite cases.json
+        Check accept/reject/error verdicts for adversarial ML-DSA inputs
+
+    %s explain -pub pk.hex -sig sig.hex -msg msg.bin -json
+        Dump every FIPS 204 intermediate value to find where a signature diverges
+
+    %s trace-diff ours.json theirs.json
+        Point to the first intermediate value where a vendor trace differs
+
+    %s mutate -pub pk.hex -sig sig.hex -msg msg.bin -out suite.json
+        Write structured signature mutations as a suite (or -format acvp)
+
+    %s diff-impl -impl ref=./ref -impl vendor=./vendor
+        Compare implementations on KAT, mutated and random inputs
+
+    %s fuzz-target -impl ./vendor-verify -duration 1h
+        Hunt crashes, hangs and verdict mismatches; save reproducers
+
+    %s corpus export -format wycheproof -out corpus-suite.json
+        Replay fuzz/testdata/fuzz entries and record verdict and stage
+
+    %s corpus minimize -in fuzz/testdata/fuzz/FuzzVerify/0123abcd
+        Shrink a corpus entry while it fails at the same stage
+
+    %s vet -impl ./vendor -format html -out scorecard.html
+        Grade a vendor library and write a self-contained HTML scorecard
+
+    %s ct-test -target ntt,verify -measurements 100000
+        Check poly arithmetic and Verify for fixed-vs-random timing leaks
+
+    %s fault-sim -impl ./vendor-faulty -session -countermeasure verify-after-sign
+        Check whether faulty signatures leak s₁ past verify-after-sign
+
+    %s corpus-analyze -pub device.pk -in signatures.jsonl
+        Look for repeated w1, duplicate signatures and skewed z, hints or c̃
+
+    %s sign-stats -n 100000
+        Check the rejection loop of every parameter set against the model
+
+    %s acvp-respond -prompt prompt.json -impl ./my-signer -out response.json
+        Run an implementation over an ACVP prompt and write the response
+
+    %s acvp run -config acvp.json -mock
+        Exercise a full ACVP session against the in-process mock server
 
 DOCUMENTATION:
     GitHub: https://github.com/codethor0/dilivet
@@ -119,5 +230,5 @@ DOCUMENTATION:
 
 LICENSE:
     MIT License - see LICENSE file for details
-`, a.Name, a.Version, a.Name, a.Name, a.Name, a.Name)
+`, a.Name, a.Version, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name, a.Name)
 }
@@ -11,6 +11,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/kat"
 )
 
 func TestApp_Version(t *testing.T) {
@@ -266,3 +271,275 @@ func TestApp_KATVerifyCommand(t *testing.T) {
 		t.Fatalf("unexpected stdout: %q", out.String())
 	}
 }
+
+func TestApp_KATKeyGenCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{
+		Name:    "dilivet",
+		Version: "dev",
+		Out:     &out,
+		Err:     &errOut,
+	}
+
+	if exitCode := app.Run([]string{"kat-keygen", "-json"}); exitCode != 0 {
+		t.Fatalf("kat-keygen exit = %d, stderr=%q, stdout=%q", exitCode, errOut.String(), out.String())
+	}
+
+	var payload struct {
+		diag.Report
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if payload.TotalTests == 0 || payload.StrictPasses != payload.TotalTests {
+		t.Fatalf("unexpected summary: %+v", payload)
+	}
+	if payload.SchemaVersion != diag.SchemaVersion || len(payload.ByParameterSet) != 3 || len(payload.ByGroup) == 0 ||
+		payload.Durations == nil || payload.Durations.Count != payload.TotalTests {
+		t.Errorf("report breakdowns: %+v", payload.Report)
+	}
+	if env := payload.Environment; env == nil || env.Version != "dev" || len(env.VectorFiles) != 1 || len(env.VectorFiles[0].SHA256) != 64 {
+		t.Errorf("environment: %+v", env)
+	}
+}
+
+func TestApp_KATSigGenCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{
+		Name:    "dilivet",
+		Version: "dev",
+		Out:     &out,
+		Err:     &errOut,
+	}
+
+	if exitCode := app.Run([]string{"kat-siggen"}); exitCode != 0 {
+		t.Fatalf("kat-siggen exit = %d, stderr=%q, stdout=%q", exitCode, errOut.String(), out.String())
+	}
+	for _, want := range []string{"Total tests:", "tgId=1 ML-DSA-44 internal deterministic", "external/pure hedged"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("stdout missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestApp_ACVPRespondCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{
+		Name:    "dilivet",
+		Version: "dev",
+		Out:     &out,
+		Err:     &errOut,
+	}
+
+	outPath := filepath.Join(t.TempDir(), "response.json")
+	args := []string{"acvp-respond", "-prompt", defaultSigVerVectors, "-out", outPath}
+	if exitCode := app.Run(args); exitCode != 0 {
+		t.Fatalf("acvp-respond exit = %d, stderr=%q", exitCode, errOut.String())
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	var doc []map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("response is not a JSON array: %v", err)
+	}
+	if len(doc) != 2 || doc[0]["acvVersion"] != "1.0" {
+		t.Fatalf("want version header plus one vector set, got %d elements", len(doc))
+	}
+	set := doc[1]
+	if set["mode"] != "sigVer" || set["vsId"] == nil {
+		t.Fatalf("unexpected vector set header: mode=%v vsId=%v", set["mode"], set["vsId"])
+	}
+	groups, _ := set["testGroups"].([]any)
+	if len(groups) == 0 {
+		t.Fatal("response has no test groups")
+	}
+	first := groups[0].(map[string]any)["tests"].([]any)[0].(map[string]any)
+	if _, ok := first["testPassed"].(bool); !ok || first["tcId"] == nil {
+		t.Fatalf("sigVer case missing tcId/testPassed: %v", first)
+	}
+
+	if exitCode := app.Run([]string{"acvp-respond"}); exitCode == 0 {
+		t.Fatal("acvp-respond without -prompt should fail")
+	}
+}
+
+func TestApp_ACVPRunMock(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{
+		Name:    "dilivet",
+		Version: "dev",
+		Out:     &out,
+		Err:     &errOut,
+	}
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "acvp.json")
+	// -mock supplies the server, so the config needs no serverUrl.
+	content := `{"pollInterval":"1ms","workDir":"` + filepath.Join(dir, "work") + `"}`
+	if err := os.WriteFile(config, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if exitCode := app.Run([]string{"acvp", "run", "-config", config, "-mock"}); exitCode != 0 {
+		t.Fatalf("acvp run exit = %d, stderr=%q, stdout=%q", exitCode, errOut.String(), out.String())
+	}
+	for _, want := range []string{"keyGen: passed", "sigGen: passed", "sigVer: passed", "Session PASSED"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("stdout missing %q:\n%s", want, out.String())
+		}
+	}
+
+	errOut.Reset()
+	if exitCode := app.Run([]string{"acvp", "run", "-config", config}); exitCode == 0 || !strings.Contains(errOut.String(), "serverUrl is required") {
+		t.Errorf("acvp run without server: exit = %d, stderr=%q", exitCode, errOut.String())
+	}
+	errOut.Reset()
+	if exitCode := app.Run([]string{"acvp", "run", "-config", config, "-mock", "-timeout", "1ns"}); exitCode == 0 || !strings.Contains(errOut.String(), "deadline exceeded") {
+		t.Errorf("acvp run -timeout: exit = %d, stderr=%q", exitCode, errOut.String())
+	}
+
+	if exitCode := app.Run([]string{"acvp", "submit"}); exitCode == 0 {
+		t.Fatal("unknown acvp subcommand should fail")
+	}
+}
+
+func TestApp_KATRSPCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{
+		Name:    "dilivet",
+		Version: "dev",
+		Out:     &out,
+		Err:     &errOut,
+	}
+
+	if exitCode := app.Run([]string{"kat-rsp"}); exitCode != 0 {
+		t.Fatalf("kat-rsp exit = %d, stderr=%q, stdout=%q", exitCode, errOut.String(), out.String())
+	}
+	if !strings.Contains(out.String(), "Dilithium2, ML-DSA-44") || !strings.Contains(out.String(), "Mismatches: 0") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	// Hedged signing draws rnd from the DRBG, so sm no longer matches.
+	out.Reset()
+	if exitCode := app.Run([]string{"kat-rsp", "-hedged"}); exitCode != 1 {
+		t.Fatalf("kat-rsp -hedged exit = %d, want 1", exitCode)
+	}
+	if !strings.Contains(out.String(), "sm differs at byte") {
+		t.Fatalf("mismatch not reported:\n%s", out.String())
+	}
+
+	// Round-3 Dilithium secret keys are 32 bytes shorter than ML-DSA ones;
+	// any other size is a malformed file.
+	path, err := kats.ResolvePath(defaultRSPFile)
+	if err != nil {
+		t.Fatalf("ResolvePath: %v", err)
+	}
+	file, err := kat.LoadRSP(path)
+	if err != nil {
+		t.Fatalf("LoadRSP: %v", err)
+	}
+	for _, tt := range []struct {
+		trim int
+		want string
+	}{
+		{32, "Dilithium2 keys"},
+		{1, "sk is 2559 bytes, want 2560"},
+	} {
+		c := file.Cases[0]
+		c.SK = c.SK[:len(c.SK)-tt.trim]
+		trimmed := &kat.RSPFile{Scheme: file.Scheme, Cases: []kat.RSPCase{c}}
+		path := filepath.Join(t.TempDir(), "kat.rsp")
+		var buf bytes.Buffer
+		if _, err := trimmed.WriteTo(&buf); err != nil {
+			t.Fatalf("WriteTo: %v", err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		errOut.Reset()
+		if exitCode := app.Run([]string{"kat-rsp", "-rsp", path}); exitCode != 1 || !strings.Contains(errOut.String(), tt.want) {
+			t.Errorf("sk trimmed by %d: exit = %d, stderr=%q", tt.trim, exitCode, errOut.String())
+		}
+	}
+}
+
+func TestApp_WycheproofCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{
+		Name:    "dilivet",
+		Version: "dev",
+		Out:     &out,
+		Err:     &errOut,
+	}
+
+	if exitCode := app.Run([]string{"wycheproof", "-json"}); exitCode != 0 {
+		t.Fatalf("wycheproof exit = %d, stderr=%q, stdout=%q", exitCode, errOut.String(), out.String())
+	}
+	var summary struct {
+		Suite  string `json:"suite"`
+		Total  int    `json:"total"`
+		Failed int    `json:"failed"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &summary); err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	if summary.Suite != "mldsa-wycheproof-alpha1" || summary.Total == 0 || summary.Failed != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestApp_MutateCommand(t *testing.T) {
+	pk, sk, err := mldsa.KeyGen(mldsa.ParamsMLDSA44, make([]byte, mldsa.SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("mutate me")
+	sig, err := mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	pubPath := filepath.Join(dir, "pk.hex")
+	sigPath := filepath.Join(dir, "sig.hex")
+	msgPath := filepath.Join(dir, "msg.bin")
+	outPath := filepath.Join(dir, "suite.json")
+	for path, data := range map[string][]byte{
+		pubPath: []byte(hex.EncodeToString(pk)),
+		sigPath: []byte(hex.EncodeToString(sig)),
+		msgPath: msg,
+	} {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	if exitCode := app.Run([]string{"mutate", "-pub", pubPath, "-sig", sigPath, "-msg", msgPath, "-out", outPath}); exitCode != 0 {
+		t.Fatalf("mutate exit = %d, stderr=%q", exitCode, errOut.String())
+	}
+	if !strings.Contains(out.String(), "z-at-bound") {
+		t.Fatalf("missing case listing: %q", out.String())
+	}
+
+	// The generated suite must hold up against the builtin verifier.
+	out.Reset()
+	if exitCode := app.Run([]string{"wycheproof", "-suite", outPath}); exitCode != 0 {
+		t.Fatalf("wycheproof exit = %d, stdout=%q, stderr=%q", exitCode, out.String(), errOut.String())
+	}
+
+	acvpPath := filepath.Join(dir, "sigver.json")
+	if exitCode := app.Run([]string{"mutate", "-pub", pubPath, "-sig", sigPath, "-msg", msgPath, "-out", acvpPath, "-format", "acvp"}); exitCode != 0 {
+		t.Fatalf("mutate -format acvp exit = %d, stderr=%q", exitCode, errOut.String())
+	}
+	if exitCode := app.Run([]string{"kat-verify", "-vectors", acvpPath}); exitCode != 0 {
+		t.Fatalf("kat-verify exit = %d, stderr=%q", exitCode, errOut.String())
+	}
+
+	if exitCode := app.Run([]string{"mutate", "-pub", pubPath, "-sig", sigPath, "-msg", pubPath, "-out", outPath}); exitCode == 0 {
+		t.Fatal("expected failure when the baseline does not verify")
+	}
+}
@@ -0,0 +1,184 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/corpus"
+)
+
+const (
+	corpusFormatVectors    = "vectors"
+	corpusFormatWycheproof = "wycheproof"
+)
+
+func (a *App) runCorpus(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(a.Err, "corpus: expected subcommand \"export\" or \"minimize\"")
+		return 1
+	}
+	switch args[0] {
+	case "export":
+		return a.runCorpusExport(args[1:])
+	case "minimize":
+		return a.runCorpusMinimize(args[1:])
+	}
+	fmt.Fprintf(a.Err, "corpus: unknown subcommand %q (want export or minimize)\n", args[0])
+	return 1
+}
+
+func (a *App) runCorpusExport(args []string) int {
+	fs := flag.NewFlagSet("corpus export", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	dir := fs.String("dir", corpus.DefaultDir, "Go fuzz corpus directory (one subdirectory per fuzz target)")
+	targets := fs.String("target", "", "comma-separated fuzz targets to export (default: "+strings.Join(corpus.Targets, ",")+")")
+	format := fs.String("format", corpusFormatVectors, "output shape (vectors|wycheproof)")
+	name := fs.String("name", "fuzz-corpus", "suite name for wycheproof output")
+	outPath := fs.String("out", "", "write the exported vectors to this file")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "corpus export: unexpected positional arguments")
+		return 1
+	}
+	if *outPath == "" {
+		fmt.Fprintln(a.Err, "corpus export: -out is required")
+		return 1
+	}
+	if *format != corpusFormatVectors && *format != corpusFormatWycheproof {
+		fmt.Fprintf(a.Err, "corpus export: unknown format %q\n", *format)
+		return 1
+	}
+
+	root, err := kats.ResolvePath(*dir)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus export: %v\n", err)
+		return 1
+	}
+	var names []string
+	if *targets != "" {
+		names = strings.Split(*targets, ",")
+	}
+	vectors, err := corpus.Export(root, names...)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus export: %v\n", err)
+		return 1
+	}
+
+	var doc any = struct {
+		Dir     string          `json:"dir"`
+		Vectors []corpus.Vector `json:"vectors"`
+	}{*dir, vectors}
+	written := len(vectors)
+	if *format == corpusFormatWycheproof {
+		suite := corpus.Suite(*name, vectors)
+		doc, written = suite, len(suite.Cases)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus export: encode json: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*outPath, append(data, '\n'), 0o644); err != nil {
+		fmt.Fprintf(a.Err, "corpus export: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.Out, "Wrote %d of %d corpus entries (%s) to %s\n", written, len(vectors), *format, *outPath)
+	for _, v := range vectors {
+		fmt.Fprintf(a.Out, "  %-40s %-7s %s\n", v.Name, v.Verdict, v.Stage)
+	}
+	return 0
+}
+
+func (a *App) runCorpusMinimize(args []string) int {
+	fs := flag.NewFlagSet("corpus minimize", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	inPath := fs.String("in", "", "corpus file to minimize (required)")
+	target := fs.String("target", "", "fuzz target the file belongs to (default: its directory name)")
+	outDir := fs.String("out", "", "directory for the minimized corpus file (default: next to -in)")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON summary")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "corpus minimize: unexpected positional arguments")
+		return 1
+	}
+	if *inPath == "" {
+		fmt.Fprintln(a.Err, "corpus minimize: -in is required")
+		return 1
+	}
+	if *target == "" {
+		*target = filepath.Base(filepath.Dir(*inPath))
+	}
+	if *outDir == "" {
+		*outDir = filepath.Dir(*inPath)
+	}
+
+	values, err := corpus.ReadFile(*inPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus minimize: %v\n", err)
+		return 1
+	}
+	if len(values) != 1 {
+		fmt.Fprintf(a.Err, "corpus minimize: %s holds %d values, want one []byte\n", *inPath, len(values))
+		return 1
+	}
+	m, err := corpus.Minimize(*target, values[0])
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus minimize: %v\n", err)
+		return 1
+	}
+
+	data := corpus.Encode(m.Value)
+	outPath := filepath.Join(*outDir, corpus.FileName(data))
+	m.Vector.Name = *target + "/" + filepath.Base(outPath)
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		fmt.Fprintf(a.Err, "corpus minimize: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(outPath, data, 0o644); err != nil {
+		fmt.Fprintf(a.Err, "corpus minimize: %v\n", err)
+		return 1
+	}
+
+	if *jsonOut {
+		payload := struct {
+			Input         string        `json:"input"`
+			Output        string        `json:"output"`
+			OriginalBytes int           `json:"originalBytes"`
+			Bytes         int           `json:"bytes"`
+			Steps         int           `json:"steps"`
+			Vector        corpus.Vector `json:"vector"`
+		}{*inPath, outPath, len(m.Original), len(m.Value), m.Steps, m.Vector}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "corpus minimize: encode json: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintf(a.Out, "Minimized %s: %d -> %d bytes in %d replays\n", *inPath, len(m.Original), len(m.Value), m.Steps)
+	fmt.Fprintf(a.Out, "Stage: %s (%s)", m.Vector.Stage, m.Vector.Verdict)
+	if m.Vector.Error != "" {
+		fmt.Fprintf(a.Out, ": %s", m.Vector.Error)
+	}
+	fmt.Fprintln(a.Out)
+	fmt.Fprintf(a.Out, "Wrote %s\n", outPath)
+	return 0
+}
@@ -0,0 +1,98 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/corpus"
+)
+
+func writeCorpusEntry(t *testing.T, dir, target string, value []byte) string {
+	t.Helper()
+	data := corpus.Encode(value)
+	path := filepath.Join(dir, target, corpus.FileName(data))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApp_CorpusExportCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeCorpusEntry(t, dir, corpus.TargetVerify, []byte("fuzz find"))
+	writeCorpusEntry(t, dir, corpus.TargetVerify, nil)
+	writeCorpusEntry(t, dir, corpus.TargetDecodePublicKey, []byte{0xff})
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	vectorsPath := filepath.Join(dir, "vectors.json")
+	if code := app.Run([]string{"corpus", "export", "-dir", dir, "-out", vectorsPath}); code != 0 {
+		t.Fatalf("export exit = %d, stderr=%q", code, errOut.String())
+	}
+	data, err := os.ReadFile(vectorsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Vectors []corpus.Vector `json:"vectors"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Vectors) != 3 {
+		t.Fatalf("got %d vectors: %s", len(doc.Vectors), data)
+	}
+
+	// The wycheproof export replays as a regression suite.
+	suitePath := filepath.Join(dir, "suite.json")
+	if code := app.Run([]string{"corpus", "export", "-dir", dir, "-format", "wycheproof", "-out", suitePath}); code != 0 {
+		t.Fatalf("export exit = %d, stderr=%q", code, errOut.String())
+	}
+	out.Reset()
+	if code := app.Run([]string{"wycheproof", "-suite", suitePath}); code != 0 {
+		t.Fatalf("wycheproof exit = %d, stdout=%q", code, out.String())
+	}
+
+	if code := app.Run([]string{"corpus"}); code != 1 {
+		t.Errorf("missing subcommand: exit %d", code)
+	}
+}
+
+func TestApp_CorpusMinimizeCommand(t *testing.T) {
+	dir := t.TempDir()
+	in := writeCorpusEntry(t, dir, corpus.TargetDecodePublicKey, bytes.Repeat([]byte{7}, 1315))
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	if code := app.Run([]string{"corpus", "minimize", "-in", in, "-json"}); code != 0 {
+		t.Fatalf("minimize exit = %d, stderr=%q", code, errOut.String())
+	}
+	var summary struct {
+		Output        string        `json:"output"`
+		OriginalBytes int           `json:"originalBytes"`
+		Bytes         int           `json:"bytes"`
+		Vector        corpus.Vector `json:"vector"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.OriginalBytes != 1315 || summary.Bytes >= 1315 || summary.Vector.Stage != corpus.StagePublicKey {
+		t.Fatalf("summary %+v", summary)
+	}
+	if filepath.Dir(summary.Output) != filepath.Dir(in) {
+		t.Errorf("written to %s, want next to the input", summary.Output)
+	}
+	values, err := corpus.ReadFile(summary.Output)
+	if err != nil || len(values) != 1 || len(values[0]) != summary.Bytes {
+		t.Fatalf("minimized file: %v %v", values, err)
+	}
+}
@@ -0,0 +1,129 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/sigcorpus"
+)
+
+func (a *App) runCorpusAnalyze(args []string) int {
+	fs := flag.NewFlagSet("corpus-analyze", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	pubPath := fs.String("pub", "", "path to the ML-DSA public key every signature was made under")
+	pubFormat := fs.String("pub-format", formatHex, "format of public key file (hex|raw)")
+	inPath := fs.String("in", "", "JSONL corpus, one {\"msg\": hex, \"sig\": hex} object per line (default: stdin)")
+	ctxHex := fs.String("ctx", "", "hex context: verify with external ML-DSA.Verify instead of Verify_internal")
+	deterministic := fs.Bool("deterministic", false, "the signer is deterministic; repeated signatures on one message are expected")
+	alpha := fs.Float64("alpha", 0.001, "significance level of the distribution tests")
+	bins := fs.Int("bins", 64, "z histogram bins")
+	reference := fs.Int("reference", 256, "reference signatures the hint weight is compared with")
+	jsonOut := fs.Bool("json", false, "emit the report as JSON")
+	baseline := addBaselineFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "corpus-analyze: unexpected positional arguments")
+		return 1
+	}
+	if *pubPath == "" {
+		fmt.Fprintln(a.Err, "corpus-analyze: -pub is required")
+		return 1
+	}
+	var ctx []byte
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "ctx" {
+			ctx = []byte{}
+		}
+	})
+	if ctx != nil {
+		var err error
+		if ctx, err = hex.DecodeString(*ctxHex); err != nil {
+			fmt.Fprintf(a.Err, "corpus-analyze: -ctx: %v\n", err)
+			return 1
+		}
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "corpus-analyze: %v\n", err)
+		return 1
+	}
+
+	pub, err := loadData(*pubPath, *pubFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus-analyze: read public key: %v\n", err)
+		return 1
+	}
+	var in io.Reader = os.Stdin
+	if *inPath != "" {
+		f, err := os.Open(*inPath)
+		if err != nil {
+			fmt.Fprintf(a.Err, "corpus-analyze: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	records, err := sigcorpus.ReadJSONL(in)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus-analyze: %v\n", err)
+		return 1
+	}
+
+	opts := sigcorpus.Options{Ctx: ctx, Deterministic: *deterministic, Alpha: *alpha, Bins: *bins, Reference: *reference}
+	rep, err := sigcorpus.Analyze(pub, records, opts)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus-analyze: %v\n", err)
+		return 1
+	}
+	kept, suppressed, err := baseline.apply(rep.Findings)
+	if err != nil {
+		fmt.Fprintf(a.Err, "corpus-analyze: write baseline: %v\n", err)
+		return 1
+	}
+
+	if *jsonOut {
+		rep.Findings = kept
+		payload := struct {
+			*sigcorpus.Report
+			Suppressed []diag.Finding `json:"suppressed,omitempty"`
+		}{rep, suppressed}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "corpus-analyze: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(a.Out, "%s: %d signatures (%d valid, %d invalid, %d malformed, %d repeated), %d distinct w1\n",
+			rep.ParameterSet, rep.Signatures, rep.Valid, rep.Invalid, rep.Malformed, rep.Duplicates, rep.DistinctW1)
+		fmt.Fprintf(a.Out, "Hint weight: min %d, mean %.1f, max %d (ω = %d)\n",
+			rep.HintWeights.Min, rep.HintWeights.Mean, rep.HintWeights.Max, rep.HintWeights.Omega)
+		for _, t := range rep.Tests {
+			verdict := "ok"
+			switch {
+			case t.Skipped:
+				verdict = "skipped (too few samples)"
+			case t.Fail:
+				verdict = "FAIL"
+			}
+			fmt.Fprintf(a.Out, "  %-15s χ²=%-10.1f df=%-4d p=%-10.3g %s\n", t.Name, t.Statistic, t.DF, t.P, verdict)
+		}
+		printFindings(a.Out, kept, suppressed)
+	}
+
+	if len(kept) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+func TestApp_CorpusAnalyzeCommand(t *testing.T) {
+	dir := t.TempDir()
+	pk, sk, err := mldsa.KeyGen(mldsa.ParamsMLDSA44, bytes.Repeat([]byte{0x0c}, mldsa.SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPath := filepath.Join(dir, "pk.hex")
+	if err := os.WriteFile(pubPath, []byte(hex.EncodeToString(pk)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for i := 0; i < 60; i++ {
+		msg := []byte(fmt.Sprintf("reading %d", i))
+		rnd := bytes.Repeat([]byte{byte(i)}, mldsa.RndBytes)
+		sig, err := mldsa.Sign(sk, msg, rnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, fmt.Sprintf(`{"msg":%q,"sig":%q}`, hex.EncodeToString(msg), hex.EncodeToString(sig)))
+	}
+	write := func(name string, lines []string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	clean := write("clean.jsonl", lines)
+	stuck := write("stuck.jsonl", append(lines, lines[3]))
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	if code := app.Run([]string{"corpus-analyze", "-pub", pubPath, "-in", clean, "-reference", "32"}); code != 0 {
+		t.Fatalf("exit = %d, stderr=%q\n%s", code, errOut.String(), out.String())
+	}
+	for _, want := range []string{"ML-DSA-44: 60 signatures (60 valid", "60 distinct w1", "z-uniform", "hint-weight"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := app.Run([]string{"corpus-analyze", "-pub", pubPath, "-in", stuck, "-reference", "32", "-json"}); code != 1 {
+		t.Fatalf("stuck generator: exit = %d, stderr=%q", code, errOut.String())
+	}
+	var rep struct {
+		Duplicates int            `json:"duplicates"`
+		Findings   []diag.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+	if rep.Duplicates != 1 || len(rep.Findings) != 1 || rep.Findings[0].RuleID != diag.RuleDuplicateSig || rep.Findings[0].Evidence[0] != "lines 4 and 61" {
+		t.Errorf("report %+v", rep)
+	}
+	if code := app.Run([]string{"corpus-analyze", "-pub", pubPath, "-in", stuck, "-reference", "32", "-deterministic"}); code != 0 {
+		t.Errorf("deterministic: exit = %d", code)
+	}
+
+	for _, args := range [][]string{
+		{"corpus-analyze", "-in", clean},
+		{"corpus-analyze", "-pub", pubPath, "-in", filepath.Join(dir, "missing.jsonl")},
+		{"corpus-analyze", "-pub", pubPath, "-in", pubPath},
+		{"corpus-analyze", "-pub", pubPath, "-in", clean, "-ctx", "zz"},
+		{"corpus-analyze", "-pub", pubPath, "extra"},
+	} {
+		if code := app.Run(args); code != 1 {
+			t.Errorf("%v: exit %d", args, code)
+		}
+	}
+}
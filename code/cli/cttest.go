@@ -0,0 +1,170 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/ct"
+)
+
+const (
+	ctClockAuto   = "auto"
+	ctClockCycles = "cycles"
+	ctClockNS     = "ns"
+)
+
+func (a *App) runCTTest(args []string) int {
+	fs := flag.NewFlagSet("ct-test", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	targetList := fs.String("target", "", "comma-separated targets: ntt, invntt, pointwise, freeze, verify, sign (default: all, or verify,sign with -impl)")
+	paramSet := fs.String("params", "ML-DSA-44", "parameter set for the verify and sign targets")
+	implPath := fs.String("impl", "", "time an implementation speaking the kats.Exec protocol instead of the built-in one")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-call timeout for -impl")
+	limitFlags := addImplFlags(fs)
+	session := fs.Bool("session", false, "keep -impl running and speak the JSON-lines protocol (docs/execsign-protocol.md)")
+	measurements := fs.Int("measurements", 10000, "timed calls per target after warm-up")
+	batches := fs.Int("batches", 10, "t-statistic snapshots per target")
+	threshold := fs.Float64("threshold", ct.DefaultThreshold, "max |t| above which a target is reported as leaking")
+	seed := fs.Int64("seed", 1, "seed for the class sequence and random inputs")
+	clockName := fs.String("clock", ctClockAuto, "timer (auto|cycles|ns); auto uses the cycle counter where available")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON results")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "ct-test: unexpected positional arguments")
+		return 1
+	}
+
+	var clock ct.Clock
+	switch *clockName {
+	case ctClockAuto:
+		clock = ct.DefaultClock()
+	case ctClockCycles:
+		var ok bool
+		if clock, ok = ct.Cycles(); !ok {
+			fmt.Fprintln(a.Err, "ct-test: no cycle counter on this platform; use -clock ns")
+			return 1
+		}
+	case ctClockNS:
+		clock = ct.Nanoseconds
+	default:
+		fmt.Fprintf(a.Err, "ct-test: unknown clock %q\n", *clockName)
+		return 1
+	}
+	params, err := mldsa.FromName(*paramSet)
+	if err != nil {
+		fmt.Fprintf(a.Err, "ct-test: %v\n", err)
+		return 1
+	}
+
+	var impl kats.Implementation = kats.Builtin{}
+	names := []string{"ntt", "invntt", "pointwise", "freeze", "verify", "sign"}
+	if *implPath != "" {
+		target := kats.Exec{Bin: execsign.Bin{Path: *implPath, Timeout: *timeout, Limits: limitFlags.limits()}}
+		if *session {
+			s, err := execsign.StartSession(context.Background(), target.Bin)
+			if err != nil {
+				fmt.Fprintf(a.Err, "ct-test: %v\n", err)
+				return 1
+			}
+			defer s.Close()
+			target.Session = s
+		}
+		impl = target
+		names = []string{"verify", "sign"}
+	}
+	if *targetList != "" {
+		names = strings.Split(*targetList, ",")
+	}
+
+	polys := map[string]ct.Target{}
+	for _, t := range ct.PolyTargets() {
+		polys[t.Name] = t
+	}
+	var targets []ct.Target
+	for _, name := range names {
+		var t ct.Target
+		var err error
+		switch name = strings.TrimSpace(name); name {
+		case "verify":
+			t, err = ct.VerifyTarget(impl, params, *seed)
+		case "sign":
+			t, err = ct.SignTarget(impl, params, *seed)
+		default:
+			var ok bool
+			if t, ok = polys[name]; !ok {
+				err = fmt.Errorf("unknown target %q", name)
+			} else if *implPath != "" {
+				err = fmt.Errorf("target %q only runs in-process", name)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(a.Err, "ct-test: %v\n", err)
+			return 1
+		}
+		targets = append(targets, t)
+	}
+
+	opts := ct.Options{Measurements: *measurements, Batches: *batches, Threshold: *threshold, Seed: *seed, Clock: clock}
+	var results []*ct.Result
+	leaks := 0
+	for _, t := range targets {
+		res, err := ct.Run(t, opts)
+		if err != nil {
+			fmt.Fprintf(a.Err, "ct-test: %v\n", err)
+			return 1
+		}
+		if res.Leak() {
+			leaks++
+		}
+		results = append(results, res)
+		if !*jsonOut {
+			printCTResult(a.Out, res)
+		}
+	}
+
+	if *jsonOut {
+		payload := struct {
+			Clock   string       `json:"clock"`
+			Leaks   int          `json:"leaks"`
+			Results []*ct.Result `json:"results"`
+		}{clock.Unit, leaks, results}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "ct-test: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(a.Out, "Targets: %d, leaking: %d (|t| > %g, clock %s)\n", len(results), leaks, *threshold, clock.Unit)
+	}
+
+	if leaks > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printCTResult(w io.Writer, r *ct.Result) {
+	fmt.Fprintf(w, "%s: %s (max |t| %.2f in %s test after %d measurements)\n", r.Target, strings.ToUpper(r.Verdict), r.MaxT, r.MaxTTest, r.Measurements)
+	fmt.Fprintf(w, "    mean %.1f fixed, %.1f random %s\n", r.MeanFixed, r.MeanRandom, r.Clock)
+	points := make([]string, len(r.Series))
+	for i, p := range r.Series {
+		points[i] = fmt.Sprintf("%d:%.2f", p.Measurements, p.MaxT)
+	}
+	fmt.Fprintf(w, "    |t| over time: %s\n", strings.Join(points, " "))
+}
@@ -0,0 +1,72 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/ct"
+)
+
+func TestApp_CTTestCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+
+	// A huge threshold keeps a noisy machine from turning this into a leak;
+	// the statistics themselves are covered in code/ct.
+	code := app.Run([]string{"ct-test", "-target", "ntt,freeze", "-measurements", "200", "-batches", "2", "-clock", "ns", "-threshold", "1e9", "-json"})
+	if code != 0 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	var payload struct {
+		Clock   string       `json:"clock"`
+		Leaks   int          `json:"leaks"`
+		Results []*ct.Result `json:"results"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Clock != "ns" || payload.Leaks != 0 || len(payload.Results) != 2 || payload.Results[1].Target != "freeze" ||
+		len(payload.Results[0].Series) != 2 || payload.Results[0].Verdict != ct.VerdictNoLeak {
+		t.Errorf("payload %+v", payload)
+	}
+
+	for _, args := range [][]string{
+		{"ct-test", "-target", "bogus"},
+		{"ct-test", "-clock", "sundial"},
+		{"ct-test", "-params", "ML-DSA-1"},
+	} {
+		if code := app.Run(args); code != 1 {
+			t.Errorf("%v: exit %d", args, code)
+		}
+	}
+}
+
+func TestApp_CTTestImpl(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	t.Setenv(execHelperEnv, "jsonl")
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	code := app.Run([]string{"ct-test", "-impl", exe, "-session", "-measurements", "40", "-batches", "2", "-threshold", "1e9"})
+	if code != 0 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	for _, want := range []string{"verify/ML-DSA-44: NO LEAK", "sign/ML-DSA-44: NO LEAK", "|t| over time:", "Targets: 2, leaking: 0"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	if code := app.Run([]string{"ct-test", "-impl", exe, "-target", "ntt"}); code != 1 || !strings.Contains(errOut.String(), "only runs in-process") {
+		t.Errorf("ntt with -impl: exit %d, stderr %q", code, errOut.String())
+	}
+}
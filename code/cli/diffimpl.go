@@ -0,0 +1,197 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/interop"
+)
+
+// implFlags collects repeated -impl name=path flags.
+type implFlags []namedImpl
+
+type namedImpl struct {
+	name, path string
+}
+
+func (f *implFlags) String() string {
+	names := make([]string, 0, len(*f))
+	for _, impl := range *f {
+		names = append(names, impl.name+"="+impl.path)
+	}
+	return strings.Join(names, ",")
+}
+
+// Set parses name=path; a bare path is named after its base name.
+func (f *implFlags) Set(v string) error {
+	name, path, ok := strings.Cut(v, "=")
+	if !ok {
+		name, path = filepath.Base(v), v
+	}
+	if name == "" || path == "" {
+		return fmt.Errorf("want name=path, got %q", v)
+	}
+	for _, impl := range *f {
+		if impl.name == name {
+			return fmt.Errorf("duplicate implementation name %q", name)
+		}
+	}
+	if name == interop.BuiltinName {
+		return fmt.Errorf("%q is reserved for the built-in implementation", name)
+	}
+	*f = append(*f, namedImpl{name, path})
+	return nil
+}
+
+func (a *App) runDiffImpl(args []string) int {
+	fs := flag.NewFlagSet("diff-impl", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	var impls implFlags
+	fs.Var(&impls, "impl", "external implementation as name=path (repeatable)")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-call timeout for -impl binaries")
+	limitFlags := addImplFlags(fs)
+	session := fs.Bool("session", false, "keep each -impl running and speak the JSON-lines protocol (docs/execsign-protocol.md)")
+	useKATs := fs.Bool("kats", true, "feed the bundled ACVP keyGen/sigGen/sigVer vectors")
+	perGroup := fs.Int("per-group", 4, "KAT cases taken from each test group (0 = all)")
+	mutations := fs.Bool("mutations", true, "feed mutated signatures (see mutate)")
+	random := fs.Int("random", 4, "random keygen/sign/verify rounds per parameter set")
+	seed := fs.Int64("seed", 1, "seed for random inputs and matrix keys")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON report")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "diff-impl: unexpected positional arguments")
+		return 1
+	}
+
+	limits := limitFlags.limits()
+	usage := map[string]*execsign.UsageLog{}
+	targets := []interop.Target{{Name: interop.BuiltinName, Impl: kats.Builtin{}}}
+	for _, impl := range impls {
+		usage[impl.name] = &execsign.UsageLog{}
+		target := kats.Exec{Bin: execsign.Bin{Path: impl.path, Timeout: *timeout, Limits: limits, Usage: usage[impl.name]}}
+		if *session {
+			s, err := execsign.StartSession(context.Background(), target.Bin)
+			if err != nil {
+				fmt.Fprintf(a.Err, "diff-impl: %s: %v\n", impl.name, err)
+				return 1
+			}
+			defer s.Close()
+			target.Session = s
+		}
+		targets = append(targets, interop.Target{Name: impl.name, Impl: target})
+	}
+
+	report, err := interop.Run(targets, interop.Options{
+		KATs: *useKATs, Mutations: *mutations, Random: *random, PerGroup: *perGroup, Seed: *seed,
+	})
+	if err != nil {
+		fmt.Fprintf(a.Err, "diff-impl: %v\n", err)
+		return 1
+	}
+
+	// Close sessions first so their processes' CPU time and RSS are counted.
+	for _, t := range targets {
+		if e, ok := t.Impl.(kats.Exec); ok && e.Session != nil {
+			e.Session.Close()
+		}
+	}
+	summaries := map[string]execsign.UsageSummary{}
+	for name, log := range usage {
+		summaries[name] = log.Summary()
+	}
+
+	if *jsonOut {
+		payload := struct {
+			*interop.Report
+			Usage map[string]execsign.UsageSummary `json:"usage,omitempty"`
+		}{report, summaries}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "diff-impl: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		a.printDiffReport(report)
+		a.printUsage(report.Targets, summaries)
+	}
+
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
+
+func (a *App) printDiffReport(r *interop.Report) {
+	fmt.Fprintf(a.Out, "Implementations: %s\n", strings.Join(r.Targets, ", "))
+	fmt.Fprintf(a.Out, "Inputs: %d (compared by two or more: %d)\n", r.Inputs, r.Compared)
+	fmt.Fprintf(a.Out, "Disagreements: %d\n", len(r.Disagreements))
+	for _, d := range r.Disagreements {
+		in := d.Input
+		fmt.Fprintf(a.Out, "\n  %s %s [%s] %s", in.Source, in.Label, in.ParameterSet, in.Op)
+		if d.Original != nil {
+			fmt.Fprintf(a.Out, " (message shrunk from %d to %d bytes)", len(d.Original.Msg), len(in.Msg))
+		}
+		fmt.Fprintln(a.Out)
+		for _, o := range d.Outcomes {
+			fmt.Fprintf(a.Out, "    %-12s %s", o.Target, o.Value)
+			if o.Err != "" {
+				fmt.Fprintf(a.Out, " (%s)", o.Err)
+			}
+			fmt.Fprintln(a.Out)
+		}
+		fmt.Fprintln(a.Out, "    reproducer:")
+		for _, line := range strings.Split(strings.TrimSpace(d.Reproducer), "\n") {
+			fmt.Fprintf(a.Out, "      %s\n", line)
+		}
+	}
+
+	for _, m := range r.Matrices {
+		fmt.Fprintf(a.Out, "\nInterop matrix %s, %s (rows sign, columns verify):\n", m.ParameterSet, m.Interface)
+		tw := tabwriter.NewWriter(a.Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  \t%s\n", strings.Join(m.Verifiers, "\t"))
+		for i, row := range m.Cells {
+			states := make([]string, len(row))
+			for j, c := range row {
+				states[j] = c.Status
+			}
+			fmt.Fprintf(tw, "  %s\t%s\n", m.Signers[i], strings.Join(states, "\t"))
+		}
+		tw.Flush()
+	}
+}
+
+// printUsage lists the resource usage of each external target.
+func (a *App) printUsage(names []string, usage map[string]execsign.UsageSummary) {
+	if len(usage) == 0 {
+		return
+	}
+	fmt.Fprintln(a.Out, "\nResource usage:")
+	tw := tabwriter.NewWriter(a.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  target\tcalls\tmean wall\tuser\tsys\tmax RSS")
+	for _, name := range names {
+		u, ok := usage[name]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\t%s\t%.1f MiB\n", name, u.Calls,
+			u.MeanWall().Round(time.Microsecond), u.User.Round(time.Millisecond),
+			u.System.Round(time.Millisecond), float64(u.MaxRSS)/(1<<20))
+	}
+	tw.Flush()
+}
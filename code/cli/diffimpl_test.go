@@ -0,0 +1,183 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+const execHelperEnv = "DILIVET_CLI_EXEC_HELPER"
+
+// TestMain lets the test binary act as an external implementation for
+// keygen, sign-internal, sign-fault and verify-internal: one request per process in the
+// kats.Exec key=value format, or a JSON-lines session when the helper
+// variable is "jsonl". In "accept-jsonl" the session accepts every
+// signature.
+func TestMain(m *testing.M) {
+	execsign.LimitsMain()
+	switch os.Getenv(execHelperEnv) {
+	case "1":
+		os.Exit(execHelper())
+	case "jsonl", "accept-jsonl":
+		os.Exit(sessionHelper())
+	}
+	os.Exit(m.Run())
+}
+
+func execHelper() int {
+	args := map[string]string{}
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		if k, v, ok := strings.Cut(scanner.Text(), "="); ok {
+			args[k] = v
+		}
+	}
+	for k, v := range helperAnswer(args["op"], args) {
+		fmt.Printf("%s=%s\n", k, v)
+	}
+	return 0
+}
+
+func sessionHelper() int {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	enc := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var req execsign.Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return 2
+		}
+		if req.Op == execsign.OpHello {
+			enc.Encode(execsign.Response{ID: req.ID, OK: true, Protocol: execsign.ProtocolName, Version: execsign.ProtocolVersion,
+				Capabilities: []string{execsign.CapKeyGen, execsign.CapSign, execsign.CapVerify, execsign.CapFault}})
+			continue
+		}
+		result := helperAnswer(req.Op, req.Args)
+		resp := execsign.Response{ID: req.ID, OK: true, Result: result}
+		if msg, failed := result["error"]; failed {
+			resp = execsign.Response{ID: req.ID, Error: &execsign.ResponseError{Code: msg}}
+		}
+		enc.Encode(resp)
+	}
+	return 0
+}
+
+// helperAnswer runs op with the built-in implementation and returns the
+// reply fields.
+func helperAnswer(op string, args map[string]string) map[string]string {
+	arg := func(k string) []byte {
+		b, _ := hex.DecodeString(args[k])
+		return b
+	}
+	impl := kats.Builtin{}
+	switch op {
+	case "keygen":
+		pk, sk, err := impl.KeyGen(args["parameterSet"], arg("seed"))
+		if err != nil {
+			return map[string]string{"error": err.Error()}
+		}
+		return map[string]string{"pk": hex.EncodeToString(pk), "sk": hex.EncodeToString(sk)}
+	case "sign-internal":
+		sig, err := impl.SignInternal(arg("sk"), arg("message"), arg("rnd"))
+		if err != nil {
+			return map[string]string{"error": err.Error()}
+		}
+		return map[string]string{"signature": hex.EncodeToString(sig)}
+	case "sign-fault":
+		plan, err := kats.ParseFaultArgs(args)
+		if err != nil {
+			return map[string]string{"error": err.Error()}
+		}
+		sig, err := impl.SignFaulty(arg("sk"), arg("message"), arg("rnd"), plan)
+		if err != nil {
+			return map[string]string{"error": err.Error()}
+		}
+		return map[string]string{"signature": hex.EncodeToString(sig)}
+	case "verify-internal":
+		if os.Getenv(execHelperEnv) == "accept-jsonl" {
+			return map[string]string{"valid": "true"}
+		}
+		ok, _ := impl.VerifyInternal(arg("pk"), arg("message"), arg("signature"))
+		return map[string]string{"valid": fmt.Sprint(ok)}
+	}
+	return map[string]string{"error": "unsupported"}
+}
+
+func TestApp_DiffImplCommand(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	// Exec binaries inherit the environment, so the helper mode reaches them.
+	t.Setenv(execHelperEnv, "1")
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	code := app.Run([]string{"diff-impl", "-impl", "helper=" + exe, "-per-group", "1", "-random", "1", "-json"})
+	if code != 0 {
+		t.Fatalf("diff-impl exit = %d, stderr=%q, stdout=%q", code, errOut.String(), out.String())
+	}
+	var report struct {
+		Targets       []string          `json:"targets"`
+		Compared      int               `json:"compared"`
+		Disagreements []json.RawMessage `json:"disagreements"`
+		Matrices      []struct {
+			Interface string `json:"interface"`
+			Cells     [][]struct {
+				Status string `json:"status"`
+			} `json:"cells"`
+		} `json:"matrices"`
+		Usage map[string]struct {
+			Calls     int `json:"calls"`
+			Processes int `json:"processes"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	if len(report.Targets) != 2 || report.Compared == 0 || len(report.Disagreements) != 0 || len(report.Matrices) != 9 {
+		t.Fatalf("unexpected report: %s", out.String())
+	}
+	if got := report.Matrices[0].Cells[1][0].Status; got != "ok" || report.Matrices[0].Interface != "internal" {
+		t.Errorf("helper -> builtin cell = %q (%s)", got, report.Matrices[0].Interface)
+	}
+	// The helper has no external interface: its row and column are gaps, not failures.
+	if ext := report.Matrices[1]; ext.Interface != "external" || ext.Cells[1][0].Status != "no-signature" || ext.Cells[0][1].Status != "unsupported" {
+		t.Errorf("external matrix %+v", ext)
+	}
+	if u := report.Usage["helper"]; u.Calls == 0 || u.Processes != u.Calls {
+		t.Errorf("helper usage %+v, want one process per call", u)
+	}
+
+	if code := app.Run([]string{"diff-impl", "-impl", "builtin=" + exe}); code == 0 {
+		t.Error("expected the reserved name to be rejected")
+	}
+
+	t.Setenv(execHelperEnv, "jsonl")
+	out.Reset()
+	code = app.Run([]string{"diff-impl", "-session", "-impl", "helper=" + exe, "-per-group", "1", "-random", "1", "-json"})
+	if code != 0 {
+		t.Fatalf("diff-impl -session exit = %d, stderr=%q", code, errOut.String())
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	if report.Compared == 0 || len(report.Disagreements) != 0 {
+		t.Fatalf("unexpected session report: %s", out.String())
+	}
+	if u := report.Usage["helper"]; u.Calls == 0 || u.Processes != 1 {
+		t.Errorf("session usage %+v, want many calls on one process", u)
+	}
+}
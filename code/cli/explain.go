@@ -0,0 +1,142 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/trace"
+)
+
+// explainPreview is how many coefficients of each polynomial the text
+// output shows; -json carries all of them.
+const explainPreview = 8
+
+func (a *App) runExplain(args []string) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	pubPath := fs.String("pub", "", "path to ML-DSA public key")
+	sigPath := fs.String("sig", "", "path to ML-DSA signature")
+	msgPath := fs.String("msg", "", "path to message bytes")
+	pubFormat := fs.String("pub-format", formatHex, "format of public key file (hex|raw)")
+	sigFormat := fs.String("sig-format", formatHex, "format of signature file (hex|raw)")
+	msgFormat := fs.String("msg-format", formatRaw, "format of message file (hex|raw)")
+	ctxHex := fs.String("ctx", "", "hex context: verify with external ML-DSA.Verify instead of Verify_internal")
+	jsonOut := fs.Bool("json", false, "emit every value, with full coefficient vectors, as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "explain: unexpected positional arguments")
+		return 1
+	}
+	if *pubPath == "" || *sigPath == "" || *msgPath == "" {
+		fmt.Fprintln(a.Err, "explain: -pub, -sig and -msg are required")
+		return 1
+	}
+	external := false
+	fs.Visit(func(f *flag.Flag) { external = external || f.Name == "ctx" })
+	ctx, err := hex.DecodeString(*ctxHex)
+	if err != nil {
+		fmt.Fprintf(a.Err, "explain: -ctx: %v\n", err)
+		return 1
+	}
+
+	pub, err := loadData(*pubPath, *pubFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "explain: read public key: %v\n", err)
+		return 1
+	}
+	sig, err := loadData(*sigPath, *sigFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "explain: read signature: %v\n", err)
+		return 1
+	}
+	msg, err := loadData(*msgPath, *msgFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "explain: read message: %v\n", err)
+		return 1
+	}
+	params, err := mldsa.FromPublicKeyLength(len(pub))
+	if err != nil {
+		fmt.Fprintf(a.Err, "explain: %v\n", err)
+		return 1
+	}
+
+	rec := trace.NewRecorder(params)
+	var valid bool
+	var verr error
+	iface := "internal"
+	if external {
+		iface = "external"
+		valid, verr = mldsa.VerifyWithContextTraced(pub, msg, ctx, sig, rec)
+	} else {
+		valid, verr = mldsa.VerifyTraced(pub, msg, sig, rec)
+	}
+	verdict := "valid"
+	switch {
+	case verr != nil:
+		verdict = "error: " + verr.Error()
+	case !valid:
+		verdict = "rejected: c̃′ ≠ c̃"
+	}
+
+	if *jsonOut {
+		file := &trace.File{
+			SchemaVersion: trace.SchemaVersion,
+			ParameterSet:  trace.NewParameterSet(params),
+			Interface:     iface,
+			Valid:         valid && verr == nil,
+			Steps:         rec.Steps,
+		}
+		if verr != nil {
+			file.Error = verr.Error()
+		}
+		if err := file.Write(a.Out); err != nil {
+			fmt.Fprintf(a.Err, "explain: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(a.Out, "Parameter set: %s (k=%d, l=%d, η=%d, β=%d, γ1=%d, γ2=%d, τ=%d, ω=%d, λ=%d)\n",
+			params.Name, params.K, params.L, params.Eta, params.Beta, params.Gamma1, params.Gamma2, params.Tau, params.Omega, params.Lambda)
+		fmt.Fprintf(a.Out, "Interface: %s\n", iface)
+		for _, s := range rec.Steps {
+			printExplainStep(a.Out, s)
+		}
+		fmt.Fprintf(a.Out, "Result: %s\n", verdict)
+	}
+
+	if verr != nil || !valid {
+		return 1
+	}
+	return 0
+}
+
+func printExplainStep(w io.Writer, s trace.Step) {
+	fmt.Fprintf(w, "%s: %s\n", s.Step, s.Description)
+	if s.Note != "" {
+		fmt.Fprintf(w, "    %s\n", s.Note)
+	}
+	if s.Hex != "" {
+		fmt.Fprintf(w, "    %s\n", s.Hex)
+	}
+	for i, p := range s.Polys {
+		n := len(p)
+		if n > explainPreview {
+			n = explainPreview
+		}
+		var b strings.Builder
+		for _, c := range p[:n] {
+			fmt.Fprintf(&b, " %d", c)
+		}
+		fmt.Fprintf(w, "    [%d]%s … (%d coefficients)\n", i, b.String(), len(p))
+	}
+}
@@ -0,0 +1,163 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/faultsim"
+)
+
+func (a *App) runFaultSim(args []string) int {
+	fs := flag.NewFlagSet("fault-sim", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	faultList := fs.String("fault", "", "comma-separated faults: skip-norm-check, flip-y, corrupt-c, reuse-rnd (default: all)")
+	paramSet := fs.String("params", "ML-DSA-44", "parameter set of the generated key")
+	implPath := fs.String("impl", "", "assess an implementation speaking the kats.Exec protocol with op=sign-fault instead of the built-in signer")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-call timeout for -impl")
+	limitFlags := addImplFlags(fs)
+	session := fs.Bool("session", false, "keep -impl running and speak the JSON-lines protocol (docs/execsign-protocol.md)")
+	messages := fs.Int("messages", 4, "messages signed per fault")
+	attempts := fs.Int("attempts", 8, "rejection-loop iterations each transient fault is swept over")
+	seed := fs.Int64("seed", 1, "seed for the key, messages and fault positions")
+	counter := fs.String("countermeasure", "", "deployed countermeasure (verify-after-sign|redundant-sign); only leaks it misses fail the run")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON results")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "fault-sim: unexpected positional arguments")
+		return 1
+	}
+	if *counter != "" && *counter != faultsim.CounterVerify && *counter != faultsim.CounterRedundant {
+		fmt.Fprintf(a.Err, "fault-sim: unknown countermeasure %q\n", *counter)
+		return 1
+	}
+	params, err := mldsa.FromName(*paramSet)
+	if err != nil {
+		fmt.Fprintf(a.Err, "fault-sim: %v\n", err)
+		return 1
+	}
+
+	faults := mldsa.FaultKinds
+	if *faultList != "" {
+		faults = nil
+		for _, name := range strings.Split(*faultList, ",") {
+			kind := mldsa.FaultKind(strings.TrimSpace(name))
+			known := false
+			for _, k := range mldsa.FaultKinds {
+				known = known || k == kind
+			}
+			if !known {
+				fmt.Fprintf(a.Err, "fault-sim: unknown fault %q\n", kind)
+				return 1
+			}
+			faults = append(faults, kind)
+		}
+	}
+
+	var signer faultsim.Signer = kats.Builtin{}
+	if *implPath != "" {
+		target := kats.Exec{Bin: execsign.Bin{Path: *implPath, Timeout: *timeout, Limits: limitFlags.limits()}}
+		if *session {
+			s, err := execsign.StartSession(context.Background(), target.Bin)
+			if err != nil {
+				fmt.Fprintf(a.Err, "fault-sim: %v\n", err)
+				return 1
+			}
+			defer s.Close()
+			target.Session = s
+		}
+		signer = target
+	}
+
+	opts := faultsim.Options{Messages: *messages, Attempts: *attempts, Seed: *seed}
+	var results []*faultsim.Result
+	leaks, failing := 0, 0
+	for _, kind := range faults {
+		res, err := faultsim.Run(signer, params, kind, opts)
+		if err != nil {
+			fmt.Fprintf(a.Err, "fault-sim: %s: %v\n", kind, err)
+			return 1
+		}
+		if res.Effective == 0 && res.Failed == res.Signed {
+			fmt.Fprintf(a.Err, "fault-sim: %s: every faulty signing call failed; does the implementation support sign-fault?\n", kind)
+			return 1
+		}
+		if res.Leak() {
+			leaks++
+			if escaped(res, *counter) {
+				failing++
+			}
+		}
+		results = append(results, res)
+		if !*jsonOut {
+			printFaultResult(a.Out, res)
+		}
+	}
+
+	if *jsonOut {
+		payload := struct {
+			Countermeasure string             `json:"countermeasure,omitempty"`
+			Leaks          int                `json:"leaks"`
+			Unmitigated    int                `json:"unmitigated"`
+			Results        []*faultsim.Result `json:"results"`
+		}{*counter, leaks, failing, results}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "fault-sim: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(a.Out, "Faults: %d, leaking: %d", len(results), leaks)
+		if *counter != "" {
+			fmt.Fprintf(a.Out, ", leaking past %s: %d", *counter, failing)
+		}
+		fmt.Fprintln(a.Out)
+	}
+
+	if failing > 0 {
+		return 1
+	}
+	return 0
+}
+
+// escaped reports whether a leaking result has pairs the countermeasure
+// missed; with no countermeasure every leak counts.
+func escaped(r *faultsim.Result, counter string) bool {
+	if counter == "" {
+		return true
+	}
+	for _, c := range r.Countermeasures {
+		if c.Name == counter {
+			return c.MissedLeaks > 0
+		}
+	}
+	return true
+}
+
+func printFaultResult(w io.Writer, r *faultsim.Result) {
+	fmt.Fprintf(w, "%s/%s: %s (%d of %d faulty signatures changed, %d failed)\n",
+		r.Fault, r.ParameterSet, strings.ToUpper(r.Verdict), r.Effective, r.Signed, r.Failed)
+	for _, check := range faultsim.Checks {
+		if n := r.Leaks[check]; n > 0 {
+			fmt.Fprintf(w, "    %s: %d leaking pairs\n", check, n)
+		}
+	}
+	for _, c := range r.Countermeasures {
+		fmt.Fprintf(w, "    %s caught %d/%d, missed %d leaking pairs\n", c.Name, c.Caught, c.Of, c.MissedLeaks)
+	}
+}
@@ -0,0 +1,78 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/faultsim"
+)
+
+func TestApp_FaultSimCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+
+	// corrupt-c leaks but verify-after-sign catches every faulty signature.
+	code := app.Run([]string{"fault-sim", "-fault", "corrupt-c", "-countermeasure", "verify-after-sign", "-json"})
+	if code != 0 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	var payload struct {
+		Countermeasure string             `json:"countermeasure"`
+		Leaks          int                `json:"leaks"`
+		Unmitigated    int                `json:"unmitigated"`
+		Results        []*faultsim.Result `json:"results"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Countermeasure != faultsim.CounterVerify || payload.Leaks != 1 || payload.Unmitigated != 0 ||
+		len(payload.Results) != 1 || payload.Results[0].Leaks[faultsim.CheckKeyRecovery] == 0 {
+		t.Errorf("payload %+v", payload)
+	}
+
+	// flip-y yields valid signatures, so verifying them does not help.
+	out.Reset()
+	if code := app.Run([]string{"fault-sim", "-fault", "flip-y", "-countermeasure", "verify-after-sign"}); code != 1 {
+		t.Errorf("flip-y past verify-after-sign: exit %d, stderr %q", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "flip-y/ML-DSA-44: LEAK") || !strings.Contains(out.String(), "leaking past verify-after-sign: 1") {
+		t.Errorf("output:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{
+		{"fault-sim", "-fault", "laser"},
+		{"fault-sim", "-countermeasure", "prayer"},
+		{"fault-sim", "-params", "ML-DSA-1"},
+		{"fault-sim", "extra"},
+	} {
+		if code := app.Run(args); code != 1 {
+			t.Errorf("%v: exit %d", args, code)
+		}
+	}
+}
+
+func TestApp_FaultSimImpl(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	t.Setenv(execHelperEnv, "jsonl")
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	code := app.Run([]string{"fault-sim", "-impl", exe, "-session", "-fault", "reuse-rnd", "-messages", "2"})
+	if code != 1 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	for _, want := range []string{"reuse-rnd/ML-DSA-44: LEAK (2 of 2", "rnd-reuse: 2 leaking pairs", "Faults: 1, leaking: 1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// baselineFlags are the -baseline and -write-baseline switches shared by
+// the commands that report diag findings.
+type baselineFlags struct {
+	load, write *string
+	baseline    *diag.Baseline
+}
+
+func addBaselineFlags(fs *flag.FlagSet) *baselineFlags {
+	return &baselineFlags{
+		load:  fs.String("baseline", "", "baseline JSON of accepted rules; their findings no longer fail the run"),
+		write: fs.String("write-baseline", "", "write a baseline accepting every finding of this run to this file"),
+	}
+}
+
+// open loads the -baseline file, if one was given.
+func (b *baselineFlags) open() error {
+	if *b.load == "" {
+		return nil
+	}
+	baseline, err := diag.LoadBaseline(*b.load)
+	if err != nil {
+		return err
+	}
+	b.baseline = baseline
+	return nil
+}
+
+// apply splits findings into kept and suppressed after saving them.
+func (b *baselineFlags) apply(findings []diag.Finding) (kept, suppressed []diag.Finding, err error) {
+	if err := b.save(findings); err != nil {
+		return nil, nil, err
+	}
+	kept, suppressed = b.baseline.Apply(findings)
+	return kept, suppressed, nil
+}
+
+// save writes a baseline accepting every rule of findings to the
+// -write-baseline file, if one was given.
+func (b *baselineFlags) save(findings []diag.Finding) error {
+	if *b.write == "" {
+		return nil
+	}
+	f, err := os.Create(*b.write)
+	if err != nil {
+		return err
+	}
+	if err := diag.NewBaseline(findings).Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printFindings lists kept findings with their evidence and remediation,
+// and names the suppressed ones. It prints nothing when both are empty.
+func printFindings(w io.Writer, kept, suppressed []diag.Finding) {
+	if len(kept) == 0 && len(suppressed) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Findings: %d\n", len(kept))
+	for _, f := range kept {
+		fmt.Fprintf(w, "  [%s] %s: %s (%d cases)\n", strings.ToUpper(f.Severity), f.RuleID, f.Title, f.Count)
+		for _, ev := range f.Evidence {
+			fmt.Fprintf(w, "      %s\n", ev)
+		}
+		if f.Remediation != "" {
+			fmt.Fprintf(w, "      Fix: %s\n", f.Remediation)
+		}
+	}
+	if len(suppressed) > 0 {
+		ids := make([]string, 0, len(suppressed))
+		for _, f := range suppressed {
+			ids = append(ids, fmt.Sprintf("%s (%d cases)", f.RuleID, f.Count))
+		}
+		fmt.Fprintf(w, "Suppressed by baseline: %s\n", strings.Join(ids, ", "))
+	}
+}
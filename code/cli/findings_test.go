@@ -0,0 +1,88 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+func TestApp_KATVerifyFindingsAndBaseline(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	vectors := writeBadSigVer(t)
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+
+	code := app.Run([]string{"kat-verify", "-vectors", vectors, "-json", "-write-baseline", baselinePath})
+	if code != 1 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	var payload struct {
+		Findings []diag.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Findings) != 1 {
+		t.Fatalf("findings %+v", payload.Findings)
+	}
+	f := payload.Findings[0]
+	if f.RuleID != diag.RuleRejectValid || f.Severity != diag.SeverityHigh || f.Count != 1 ||
+		f.Remediation == "" || len(f.Evidence) != 1 || !strings.HasPrefix(f.Evidence[0], "tgId=7 tcId=3") {
+		t.Errorf("finding %+v", f)
+	}
+	written, err := diag.LoadBaseline(baselinePath)
+	if err != nil || !written.Suppresses(diag.RuleRejectValid) {
+		t.Fatalf("written baseline %+v, %v", written, err)
+	}
+
+	// With the baseline the known finding no longer fails the run.
+	out.Reset()
+	if code := app.Run([]string{"kat-verify", "-vectors", vectors, "-baseline", baselinePath}); code != 0 {
+		t.Fatalf("exit with baseline = %d, stdout=%q, stderr=%q", code, out.String(), errOut.String())
+	}
+	if !strings.Contains(out.String(), "Suppressed by baseline: DV-REJECT-VALID (1 cases)") {
+		t.Errorf("text output: %s", out.String())
+	}
+
+	out.Reset()
+	if code := app.Run([]string{"kat-verify", "-vectors", vectors, "-baseline", baselinePath, "-format", "sarif"}); code != 0 {
+		t.Fatalf("exit = %d", code)
+	}
+	if !strings.Contains(out.String(), `"suppressions"`) {
+		t.Errorf("SARIF lacks the suppression: %s", out.String())
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"schemaVersion":2,"suppress":[{"ruleId":"DV-NOPE"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	errOut.Reset()
+	if code := app.Run([]string{"kat-verify", "-baseline", bad}); code != 1 || !strings.Contains(errOut.String(), "unknown rule") {
+		t.Errorf("bad baseline: exit %d, stderr %q", code, errOut.String())
+	}
+}
+
+func TestApp_WycheproofFindings(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	if code := app.Run([]string{"wycheproof", "-json"}); code != 0 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	var payload struct {
+		Findings []diag.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Findings == nil || len(payload.Findings) != 0 {
+		t.Errorf("builtin verifier: findings %+v", payload.Findings)
+	}
+}
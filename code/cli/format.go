@@ -0,0 +1,58 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// Output formats shared by the vector runners.
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatJUnit = "junit"
+	formatSARIF = "sarif"
+)
+
+const projectURI = "https://github.com/codethor0/dilivet"
+
+// addFormatFlags registers -format and the older -json switch, which
+// stays as shorthand for -format json.
+func addFormatFlags(fs *flag.FlagSet) (format *string, jsonOut *bool) {
+	format = fs.String("format", formatText, "output format (text|json|junit|sarif)")
+	jsonOut = fs.Bool("json", false, "emit machine-readable JSON summary (same as -format json)")
+	return format, jsonOut
+}
+
+// resolveFormat checks the parsed -format and -json values and returns the
+// format to write.
+func resolveFormat(format string, jsonOut bool) (string, error) {
+	switch format {
+	case formatText, formatJSON, formatJUnit, formatSARIF:
+	default:
+		return "", fmt.Errorf("unknown format %q (want text, json, junit or sarif)", format)
+	}
+	if jsonOut {
+		if format != formatText && format != formatJSON {
+			return "", fmt.Errorf("-json conflicts with -format %s", format)
+		}
+		return formatJSON, nil
+	}
+	return format, nil
+}
+
+// writeDiagCases writes cases as JUnit XML or SARIF. name titles the
+// JUnit report and artifact is the vector file the SARIF results point at,
+// named relative to the module root (kats.RelPath).
+func (a *App) writeDiagCases(format, name, artifact string, cases []diag.Case) error {
+	if format == formatJUnit {
+		return diag.WriteJUnit(a.Out, name, cases)
+	}
+	tool := diag.Tool{Name: a.Name, Version: a.Version, URI: projectURI}
+	return diag.WriteSARIF(a.Out, tool, kats.RelPath(artifact), cases)
+}
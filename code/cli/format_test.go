@@ -0,0 +1,161 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+type junitDoc struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Suites   []struct {
+		Name  string `xml:"name,attr"`
+		Cases []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Type    string `xml:"type,attr"`
+				Message string `xml:"message,attr"`
+			} `xml:"failure"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+// writeBadSigVer writes one internal-interface sigVer case whose signature
+// cannot verify although testPassed is true.
+func writeBadSigVer(t *testing.T) string {
+	t.Helper()
+	payload := map[string]any{
+		"algorithm": "ML-DSA", "mode": "sigVer", "revision": "FIPS204",
+		"testGroups": []map[string]any{{
+			"tgId": 7, "testType": "AFT", "parameterSet": "ML-DSA-44",
+			"signatureInterface": "internal", "preHash": "pure",
+			"tests": []map[string]any{{
+				"tcId": 3, "testPassed": true, "pk": strings.Repeat("AA", 1312),
+				"message": "00", "signature": strings.Repeat("00", 2420),
+			}},
+		}},
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "sigver.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApp_KATVerifyJUnit(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	if code := app.Run([]string{"kat-verify", "-format", "junit"}); code != 0 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	var doc junitDoc
+	if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("parse junit: %v", err)
+	}
+	if doc.Tests == 0 || doc.Failures != 0 || len(doc.Suites) < 2 || !strings.HasPrefix(doc.Suites[0].Name, "tgId=") {
+		t.Fatalf("unexpected report: %+v", doc)
+	}
+
+	out.Reset()
+	if code := app.Run([]string{"kat-verify", "-vectors", writeBadSigVer(t), "-format", "junit"}); code != 1 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	doc = junitDoc{}
+	if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("parse junit: %v", err)
+	}
+	if doc.Failures != 1 || doc.Suites[0].Name != "tgId=7 ML-DSA-44 internal" {
+		t.Fatalf("unexpected report: %s", out.String())
+	}
+	c := doc.Suites[0].Cases[0]
+	if c.Name != "tcId=3" || c.Failure == nil || c.Failure.Type == "" || !strings.Contains(c.Failure.Message, "testPassed=true") {
+		t.Errorf("testcase %+v", c)
+	}
+}
+
+func TestApp_KATVerifySARIF(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	path := writeBadSigVer(t)
+	if code := app.Run([]string{"kat-verify", "-vectors", path, "-format", "sarif"}); code != 1 {
+		t.Fatalf("exit = %d, stderr=%q", code, errOut.String())
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("parse sarif: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("unexpected log: %s", out.String())
+	}
+	run := log.Runs[0]
+	r := run.Results[0]
+	if run.Tool.Driver.Name != "dilivet" || r.Level != "error" || r.RuleID != diag.RuleRejectValid ||
+		run.Tool.Driver.Rules[0].ID != r.RuleID || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != filepath.Base(path) {
+		t.Errorf("unexpected log: %s", out.String())
+	}
+}
+
+func TestApp_RunnerFormats(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	for _, args := range [][]string{
+		{"wycheproof", "-format", "junit"},
+		{"kat-rsp", "-format", "junit"},
+		{"kat-keygen", "-format", "sarif"},
+	} {
+		out.Reset()
+		if code := app.Run(args); code != 0 {
+			t.Fatalf("%v: exit = %d, stderr=%q", args, code, errOut.String())
+		}
+		if !strings.HasPrefix(out.String(), "<?xml") && !strings.Contains(out.String(), `"version": "2.1.0"`) {
+			t.Errorf("%v: unexpected output %q", args, out.String())
+		}
+	}
+
+	for _, args := range [][]string{
+		{"kat-verify", "-format", "xml"},
+		{"kat-siggen", "-json", "-format", "junit"},
+	} {
+		errOut.Reset()
+		if code := app.Run(args); code != 1 || errOut.Len() == 0 {
+			t.Errorf("%v: exit %d, stderr %q", args, code, errOut.String())
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/corpus"
+	"github.com/codethor0/dilivet/code/fuzztarget"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+func (a *App) runFuzzTarget(args []string) int {
+	fs := flag.NewFlagSet("fuzz-target", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	implPath := fs.String("impl", "", "external verifier speaking the kats.Exec protocol (required)")
+	duration := fs.Duration("duration", time.Minute, "how long to fuzz (0 = until -iterations)")
+	iterations := fs.Int("iterations", 0, "stop after this many mutated inputs (0 = until -duration)")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-call timeout; a call that exceeds it is a hang")
+	limitFlags := addImplFlags(fs)
+	session := fs.Bool("session", false, "keep -impl running and speak the JSON-lines protocol (docs/execsign-protocol.md)")
+	corpusPath := fs.String("corpus", corpus.DefaultDir, "Go fuzz corpus directory to seed from (skipped if missing)")
+	repeat := fs.Int("repeat", 2, "calls per input, to catch non-deterministic answers")
+	maxFindings := fs.Int("max-findings", 32, "distinct findings to keep")
+	seed := fs.Int64("seed", 1, "seed for mutation")
+	outDir := fs.String("out", "fuzz-target-findings", "directory for one wycheproof suite per finding (empty = do not write)")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON report")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "fuzz-target: unexpected positional arguments")
+		return 1
+	}
+	if *implPath == "" {
+		fmt.Fprintln(a.Err, "fuzz-target: -impl is required")
+		return 1
+	}
+
+	corpusDir, err := kats.ResolvePath(*corpusPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "fuzz-target: %v\n", err)
+		return 1
+	}
+	seeds, err := fuzztarget.Seeds(corpusDir)
+	if err != nil {
+		fmt.Fprintf(a.Err, "fuzz-target: %v\n", err)
+		return 1
+	}
+
+	limits := limitFlags.limits()
+	target := kats.Exec{Bin: execsign.Bin{Path: *implPath, Timeout: *timeout, Limits: limits}}
+	if *session {
+		s, err := execsign.StartSession(context.Background(), target.Bin)
+		if err != nil {
+			fmt.Fprintf(a.Err, "fuzz-target: %v\n", err)
+			return 1
+		}
+		defer s.Close()
+		// Crashes are what we are looking for; keep restarting.
+		s.MaxRestarts = math.MaxInt32
+		target.Session = s
+	}
+
+	report, err := fuzztarget.Run(context.Background(), target.VerifyInternal, fuzztarget.Reference, seeds, fuzztarget.Options{
+		Duration: *duration, Iterations: *iterations, Repeat: *repeat, MaxFindings: *maxFindings, Seed: *seed,
+	})
+	if err != nil {
+		fmt.Fprintf(a.Err, "fuzz-target: %v\n", err)
+		return 1
+	}
+
+	var files []string
+	if *outDir != "" && len(report.Findings) > 0 {
+		if files, err = writeFindings(*outDir, report.Findings); err != nil {
+			fmt.Fprintf(a.Err, "fuzz-target: %v\n", err)
+			return 1
+		}
+	}
+
+	if *jsonOut {
+		payload := struct {
+			*fuzztarget.Report
+			Files []string `json:"files,omitempty"`
+		}{report, files}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "fuzz-target: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		a.printFuzzReport(*implPath, report, files)
+	}
+
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
+
+// writeFindings saves each finding as a one-case suite named after its
+// kind and index, and returns the paths.
+func writeFindings(dir string, findings []fuzztarget.Finding) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for i, f := range findings {
+		id := fmt.Sprintf("%s-%03d", f.Kind, i+1)
+		suite := kat.Suite{Name: "fuzz-target " + id, Cases: []kat.SuiteCase{f.Case(id)}}
+		data, err := json.MarshalIndent(suite, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, id+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func (a *App) printFuzzReport(impl string, r *fuzztarget.Report, files []string) {
+	fmt.Fprintf(a.Out, "Target: %s\n", impl)
+	fmt.Fprintf(a.Out, "Seeds: %d\n", r.Seeds)
+	fmt.Fprintf(a.Out, "Executions: %d in %s (%.0f/s)\n", r.Executions, r.Elapsed.Round(time.Millisecond),
+		float64(r.Executions)/math.Max(r.Elapsed.Seconds(), 1e-9))
+	kinds := make([]string, 0, len(r.Counts))
+	for kind := range r.Counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(a.Out, "  %-17s %d inputs\n", kind, r.Counts[kind])
+	}
+	fmt.Fprintf(a.Out, "Findings: %d (duplicates dropped: %d)\n", len(r.Findings), r.Duplicates)
+	for i, f := range r.Findings {
+		fmt.Fprintf(a.Out, "\n  %s [%s] seed %s via %v\n", f.Kind, f.ParameterSet, f.Seed, f.Mutators)
+		fmt.Fprintf(a.Out, "    reference %s, target", f.Reference)
+		for _, o := range f.Outcomes {
+			fmt.Fprintf(a.Out, " %s", o.Value)
+		}
+		fmt.Fprintln(a.Out)
+		if f.Original != nil {
+			fmt.Fprintf(a.Out, "    minimized: pk %d, msg %d, sig %d bytes\n", len(f.Input.PK), len(f.Input.Msg), len(f.Input.Sig))
+		}
+		if i < len(files) {
+			fmt.Fprintf(a.Out, "    reproducer: %s\n", files[i])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+func TestApp_FuzzTargetCommand(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	t.Setenv(execHelperEnv, "accept-jsonl")
+	outDir := t.TempDir()
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	code := app.Run([]string{"fuzz-target", "-impl", exe, "-session", "-duration", "0", "-iterations", "20",
+		"-max-findings", "3", "-out", outDir, "-json"})
+	if code != 1 {
+		t.Fatalf("fuzz-target exit = %d, want 1 for an accept-all verifier; stderr=%q", code, errOut.String())
+	}
+	var report struct {
+		Executions int `json:"executions"`
+		Findings   []struct {
+			Kind      string `json:"kind"`
+			Reference string `json:"reference"`
+		} `json:"findings"`
+		Files []string `json:"files"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	if len(report.Findings) != 3 || len(report.Files) != 3 || report.Executions <= 20 {
+		t.Fatalf("unexpected report: %s", out.String())
+	}
+	for i, f := range report.Findings {
+		if f.Kind != "disagreement" || f.Reference != "valid=false" {
+			t.Errorf("finding %+v", f)
+		}
+		suite, err := kat.LoadSuite(report.Files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := suite.Cases[0]; c.Expected != kat.ExpectReject || c.Category != "disagreement" {
+			t.Errorf("reproducer case %+v", c)
+		}
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := app.Run([]string{"fuzz-target"}); code != 1 || errOut.Len() == 0 {
+		t.Errorf("missing -impl: exit %d, stderr %q", code, errOut.String())
+	}
+}
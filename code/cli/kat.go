@@ -4,25 +4,33 @@
 package cli
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"path/filepath"
+	"sort"
+	"time"
 
-	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/adapter/execsign"
 	"github.com/codethor0/dilivet/code/clean/kats"
 	"github.com/codethor0/dilivet/code/diag"
 )
 
-const defaultSigVerVectors = "code/clean/testdata/kats/ml-dsa/ML-DSA-sigVer-FIPS204-internalProjection.json"
+const (
+	defaultSigVerVectors = "code/clean/testdata/kats/ml-dsa/ML-DSA-sigVer-FIPS204-internalProjection.json"
+	defaultKeyGenVectors = "code/clean/testdata/kats/ml-dsa/ML-DSA-keyGen-FIPS204-internalProjection.json"
+	defaultSigGenVectors = "code/clean/testdata/kats/ml-dsa/ML-DSA-sigGen-FIPS204-internalProjection.json"
+)
 
 func (a *App) runKATVerify(args []string) int {
 	fs := flag.NewFlagSet("kat-verify", flag.ContinueOnError)
 	fs.SetOutput(a.Err)
 
 	vectorsPath := fs.String("vectors", defaultSigVerVectors, "path to ACVP sigVer vector JSON")
-	jsonOut := fs.Bool("json", false, "emit machine-readable JSON summary")
+	expectedPath := fs.String("expected", "", "ACVP expectedResults JSON to join with a prompt file")
+	format, jsonOut := addFormatFlags(fs)
+	baseline := addBaselineFlags(fs)
 
 	if err := fs.Parse(args); err != nil {
 		return exitFromFlagError(err)
@@ -31,86 +39,280 @@ func (a *App) runKATVerify(args []string) int {
 		fmt.Fprintln(a.Err, "kat-verify: unexpected positional arguments")
 		return 1
 	}
+	outFormat, err := resolveFormat(*format, *jsonOut)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-verify: %v\n", err)
+		return 1
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "kat-verify: %v\n", err)
+		return 1
+	}
 
 	path := *vectorsPath
 	if !filepath.IsAbs(path) {
 		path = filepath.Clean(path)
 	}
 
-	vectors, err := kats.LoadSigVerVectors(path)
+	vectors, err := kats.LoadSigVerPair(path, *expectedPath)
 	if err != nil {
 		fmt.Fprintf(a.Err, "kat-verify: load vectors: %v\n", err)
 		return 1
 	}
 
-	report := diag.Report{}
+	result := kats.RunSigVer(vectors, kats.Builtin{})
+	return a.writeKATResult("kat-verify", path, *expectedPath, *vectorsPath, result, outFormat, baseline, "")
+}
 
-	for _, tg := range vectors.TestGroups {
-		for _, tc := range tg.Tests {
-			report.TotalTests++
+func (a *App) runKATKeyGen(args []string) int {
+	fs := flag.NewFlagSet("kat-keygen", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
 
-			pk, err := hex.DecodeString(tc.Public)
-			if err != nil {
-				report.DecodeFailures++
-				continue
-			}
-			sig, err := hex.DecodeString(tc.Signature)
-			if err != nil {
-				report.DecodeFailures++
-				continue
-			}
-			msg, err := hex.DecodeString(tc.Message)
-			if err != nil {
-				report.DecodeFailures++
-				continue
-			}
+	vectorsPath := fs.String("vectors", defaultKeyGenVectors, "path to ACVP keyGen vector JSON")
+	expectedPath := fs.String("expected", "", "ACVP expectedResults JSON to join with a prompt file")
+	format, jsonOut := addFormatFlags(fs)
+	baseline := addBaselineFlags(fs)
+	impl := fs.String("impl", "", "external implementation binary (default: built-in)")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-case timeout for -impl")
+	limitFlags := addImplFlags(fs)
 
-			ok, verr := mldsa.Verify(pk, msg, sig)
-			switch {
-			case verr == nil && ok:
-				report.StrictPasses++
-			case verr == nil && !ok:
-				// Verification returned false (signature rejected)
-				report.StructuralFailures++
-			default:
-				// Verification error (unpacking, format, etc.)
-				report.StructuralFailures++
-			}
-		}
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "kat-keygen: unexpected positional arguments")
+		return 1
+	}
+	outFormat, err := resolveFormat(*format, *jsonOut)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-keygen: %v\n", err)
+		return 1
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "kat-keygen: %v\n", err)
+		return 1
+	}
+
+	path := *vectorsPath
+	if !filepath.IsAbs(path) {
+		path = filepath.Clean(path)
+	}
+
+	vectors, err := kats.LoadKeyGenPair(path, *expectedPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-keygen: load vectors: %v\n", err)
+		return 1
+	}
+
+	var gen kats.KeyGenerator = kats.Builtin{}
+	if *impl != "" {
+		gen = kats.Exec{Bin: execsign.Bin{Path: *impl, Timeout: *timeout, Limits: limitFlags.limits()}}
+	}
+
+	result := kats.RunKeyGen(vectors, gen)
+	return a.writeKATResult("kat-keygen", path, *expectedPath, *vectorsPath, result, outFormat, baseline, "")
+}
+
+func (a *App) runKATSigGen(args []string) int {
+	fs := flag.NewFlagSet("kat-siggen", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	vectorsPath := fs.String("vectors", defaultSigGenVectors, "path to ACVP sigGen vector JSON")
+	expectedPath := fs.String("expected", "", "ACVP expectedResults JSON to join with a prompt file")
+	format, jsonOut := addFormatFlags(fs)
+	baseline := addBaselineFlags(fs)
+	impl := fs.String("impl", "", "external implementation binary (default: built-in)")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-case timeout for -impl")
+	limitFlags := addImplFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "kat-siggen: unexpected positional arguments")
+		return 1
+	}
+	outFormat, err := resolveFormat(*format, *jsonOut)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-siggen: %v\n", err)
+		return 1
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "kat-siggen: %v\n", err)
+		return 1
+	}
+
+	path := *vectorsPath
+	if !filepath.IsAbs(path) {
+		path = filepath.Clean(path)
+	}
+
+	vectors, err := kats.LoadSigGenPair(path, *expectedPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-siggen: load vectors: %v\n", err)
+		return 1
+	}
+
+	var signer kats.Signer = kats.Builtin{}
+	if *impl != "" {
+		signer = kats.Exec{Bin: execsign.Bin{Path: *impl, Timeout: *timeout, Limits: limitFlags.limits()}}
 	}
 
-	if *jsonOut {
+	result := kats.RunSigGen(vectors, signer)
+	return a.writeKATResult("kat-siggen", path, *expectedPath, *vectorsPath, result, outFormat, baseline,
+		"Hedged cases without a published rnd are signed with fresh randomness and verified.")
+}
+
+// writeKATResult prints a KAT run in format and returns the exit code:
+// 1 if a case failed under a rule the baseline does not accept. The
+// report's environment records the vector file and, if given, the
+// expectedResults file it was joined with.
+func (a *App) writeKATResult(cmd, path, expectedPath, displayPath string, result *kats.Result, format string, bf *baselineFlags, note string) int {
+	env, err := vectorEnvironment(a.Version, path, expectedPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "%s: %v\n", cmd, err)
+		return 1
+	}
+	result.Report.Environment = env
+	report := result.Report
+	findings, suppressed, err := bf.apply(result.Findings())
+	if err != nil {
+		fmt.Fprintf(a.Err, "%s: write baseline: %v\n", cmd, err)
+		return 1
+	}
+
+	switch format {
+	case formatJUnit, formatSARIF:
+		cases := result.DiagCases()
+		bf.baseline.MarkCases(cases)
+		if err := a.writeDiagCases(format, cmd, path, cases); err != nil {
+			fmt.Fprintf(a.Err, "%s: encode %s: %v\n", cmd, format, err)
+			return 1
+		}
+	case formatJSON:
 		payload := struct {
 			Vectors string `json:"vectors"`
 			diag.Report
-			Note string `json:"note,omitempty"`
+			Findings   []diag.Finding     `json:"findings"`
+			Suppressed []diag.Finding     `json:"suppressed,omitempty"`
+			Groups     []kats.GroupResult `json:"groups,omitempty"`
+			Cases      []kats.CaseResult  `json:"cases"`
+			Note       string             `json:"note,omitempty"`
 		}{
-			Vectors: path,
-			Report:  report,
-			Note:    "Full ML-DSA verification is implemented; counts reflect complete cryptographic verification.",
+			Vectors:    path,
+			Report:     report,
+			Findings:   findings,
+			Suppressed: suppressed,
+			Groups:     result.Groups,
+			Cases:      result.Cases,
+			Note:       note,
 		}
 		enc := json.NewEncoder(a.Out)
 		enc.SetIndent("", "  ")
 		if err := enc.Encode(payload); err != nil {
-			fmt.Fprintf(a.Err, "kat-verify: encode json: %v\n", err)
+			fmt.Fprintf(a.Err, "%s: encode json: %v\n", cmd, err)
 			return 1
 		}
-	} else {
-		fmt.Fprintf(a.Out, "Vectors: %s\n", *vectorsPath)
+	default:
+		fmt.Fprintf(a.Out, "Vectors: %s\n", displayPath)
 		fmt.Fprintf(a.Out, "Total tests: %d\n", report.TotalTests)
 		fmt.Fprintf(a.Out, "Strict passes: %d\n", report.StrictPasses)
 		fmt.Fprintf(a.Out, "Structural warnings: %d\n", report.StructuralWarnings)
 		fmt.Fprintf(a.Out, "Structural failures: %d\n", report.StructuralFailures)
 		fmt.Fprintf(a.Out, "Decode failures: %d\n", report.DecodeFailures)
-		fmt.Fprintf(a.Out, "Note: full ML-DSA verification is implemented; results indicate complete cryptographic verification.\n")
+		fmt.Fprintf(a.Out, "Skipped: %d\n", report.Skipped)
+		printReportDetails(a.Out, &report)
+		for _, g := range result.Groups {
+			fmt.Fprintf(a.Out, "  tgId=%d %s", g.GroupID, g.ParameterSet)
+			if g.Mode != "" {
+				fmt.Fprintf(a.Out, " %s", g.Mode)
+			}
+			if g.CornerCase != "" {
+				fmt.Fprintf(a.Out, " cornerCase=%s", g.CornerCase)
+			}
+			fmt.Fprintf(a.Out, ": %d/%d passed", g.Passed, g.Total)
+			if g.Failed > 0 {
+				fmt.Fprintf(a.Out, ", %d failed", g.Failed)
+			}
+			if g.Skipped > 0 {
+				fmt.Fprintf(a.Out, ", %d skipped", g.Skipped)
+			}
+			fmt.Fprintln(a.Out)
+		}
+		for _, c := range result.Cases {
+			if c.Outcome == kats.OutcomePass {
+				continue
+			}
+			fmt.Fprintf(a.Out, "  tgId=%d tcId=%d %s %s: %s\n", c.GroupID, c.CaseID, c.ParameterSet, c.Outcome, c.Reason)
+		}
+		printFindings(a.Out, findings, suppressed)
+		if note != "" {
+			fmt.Fprintf(a.Out, "Note: %s\n", note)
+		}
 	}
 
-	if report.DecodeFailures > 0 || report.StructuralFailures > 0 {
+	if result.Failed() && len(findings) > 0 {
 		return 1
 	}
 	return 0
 }
 
+// vectorEnvironment is diag.NewEnvironment for vector paths as the loaders
+// see them: relative paths resolve against the module root, but the report
+// keeps the path the user gave.
+func vectorEnvironment(version string, paths ...string) (*diag.Environment, error) {
+	var given, resolved []string
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		r, err := kats.ResolvePath(p)
+		if err != nil {
+			return nil, err
+		}
+		given, resolved = append(given, p), append(resolved, r)
+	}
+	env, err := diag.NewEnvironment(version, resolved...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range env.VectorFiles {
+		env.VectorFiles[i].Path = given[i]
+	}
+	return env, nil
+}
+
+// printReportDetails prints the per-parameter-set counts, the failure
+// stages and the case durations of report.
+func printReportDetails(w io.Writer, report *diag.Report) {
+	for _, p := range report.ByParameterSet {
+		fmt.Fprintf(w, "Parameter set %s: %d/%d passed", p.Name, p.Passed+p.Warnings, p.Total)
+		if failed := p.Failed + p.DecodeFailures; failed > 0 {
+			fmt.Fprintf(w, ", %d failed", failed)
+		}
+		if p.Skipped > 0 {
+			fmt.Fprintf(w, ", %d skipped", p.Skipped)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(report.FailuresByStage) > 0 {
+		stages := make([]string, 0, len(report.FailuresByStage))
+		for stage := range report.FailuresByStage {
+			stages = append(stages, stage)
+		}
+		sort.Strings(stages)
+		fmt.Fprint(w, "Failures by stage:")
+		for _, stage := range stages {
+			fmt.Fprintf(w, " %s=%d", stage, report.FailuresByStage[stage])
+		}
+		fmt.Fprintln(w)
+	}
+	if d := report.Durations; d != nil {
+		fmt.Fprintf(w, "Case time: min %s, median %s, p99 %s (%d cases)\n",
+			time.Duration(d.MinNS), time.Duration(d.MedianNS), time.Duration(d.P99NS), d.Count)
+	}
+}
+
 func exitFromFlagError(err error) int {
 	if err == flag.ErrHelp {
 		return 0
@@ -0,0 +1,29 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"flag"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+)
+
+// implLimitFlags are the resource limits every command taking -impl
+// applies to the processes it starts.
+type implLimitFlags struct {
+	maxMemory, maxCPU, maxFiles *uint64
+}
+
+func addImplFlags(fs *flag.FlagSet) *implLimitFlags {
+	return &implLimitFlags{
+		maxMemory: fs.Uint64("max-memory", 0, "address-space limit per -impl process in MiB (Linux; 0 = none)"),
+		maxCPU:    fs.Uint64("max-cpu", 0, "CPU-time limit per -impl process in seconds (Linux; 0 = none)"),
+		maxFiles:  fs.Uint64("max-files", 0, "open-file limit per -impl process (Linux; 0 = none)"),
+	}
+}
+
+// limits returns the parsed limits.
+func (f *implLimitFlags) limits() execsign.Limits {
+	return execsign.Limits{AddressSpace: *f.maxMemory << 20, CPUSeconds: *f.maxCPU, OpenFiles: *f.maxFiles}
+}
@@ -0,0 +1,38 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Every command taking -impl accepts the resource-limit flags and starts
+// its target through the limits wrapper.
+func TestApp_ImplLimits(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	t.Setenv(execHelperEnv, "jsonl")
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	code := app.Run([]string{"fault-sim", "-impl", exe, "-session", "-max-memory", "4096", "-max-files", "64",
+		"-fault", "reuse-rnd", "-messages", "2"})
+	if code != 1 || !strings.Contains(out.String(), "Faults: 1, leaking: 1") {
+		t.Fatalf("exit = %d, stdout=%q, stderr=%q", code, out.String(), errOut.String())
+	}
+
+	for _, cmd := range [][]string{{"acvp-respond"}, {"acvp", "run"}, {"kat-keygen"}, {"kat-siggen"}, {"ct-test"},
+		{"diff-impl"}, {"fuzz-target"}, {"vet"}} {
+		errOut.Reset()
+		app.Run(append(cmd, "-help"))
+		if !strings.Contains(errOut.String(), "-max-memory") {
+			t.Errorf("%s lacks -max-memory", strings.Join(cmd, " "))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/mutate"
+)
+
+const (
+	mutateFormatWycheproof = "wycheproof"
+	mutateFormatACVP       = "acvp"
+)
+
+func (a *App) runMutate(args []string) int {
+	fs := flag.NewFlagSet("mutate", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	pubPath := fs.String("pub", "", "path to ML-DSA public key")
+	sigPath := fs.String("sig", "", "path to a valid ML-DSA signature")
+	msgPath := fs.String("msg", "", "path to message bytes")
+	pubFormat := fs.String("pub-format", formatHex, "format of public key file (hex|raw)")
+	sigFormat := fs.String("sig-format", formatHex, "format of signature file (hex|raw)")
+	msgFormat := fs.String("msg-format", formatRaw, "format of message file (hex|raw)")
+	outPath := fs.String("out", "", "write the generated vectors to this file")
+	format := fs.String("format", mutateFormatWycheproof, "output shape (wycheproof|acvp)")
+	ctxHex := fs.String("ctx", "", "hex context: the signature is from external ML-DSA.Sign (acvp format only)")
+	name := fs.String("name", "mldsa-mutations", "suite name for wycheproof output")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "mutate: unexpected positional arguments")
+		return 1
+	}
+	if *pubPath == "" || *sigPath == "" || *msgPath == "" || *outPath == "" {
+		fmt.Fprintln(a.Err, "mutate: -pub, -sig, -msg and -out are required")
+		return 1
+	}
+
+	external := false
+	var ctx []byte
+	fs.Visit(func(f *flag.Flag) { external = external || f.Name == "ctx" })
+	switch *format {
+	case mutateFormatWycheproof:
+		if external {
+			fmt.Fprintln(a.Err, "mutate: -ctx needs -format acvp (suites are checked with Verify_internal)")
+			return 1
+		}
+	case mutateFormatACVP:
+		var err error
+		if ctx, err = hex.DecodeString(*ctxHex); err != nil {
+			fmt.Fprintf(a.Err, "mutate: -ctx: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(a.Err, "mutate: unknown format %q\n", *format)
+		return 1
+	}
+
+	pub, err := loadData(*pubPath, *pubFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "mutate: read public key: %v\n", err)
+		return 1
+	}
+	sig, err := loadData(*sigPath, *sigFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "mutate: read signature: %v\n", err)
+		return 1
+	}
+	msg, err := loadData(*msgPath, *msgFormat)
+	if err != nil {
+		fmt.Fprintf(a.Err, "mutate: read message: %v\n", err)
+		return 1
+	}
+
+	verify := mldsa.Verify
+	if external {
+		verify = func(pk, msg, sig []byte) (bool, error) { return mldsa.VerifyWithContext(pk, msg, ctx, sig) }
+	}
+	cases, err := mutate.Generate(pub, msg, sig, verify)
+	if err != nil {
+		fmt.Fprintf(a.Err, "mutate: %v\n", err)
+		return 1
+	}
+
+	var doc any = mutate.Suite(*name, msg, cases)
+	if *format == mutateFormatACVP {
+		doc = mutate.SigVer(msg, ctx, external, cases)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fmt.Fprintf(a.Err, "mutate: encode json: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*outPath, append(data, '\n'), 0o644); err != nil {
+		fmt.Fprintf(a.Err, "mutate: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(a.Out, "Wrote %d cases (%s, %s) to %s\n", len(cases), cases[0].ParameterSet, *format, *outPath)
+	for _, c := range cases {
+		fmt.Fprintf(a.Out, "  %-22s %-14s %s\n", c.Name, c.Component, c.Expected)
+	}
+	return 0
+}
@@ -0,0 +1,189 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+const defaultRSPFile = "code/clean/testdata/mldsa_kat.rsp"
+
+func (a *App) runKATRSP(args []string) int {
+	fs := flag.NewFlagSet("kat-rsp", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	rspPath := fs.String("rsp", defaultRSPFile, "NIST PQC signature .rsp file")
+	hedged := fs.Bool("hedged", false, "draw rnd from the DRBG (randomized signing) instead of rnd = 0^32")
+	keygenOnly := fs.Bool("keygen-only", false, "check pk/sk only, not sm")
+	format, jsonOut := addFormatFlags(fs)
+	baseline := addBaselineFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "kat-rsp: unexpected positional arguments")
+		return 1
+	}
+	outFormat, err := resolveFormat(*format, *jsonOut)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: %v\n", err)
+		return 1
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: %v\n", err)
+		return 1
+	}
+
+	path, err := kats.ResolvePath(*rspPath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: %v\n", err)
+		return 1
+	}
+	file, err := kat.LoadRSP(path)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: %v\n", err)
+		return 1
+	}
+	params, err := rspParams(file)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: %s: %v\n", path, err)
+		return 1
+	}
+
+	// Mirror the pq-crystals PQCgenKAT_sign driver: ξ is one randombytes(32)
+	// call, signing is ML-DSA.Sign with an empty context.
+	keygen := func(rng io.Reader) ([]byte, []byte, error) {
+		xi := make([]byte, mldsa.SeedBytes)
+		if _, err := rng.Read(xi); err != nil {
+			return nil, nil, err
+		}
+		return mldsa.KeyGen(params, xi)
+	}
+	sign := func(sk, msg []byte, rng io.Reader) ([]byte, error) {
+		rnd := make([]byte, mldsa.RndBytes)
+		if *hedged {
+			if _, err := rng.Read(rnd); err != nil {
+				return nil, err
+			}
+		}
+		return mldsa.SignWithContext(sk, msg, nil, rnd)
+	}
+	if *keygenOnly {
+		sign = nil
+	}
+
+	results := kat.CheckRSP(file, keygen, sign)
+	env, err := vectorEnvironment(a.Version, path)
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: %v\n", err)
+		return 1
+	}
+	report := diag.Report{Environment: env}
+	for _, r := range results {
+		rec := diag.Record{ParameterSet: params.Name, Status: diag.StatusPass}
+		if r.Err != nil {
+			rec.Status = diag.StatusFail
+			if errors.Is(r.Err, kat.ErrRSPMismatch) {
+				rec.Stage = "mismatch"
+			}
+		}
+		report.Add(rec)
+	}
+	report.Finish()
+	findings, suppressed, err := baseline.apply(kat.RSPFindings(results))
+	if err != nil {
+		fmt.Fprintf(a.Err, "kat-rsp: write baseline: %v\n", err)
+		return 1
+	}
+
+	switch outFormat {
+	case formatJUnit, formatSARIF:
+		cases := kat.RSPDiagCases(file.Scheme+" "+params.Name, results)
+		baseline.baseline.MarkCases(cases)
+		if err := a.writeDiagCases(outFormat, "kat-rsp", path, cases); err != nil {
+			fmt.Fprintf(a.Err, "kat-rsp: encode %s: %v\n", outFormat, err)
+			return 1
+		}
+	case formatJSON:
+		type caseJSON struct {
+			Count  int    `json:"count"`
+			Line   int    `json:"line"`
+			Error  string `json:"error,omitempty"`
+			RuleID string `json:"ruleId,omitempty"`
+		}
+		payload := struct {
+			File         string `json:"file"`
+			Scheme       string `json:"scheme"`
+			ParameterSet string `json:"parameterSet"`
+			diag.Report
+			Findings   []diag.Finding `json:"findings"`
+			Suppressed []diag.Finding `json:"suppressed,omitempty"`
+			Cases      []caseJSON     `json:"cases"`
+		}{File: path, Scheme: file.Scheme, ParameterSet: params.Name, Report: report, Findings: findings, Suppressed: suppressed}
+		for _, r := range results {
+			c := caseJSON{Count: r.Count, Line: r.Line}
+			if r.Err != nil {
+				c.Error, c.RuleID = r.Err.Error(), r.RuleID
+			}
+			payload.Cases = append(payload.Cases, c)
+		}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "kat-rsp: encode json: %v\n", err)
+			return 1
+		}
+	default:
+		if file.Scheme == "" || file.Scheme == params.Name {
+			fmt.Fprintf(a.Out, "RSP file: %s (%s)\n", *rspPath, params.Name)
+		} else {
+			fmt.Fprintf(a.Out, "RSP file: %s (%s, %s)\n", *rspPath, file.Scheme, params.Name)
+		}
+		fmt.Fprintf(a.Out, "Total tests: %d\n", report.TotalTests)
+		fmt.Fprintf(a.Out, "Strict passes: %d\n", report.StrictPasses)
+		fmt.Fprintf(a.Out, "Mismatches: %d\n", report.StructuralFailures)
+		printReportDetails(a.Out, &report)
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Fprintf(a.Out, "  count=%d (line %d): %v\n", r.Count, r.Line, r.Err)
+			}
+		}
+		printFindings(a.Out, findings, suppressed)
+	}
+
+	if report.StructuralFailures > 0 && len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// rspParams picks the parameter set from the key sizes of the first case.
+// Files from the Round-3 Dilithium submission share the ML-DSA public key
+// sizes, so the secret key size tells them apart.
+func rspParams(file *kat.RSPFile) (*mldsa.Params, error) {
+	c := &file.Cases[0]
+	if scheme, ok := kat.Round3Scheme(len(c.PK), len(c.SK)); ok {
+		return nil, fmt.Errorf("%w: %d-byte pk and %d-byte sk are %s keys; FIPS 204 changed the key format and signing, so use an ML-DSA KAT file",
+			kat.ErrRSPRound3, len(c.PK), len(c.SK), scheme)
+	}
+	params, err := mldsa.FromPublicKeyLength(len(c.PK))
+	if err != nil {
+		return nil, fmt.Errorf("%d-byte public keys: %w", len(c.PK), err)
+	}
+	if len(c.SK) != params.SKBytes {
+		return nil, fmt.Errorf("%w: %d-byte pk is %s but sk is %d bytes, want %d",
+			kat.ErrRSPFormat, len(c.PK), params.Name, len(c.SK), params.SKBytes)
+	}
+	return params, nil
+}
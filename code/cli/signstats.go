@@ -0,0 +1,131 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/signstats"
+)
+
+// signStatsRows is how many iteration counts the text histogram shows
+// before folding the rest into one row.
+const signStatsRows = 8
+
+func (a *App) runSignStats(args []string) int {
+	fs := flag.NewFlagSet("sign-stats", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	n := fs.Int("n", 10000, "signatures per parameter set")
+	paramList := fs.String("params", "ML-DSA-44,ML-DSA-65,ML-DSA-87", "comma-separated parameter sets")
+	seed := fs.Int64("seed", 1, "seed for the key, messages and rnd")
+	workers := fs.Int("workers", 0, "signing goroutines (default: number of CPUs)")
+	alpha := fs.Float64("alpha", 0.001, "significance level of the tests")
+	jsonOut := fs.Bool("json", false, "emit machine-readable JSON results")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "sign-stats: unexpected positional arguments")
+		return 1
+	}
+	if *n <= 0 {
+		fmt.Fprintln(a.Err, "sign-stats: -n must be positive")
+		return 1
+	}
+	var sets []*mldsa.Params
+	for _, name := range strings.Split(*paramList, ",") {
+		params, err := mldsa.FromName(strings.TrimSpace(name))
+		if err != nil {
+			fmt.Fprintf(a.Err, "sign-stats: %v\n", err)
+			return 1
+		}
+		sets = append(sets, params)
+	}
+
+	opts := signstats.Options{Signatures: *n, Workers: *workers, Seed: *seed, Alpha: *alpha}
+	var results []*signstats.Result
+	failing := 0
+	for _, params := range sets {
+		res, err := signstats.Run(params, opts)
+		if err != nil {
+			fmt.Fprintf(a.Err, "sign-stats: %s: %v\n", params.Name, err)
+			return 1
+		}
+		if res.Fail() {
+			failing++
+		}
+		results = append(results, res)
+		if !*jsonOut {
+			printSignStats(a.Out, res)
+		}
+	}
+
+	if *jsonOut {
+		payload := struct {
+			Failing int                 `json:"failing"`
+			Results []*signstats.Result `json:"results"`
+		}{failing, results}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "sign-stats: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(a.Out, "Parameter sets: %d, failing: %d\n", len(results), failing)
+	}
+
+	if failing > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printSignStats(w io.Writer, r *signstats.Result) {
+	verdict := "OK"
+	if r.Fail() {
+		verdict = "FAIL"
+	}
+	fmt.Fprintf(w, "%s: %s (%d signatures, %d iterations, mean %.3f, expected %.3f)\n",
+		r.ParameterSet, verdict, r.Signatures, r.Iterations, r.Mean, r.Expected)
+
+	var parts []string
+	for _, reason := range mldsa.RejectReasons {
+		parts = append(parts, fmt.Sprintf("%s %d", reason, r.Rejections[reason]))
+	}
+	fmt.Fprintf(w, "    rejections: %s\n", strings.Join(parts, ", "))
+	fmt.Fprintf(w, "    model: z passes %.4f, r0 passes %.4f per iteration\n", r.Model.ZPass, r.Model.R0Pass)
+
+	p := 1 / r.Expected
+	left := float64(r.Signatures) // expected signatures still running
+	tail := 0
+	for i, count := range r.Histogram {
+		if i >= signStatsRows {
+			tail += count
+			continue
+		}
+		fmt.Fprintf(w, "    %3d iterations: %8d (expected %10.1f)\n", i+1, count, left*p)
+		left *= 1 - p
+	}
+	if tail > 0 {
+		fmt.Fprintf(w, "    %3s iterations: %8d (expected %10.1f)\n", fmt.Sprintf(">%d", signStatsRows), tail, left)
+	}
+	for _, t := range r.Tests {
+		status := "ok"
+		switch {
+		case t.Skipped:
+			status = "skipped (too few samples)"
+		case t.Fail:
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "    %-10s χ²=%-10.2f df=%-3d p=%-10.3g %s\n", t.Name, t.Statistic, t.DF, t.P, status)
+	}
+}
@@ -0,0 +1,53 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/signstats"
+)
+
+func TestApp_SignStatsCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+
+	code := app.Run([]string{"sign-stats", "-n", "150", "-params", "ML-DSA-44"})
+	if code != 0 {
+		t.Fatalf("exit = %d, stderr=%q\n%s", code, errOut.String(), out.String())
+	}
+	for _, want := range []string{"ML-DSA-44: OK (150 signatures", "rejections: z-norm", "1 iterations:", "z-rate", "Parameter sets: 1, failing: 0"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := app.Run([]string{"sign-stats", "-n", "40", "-params", "ML-DSA-87", "-workers", "2", "-json"}); code != 0 {
+		t.Fatalf("json: exit = %d, stderr=%q", code, errOut.String())
+	}
+	var payload struct {
+		Failing int                 `json:"failing"`
+		Results []*signstats.Result `json:"results"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Results) != 1 || payload.Results[0].ParameterSet != "ML-DSA-87" || payload.Results[0].Signatures != 40 || len(payload.Results[0].Tests) != 3 {
+		t.Errorf("payload %+v", payload)
+	}
+
+	for _, args := range [][]string{
+		{"sign-stats", "-n", "0"},
+		{"sign-stats", "-params", "ML-DSA-1"},
+		{"sign-stats", "extra"},
+	} {
+		if code := app.Run(args); code != 1 {
+			t.Errorf("%v: exit %d", args, code)
+		}
+	}
+}
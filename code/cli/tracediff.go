@@ -0,0 +1,91 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/trace"
+)
+
+func (a *App) runTraceDiff(args []string) int {
+	fs := flag.NewFlagSet("trace-diff", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	jsonOut := fs.Bool("json", false, "emit the per-step comparison as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(a.Err, "trace-diff: want two trace files: ours.json theirs.json")
+		return 1
+	}
+
+	ours, err := trace.Load(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(a.Err, "trace-diff: %v\n", err)
+		return 1
+	}
+	theirs, err := trace.Load(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(a.Err, "trace-diff: %v\n", err)
+		return 1
+	}
+	if ours.ParameterSet != nil && theirs.ParameterSet != nil && ours.ParameterSet.Name != theirs.ParameterSet.Name {
+		fmt.Fprintf(a.Err, "trace-diff: parameter sets differ: %s vs %s\n", ours.ParameterSet.Name, theirs.ParameterSet.Name)
+		return 1
+	}
+
+	diffs := trace.Diff(ours, theirs)
+	compared := 0
+	for _, d := range diffs {
+		if d.Status == trace.StatusMatch || d.Status == trace.StatusDiffers {
+			compared++
+		}
+	}
+	if compared == 0 {
+		fmt.Fprintln(a.Err, "trace-diff: the traces have no step in common")
+		return 1
+	}
+	first := trace.FirstDivergence(diffs)
+
+	if *jsonOut {
+		payload := struct {
+			Ours            string           `json:"ours"`
+			Theirs          string           `json:"theirs"`
+			FirstDivergence *trace.StepDiff  `json:"firstDivergence"`
+			Steps           []trace.StepDiff `json:"steps"`
+		}{fs.Arg(0), fs.Arg(1), first, diffs}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "trace-diff: encode json: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, d := range diffs {
+			fmt.Fprintf(a.Out, "%-14s %s", d.Step, d.Status)
+			if d.Location != "" {
+				fmt.Fprintf(a.Out, " at %s", d.Location)
+			}
+			if d.Ours != "" || d.Theirs != "" {
+				fmt.Fprintf(a.Out, " (ours %s, theirs %s)", d.Ours, d.Theirs)
+			}
+			fmt.Fprintln(a.Out)
+		}
+		if first == nil {
+			fmt.Fprintf(a.Out, "No divergence across %d common steps.\n", compared)
+		} else {
+			fmt.Fprintf(a.Out, "First divergence: %s at %s\n", first.Step, first.Location)
+		}
+	}
+
+	if first != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/vet"
+)
+
+const (
+	vetFormatText = "text"
+	vetFormatJSON = "json"
+	vetFormatHTML = "html"
+)
+
+func (a *App) runVet(args []string) int {
+	fs := flag.NewFlagSet("vet", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	implPath := fs.String("impl", "", "implementation speaking the kats.Exec protocol (required)")
+	timeout := fs.Duration("timeout", 5*time.Second, "per-call timeout for -impl")
+	limitFlags := addImplFlags(fs)
+	session := fs.Bool("session", false, "keep -impl running and speak the JSON-lines protocol (docs/execsign-protocol.md)")
+	perGroup := fs.Int("per-group", 4, "ACVP cases taken from each test group (0 = all)")
+	timingSamples := fs.Int("timing-samples", 16, "verifications timed per parameter set and input (0 = skip timing)")
+	format := fs.String("format", vetFormatText, "report format (text|json|html)")
+	outPath := fs.String("out", "", "write the report to this file instead of stdout")
+	baseline := addBaselineFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "vet: unexpected positional arguments")
+		return 1
+	}
+	if *implPath == "" {
+		fmt.Fprintln(a.Err, "vet: -impl is required")
+		return 1
+	}
+	if *format != vetFormatText && *format != vetFormatJSON && *format != vetFormatHTML {
+		fmt.Fprintf(a.Err, "vet: unknown format %q\n", *format)
+		return 1
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "vet: %v\n", err)
+		return 1
+	}
+
+	limits := limitFlags.limits()
+	target := kats.Exec{Bin: execsign.Bin{Path: *implPath, Timeout: *timeout, Limits: limits}}
+	if *session {
+		s, err := execsign.StartSession(context.Background(), target.Bin)
+		if err != nil {
+			fmt.Fprintf(a.Err, "vet: %v\n", err)
+			return 1
+		}
+		defer s.Close()
+		target.Session = s
+	}
+
+	opts := vet.Options{PerGroup: *perGroup, TimingSamples: *timingSamples, Baseline: baseline.baseline}
+	card, err := vet.Run(*implPath, target, opts)
+	if err != nil {
+		fmt.Fprintf(a.Err, "vet: %v\n", err)
+		return 1
+	}
+	var all []diag.Finding
+	for _, f := range append(card.Findings, card.Suppressed...) {
+		all = append(all, f.Finding)
+	}
+	if err := baseline.save(all); err != nil {
+		fmt.Fprintf(a.Err, "vet: write baseline: %v\n", err)
+		return 1
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case vetFormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(card)
+	case vetFormatHTML:
+		err = vet.WriteHTML(&buf, card)
+	default:
+		printScorecard(&buf, card)
+	}
+	if err != nil {
+		fmt.Fprintf(a.Err, "vet: encode %s: %v\n", *format, err)
+		return 1
+	}
+	if *outPath == "" {
+		a.Out.Write(buf.Bytes())
+	} else {
+		if err := os.WriteFile(*outPath, buf.Bytes(), 0o644); err != nil {
+			fmt.Fprintf(a.Err, "vet: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(a.Out, "Grade %s (%d/100) for %s; wrote %s report to %s\n", card.Grade, card.Score, *implPath, *format, *outPath)
+	}
+
+	if card.Failed() {
+		return 1
+	}
+	return 0
+}
+
+func printScorecard(w io.Writer, s *vet.Scorecard) {
+	fmt.Fprintf(w, "Target: %s\n", s.Target)
+	if len(s.Incomplete) > 0 {
+		fmt.Fprintf(w, "Grade: %s (incomplete: every check of %s was skipped; score %d/100)\n",
+			s.Grade, strings.Join(s.Incomplete, ", "), s.Score)
+	} else {
+		fmt.Fprintf(w, "Grade: %s (%d/100)\n", s.Grade, s.Score)
+	}
+	fmt.Fprintf(w, "Interfaces: sign %s, verify %s\n\n", s.SignInterface, s.VerifyInterface)
+	fmt.Fprintln(w, "Categories:")
+	for _, c := range s.Categories {
+		fmt.Fprintf(w, "  %-14s %-4s  %d/%d passed, %d failed, %d skipped",
+			c.Name, strings.ToUpper(c.Status), c.Passed, c.Total, c.Failed, c.Skipped)
+		if c.Suppressed > 0 {
+			fmt.Fprintf(w, ", %d suppressed", c.Suppressed)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "\nFindings: %d\n", len(s.Findings))
+	for _, sev := range vet.Severities {
+		for _, f := range s.Findings {
+			if f.Severity != sev {
+				continue
+			}
+			fmt.Fprintf(w, "  [%s] %s %s: %s (%d cases)\n", strings.ToUpper(f.Severity), f.RuleID, f.Category, f.Title, f.Count)
+			for _, ev := range f.Evidence {
+				fmt.Fprintf(w, "      %s\n", ev)
+			}
+			fmt.Fprintf(w, "      Fix: %s\n", f.Remediation)
+		}
+	}
+	for _, f := range s.Suppressed {
+		fmt.Fprintf(w, "  suppressed by baseline: %s %s (%d cases)\n", f.RuleID, f.Category, f.Count)
+	}
+}
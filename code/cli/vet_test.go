@@ -0,0 +1,59 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/vet"
+)
+
+func TestApp_VetCommand(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+	t.Setenv(execHelperEnv, "jsonl")
+
+	var out, errOut bytes.Buffer
+	app := &App{Name: "dilivet", Version: "dev", Out: &out, Err: &errOut}
+	code := app.Run([]string{"vet", "-impl", exe, "-session", "-per-group", "1", "-timing-samples", "0", "-format", "json"})
+	if code != 0 {
+		t.Fatalf("vet exit = %d, stderr=%q, stdout=%q", code, errOut.String(), out.String())
+	}
+	var card vet.Scorecard
+	if err := json.Unmarshal(out.Bytes(), &card); err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	if card.Grade != "A" || len(card.Findings) != 0 || len(card.Categories) != 6 {
+		t.Fatalf("unexpected scorecard: %s", out.String())
+	}
+
+	// A verifier that accepts everything fails, and the HTML report says so.
+	t.Setenv(execHelperEnv, "accept-jsonl")
+	htmlPath := filepath.Join(t.TempDir(), "scorecard.html")
+	out.Reset()
+	code = app.Run([]string{"vet", "-impl", exe, "-session", "-per-group", "1", "-timing-samples", "0", "-format", "html", "-out", htmlPath})
+	if code != 1 || !strings.Contains(out.String(), "Grade F") {
+		t.Fatalf("vet exit = %d, stdout=%q, stderr=%q", code, out.String(), errOut.String())
+	}
+	html, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(html, []byte("DV-Z-BOUND")) || !bytes.Contains(html, []byte("accepts a z coefficient")) {
+		t.Errorf("HTML report lacks the mutation finding")
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := app.Run([]string{"vet", "-impl", exe, "-format", "pdf"}); code != 1 || errOut.Len() == 0 {
+		t.Errorf("bad format: exit %d, stderr %q", code, errOut.String())
+	}
+}
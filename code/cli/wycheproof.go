@@ -0,0 +1,119 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+const defaultWycheproofSuite = "code/clean/testdata/wycheproof/mldsa-wycheproof-alpha1.json"
+
+func (a *App) runWycheproof(args []string) int {
+	fs := flag.NewFlagSet("wycheproof", flag.ContinueOnError)
+	fs.SetOutput(a.Err)
+
+	suitePath := fs.String("suite", defaultWycheproofSuite, "Wycheproof-style suite JSON (docs/wycheproof-plan.md)")
+	format, jsonOut := addFormatFlags(fs)
+	baseline := addBaselineFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return exitFromFlagError(err)
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(a.Err, "wycheproof: unexpected positional arguments")
+		return 1
+	}
+	outFormat, err := resolveFormat(*format, *jsonOut)
+	if err != nil {
+		fmt.Fprintf(a.Err, "wycheproof: %v\n", err)
+		return 1
+	}
+	if err := baseline.open(); err != nil {
+		fmt.Fprintf(a.Err, "wycheproof: %v\n", err)
+		return 1
+	}
+
+	path, err := kats.ResolvePath(*suitePath)
+	if err != nil {
+		fmt.Fprintf(a.Err, "wycheproof: %v\n", err)
+		return 1
+	}
+	suite, err := kat.LoadSuite(path)
+	if err != nil {
+		fmt.Fprintf(a.Err, "wycheproof: %v\n", err)
+		return 1
+	}
+
+	// Cases without sig are filled by deterministic Sign_internal, matching
+	// the interface mldsa.Verify checks.
+	sign := func(_, sk, msg []byte) ([]byte, error) {
+		return mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	}
+	results := kat.RunSuite(suite, mldsa.Verify, sign)
+	failed := 0
+	for _, r := range results {
+		if !r.Pass {
+			failed++
+		}
+	}
+	findings, suppressed, err := baseline.apply(kat.SuiteFindings(results))
+	if err != nil {
+		fmt.Fprintf(a.Err, "wycheproof: write baseline: %v\n", err)
+		return 1
+	}
+
+	switch outFormat {
+	case formatJUnit, formatSARIF:
+		cases := kat.SuiteDiagCases(suite.Name, results)
+		baseline.baseline.MarkCases(cases)
+		if err := a.writeDiagCases(outFormat, suite.Name, path, cases); err != nil {
+			fmt.Fprintf(a.Err, "wycheproof: encode %s: %v\n", outFormat, err)
+			return 1
+		}
+	case formatJSON:
+		payload := struct {
+			Suite      string            `json:"suite"`
+			File       string            `json:"file"`
+			Total      int               `json:"total"`
+			Failed     int               `json:"failed"`
+			Findings   []diag.Finding    `json:"findings"`
+			Suppressed []diag.Finding    `json:"suppressed,omitempty"`
+			Results    []kat.SuiteResult `json:"results"`
+		}{suite.Name, path, len(results), failed, findings, suppressed, results}
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintf(a.Err, "wycheproof: encode json: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(a.Out, "Suite: %s (%s)\n", suite.Name, *suitePath)
+		fmt.Fprintf(a.Out, "Total cases: %d\n", len(results))
+		fmt.Fprintf(a.Out, "Passed: %d\n", len(results)-failed)
+		fmt.Fprintf(a.Out, "Failed: %d\n", failed)
+		for _, r := range results {
+			if r.Pass {
+				continue
+			}
+			fmt.Fprintf(a.Out, "  %s [%s]: expected %s, got %s", r.ID, r.Category, r.Expected, r.Got)
+			if r.Detail != "" {
+				fmt.Fprintf(a.Out, " (%s)", r.Detail)
+			}
+			fmt.Fprintln(a.Out)
+		}
+		printFindings(a.Out, findings, suppressed)
+	}
+
+	if failed > 0 && len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
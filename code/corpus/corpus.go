@@ -0,0 +1,87 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package corpus turns Go fuzz corpus entries of the fuzz/ package into
+// named pk/msg/sig vectors. Each entry is replayed the way its fuzz target
+// does, and the vector records the verdict and the stage where the input
+// stopped. Failing inputs can be minimized while keeping that stage.
+package corpus
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Header opens every file in a Go fuzz corpus.
+const Header = "go test fuzz v1"
+
+// DefaultDir is the corpus of the fuzz/ package.
+const DefaultDir = "fuzz/testdata/fuzz"
+
+// ErrFormat reports a corpus file that is not in the Go fuzz format or
+// holds values other than []byte.
+var ErrFormat = errors.New("corpus: malformed corpus file")
+
+// ReadFile parses a corpus file whose values are all []byte literals.
+func ReadFile(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("corpus: %w", err)
+	}
+	values, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrFormat, path, err)
+	}
+	return values, nil
+}
+
+// Parse decodes the contents of a corpus file.
+func Parse(data []byte) ([][]byte, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != Header {
+		return nil, fmt.Errorf("missing %q header", Header)
+	}
+	var values [][]byte
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lit, ok := strings.CutPrefix(line, "[]byte(")
+		lit, ok2 := strings.CutSuffix(lit, ")")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("unsupported value %.40q", line)
+		}
+		s, err := strconv.Unquote(lit)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, []byte(s))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Encode renders values as a corpus file, as go test writes them.
+func Encode(values ...[]byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(Header + "\n")
+	for _, v := range values {
+		fmt.Fprintf(&b, "[]byte(%q)\n", v)
+	}
+	return b.Bytes()
+}
+
+// FileName is the name go test gives a corpus file with these contents.
+func FileName(contents []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(contents))[:16]
+}
@@ -0,0 +1,141 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package corpus
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	values := [][]byte{{}, []byte("hello\x00\xff\"\\"), bytes.Repeat([]byte{0x80}, 300)}
+	data := Encode(values...)
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(values) {
+		t.Fatalf("got %d values", len(got))
+	}
+	for i := range values {
+		if !bytes.Equal(got[i], values[i]) {
+			t.Errorf("value %d = %q, want %q", i, got[i], values[i])
+		}
+	}
+	if name := FileName(data); len(name) != 16 {
+		t.Errorf("file name %q", name)
+	}
+	for _, bad := range []string{"[]byte(\"x\")\n", Header + "\nstring(\"x\")\n", Header + "\n[]byte(\"x)\n"} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%q) accepted", bad)
+		}
+	}
+}
+
+func TestReplayStages(t *testing.T) {
+	tests := []struct {
+		target string
+		value  []byte
+		stage  string
+	}{
+		{TargetVerify, []byte("message"), StageOK},
+		{TargetVerify, nil, StageSign},
+		{TargetDecodePublicKey, make([]byte, 32), StageOK},
+		{TargetDecodePublicKey, make([]byte, mldsa.ParamsMLDSA65.PKBytes), StageOK},
+		{TargetDecodePublicKey, make([]byte, 5), StagePublicKey},
+		// A line longer than bufio.Scanner's default buffer fails kat.Load.
+		{TargetDecodePublicKey, make([]byte, 40000), StageLoad},
+	}
+	for _, tc := range tests {
+		v, err := Replay(tc.target, tc.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Stage != tc.stage {
+			t.Errorf("%s %d bytes: stage %q (%s), want %q", tc.target, len(tc.value), v.Stage, v.Error, tc.stage)
+		}
+	}
+	if _, err := Replay("FuzzNope", nil); !errors.Is(err, ErrTarget) {
+		t.Errorf("got %v, want ErrTarget", err)
+	}
+}
+
+func writeCorpus(t *testing.T, dir, target string, value []byte) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := Encode(value)
+	path := filepath.Join(dir, target, FileName(data))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExportSuiteReplays(t *testing.T) {
+	dir := t.TempDir()
+	writeCorpus(t, dir, TargetVerify, []byte("abc"))
+	writeCorpus(t, dir, TargetVerify, []byte{})
+	writeCorpus(t, dir, TargetDecodePublicKey, []byte{1, 2, 3})
+
+	vectors, err := Export(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 3 {
+		t.Fatalf("got %d vectors", len(vectors))
+	}
+	stages := map[string]int{}
+	for _, v := range vectors {
+		stages[v.Stage]++
+	}
+	if stages[StageOK] != 1 || stages[StageSign] != 1 || stages[StagePublicKey] != 1 {
+		t.Errorf("stages %v", stages)
+	}
+
+	// Only the vector that reached the verifier becomes a suite case, and
+	// it replays.
+	suite := Suite("corpus", vectors)
+	if len(suite.Cases) != 1 {
+		t.Fatalf("got %d cases", len(suite.Cases))
+	}
+	for _, r := range kat.RunSuite(suite, mldsa.Verify, nil) {
+		if !r.Pass {
+			t.Errorf("case %s: expected %s, got %s", r.ID, r.Expected, r.Got)
+		}
+	}
+}
+
+func TestMinimizeKeepsStage(t *testing.T) {
+	// A key 3 bytes too long still fails at the public-key stage once cut
+	// down, so the minimizer should get rid of almost all of it.
+	value := bytes.Repeat([]byte{0xab}, mldsa.ParamsMLDSA44.PKBytes+3)
+	m, err := Minimize(TargetDecodePublicKey, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Vector.Stage != StagePublicKey {
+		t.Fatalf("stage %q", m.Vector.Stage)
+	}
+	if len(m.Value) != 0 || m.Steps == 0 {
+		t.Errorf("minimized to %d bytes in %d steps", len(m.Value), m.Steps)
+	}
+
+	// An accepted message keeps being accepted; it shrinks to one zero byte
+	// since the empty message fails at signing.
+	m, err = Minimize(TargetVerify, []byte("a longer accepted message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Vector.Stage != StageOK || !bytes.Equal(m.Value, []byte{0}) {
+		t.Errorf("got %q at stage %q", m.Value, m.Vector.Stage)
+	}
+}
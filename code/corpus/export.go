@@ -0,0 +1,90 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package corpus
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// Export replays every corpus file under dir/<target> for the given
+// targets (all of Targets when empty). Vectors are named target/file and
+// sorted by name. Missing target directories are skipped.
+func Export(dir string, targets ...string) ([]Vector, error) {
+	if len(targets) == 0 {
+		targets = Targets
+	}
+	var vectors []Vector
+	for _, target := range targets {
+		paths, err := filepath.Glob(filepath.Join(dir, target, "*"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			v, err := ReplayFile(target, path)
+			if err != nil {
+				return nil, err
+			}
+			vectors = append(vectors, v)
+		}
+	}
+	return vectors, nil
+}
+
+// ReplayFile replays the single value of one corpus file.
+func ReplayFile(target, path string) (Vector, error) {
+	values, err := ReadFile(path)
+	if err != nil {
+		return Vector{}, err
+	}
+	if len(values) != 1 {
+		return Vector{}, fmt.Errorf("%w: %s: want one []byte value, got %d", ErrFormat, path, len(values))
+	}
+	v, err := Replay(target, values[0])
+	if err != nil {
+		return Vector{}, err
+	}
+	v.Name = target + "/" + filepath.Base(path)
+	return v, nil
+}
+
+// stageExpected maps stages to the suite verdict mldsa.Verify gives.
+var stageExpected = map[string]string{
+	StageOK:        kat.ExpectAccept,
+	StageChallenge: kat.ExpectReject,
+	StageMessage:   "error:empty-message",
+	StagePublicKey: "error:invalid-public-key",
+	StageSignature: "error:invalid-signature",
+	StageHint:      "error:malformed-hint",
+	StageZRange:    "error:z-out-of-range",
+}
+
+// Suite renders the vectors that reached the verifier as a
+// Wycheproof-style suite (docs/wycheproof-plan.md), so `dilivet wycheproof`
+// replays them as regression cases. Vectors without a signature, or
+// stopped before verification, are left out.
+func Suite(name string, vectors []Vector) *kat.Suite {
+	suite := &kat.Suite{Name: name}
+	for _, v := range vectors {
+		expected, ok := stageExpected[v.Stage]
+		if !ok || v.Sig == "" {
+			continue
+		}
+		suite.Cases = append(suite.Cases, kat.SuiteCase{
+			ID:          v.Name,
+			Description: fmt.Sprintf("Go fuzz corpus entry %s (stage %s).", v.Name, v.Stage),
+			Category:    v.Target,
+			Reason:      v.Stage,
+			Msg:         v.Msg,
+			PK:          v.PK,
+			Sig:         v.Sig,
+			Expected:    expected,
+		})
+	}
+	return suite
+}
@@ -0,0 +1,71 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package corpus
+
+// maxMinimizeSteps bounds the replays spent minimizing one input.
+const maxMinimizeSteps = 10000
+
+// Minimized is the result of Minimize.
+type Minimized struct {
+	Original []byte `json:"-"`
+	Value    []byte `json:"-"`
+	Vector   Vector `json:"vector"` // replay of Value
+	Steps    int    `json:"steps"`
+}
+
+// Minimize shrinks value while its replay through target stops at the same
+// stage. It first removes chunks, halving the chunk size down to one byte,
+// and then zeroes the bytes that remain.
+func Minimize(target string, value []byte) (*Minimized, error) {
+	want, err := Replay(target, value)
+	if err != nil {
+		return nil, err
+	}
+	m := &Minimized{Original: value, Value: append([]byte{}, value...), Vector: want}
+	var replayErr error
+	keeps := func(candidate []byte) bool {
+		if m.Steps >= maxMinimizeSteps || replayErr != nil {
+			return false
+		}
+		m.Steps++
+		v, err := Replay(target, candidate)
+		if err != nil {
+			replayErr = err
+			return false
+		}
+		if v.Stage != want.Stage {
+			return false
+		}
+		m.Vector = v
+		return true
+	}
+
+	for size := len(m.Value) / 2; size >= 1; size /= 2 {
+		for start := 0; start+size <= len(m.Value); {
+			candidate := append(append([]byte{}, m.Value[:start]...), m.Value[start+size:]...)
+			if keeps(candidate) {
+				m.Value = candidate
+				continue
+			}
+			start += size
+		}
+	}
+	if len(m.Value) == 1 && keeps(nil) {
+		m.Value = []byte{}
+	}
+	for i := range m.Value {
+		if m.Value[i] == 0 {
+			continue
+		}
+		candidate := append([]byte{}, m.Value...)
+		candidate[i] = 0
+		if keeps(candidate) {
+			m.Value = candidate
+		}
+	}
+	if replayErr != nil {
+		return nil, replayErr
+	}
+	return m, nil
+}
@@ -0,0 +1,136 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package corpus
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/kat"
+	"github.com/codethor0/dilivet/code/signer"
+)
+
+// Fuzz targets of the fuzz/ package.
+const (
+	TargetVerify          = "FuzzVerify"
+	TargetDecodePublicKey = "FuzzDecodePublicKey"
+)
+
+// Targets lists the fuzz targets Replay understands.
+var Targets = []string{TargetVerify, TargetDecodePublicKey}
+
+// Stages name where an input stopped. StageOK means it went all the way
+// through; the rest follow the order of the target's pipeline. The
+// verifier stages are those of kat.VerifyStage.
+const (
+	StageOK        = kat.StageOK
+	StageLoad      = "load" // kat.Load rejected the request file
+	StageSign      = "sign" // the harness could not sign the message
+	StageMessage   = kat.StageMessage
+	StagePublicKey = kat.StagePublicKey
+	StageSignature = kat.StageSignature
+	StageHint      = kat.StageHint
+	StageZRange    = kat.StageZRange
+	StageChallenge = kat.StageChallenge
+	StageInternal  = kat.StageInternal
+)
+
+// Verdicts.
+const (
+	VerdictAccept = "accept"
+	VerdictReject = "reject"
+	VerdictError  = "error"
+)
+
+// ErrTarget reports a fuzz target Replay does not know.
+var ErrTarget = errors.New("corpus: unknown fuzz target")
+
+// Vector is a corpus entry replayed through its target. Byte fields are
+// hex, as in Wycheproof-style suites; Sig is empty when the target never
+// produced one.
+type Vector struct {
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	PK      string `json:"pk"`
+	Msg     string `json:"msg"`
+	Sig     string `json:"sig,omitempty"`
+	Verdict string `json:"verdict"`
+	Stage   string `json:"stage"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Replay runs value the way target does and classifies the result.
+//
+// FuzzVerify pads or cuts the value to a secret key of the deterministic
+// signer, signs the value as the message and verifies with mldsa.Verify.
+// FuzzDecodePublicKey writes the value as the pk of a request file and
+// loads it with kat.Load. The key is then checked against the known key
+// lengths, since the target itself never verifies.
+func Replay(target string, value []byte) (Vector, error) {
+	switch target {
+	case TargetVerify:
+		return replayVerify(value), nil
+	case TargetDecodePublicKey:
+		return replayDecodePublicKey(value)
+	}
+	return Vector{}, fmt.Errorf("%w: %q", ErrTarget, target)
+}
+
+func replayVerify(value []byte) Vector {
+	sk := make([]byte, signer.SecretKeySize)
+	copy(sk, value)
+	pk, _ := signer.DerivePublicKey(sk)
+	v := Vector{Target: TargetVerify, PK: hex.EncodeToString(pk), Msg: hex.EncodeToString(value)}
+	sig, err := signer.SignDet(sk, value, nil)
+	if err != nil {
+		return v.stopped(StageSign, err)
+	}
+	v.Sig = hex.EncodeToString(sig)
+	return v.verified(mldsa.Verify(pk, value, sig))
+}
+
+func replayDecodePublicKey(value []byte) (Vector, error) {
+	v := Vector{Target: TargetDecodePublicKey, PK: hex.EncodeToString(value), Msg: "00"}
+	dir, err := os.MkdirTemp("", "dilivet-corpus-")
+	if err != nil {
+		return v, fmt.Errorf("corpus: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fuzz.req")
+	if err := os.WriteFile(path, []byte("msg=00\npk="+v.PK+"\nend\n"), 0o600); err != nil {
+		return v, fmt.Errorf("corpus: %w", err)
+	}
+	if _, err := kat.Load(path); err != nil {
+		return v.stopped(StageLoad, err), nil
+	}
+	if _, err := mldsa.FromPublicKeyLength(len(value)); err != nil && len(value) != signer.PublicKeySize {
+		return v.stopped(StagePublicKey, err), nil
+	}
+	v.Verdict, v.Stage = VerdictAccept, StageOK
+	return v, nil
+}
+
+// verified classifies a verifier result.
+func (v Vector) verified(ok bool, err error) Vector {
+	stage := kat.VerifyStage(ok, err)
+	switch {
+	case err != nil:
+		return v.stopped(stage, err)
+	case ok:
+		v.Verdict = VerdictAccept
+	default:
+		v.Verdict = VerdictReject
+	}
+	v.Stage = stage
+	return v
+}
+
+func (v Vector) stopped(stage string, err error) Vector {
+	v.Verdict, v.Stage, v.Error = VerdictError, stage, err.Error()
+	return v
+}
@@ -0,0 +1,232 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package ct tests operations for timing leakage with the dudect
+// methodology (Reparaz, Balasch and Verbauwhede, "Dude, is my code
+// constant time?", DATE 2017). Inputs from a fixed class and a random
+// class are timed in random order. Welch's t-test then compares the two
+// timing distributions, on the raw times, on copies cropped at
+// percentile thresholds, and as a second-order test on centred squares.
+// A large |t| means the time depends on the input.
+package ct
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"time"
+)
+
+// Class is the input class of one measurement.
+type Class int
+
+// Input classes.
+const (
+	Fixed  Class = 0
+	Random Class = 1
+)
+
+// Verdicts.
+const (
+	VerdictLeak   = "leak"
+	VerdictNoLeak = "no leak"
+)
+
+// DefaultThreshold is the |t| above which a target is reported as
+// leaking; dudect reads |t| > 10 as "definitely not constant time".
+const DefaultThreshold = 10
+
+// cropTests is the number of cropped t-tests, as in dudect.
+const cropTests = 100
+
+// ErrMeasure reports a target that failed during a measurement.
+var ErrMeasure = errors.New("ct: measurement failed")
+
+// Target is an operation timed on fixed- and random-class inputs.
+type Target struct {
+	Name string
+	// Prepare builds the input of one measurement of class c. It runs
+	// outside the timed region.
+	Prepare func(c Class, rng *rand.Rand) (any, error)
+	// Measure runs the operation once on input; only this call is timed.
+	Measure func(input any) error
+}
+
+// Clock reads a monotonic counter in Unit.
+type Clock struct {
+	Unit string
+	Now  func() uint64
+}
+
+var epoch = time.Now()
+
+// Nanoseconds is the monotonic wall clock.
+var Nanoseconds = Clock{Unit: "ns", Now: func() uint64 { return uint64(time.Since(epoch)) }}
+
+// Cycles returns the CPU time-stamp counter and true on amd64, where
+// dudect's cycle-accurate timing is available.
+func Cycles() (Clock, bool) {
+	if !haveCycles {
+		return Clock{}, false
+	}
+	return Clock{Unit: "cycles", Now: rdtsc}, true
+}
+
+// DefaultClock returns Cycles where available and Nanoseconds otherwise.
+func DefaultClock() Clock {
+	if c, ok := Cycles(); ok {
+		return c
+	}
+	return Nanoseconds
+}
+
+// Options bounds and seeds a run.
+type Options struct {
+	Measurements int     // timed calls after warm-up; zero uses 10000
+	Batches      int     // t-statistic snapshots over the run; zero uses 10
+	Threshold    float64 // |t| reported as a leak; zero uses DefaultThreshold
+	Seed         int64   // seeds the class sequence and random inputs
+	Clock        Clock   // zero uses DefaultClock
+}
+
+// Point is the t-statistic after a number of measurements.
+type Point struct {
+	Measurements int     `json:"measurements"`
+	MaxT         float64 `json:"maxT"`
+}
+
+// Result is the outcome of one target.
+type Result struct {
+	Target       string  `json:"target"`
+	Clock        string  `json:"clock"`
+	Measurements int     `json:"measurements"`
+	MeanFixed    float64 `json:"meanFixed"`
+	MeanRandom   float64 `json:"meanRandom"`
+	MaxT         float64 `json:"maxT"`
+	MaxTTest     string  `json:"maxTTest"` // "raw", "crop N" or "second-order"
+	Threshold    float64 `json:"threshold"`
+	Verdict      string  `json:"verdict"`
+	Series       []Point `json:"series"`
+}
+
+// Leak reports whether the target's timing depends on its input class.
+func (r *Result) Leak() bool {
+	return r.Verdict == VerdictLeak
+}
+
+// Run times target and applies the dudect tests. A warm-up batch sets the
+// crop thresholds and is then discarded; the remaining measurements are
+// taken in Batches batches, each ending with a Point in the series.
+func Run(target Target, opts Options) (*Result, error) {
+	if opts.Measurements <= 0 {
+		opts.Measurements = 10000
+	}
+	if opts.Batches <= 0 {
+		opts.Batches = 10
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	if opts.Clock.Now == nil {
+		opts.Clock = DefaultClock()
+	}
+	batch := (opts.Measurements + opts.Batches - 1) / opts.Batches
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	warm, _, err := measure(target, opts.Clock, rng, batch)
+	if err != nil {
+		return nil, err
+	}
+	thresholds := cropThresholds(warm, cropTests)
+
+	// tests[0] is raw, tests[1..cropTests] cropped, the last second-order.
+	tests := make([]welch, cropTests+2)
+	res := &Result{Target: target.Name, Clock: opts.Clock.Unit, Threshold: opts.Threshold}
+	for res.Measurements < opts.Measurements {
+		n := min(batch, opts.Measurements-res.Measurements)
+		times, classes, err := measure(target, opts.Clock, rng, n)
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range times {
+			x, c := float64(t), classes[i]
+			// Centre on the means before this sample joins them, as dudect does.
+			centred := x - tests[0].mean[c]
+			tests[0].push(x, c)
+			for j, limit := range thresholds {
+				if x < limit {
+					tests[1+j].push(x, c)
+				}
+			}
+			if tests[0].n[Fixed] > 1 && tests[0].n[Random] > 1 {
+				tests[cropTests+1].push(centred*centred, c)
+			}
+		}
+		res.Measurements += n
+		res.MaxT, res.MaxTTest = maxT(tests, res.Measurements)
+		res.Series = append(res.Series, Point{Measurements: res.Measurements, MaxT: res.MaxT})
+	}
+
+	res.MeanFixed, res.MeanRandom = tests[0].mean[Fixed], tests[0].mean[Random]
+	res.Verdict = VerdictNoLeak
+	if res.MaxT > opts.Threshold {
+		res.Verdict = VerdictLeak
+	}
+	return res, nil
+}
+
+// measure prepares n inputs of random classes, then times them in one
+// tight loop on a locked OS thread after a collection, so the allocator
+// and scheduler disturb the timed region as little as possible.
+func measure(target Target, clock Clock, rng *rand.Rand, n int) ([]uint64, []Class, error) {
+	classes := make([]Class, n)
+	inputs := make([]any, n)
+	for i := range inputs {
+		classes[i] = Class(rng.Intn(2))
+		in, err := target.Prepare(classes[i], rng)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ct: %s: prepare: %w", target.Name, err)
+		}
+		inputs[i] = in
+	}
+
+	times := make([]uint64, n)
+	runtime.GC()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	for i, in := range inputs {
+		start := clock.Now()
+		err := target.Measure(in)
+		end := clock.Now()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrMeasure, target.Name, err)
+		}
+		times[i] = end - start
+	}
+	return times, classes, nil
+}
+
+// maxT returns the largest |t| among tests holding at least a tenth of
+// the measurements, so thinly cropped tests do not dominate, and names it.
+func maxT(tests []welch, total int) (float64, string) {
+	best, name := 0.0, "raw"
+	for i := range tests {
+		if tests[i].count() < total/10 {
+			continue
+		}
+		if t := math.Abs(tests[i].t()); t > best {
+			best = t
+			switch {
+			case i == 0:
+				name = "raw"
+			case i <= cropTests:
+				name = fmt.Sprintf("crop %d", i)
+			default:
+				name = "second-order"
+			}
+		}
+	}
+	return best, name
+}
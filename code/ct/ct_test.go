@@ -0,0 +1,131 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package ct
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+func TestWelch(t *testing.T) {
+	var w welch
+	for _, x := range []float64{1, 2, 3, 4} {
+		w.push(x, Fixed)
+	}
+	for _, x := range []float64{3, 4, 5, 6} {
+		w.push(x, Random)
+	}
+	// Means 2.5 and 4.5, both variances 5/3: t = −2 / sqrt(5/6).
+	if got, want := w.t(), -2/math.Sqrt(5.0/6); math.Abs(got-want) > 1e-12 {
+		t.Errorf("t = %v, want %v", got, want)
+	}
+
+	var flat welch
+	for i := 0; i < 3; i++ {
+		flat.push(1, Fixed)
+		flat.push(2, Random)
+	}
+	if flat.t() != -tCap {
+		t.Errorf("zero-variance t = %v", flat.t())
+	}
+}
+
+// fakeTarget advances a simulated clock by a class-dependent amount plus
+// noise, so the verdict does not depend on the machine.
+func fakeTarget(extra uint64) (Target, Clock) {
+	var now uint64
+	noise := rand.New(rand.NewSource(7))
+	target := Target{
+		Name:    "fake",
+		Prepare: func(c Class, _ *rand.Rand) (any, error) { return c, nil },
+		Measure: func(in any) error {
+			now += 1000 + uint64(noise.Intn(50))
+			if in.(Class) == Fixed {
+				now += extra
+			}
+			if noise.Intn(100) == 0 {
+				now += 100000 // an interrupt; cropping must absorb it
+			}
+			return nil
+		},
+	}
+	return target, Clock{Unit: "ticks", Now: func() uint64 { return now }}
+}
+
+func TestRunVerdicts(t *testing.T) {
+	leaky, clock := fakeTarget(5)
+	res, err := Run(leaky, Options{Measurements: 5000, Batches: 5, Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Leak() || res.Clock != "ticks" || len(res.Series) != 5 || res.Series[4].Measurements != 5000 {
+		t.Errorf("leaky: %+v", res)
+	}
+	if res.MaxTTest == "raw" {
+		t.Errorf("interrupts should hide the leak from the raw test: %+v", res)
+	}
+
+	flat, clock := fakeTarget(0)
+	res, err = Run(flat, Options{Measurements: 5000, Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Leak() || res.MaxT > 5 {
+		t.Errorf("constant-time target: verdict %s, max t %v (%s)", res.Verdict, res.MaxT, res.MaxTTest)
+	}
+}
+
+func TestTargetsRun(t *testing.T) {
+	verify, err := VerifyTarget(kats.Builtin{}, mldsa.ParamsMLDSA44, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign, err := SignTarget(kats.Builtin{}, mldsa.ParamsMLDSA44, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range append(PolyTargets(), verify, sign) {
+		res, err := Run(target, Options{Measurements: 40, Batches: 2, Clock: Nanoseconds})
+		if err != nil {
+			t.Fatalf("%s: %v", target.Name, err)
+		}
+		if res.Measurements != 40 || res.Clock != "ns" || len(res.Series) != 2 {
+			t.Errorf("%s: %+v", target.Name, res)
+		}
+	}
+}
+
+type acceptAll struct{ kats.Builtin }
+
+func (acceptAll) VerifyInternal(_, _, _ []byte) (bool, error) { return true, nil }
+
+func TestVerifyTargetWrongVerdict(t *testing.T) {
+	target, err := VerifyTarget(acceptAll{}, mldsa.ParamsMLDSA44, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(target, Options{Measurements: 10}); !errors.Is(err, ErrMeasure) || !strings.Contains(err.Error(), ErrVerdict.Error()) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	c, ok := Cycles()
+	if !ok {
+		t.Skip("no cycle counter on this platform")
+	}
+	a := c.Now()
+	for i := 0; i < 1000; i++ {
+		_ = math.Sqrt(float64(i))
+	}
+	if b := c.Now(); b <= a {
+		t.Errorf("cycle counter did not advance: %d then %d", a, b)
+	}
+}
@@ -0,0 +1,10 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package ct
+
+const haveCycles = true
+
+// rdtsc returns the time-stamp counter after an LFENCE, so earlier
+// instructions have retired before it is read.
+func rdtsc() uint64
@@ -0,0 +1,10 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+//go:build !amd64
+
+package ct
+
+const haveCycles = false
+
+func rdtsc() uint64 { return 0 }
@@ -0,0 +1,61 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package ct
+
+import (
+	"math"
+	"sort"
+)
+
+// tCap stands in for an infinite t when both classes have zero variance
+// but different means, which JSON cannot encode.
+const tCap = 1e6
+
+// welch accumulates per-class means and variances online (Welford) and
+// computes Welch's t statistic.
+type welch struct {
+	n, mean, m2 [2]float64
+}
+
+func (w *welch) push(x float64, c Class) {
+	w.n[c]++
+	delta := x - w.mean[c]
+	w.mean[c] += delta / w.n[c]
+	w.m2[c] += delta * (x - w.mean[c])
+}
+
+func (w *welch) count() int {
+	return int(w.n[0] + w.n[1])
+}
+
+func (w *welch) t() float64 {
+	if w.n[Fixed] < 2 || w.n[Random] < 2 {
+		return 0
+	}
+	diff := w.mean[Fixed] - w.mean[Random]
+	den := math.Sqrt(w.m2[Fixed]/(w.n[Fixed]-1)/w.n[Fixed] + w.m2[Random]/(w.n[Random]-1)/w.n[Random])
+	switch {
+	case den > 0:
+		return diff / den
+	case diff == 0:
+		return 0
+	default:
+		return math.Copysign(tCap, diff)
+	}
+}
+
+// cropThresholds returns the execution-time thresholds of dudect's
+// cropped tests: percentiles 1 − 0.5^(10·(i+1)/n) of times, so most
+// thresholds sit in the upper tail where outliers are removed one slice
+// at a time.
+func cropThresholds(times []uint64, n int) []float64 {
+	s := append([]uint64(nil), times...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	out := make([]float64, n)
+	for i := range out {
+		p := 1 - math.Pow(0.5, 10*float64(i+1)/float64(n))
+		out[i] = float64(s[int(p*float64(len(s)-1))])
+	}
+	return out
+}
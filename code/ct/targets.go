@@ -0,0 +1,149 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package ct
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// poolSize is the number of distinct secret keys behind the random class
+// of the Sign target.
+const poolSize = 16
+
+// ErrVerdict reports a Verify target accepting a random-class input or
+// rejecting the fixed valid one, which makes the timing meaningless.
+var ErrVerdict = errors.New("ct: verify verdict does not match the input class")
+
+// PolyTargets returns the in-process polynomial arithmetic targets: the
+// NTT and its inverse, pointwise Montgomery multiplication and Freeze.
+// The fixed class is the zero polynomial, the random class uniform
+// coefficients.
+func PolyTargets() []Target {
+	unary := func(name string, op func(*poly.Poly) error) Target {
+		return Target{
+			Name:    name,
+			Prepare: func(c Class, rng *rand.Rand) (any, error) { return classPoly(c, rng), nil },
+			Measure: func(in any) error { return op(in.(*poly.Poly)) },
+		}
+	}
+	// The operand fixed across both classes is random, so only the class
+	// input varies.
+	other := classPoly(Random, rand.New(rand.NewSource(1)))
+	return []Target{
+		unary("ntt", poly.NTT),
+		unary("invntt", poly.InvNTT),
+		{
+			Name:    "pointwise",
+			Prepare: func(c Class, rng *rand.Rand) (any, error) { return classPoly(c, rng), nil },
+			Measure: func(in any) error {
+				p := in.(*poly.Poly)
+				p.PointwiseMontgomery(p, other)
+				return nil
+			},
+		},
+		unary("freeze", func(p *poly.Poly) error { poly.Freeze(p); return nil }),
+	}
+}
+
+func classPoly(c Class, rng *rand.Rand) *poly.Poly {
+	p := &poly.Poly{}
+	if c == Random {
+		for i := range p.Coeffs {
+			p.Coeffs[i] = uint32(rng.Intn(poly.Q))
+		}
+	}
+	return p
+}
+
+type verifyInput struct {
+	msg   []byte
+	valid bool
+}
+
+// VerifyTarget times v.VerifyInternal on one signature. The fixed class
+// is the signed message, the random class random messages of the same
+// length. Decoding, ExpandA and SampleInBall then see identical inputs and
+// only μ differs, so a gap between the classes means verification time
+// depends on whether the signature is valid, e.g. an early-exit c̃
+// comparison.
+func VerifyTarget(v kats.Verifier, params *mldsa.Params, seed int64) (Target, error) {
+	rng := rand.New(rand.NewSource(seed))
+	pk, sk, err := mldsa.KeyGen(params, randBytes(rng, mldsa.SeedBytes))
+	if err != nil {
+		return Target{}, err
+	}
+	msg := randBytes(rng, 32)
+	sig, err := mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	if err != nil {
+		return Target{}, err
+	}
+	return Target{
+		Name: "verify/" + params.Name,
+		Prepare: func(c Class, rng *rand.Rand) (any, error) {
+			if c == Fixed {
+				return verifyInput{msg, true}, nil
+			}
+			return verifyInput{randBytes(rng, len(msg)), false}, nil
+		},
+		Measure: func(in any) error {
+			vi := in.(verifyInput)
+			ok, err := v.VerifyInternal(pk, vi.msg, sig)
+			if err != nil {
+				return err
+			}
+			if ok != vi.valid {
+				return ErrVerdict
+			}
+			return nil
+		},
+	}, nil
+}
+
+type signInput struct{ sk, msg, rnd []byte }
+
+// SignTarget times s.SignInternal on one message. The fixed class is one
+// secret key, the random class a pool of keys; both use fresh randomness
+// per call, so the number of rejection-loop iterations is distributed
+// alike and only key-dependent timing separates them.
+func SignTarget(s kats.Signer, params *mldsa.Params, seed int64) (Target, error) {
+	rng := rand.New(rand.NewSource(seed))
+	keys := make([][]byte, poolSize+1)
+	for i := range keys {
+		_, sk, err := mldsa.KeyGen(params, randBytes(rng, mldsa.SeedBytes))
+		if err != nil {
+			return Target{}, err
+		}
+		keys[i] = sk
+	}
+	msg := []byte("dilivet ct-test message")
+	return Target{
+		Name: "sign/" + params.Name,
+		Prepare: func(c Class, rng *rand.Rand) (any, error) {
+			sk := keys[0]
+			if c == Random {
+				sk = keys[1+rng.Intn(poolSize)]
+			}
+			return signInput{sk, msg, randBytes(rng, mldsa.RndBytes)}, nil
+		},
+		Measure: func(in any) error {
+			si := in.(signInput)
+			if _, err := s.SignInternal(si.sk, si.msg, si.rnd); err != nil {
+				return fmt.Errorf("sign: %w", err)
+			}
+			return nil
+		},
+	}, nil
+}
+
+func randBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rng.Read(b)
+	return b
+}
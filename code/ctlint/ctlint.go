@@ -0,0 +1,200 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package ctlint statically flags code whose timing may depend on secret
+// data: branches, slice indexing, map lookups and integer divisions with
+// an operand derived from a value annotated as secret. It uses only
+// go/ast and go/types.
+//
+// Secrets are declared with directives:
+//
+//	//dilivet:secret seed sk   (in a function's doc comment: those parameters)
+//	//dilivet:secret           (in a function's doc comment: all parameters)
+//	x := f() //dilivet:secret  (on, or on the line above, a variable or field)
+//
+// A finding is silenced by //dilivet:ignore on its line or the line above.
+//
+// Secrecy flows through assignments, operators, conversions, indexing,
+// field selection and calls: a call with a secret argument returns a
+// secret and marks its pointer, slice and receiver arguments secret, as
+// they may be outputs. Within a package, secret arguments also mark the
+// callee's parameters, and a function returning a secret taints its calls.
+// Lengths (len, cap) are public. The analysis is flow-insensitive, so a
+// variable is secret everywhere in its scope once anything secret reaches
+// it.
+package ctlint
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Directives.
+const (
+	DirectiveSecret = "//dilivet:secret"
+	DirectiveIgnore = "//dilivet:ignore"
+)
+
+// Rule IDs.
+const (
+	RuleBranch   = "secret-branch"
+	RuleIndex    = "secret-index"
+	RuleMap      = "secret-map"
+	RuleDivision = "secret-division"
+)
+
+// Rule describes one kind of finding.
+type Rule struct {
+	ID          string
+	Description string
+	Help        string
+}
+
+// Rules lists every rule ctlint reports.
+var Rules = []Rule{
+	{RuleBranch, "Branch on a secret-dependent condition",
+		"Replace the branch with masked arithmetic, e.g. mask := -(x >> 31) and a select via &, ^ and |."},
+	{RuleIndex, "Slice or array index derived from a secret",
+		"Scan the whole table and select the entry with a constant-time mask instead of indexing by the secret."},
+	{RuleMap, "Map lookup keyed by a secret",
+		"Map lookups hash and compare the key in variable time; use a fixed-size table scanned with masks."},
+	{RuleDivision, "Integer division or remainder with a secret operand",
+		"Hardware division is variable-time on many CPUs; use Barrett or Montgomery reduction, or a constant divisor."},
+}
+
+// ErrNoPackages reports patterns that matched no Go package.
+var ErrNoPackages = errors.New("ctlint: no Go packages matched")
+
+// Finding is one flagged expression.
+type Finding struct {
+	Rule    string         `json:"rule"`
+	Pos     token.Position `json:"pos"`
+	Package string         `json:"package"`
+	Message string         `json:"message"`
+}
+
+// String formats f the way go vet prints diagnostics, with the rule last.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Pos, f.Message, f.Rule)
+}
+
+// Dirs expands command-line patterns into package directories. A pattern
+// ending in "/..." matches the directory and every subdirectory except
+// testdata, vendor and hidden ones.
+func Dirs(patterns []string) ([]string, error) {
+	var dirs []string
+	seen := map[string]bool{}
+	add := func(d string) {
+		if !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	for _, p := range patterns {
+		root, recursive := strings.CutSuffix(p, "/...")
+		if root == "" {
+			root = "."
+		}
+		if !recursive {
+			add(filepath.Clean(root))
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			add(filepath.Clean(path))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ctlint: %w", err)
+		}
+	}
+	return dirs, nil
+}
+
+// Check type-checks the non-test Go files of each directory and returns
+// the findings sorted by position. Directories without Go files are
+// skipped; type errors are tolerated as far as the remaining type
+// information allows.
+func Check(dirs []string) ([]Finding, error) {
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	var findings []Finding
+	checked := 0
+	for _, dir := range dirs {
+		bp, err := build.ImportDir(dir, 0)
+		if err != nil {
+			var noGo *build.NoGoError
+			if errors.As(err, &noGo) {
+				continue
+			}
+			return nil, fmt.Errorf("ctlint: %s: %w", dir, err)
+		}
+		var files []*ast.File
+		for _, name := range bp.GoFiles {
+			f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+			if err != nil {
+				return nil, fmt.Errorf("ctlint: %w", err)
+			}
+			files = append(files, f)
+		}
+		info := &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+		}
+		conf := types.Config{Importer: imp, Error: func(error) {}}
+		pkg, _ := conf.Check(bp.ImportPath, fset, files, info)
+		name := bp.ImportPath
+		if name == "." {
+			name = filepath.ToSlash(dir) // outside GOPATH: name it by directory
+		}
+		findings = append(findings, newChecker(fset, info, files, pkg, name).run()...)
+		checked++
+	}
+	if checked == 0 {
+		return nil, ErrNoPackages
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].Pos, findings[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return findings, nil
+}
+
+// RelPath returns path relative to the working directory when it lies
+// below it, slash-separated, for stable output.
+func RelPath(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
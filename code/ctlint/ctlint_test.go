@@ -0,0 +1,83 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package ctlint
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var wantRE = regexp.MustCompile(`// want "([a-z-]+)"`)
+
+func TestCheckLeaky(t *testing.T) {
+	dir := filepath.Join("testdata", "src", "leaky")
+	findings, err := Check([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "leaky.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var want []string
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		if m := wantRE.FindStringSubmatch(sc.Text()); m != nil {
+			want = append(want, fmt.Sprintf("%d %s", line, m[1]))
+		}
+	}
+
+	var got []string
+	for _, fd := range findings {
+		got = append(got, fmt.Sprintf("%d %s", fd.Pos.Line, fd.Rule))
+		if !strings.Contains(fd.Message, "secret ") || fd.Package == "" {
+			t.Errorf("finding %v", fd)
+		}
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		for _, fd := range findings {
+			t.Log(fd)
+		}
+	}
+}
+
+func TestFindingOrigin(t *testing.T) {
+	findings, err := Check([]string{filepath.Join("testdata", "src", "leaky")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) == 0 {
+		t.Fatal("no findings")
+	}
+	first := findings[0]
+	if first.Rule != RuleBranch || first.Message != "if condition depends on secret x" ||
+		!strings.HasSuffix(first.String(), "if condition depends on secret x (secret-branch)") {
+		t.Errorf("first finding %v", first)
+	}
+}
+
+func TestDirs(t *testing.T) {
+	dirs, err := Dirs([]string{"./...", "testdata/src/leaky"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 || dirs[0] != "." || dirs[1] != filepath.Join("testdata", "src", "leaky") {
+		t.Errorf("dirs = %v", dirs)
+	}
+	if _, err := Check([]string{"testdata"}); !errors.Is(err, ErrNoPackages) {
+		t.Errorf("no packages: err = %v", err)
+	}
+}
@@ -0,0 +1,593 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package ctlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// source is an annotated secret. fn is set for a parameter annotated in
+// its function's doc comment: returning it does not make every call of fn
+// secret, only those passing a secret.
+type source struct {
+	name string
+	fn   *types.Func
+}
+
+func (s *source) String() string { return s.name }
+
+// checker holds the taint state of one package. secret maps each secret
+// object to the source it derives from; returns does the same for
+// functions that return a secret.
+type checker struct {
+	fset    *token.FileSet
+	info    *types.Info
+	files   []*ast.File
+	types   *types.Package
+	pkg     string
+	secret  map[types.Object]*source
+	returns map[*types.Func]*source
+	ignore  map[string]map[int]bool
+	changed bool
+}
+
+func newChecker(fset *token.FileSet, info *types.Info, files []*ast.File, tpkg *types.Package, pkg string) *checker {
+	return &checker{
+		fset:    fset,
+		info:    info,
+		files:   files,
+		types:   tpkg,
+		pkg:     pkg,
+		secret:  map[types.Object]*source{},
+		returns: map[*types.Func]*source{},
+		ignore:  map[string]map[int]bool{},
+	}
+}
+
+func (c *checker) run() []Finding {
+	c.directives()
+	if len(c.secret) == 0 {
+		return nil
+	}
+	for c.changed = true; c.changed; {
+		c.changed = false
+		for _, f := range c.files {
+			c.propagate(f)
+		}
+	}
+	var findings []Finding
+	seen := map[string]bool{}
+	for _, f := range c.files {
+		for _, fd := range c.sinks(f) {
+			key := fmt.Sprintf("%s@%s", fd.Rule, fd.Pos)
+			lines := c.ignore[fd.Pos.Filename]
+			if seen[key] || lines[fd.Pos.Line] || lines[fd.Pos.Line-1] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, fd)
+		}
+	}
+	return findings
+}
+
+// directives seeds the secret set from //dilivet:secret comments and
+// records //dilivet:ignore lines.
+func (c *checker) directives() {
+	// Objects defined on each line, for line-scoped directives.
+	defs := map[string]map[int][]types.Object{}
+	for id, obj := range c.info.Defs {
+		if obj == nil || id.Name == "_" {
+			continue
+		}
+		if _, ok := obj.(*types.Var); !ok {
+			continue
+		}
+		p := c.fset.Position(id.Pos())
+		if defs[p.Filename] == nil {
+			defs[p.Filename] = map[int][]types.Object{}
+		}
+		defs[p.Filename][p.Line] = append(defs[p.Filename][p.Line], obj)
+	}
+
+	for _, f := range c.files {
+		funcDocs := map[*ast.Comment]*ast.FuncDecl{}
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Doc != nil {
+				for _, cm := range fd.Doc.List {
+					funcDocs[cm] = fd
+				}
+			}
+		}
+		for _, group := range f.Comments {
+			for _, cm := range group.List {
+				p := c.fset.Position(cm.Slash)
+				if directive(cm.Text, DirectiveIgnore) != nil {
+					if c.ignore[p.Filename] == nil {
+						c.ignore[p.Filename] = map[int]bool{}
+					}
+					c.ignore[p.Filename][p.Line] = true
+					continue
+				}
+				names := directive(cm.Text, DirectiveSecret)
+				if names == nil {
+					continue
+				}
+				if fd := funcDocs[cm]; fd != nil {
+					c.secretParams(fd, names[1:])
+					continue
+				}
+				// A trailing directive covers its own line, one on a line of
+				// its own the next.
+				objs := defs[p.Filename][p.Line]
+				if len(objs) == 0 {
+					objs = defs[p.Filename][p.Line+1]
+				}
+				for _, obj := range objs {
+					c.mark(obj, &source{name: obj.Name()})
+				}
+			}
+		}
+	}
+}
+
+// directive returns the fields of text when it is the named directive,
+// with the directive itself first, and nil otherwise.
+func directive(text, name string) []string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || fields[0] != name {
+		return nil
+	}
+	return fields
+}
+
+// secretParams marks the named parameters (and receiver) of fd, or all of
+// its parameters when no names are given.
+func (c *checker) secretParams(fd *ast.FuncDecl, names []string) {
+	fn, _ := c.info.Defs[fd.Name].(*types.Func)
+	want := map[string]bool{}
+	for _, n := range names {
+		want[n] = true
+	}
+	lists := []*ast.FieldList{fd.Type.Params}
+	if len(names) > 0 {
+		lists = append(lists, fd.Recv)
+	}
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			for _, id := range field.Names {
+				if len(names) == 0 || want[id.Name] {
+					if obj := c.info.Defs[id]; obj != nil {
+						c.mark(obj, &source{name: id.Name, fn: fn})
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *checker) mark(obj types.Object, origin *source) {
+	if obj == nil || origin == nil {
+		return
+	}
+	if _, ok := c.secret[obj]; !ok {
+		c.secret[obj] = origin
+		c.changed = true
+	}
+}
+
+// markExpr marks the variable an assignment to e writes: the root of any
+// chain of selectors, indexing and dereferences.
+func (c *checker) markExpr(e ast.Expr, origin *source) {
+	for {
+		switch x := e.(type) {
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.StarExpr:
+			e = x.X
+		case *ast.UnaryExpr:
+			e = x.X
+		case *ast.IndexExpr:
+			e = x.X
+		case *ast.SliceExpr:
+			e = x.X
+		case *ast.SelectorExpr:
+			if _, ok := c.info.Uses[x.Sel].(*types.Var); ok && c.info.Selections[x] == nil {
+				c.mark(c.info.Uses[x.Sel], origin) // package-level variable of another package
+				return
+			}
+			e = x.X
+		case *ast.Ident:
+			if x.Name == "_" {
+				return
+			}
+			obj := c.info.Defs[x]
+			if obj == nil {
+				obj = c.info.Uses[x]
+			}
+			if _, ok := obj.(*types.Var); ok {
+				c.mark(obj, origin)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// origin returns the secret e derives from, or "" if e is public.
+func (c *checker) origin(e ast.Expr) *source {
+	if e == nil {
+		return nil
+	}
+	if tv, ok := c.info.Types[e]; ok && (tv.Value != nil || tv.IsType()) {
+		return nil
+	}
+	switch x := e.(type) {
+	case *ast.Ident:
+		if obj := c.info.Uses[x]; obj != nil {
+			return c.secret[obj]
+		}
+		return c.secret[c.info.Defs[x]]
+	case *ast.ParenExpr:
+		return c.origin(x.X)
+	case *ast.StarExpr:
+		return c.origin(x.X)
+	case *ast.UnaryExpr:
+		return c.origin(x.X)
+	case *ast.BinaryExpr:
+		return first(c.origin(x.X), c.origin(x.Y))
+	case *ast.SelectorExpr:
+		obj := c.info.Uses[x.Sel]
+		if c.info.Selections[x] == nil {
+			return c.secret[obj] // qualified identifier
+		}
+		return first(c.secret[obj], c.origin(x.X))
+	case *ast.IndexExpr:
+		return first(c.origin(x.X), c.origin(x.Index))
+	case *ast.SliceExpr:
+		return c.origin(x.X)
+	case *ast.TypeAssertExpr:
+		return c.origin(x.X)
+	case *ast.KeyValueExpr:
+		return c.origin(x.Value)
+	case *ast.CompositeLit:
+		for _, elt := range x.Elts {
+			if o := c.origin(elt); o != nil {
+				return o
+			}
+		}
+	case *ast.CallExpr:
+		return c.callOrigin(x)
+	}
+	return nil
+}
+
+func (c *checker) callOrigin(call *ast.CallExpr) *source {
+	fun := ast.Unparen(call.Fun)
+	if tv, ok := c.info.Types[fun]; ok && tv.IsType() {
+		if len(call.Args) == 1 {
+			return c.origin(call.Args[0]) // conversion
+		}
+		return nil
+	}
+	if id, ok := fun.(*ast.Ident); ok {
+		if b, ok := c.info.Uses[id].(*types.Builtin); ok && (b.Name() == "len" || b.Name() == "cap") {
+			return nil
+		}
+	}
+	if fn := c.callee(call); fn != nil {
+		if o := c.returns[fn]; o != nil {
+			return o
+		}
+	}
+	if o := c.argsOrigin(call); o != nil {
+		return o
+	}
+	if sel, ok := fun.(*ast.SelectorExpr); ok && c.info.Selections[sel] != nil {
+		return c.origin(sel.X) // method on a secret receiver
+	}
+	return nil
+}
+
+func (c *checker) argsOrigin(call *ast.CallExpr) *source {
+	for _, arg := range call.Args {
+		if o := c.origin(arg); o != nil {
+			return o
+		}
+	}
+	return nil
+}
+
+// callee returns the statically known function call invokes, if any.
+func (c *checker) callee(call *ast.CallExpr) *types.Func {
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		fn, _ := c.info.Uses[f].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := c.info.Uses[f.Sel].(*types.Func)
+		return fn
+	}
+	return nil
+}
+
+func first(a, b *source) *source {
+	if a != nil {
+		return a
+	}
+	return b
+}
+
+// propagate runs one pass of taint propagation over f.
+func (c *checker) propagate(f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Body != nil {
+				fn, _ := c.info.Defs[d.Name].(*types.Func)
+				c.propagateBody(d.Body, fn)
+			}
+		case *ast.GenDecl:
+			c.propagateBody(d, nil)
+		}
+	}
+}
+
+// propagateBody walks node; fn is the function whose returns it contains,
+// nil inside function literals and package-level declarations.
+func (c *checker) propagateBody(node ast.Node, fn *types.Func) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			c.propagateBody(n.Body, nil)
+			return false
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					c.markExpr(lhs, c.origin(n.Rhs[i]))
+				}
+			} else if len(n.Rhs) == 1 {
+				o := c.origin(n.Rhs[0])
+				for _, lhs := range n.Lhs {
+					c.markExpr(lhs, o)
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == len(n.Values) {
+				for i, id := range n.Names {
+					c.markExpr(id, c.origin(n.Values[i]))
+				}
+			} else if len(n.Values) == 1 {
+				o := c.origin(n.Values[0])
+				for _, id := range n.Names {
+					c.markExpr(id, o)
+				}
+			}
+		case *ast.RangeStmt:
+			o := c.origin(n.X)
+			if n.Value != nil {
+				c.markExpr(n.Value, o)
+			}
+			if n.Key != nil && !indexedKey(c.info.TypeOf(n.X)) {
+				c.markExpr(n.Key, o) // map keys, channel values and range-over-int
+			}
+		case *ast.SendStmt:
+			c.markExpr(n.Chan, c.origin(n.Value))
+		case *ast.ReturnStmt:
+			if fn == nil {
+				break
+			}
+			for _, r := range n.Results {
+				if o := c.origin(r); o != nil && o.fn != fn && c.returns[fn] == nil {
+					c.returns[fn] = o
+					c.changed = true
+				}
+			}
+			if len(n.Results) == 0 {
+				results := fn.Type().(*types.Signature).Results()
+				for i := 0; i < results.Len(); i++ {
+					if o := c.secret[results.At(i)]; o != nil && o.fn != fn && c.returns[fn] == nil {
+						c.returns[fn] = o
+						c.changed = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			c.propagateCall(n)
+		}
+		return true
+	})
+}
+
+// indexedKey reports whether ranging over a value of type t yields
+// positions rather than data as keys.
+func indexedKey(t types.Type) bool {
+	if t == nil {
+		return true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Basic:
+		if b, ok := u.(*types.Basic); ok && b.Info()&types.IsInteger != 0 {
+			return false
+		}
+		return true
+	case *types.Pointer:
+		_, ok := u.Elem().Underlying().(*types.Array)
+		return ok
+	}
+	return false
+}
+
+// propagateCall marks what a call with secret inputs may write: its
+// pointer, slice and map arguments and a method's receiver. Secret
+// arguments to a function of this package also mark its parameters.
+func (c *checker) propagateCall(call *ast.CallExpr) {
+	fun := ast.Unparen(call.Fun)
+	if tv, ok := c.info.Types[fun]; ok && tv.IsType() {
+		return
+	}
+	sel, isMethod := fun.(*ast.SelectorExpr)
+	isMethod = isMethod && c.info.Selections[sel] != nil
+	o := c.argsOrigin(call)
+	if isMethod {
+		o = first(o, c.origin(sel.X))
+	}
+	if o == nil {
+		return
+	}
+	for _, arg := range call.Args {
+		if mayWrite(c.info.TypeOf(arg)) {
+			c.markExpr(arg, o)
+		}
+	}
+	if isMethod {
+		if fn, ok := c.info.Uses[sel.Sel].(*types.Func); ok {
+			if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+				if _, ptr := recv.Type().Underlying().(*types.Pointer); ptr || mayWrite(recv.Type()) {
+					c.markExpr(sel.X, o)
+				}
+			}
+		}
+	}
+
+	fn := c.callee(call)
+	if fn == nil || c.types == nil || fn.Pkg() != c.types {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+	if isMethod && sig.Recv() != nil {
+		c.mark(sig.Recv(), c.origin(sel.X))
+	}
+	params := sig.Params()
+	for i, arg := range call.Args {
+		if params.Len() == 0 {
+			break
+		}
+		j := i
+		if j >= params.Len() {
+			j = params.Len() - 1 // variadic
+		}
+		c.mark(params.At(j), c.origin(arg))
+	}
+}
+
+// mayWrite reports whether a callee can write through a value of type t.
+func mayWrite(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map:
+		return true
+	}
+	return false
+}
+
+// sinks returns the secret-dependent branches, indexing and divisions of f.
+func (c *checker) sinks(f *ast.File) []Finding {
+	var out []Finding
+	add := func(rule string, at ast.Node, format string, args ...any) {
+		out = append(out, Finding{
+			Rule:    rule,
+			Pos:     c.fset.Position(at.Pos()),
+			Package: c.pkg,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	branch := func(cond ast.Expr, what string) {
+		if o := c.origin(cond); o != nil {
+			add(RuleBranch, cond, "%s depends on secret %s", what, o)
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			branch(n.Cond, "if condition")
+		case *ast.ForStmt:
+			branch(n.Cond, "loop condition")
+		case *ast.SwitchStmt:
+			if n.Tag != nil {
+				branch(n.Tag, "switch tag")
+			}
+			for _, stmt := range n.Body.List {
+				for _, e := range stmt.(*ast.CaseClause).List {
+					branch(e, "switch case")
+				}
+			}
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.LAND, token.LOR:
+				if o := c.origin(n.X); o != nil {
+					add(RuleBranch, n.X, "short-circuit %s depends on secret %s", n.Op, o)
+				}
+			case token.QUO, token.REM:
+				c.division(n.X, n.Y, n.Op, n, add)
+			}
+		case *ast.AssignStmt:
+			if (n.Tok == token.QUO_ASSIGN || n.Tok == token.REM_ASSIGN) && len(n.Lhs) == 1 {
+				c.division(n.Lhs[0], n.Rhs[0], n.Tok, n, add)
+			}
+		case *ast.IndexExpr:
+			o := c.origin(n.Index)
+			if o == nil {
+				break
+			}
+			if _, ok := c.typeUnder(n.X).(*types.Map); ok {
+				add(RuleMap, n.Index, "map key depends on secret %s", o)
+			} else if indexable(c.typeUnder(n.X)) {
+				add(RuleIndex, n.Index, "index depends on secret %s", o)
+			}
+		case *ast.SliceExpr:
+			for _, b := range []ast.Expr{n.Low, n.High, n.Max} {
+				if o := c.origin(b); o != nil {
+					add(RuleIndex, b, "slice bound depends on secret %s", o)
+				}
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func (c *checker) division(x, y ast.Expr, op token.Token, at ast.Node, add func(string, ast.Node, string, ...any)) {
+	if tv, ok := c.info.Types[y]; ok && tv.Value != nil {
+		return // constant divisors compile to multiplications
+	}
+	b, ok := c.typeUnder(x).(*types.Basic)
+	if !ok || b.Info()&types.IsInteger == 0 {
+		return
+	}
+	if o := first(c.origin(x), c.origin(y)); o != nil {
+		add(RuleDivision, at, "integer %s depends on secret %s", op, o)
+	}
+}
+
+func (c *checker) typeUnder(e ast.Expr) types.Type {
+	t := c.info.TypeOf(e)
+	if t == nil {
+		return nil
+	}
+	return t.Underlying()
+}
+
+func indexable(t types.Type) bool {
+	switch u := t.(type) {
+	case *types.Slice, *types.Array:
+		return true
+	case *types.Basic:
+		return u.Info()&types.IsString != 0
+	case *types.Pointer:
+		_, ok := u.Elem().Underlying().(*types.Array)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package leaky exercises ctlint. Each line carrying a "want" comment
+// must produce exactly the listed findings; every other line none.
+package leaky
+
+import "encoding/binary"
+
+var table = [16]byte{}
+
+var lookup = map[byte]int{}
+
+type key struct {
+	s    []byte //dilivet:secret
+	name string
+}
+
+// branchy compares a secret coefficient.
+//
+//dilivet:secret x
+func branchy(x uint32, n int) int32 {
+	y := int32(x % 8380417)
+	if y > 4190208 { // want "secret-branch"
+		y -= 8380417
+	}
+	for i := 0; i < n; i++ {
+		y++
+	}
+	if n > 3 {
+		y--
+	}
+	return y
+}
+
+// cbd samples from a secret buffer, like the CBD sampler.
+//
+//dilivet:secret
+func cbd(buf []byte, out []int32) {
+	for i := 0; i < len(buf)/4; i++ {
+		t := binary.LittleEndian.Uint32(buf[4*i:])
+		v := int32(t&3) - int32(t>>2&3)
+		if v < 0 { // want "secret-branch"
+			v += 8380417
+		}
+		out[i] = v
+	}
+	if out[0] == 1 { // want "secret-branch"
+		out[1] = 0
+	}
+}
+
+func indexing(k *key, public []byte) (byte, int) {
+	b := k.s[0]
+	x := table[b&15]   // want "secret-index"
+	m := lookup[b]     // want "secret-map"
+	_ = public[:b]     // want "secret-index"
+	q := int(b) / m    // want "secret-division"
+	r := int(b) % 3329 // constant divisor: fine
+	_ = table[len(k.s)&15]
+	_ = k.name[0]
+	return x, q + r
+}
+
+func calls() uint32 {
+	seed := make([]byte, 32) //dilivet:secret
+	var out [8]uint32
+	expand(seed, out[:])
+	if out[2] == 0 { // want "secret-branch"
+		return 0
+	}
+	v := derive(1)
+	switch v { // want "secret-branch"
+	case 1:
+		return 1
+	}
+	if v&1 == 1 && v > 2 { // want "secret-branch"
+		return 2
+	}
+	//dilivet:ignore masked in the real code
+	if v == 7 {
+		return 3
+	}
+	var w uint32                      //dilivet:secret
+	w /= uint32(len(seed))            // want "secret-division"
+	return out[0] % uint32(len(seed)) // want "secret-division"
+}
+
+func expand(seed []byte, out []uint32) {
+	for i := range out {
+		out[i] = uint32(seed[i])
+	}
+}
+
+//dilivet:secret
+var master uint32
+
+func derive(n uint32) uint32 {
+	return master + n
+}
+
+func public(n int, xs []int) int {
+	if n > len(xs) {
+		return xs[n-len(xs)]
+	}
+	return n / len(xs)
+}
+
+// canon returns its annotated parameter; only calls passing a secret
+// yield one.
+//
+//dilivet:secret x
+func canon(x uint32) uint32 {
+	return x % 8380417
+}
+
+func callers(pub uint32) uint32 {
+	if canon(pub) > 3 {
+		return 1
+	}
+	k := uint32(0)    //dilivet:secret
+	if canon(k) > 3 { // want "secret-branch"
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrBaseline reports a baseline file that cannot be used.
+var ErrBaseline = errors.New("diag: invalid baseline")
+
+// Baseline lists the rules whose findings are known and accepted, so a
+// run only reports what is new.
+type Baseline struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Suppress      []Suppression  `json:"suppress"`
+	byRule        map[string]int // index into Suppress
+}
+
+// Suppression accepts every finding of one rule.
+type Suppression struct {
+	RuleID string `json:"ruleId"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewBaseline returns a baseline suppressing the rules of findings, e.g.
+// to accept the current state of a target before tracking regressions.
+func NewBaseline(findings []Finding) *Baseline {
+	b := &Baseline{SchemaVersion: SchemaVersion, Suppress: []Suppression{}}
+	for _, f := range findings {
+		if !b.Suppresses(f.RuleID) {
+			b.add(Suppression{RuleID: f.RuleID, Reason: f.Title})
+		}
+	}
+	return b
+}
+
+// LoadBaseline reads a baseline file. Every entry must name a rule from
+// the catalogue, at most once.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("diag: open baseline: %w", err)
+	}
+	var raw Baseline
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrBaseline, path, err)
+	}
+	if raw.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("%w: %s: schemaVersion %d, want %d", ErrBaseline, path, raw.SchemaVersion, SchemaVersion)
+	}
+	b := &Baseline{SchemaVersion: raw.SchemaVersion}
+	for _, s := range raw.Suppress {
+		if _, ok := LookupRule(s.RuleID); !ok {
+			return nil, fmt.Errorf("%w: %s: unknown rule %q", ErrBaseline, path, s.RuleID)
+		}
+		if b.Suppresses(s.RuleID) {
+			return nil, fmt.Errorf("%w: %s: rule %s listed twice", ErrBaseline, path, s.RuleID)
+		}
+		b.add(s)
+	}
+	return b, nil
+}
+
+func (b *Baseline) add(s Suppression) {
+	if b.byRule == nil {
+		b.byRule = map[string]int{}
+	}
+	b.byRule[s.RuleID] = len(b.Suppress)
+	b.Suppress = append(b.Suppress, s)
+}
+
+// Suppresses reports whether findings of ruleID are accepted. A nil
+// baseline suppresses nothing.
+func (b *Baseline) Suppresses(ruleID string) bool {
+	if b == nil {
+		return false
+	}
+	_, ok := b.byRule[ruleID]
+	return ok
+}
+
+// Apply splits findings into those the baseline does not cover and those
+// it suppresses.
+func (b *Baseline) Apply(findings []Finding) (kept, suppressed []Finding) {
+	kept = []Finding{}
+	for _, f := range findings {
+		if b.Suppresses(f.RuleID) {
+			suppressed = append(suppressed, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	return kept, suppressed
+}
+
+// MarkCases sets Suppressed on the failed cases whose rule the baseline
+// accepts.
+func (b *Baseline) MarkCases(cases []Case) {
+	for i := range cases {
+		c := &cases[i]
+		if (c.Status == StatusFail || c.Status == StatusError) && b.Suppresses(c.RuleID) {
+			c.Suppressed = true
+		}
+	}
+}
+
+// Write encodes the baseline as indented JSON.
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
@@ -0,0 +1,38 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+// Case statuses.
+const (
+	StatusPass    = "pass"
+	StatusFail    = "fail"
+	StatusError   = "error"
+	StatusSkipped = "skipped"
+)
+
+// Case is the outcome of one test case in the shape every vector runner
+// shares; WriteJUnit and WriteSARIF render lists of them.
+type Case struct {
+	Suite   string `json:"suite"`             // grouping, e.g. "tgId=3 ML-DSA-65"
+	Name    string `json:"name"`              // case within the suite, e.g. "tcId=17"
+	Status  string `json:"status"`            // one of the Status* values
+	Stage   string `json:"stage,omitempty"`   // verify error stage, if known
+	RuleID  string `json:"ruleId,omitempty"` // catalogue rule the failure violates
+	Message string `json:"message,omitempty"` // why the case did not pass
+
+	// Suppressed marks a failure whose rule a Baseline accepts.
+	Suppressed bool `json:"suppressed,omitempty"`
+}
+
+// suites groups cases by Suite, in order of first appearance.
+func suites(cases []Case) (names []string, bySuite map[string][]Case) {
+	bySuite = map[string][]Case{}
+	for _, c := range cases {
+		if _, ok := bySuite[c.Suite]; !ok {
+			names = append(names, c.Suite)
+		}
+		bySuite[c.Suite] = append(bySuite[c.Suite], c)
+	}
+	return names, bySuite
+}
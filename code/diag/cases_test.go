@@ -0,0 +1,93 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+var testCases = []Case{
+	{Suite: "tgId=1 ML-DSA-44", Name: "tcId=1", Status: StatusPass, Stage: "ok"},
+	{Suite: "tgId=1 ML-DSA-44", Name: "tcId=2", Status: StatusFail, Stage: "hint", Message: "expected testPassed=true, verifier returned false: malformed hint"},
+	{Suite: "tgId=2 ML-DSA-65", Name: "tcId=3", Status: StatusError, Message: "decode pk: odd length"},
+	{Suite: "tgId=2 ML-DSA-65", Name: "tcId=4", Status: StatusSkipped, Message: "unsupported"},
+	{Suite: "tgId=1 ML-DSA-44", Name: "tcId=5", Status: StatusFail, Stage: "hint", Message: "again"},
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, "kat-verify", testCases); err != nil {
+		t.Fatal(err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("parse: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 5 || doc.Failures != 2 || doc.Errors != 1 || doc.Skipped != 1 || len(doc.Suites) != 2 {
+		t.Fatalf("totals %+v", doc)
+	}
+	first := doc.Suites[0]
+	if first.Name != "tgId=1 ML-DSA-44" || first.Tests != 3 || first.Cases[2].Name != "tcId=5" {
+		t.Fatalf("suite %+v", first)
+	}
+	if f := first.Cases[1].Failure; f == nil || f.Type != "hint" || f.Message != testCases[1].Message {
+		t.Errorf("failure %+v", f)
+	}
+	if c := doc.Suites[1].Cases[0]; c.Error == nil || c.Failure != nil {
+		t.Errorf("error case %+v", c)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, Tool{Name: "dilivet", Version: "dev"}, "vectors.json", testCases); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != SARIFVersion || len(log.Runs) != 1 {
+		t.Fatalf("log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("results %d, rules %+v", len(run.Results), run.Tool.Driver.Rules)
+	}
+	r := run.Results[0]
+	if r.RuleID != "fail-hint" || r.Level != "error" || r.Properties["stage"] != "hint" ||
+		r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "vectors.json" ||
+		r.Locations[0].LogicalLocations[0].FullyQualifiedName != "tgId=1 ML-DSA-44/tcId=2" {
+		t.Errorf("result %+v", r)
+	}
+	if run.Results[1].RuleID != "error" || run.Results[1].Level != "warning" {
+		t.Errorf("result %+v", run.Results[1])
+	}
+}
+
+func TestWriteSARIFSource(t *testing.T) {
+	rules := []SourceRule{{ID: "a", Description: "rule a", Help: "fix a"}, {ID: "b", Description: "rule b"}}
+	results := []SourceResult{{RuleID: "a", Message: "m", File: "code/x.go", Line: 3, Column: 7}}
+	var buf bytes.Buffer
+	if err := WriteSARIFSource(&buf, Tool{Name: "lint"}, rules, results); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].Help == nil || run.Tool.Driver.Rules[0].Help.Text != "fix a" {
+		t.Fatalf("rules %+v", run.Tool.Driver.Rules)
+	}
+	loc := run.Results[0].Locations[0]
+	if run.Results[0].Level != "warning" || loc.PhysicalLocation.ArtifactLocation.URI != "code/x.go" ||
+		loc.PhysicalLocation.Region == nil || loc.PhysicalLocation.Region.StartLine != 3 || loc.PhysicalLocation.Region.StartColumn != 7 ||
+		len(loc.LogicalLocations) != 0 {
+		t.Errorf("result %+v", run.Results[0])
+	}
+}
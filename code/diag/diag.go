@@ -3,16 +3,216 @@
 
 package diag
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// SchemaVersion identifies the JSON layout of Report. It changes only when
+// a field is renamed, removed or changes meaning; new fields may appear
+// without a bump. Version 2 renamed the snake_case keys of version 1
+// (total_tests, strict_passes, …) to camelCase.
+const SchemaVersion = 2
+
 // Report aggregates diagnostic counters during signing/verification.
+//
+// The top-level counters add up: TotalTests is the sum of StrictPasses,
+// StructuralWarnings, StructuralFailures, DecodeFailures and Skipped. A
+// structural warning is a case that passed without a byte-exact comparison,
+// e.g. a hedged signature that could only be verified.
 type Report struct {
-	TotalTests         int `json:"total_tests"`
-	StrictPasses       int `json:"strict_passes"`
-	StructuralWarnings int `json:"structural_warnings"`
-	StructuralFailures int `json:"structural_failures"`
-	DecodeFailures     int `json:"decode_failures"`
+	SchemaVersion      int `json:"schemaVersion"`
+	TotalTests         int `json:"totalTests"`
+	StrictPasses       int `json:"strictPasses"`
+	StructuralWarnings int `json:"structuralWarnings"`
+	StructuralFailures int `json:"structuralFailures"`
+	DecodeFailures     int `json:"decodeFailures"`
+	Skipped            int `json:"skipped"`
+
+	ByParameterSet  []Breakdown    `json:"byParameterSet,omitempty"`
+	ByGroup         []Breakdown    `json:"byGroup,omitempty"`
+	FailuresByStage map[string]int `json:"failuresByStage,omitempty"`
+	Durations       *Durations     `json:"durations,omitempty"`
+	Environment     *Environment   `json:"environment,omitempty"`
+
+	durations []time.Duration
+}
+
+// Counts are the per-breakdown versions of the Report counters.
+type Counts struct {
+	Total          int `json:"total"`
+	Passed         int `json:"passed"`
+	Warnings       int `json:"warnings"`
+	Failed         int `json:"failed"`
+	DecodeFailures int `json:"decodeFailures"`
+	Skipped        int `json:"skipped"`
+}
+
+// Breakdown counts the cases of one parameter set or test group.
+type Breakdown struct {
+	Name string `json:"name"`
+	Counts
+}
+
+// Durations summarises how long the cases took, in nanoseconds.
+type Durations struct {
+	Count    int   `json:"count"`
+	MinNS    int64 `json:"minNs"`
+	MedianNS int64 `json:"medianNs"`
+	P99NS    int64 `json:"p99Ns"`
+	MaxNS    int64 `json:"maxNs"`
+	TotalNS  int64 `json:"totalNs"`
+}
+
+// Environment records where a report was produced and from which inputs.
+type Environment struct {
+	Version     string       `json:"version"`
+	GoVersion   string       `json:"goVersion"`
+	GOOS        string       `json:"goos"`
+	GOARCH      string       `json:"goarch"`
+	VectorFiles []FileDigest `json:"vectorFiles,omitempty"`
+}
+
+// FileDigest names an input file and its SHA-256.
+type FileDigest struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
 }
 
+// Record is one case as Report.Add counts it.
+type Record struct {
+	ParameterSet string
+	Group        string
+	Status       string // one of the Status* values
+	Warning      bool   // passed, but without a byte-exact comparison
+	Stage        string // failures: verify stage or other failure class
+	Duration     time.Duration
+}
+
+// UnclassifiedStage keys failures that carry no stage.
+const UnclassifiedStage = "unclassified"
+
 // NewReport returns an empty diagnostic report.
 func NewReport() (*Report, error) {
-	return &Report{}, nil
+	return &Report{SchemaVersion: SchemaVersion}, nil
+}
+
+// Add counts one case in the totals, its parameter set and group, and for
+// failures in FailuresByStage. Empty ParameterSet or Group values are left
+// out of the breakdowns.
+func (r *Report) Add(rec Record) {
+	r.SchemaVersion = SchemaVersion
+	r.TotalTests++
+	switch {
+	case rec.Status == StatusPass && rec.Warning:
+		r.StructuralWarnings++
+	case rec.Status == StatusPass:
+		r.StrictPasses++
+	case rec.Status == StatusFail:
+		r.StructuralFailures++
+	case rec.Status == StatusError:
+		r.DecodeFailures++
+	default:
+		r.Skipped++
+	}
+	if rec.ParameterSet != "" {
+		r.ByParameterSet = addTo(r.ByParameterSet, rec.ParameterSet, rec)
+	}
+	if rec.Group != "" {
+		r.ByGroup = addTo(r.ByGroup, rec.Group, rec)
+	}
+	if rec.Status == StatusFail || rec.Status == StatusError {
+		stage := rec.Stage
+		if stage == "" {
+			stage = UnclassifiedStage
+		}
+		if r.FailuresByStage == nil {
+			r.FailuresByStage = map[string]int{}
+		}
+		r.FailuresByStage[stage]++
+	}
+	if rec.Duration > 0 {
+		r.durations = append(r.durations, rec.Duration)
+	}
+}
+
+func addTo(list []Breakdown, name string, rec Record) []Breakdown {
+	i := 0
+	for i < len(list) && list[i].Name != name {
+		i++
+	}
+	if i == len(list) {
+		list = append(list, Breakdown{Name: name})
+	}
+	c := &list[i].Counts
+	c.Total++
+	switch {
+	case rec.Status == StatusPass && rec.Warning:
+		c.Warnings++
+	case rec.Status == StatusPass:
+		c.Passed++
+	case rec.Status == StatusFail:
+		c.Failed++
+	case rec.Status == StatusError:
+		c.DecodeFailures++
+	default:
+		c.Skipped++
+	}
+	return list
+}
+
+// Finish fills Durations from the cases added so far. Call it once the run
+// is complete; it may be called again after more Adds.
+func (r *Report) Finish() {
+	if len(r.durations) == 0 {
+		r.Durations = nil
+		return
+	}
+	d := append([]time.Duration(nil), r.durations...)
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	var total time.Duration
+	for _, v := range d {
+		total += v
+	}
+	r.Durations = &Durations{
+		Count:    len(d),
+		MinNS:    int64(d[0]),
+		MedianNS: int64(d[len(d)/2]),
+		P99NS:    int64(d[percentileIndex(len(d), 99)]),
+		MaxNS:    int64(d[len(d)-1]),
+		TotalNS:  int64(total),
+	}
+}
+
+// percentileIndex returns the nearest-rank index of the p-th percentile
+// in a sorted slice of n values.
+func percentileIndex(n, p int) int {
+	i := (p*n + 99) / 100
+	if i < 1 {
+		i = 1
+	}
+	return i - 1
+}
+
+// NewEnvironment describes the running binary and hashes every vector file
+// it is given; empty paths are skipped.
+func NewEnvironment(version string, vectorFiles ...string) (*Environment, error) {
+	env := &Environment{Version: version, GoVersion: runtime.Version(), GOOS: runtime.GOOS, GOARCH: runtime.GOARCH}
+	for _, path := range vectorFiles {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("diag: hash vector file: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		env.VectorFiles = append(env.VectorFiles, FileDigest{Path: path, SHA256: hex.EncodeToString(sum[:])})
+	}
+	return env, nil
 }
@@ -0,0 +1,117 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportAdd(t *testing.T) {
+	r, _ := NewReport()
+	records := []Record{
+		{ParameterSet: "ML-DSA-44", Group: "tgId=1", Status: StatusPass, Duration: 3 * time.Millisecond},
+		{ParameterSet: "ML-DSA-44", Group: "tgId=1", Status: StatusPass, Warning: true, Duration: time.Millisecond},
+		{ParameterSet: "ML-DSA-44", Group: "tgId=2", Status: StatusFail, Stage: "hint", Duration: 2 * time.Millisecond},
+		{ParameterSet: "ML-DSA-65", Group: "tgId=3", Status: StatusFail, Duration: 4 * time.Millisecond},
+		{ParameterSet: "ML-DSA-65", Group: "tgId=3", Status: StatusError, Stage: "hint"},
+		{ParameterSet: "ML-DSA-65", Group: "tgId=3", Status: StatusSkipped},
+	}
+	for _, rec := range records {
+		r.Add(rec)
+	}
+	r.Finish()
+
+	if r.TotalTests != 6 || r.StrictPasses != 1 || r.StructuralWarnings != 1 || r.StructuralFailures != 2 ||
+		r.DecodeFailures != 1 || r.Skipped != 1 {
+		t.Fatalf("totals %+v", r)
+	}
+	if len(r.ByParameterSet) != 2 || r.ByParameterSet[0].Name != "ML-DSA-44" {
+		t.Fatalf("by parameter set %+v", r.ByParameterSet)
+	}
+	want := Counts{Total: 3, Failed: 1, DecodeFailures: 1, Skipped: 1}
+	if got := r.ByParameterSet[1].Counts; got != want {
+		t.Errorf("ML-DSA-65 counts %+v, want %+v", got, want)
+	}
+	if len(r.ByGroup) != 3 || r.ByGroup[0].Passed != 1 || r.ByGroup[0].Warnings != 1 {
+		t.Errorf("by group %+v", r.ByGroup)
+	}
+	if r.FailuresByStage["hint"] != 2 || r.FailuresByStage[UnclassifiedStage] != 1 {
+		t.Errorf("failures by stage %v", r.FailuresByStage)
+	}
+	d := r.Durations
+	if d == nil || d.Count != 4 || d.MinNS != int64(time.Millisecond) || d.MedianNS != int64(3*time.Millisecond) ||
+		d.P99NS != int64(4*time.Millisecond) || d.TotalNS != int64(10*time.Millisecond) {
+		t.Errorf("durations %+v", d)
+	}
+}
+
+func TestReportJSONSchema(t *testing.T) {
+	r, _ := NewReport()
+	empty, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, absent := range []string{"byParameterSet", "durations", "environment"} {
+		if strings.Contains(string(empty), absent) {
+			t.Errorf("empty report has %q: %s", absent, empty)
+		}
+	}
+
+	r.Add(Record{ParameterSet: "ML-DSA-87", Status: StatusFail, Stage: "z-range", Duration: time.Microsecond})
+	r.Finish()
+	data, _ := json.Marshal(r)
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["schemaVersion"] != float64(SchemaVersion) {
+		t.Errorf("schemaVersion = %v", got["schemaVersion"])
+	}
+	sets, _ := got["byParameterSet"].([]any)
+	if len(sets) != 1 || sets[0].(map[string]any)["failed"] != float64(1) {
+		t.Errorf("byParameterSet = %v", got["byParameterSet"])
+	}
+	if got["failuresByStage"].(map[string]any)["z-range"] != float64(1) {
+		t.Errorf("failuresByStage = %v", got["failuresByStage"])
+	}
+	if got["durations"].(map[string]any)["p99Ns"] != float64(time.Microsecond) {
+		t.Errorf("durations = %v", got["durations"])
+	}
+}
+
+func TestPercentileIndex(t *testing.T) {
+	for _, tc := range []struct{ n, p, want int }{
+		{1, 99, 0}, {2, 99, 1}, {100, 99, 98}, {101, 99, 99}, {200, 50, 99},
+	} {
+		if got := percentileIndex(tc.n, tc.p); got != tc.want {
+			t.Errorf("percentileIndex(%d, %d) = %d, want %d", tc.n, tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestNewEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vectors.json")
+	if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env, err := NewEnvironment("v1.2.3", path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Version != "v1.2.3" || env.GOOS == "" || env.GOARCH == "" || env.GoVersion == "" {
+		t.Errorf("environment %+v", env)
+	}
+	const abc = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if len(env.VectorFiles) != 1 || env.VectorFiles[0].Path != path || env.VectorFiles[0].SHA256 != abc {
+		t.Errorf("vector files %+v", env.VectorFiles)
+	}
+	if _, err := NewEnvironment("dev", filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing file: expected error")
+	}
+}
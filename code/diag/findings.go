@@ -0,0 +1,82 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import "sort"
+
+// Finding severities, most severe first.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// Severities lists the severities from most to least severe.
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// MaxEvidence bounds the evidence strings kept per finding.
+const MaxEvidence = 5
+
+// Finding is one rule violation, aggregated over every case that showed
+// it. Severity, Title, Remediation and References come from the rule
+// catalogue; Evidence names up to MaxEvidence of the Count cases.
+type Finding struct {
+	RuleID      string   `json:"ruleId"`
+	Severity    string   `json:"severity"`
+	Title       string   `json:"title"`
+	Count       int      `json:"count"`
+	Evidence    []string `json:"evidence,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	References  []string `json:"references,omitempty"`
+}
+
+// NewFinding returns a finding for ruleID with no evidence yet. An ID
+// missing from the catalogue yields a finding titled after the ID.
+func NewFinding(ruleID string) Finding {
+	r, ok := LookupRule(ruleID)
+	if !ok {
+		return Finding{RuleID: ruleID, Severity: SeverityInfo, Title: ruleID}
+	}
+	return Finding{RuleID: r.ID, Severity: r.Severity, Title: r.Title, Remediation: r.Remediation, References: r.References}
+}
+
+// AddFinding counts one occurrence of ruleID in list, merging it into the
+// existing finding for that rule if there is one, and returns the list.
+func AddFinding(list []Finding, ruleID, evidence string) []Finding {
+	i := 0
+	for i < len(list) && list[i].RuleID != ruleID {
+		i++
+	}
+	if i == len(list) {
+		list = append(list, NewFinding(ruleID))
+	}
+	f := &list[i]
+	f.Count++
+	if evidence != "" && len(f.Evidence) < MaxEvidence {
+		f.Evidence = append(f.Evidence, evidence)
+	}
+	return list
+}
+
+// SortFindings orders findings by severity, then by rule ID.
+func SortFindings(list []Finding) {
+	sort.SliceStable(list, func(i, j int) bool {
+		si, sj := severityRank(list[i].Severity), severityRank(list[j].Severity)
+		if si != sj {
+			return si < sj
+		}
+		return list[i].RuleID < list[j].RuleID
+	})
+}
+
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
@@ -0,0 +1,125 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRuleCatalogue(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range Rules {
+		if !strings.HasPrefix(r.ID, "DV-") || seen[r.ID] {
+			t.Errorf("rule ID %q", r.ID)
+		}
+		seen[r.ID] = true
+		if severityRank(r.Severity) == len(Severities) || r.Title == "" || r.Remediation == "" {
+			t.Errorf("rule %+v", r)
+		}
+	}
+	if _, ok := LookupRule(RuleZBound); !ok {
+		t.Error("DV-Z-BOUND missing")
+	}
+}
+
+func TestAddFinding(t *testing.T) {
+	var list []Finding
+	for i := 0; i < MaxEvidence+2; i++ {
+		list = AddFinding(list, RuleHintOrder, "case")
+	}
+	list = AddFinding(list, RuleSlowVerify, "")
+	list = AddFinding(list, RuleZBound, "z")
+	SortFindings(list)
+
+	if len(list) != 3 || list[0].RuleID != RuleHintOrder || list[1].RuleID != RuleZBound || list[2].RuleID != RuleSlowVerify {
+		t.Fatalf("order %+v", list)
+	}
+	if f := list[0]; f.Count != MaxEvidence+2 || len(f.Evidence) != MaxEvidence || f.Severity != SeverityCritical || len(f.References) == 0 {
+		t.Errorf("hint finding %+v", f)
+	}
+	if f := list[2]; f.Count != 1 || f.Evidence != nil {
+		t.Errorf("slow finding %+v", f)
+	}
+	if f := NewFinding("DV-UNKNOWN"); f.Title != "DV-UNKNOWN" || f.Severity != SeverityInfo {
+		t.Errorf("unknown rule %+v", f)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	findings := AddFinding(AddFinding(nil, RuleZBound, "a"), RuleTimingValidity, "b")
+	b := NewBaseline(findings[1:])
+	kept, suppressed := b.Apply(findings)
+	if len(kept) != 1 || kept[0].RuleID != RuleZBound || len(suppressed) != 1 {
+		t.Fatalf("kept %+v, suppressed %+v", kept, suppressed)
+	}
+	var none *Baseline
+	if kept, _ := none.Apply(findings); len(kept) != 2 {
+		t.Errorf("nil baseline kept %d", len(kept))
+	}
+
+	cases := []Case{
+		{Name: "1", Status: StatusFail, RuleID: RuleTimingValidity},
+		{Name: "2", Status: StatusFail, RuleID: RuleZBound},
+		{Name: "3", Status: StatusPass, RuleID: RuleTimingValidity},
+	}
+	b.MarkCases(cases)
+	if !cases[0].Suppressed || cases[1].Suppressed || cases[2].Suppressed {
+		t.Errorf("marked %+v", cases)
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBaseline(path)
+	if err != nil || !loaded.Suppresses(RuleTimingValidity) || loaded.Suppresses(RuleZBound) {
+		t.Fatalf("loaded %+v, %v", loaded, err)
+	}
+
+	for name, body := range map[string]string{
+		"unknown rule": `{"schemaVersion":2,"suppress":[{"ruleId":"DV-NOPE"}]}`,
+		"duplicate":    `{"schemaVersion":2,"suppress":[{"ruleId":"DV-Z-BOUND"},{"ruleId":"DV-Z-BOUND"}]}`,
+		"schema":       `{"schemaVersion":99,"suppress":[]}`,
+		"syntax":       `{`,
+	} {
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadBaseline(path); !errors.Is(err, ErrBaseline) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
+
+func TestWriteSARIFRuleAndSuppression(t *testing.T) {
+	cases := []Case{
+		{Suite: "s", Name: "a", Status: StatusFail, RuleID: RuleZBound},
+		{Suite: "s", Name: "b", Status: StatusFail, RuleID: RuleZBound, Suppressed: true},
+	}
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, Tool{Name: "dilivet"}, "", cases); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != RuleZBound || run.Tool.Driver.Rules[0].Help == nil {
+		t.Errorf("rules %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 || run.Results[0].Suppressions != nil || len(run.Results[1].Suppressions) != 1 {
+		t.Errorf("results %+v", run.Results)
+	}
+}
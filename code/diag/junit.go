@@ -0,0 +1,91 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes cases as a JUnit XML report named name, with one
+// testsuite per Case.Suite and one testcase per case. Failures carry the
+// verify stage as their type, so CI dashboards can group them by it.
+// Failures a baseline suppresses are reported as skipped.
+func WriteJUnit(w io.Writer, name string, cases []Case) error {
+	doc := junitTestSuites{Name: name}
+	names, bySuite := suites(cases)
+	for _, suiteName := range names {
+		s := junitTestSuite{Name: suiteName}
+		for _, c := range bySuite[suiteName] {
+			tc := junitTestCase{Name: c.Name, Classname: name + "." + strings.ReplaceAll(suiteName, " ", ".")}
+			problem := &junitProblem{Message: c.Message, Type: c.Stage, Text: c.Message}
+			switch {
+			case c.Suppressed:
+				tc.Skipped = &junitProblem{Message: "suppressed by baseline (" + c.RuleID + "): " + c.Message}
+				s.Skipped++
+			case c.Status == StatusFail:
+				tc.Failure = problem
+				s.Failures++
+			case c.Status == StatusError:
+				tc.Error = problem
+				s.Errors++
+			case c.Status == StatusSkipped:
+				tc.Skipped = &junitProblem{Message: c.Message}
+				s.Skipped++
+			}
+			s.Tests++
+			s.Cases = append(s.Cases, tc)
+		}
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+		doc.Skipped += s.Skipped
+		doc.Suites = append(doc.Suites, s)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,161 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+// Rule IDs. They are stable: a rule may be reworded, but its ID keeps its
+// meaning across releases so baselines and dashboards keep working.
+const (
+	RuleZBound            = "DV-Z-BOUND"
+	RuleHintOrder         = "DV-HINT-ORDER"
+	RuleHintOmega         = "DV-HINT-OMEGA"
+	RuleHintPadding       = "DV-HINT-PADDING"
+	RuleHintMalformed     = "DV-HINT-MALFORMED"
+	RuleChallenge         = "DV-CHALLENGE"
+	RuleSigLength         = "DV-SIG-LENGTH"
+	RuleSigMalformed      = "DV-SIG-MALFORMED"
+	RuleParamSet          = "DV-PARAM-SET"
+	RulePKMalformed       = "DV-PK-MALFORMED"
+	RuleEmptyMessage      = "DV-EMPTY-MESSAGE"
+	RuleAcceptInvalid     = "DV-ACCEPT-INVALID"
+	RuleRejectValid       = "DV-REJECT-VALID"
+	RuleErrorCode         = "DV-ERROR-CODE"
+	RuleKeyGenMismatch    = "DV-KEYGEN-MISMATCH"
+	RuleKeyGenError       = "DV-KEYGEN-ERROR"
+	RuleSigGenMismatch    = "DV-SIGGEN-MISMATCH"
+	RuleSigGenError       = "DV-SIGGEN-ERROR"
+	RuleDeterministicSign = "DV-DETERMINISTIC-SIGN"
+	RuleCrash             = "DV-CRASH"
+	RuleTimeout           = "DV-TIMEOUT"
+	RuleCallError         = "DV-CALL-ERROR"
+	RuleTimingValidity    = "DV-TIMING-VALIDITY"
+	RuleSlowVerify        = "DV-SLOW-VERIFY"
+	RuleVectorDecode      = "DV-VECTOR-DECODE"
+	RuleNonceReuse        = "DV-NONCE-REUSE"
+	RuleChallengeReuse    = "DV-CTILDE-REUSE"
+	RuleDuplicateSig      = "DV-DUPLICATE-SIG"
+	RuleZDistribution     = "DV-Z-DIST"
+	RuleHintDistribution  = "DV-HINT-DIST"
+	RuleChallengeDist     = "DV-CTILDE-DIST"
+)
+
+// fips204 is the specification most rules cite.
+const fips204 = "https://doi.org/10.6028/NIST.FIPS.204"
+
+// Rule is a catalogue entry: what a finding means and how to fix it.
+type Rule struct {
+	ID          string   `json:"id"`
+	Severity    string   `json:"severity"`
+	Title       string   `json:"title"`
+	Remediation string   `json:"remediation"`
+	References  []string `json:"references,omitempty"`
+}
+
+// Rules is the catalogue, grouped by area.
+var Rules = []Rule{
+	{RuleZBound, SeverityCritical, "accepts a z coefficient at or beyond γ1−β",
+		"Reject the signature when ‖z‖∞ ≥ γ1−β, checking both signs of every coefficient of every polynomial of z.",
+		[]string{"FIPS 204 Algorithm 8 (ML-DSA.Verify_internal), step 13", fips204}},
+	{RuleHintOrder, SeverityCritical, "accepts unsorted or repeated hint indices",
+		"In HintBitUnpack, require the indices of each polynomial to be strictly increasing.",
+		[]string{"FIPS 204 Algorithm 21 (HintBitUnpack), step 6", fips204}},
+	{RuleHintOmega, SeverityCritical, "accepts a hint counter above ω or below its predecessor",
+		"In HintBitUnpack, reject a counter y[ω+i] that is smaller than the previous counter or larger than ω.",
+		[]string{"FIPS 204 Algorithm 21 (HintBitUnpack), step 3", fips204}},
+	{RuleHintPadding, SeverityCritical, "accepts non-zero hint padding",
+		"In HintBitUnpack, require every index byte after the last counter to be zero.",
+		[]string{"FIPS 204 Algorithm 21 (HintBitUnpack), step 12", fips204}},
+	{RuleHintMalformed, SeverityCritical, "accepts a malformed hint encoding",
+		"Decode hints exactly as HintBitUnpack does and reject the signature when it returns ⊥.",
+		[]string{"FIPS 204 Algorithm 21 (HintBitUnpack)", fips204}},
+	{RuleChallenge, SeverityCritical, "accepts a signature whose challenge does not match",
+		"Recompute c̃′ from μ and w1′ and reject unless it equals c̃; do not skip the comparison when the norm check passes.",
+		[]string{"FIPS 204 Algorithm 8 (ML-DSA.Verify_internal), steps 12-13", fips204}},
+	{RuleSigLength, SeverityCritical, "accepts a signature of the wrong length",
+		"Reject signatures that are not exactly the parameter set's signature length before decoding them.",
+		[]string{"FIPS 204 Algorithm 27 (sigDecode)", fips204}},
+	{RuleSigMalformed, SeverityCritical, "accepts a malformed signature encoding",
+		"Decode the signature with sigDecode and reject it whenever decoding fails.",
+		[]string{"FIPS 204 Algorithm 27 (sigDecode)", fips204}},
+	{RuleParamSet, SeverityCritical, "accepts material re-encoded for another parameter set",
+		"Derive the parameter set from the key and check that key, signature and algorithm identifier agree on it.",
+		[]string{"FIPS 204 Table 1", fips204}},
+	{RulePKMalformed, SeverityCritical, "accepts a malformed public key",
+		"Reject public keys whose length does not match the parameter set; decode them with pkDecode only.",
+		[]string{"FIPS 204 Algorithm 23 (pkDecode)", fips204}},
+	{RuleEmptyMessage, SeverityInfo, "accepts an empty message the built-in verifier refuses",
+		"Nothing to fix: FIPS 204 allows empty messages. mldsa.Verify refuses them, so only suites written for it expect error:empty-message.",
+		[]string{"FIPS 204 Algorithm 8 (ML-DSA.Verify_internal)", fips204}},
+	{RuleAcceptInvalid, SeverityCritical, "accepts an invalid signature",
+		"Run every check of ML-DSA.Verify_internal; the vector's reason names the check that was skipped.",
+		[]string{"FIPS 204 Algorithm 8 (ML-DSA.Verify_internal)", fips204}},
+	{RuleRejectValid, SeverityHigh, "rejects a valid signature",
+		"Compare each verification step with the specification; a wrong μ, context or external-μ route is the usual cause.",
+		[]string{"FIPS 204 Algorithms 3 and 8", fips204}},
+	{RuleErrorCode, SeverityLow, "rejects with a different error than expected",
+		"Report the first check that fails, in the order ML-DSA.Verify_internal runs them.",
+		[]string{"docs/wycheproof-plan.md"}},
+	{RuleKeyGenMismatch, SeverityCritical, "generated key differs from the known answer",
+		"Compare ExpandA, ExpandS and Power2Round with the specification; the mismatch offset names the component.",
+		[]string{"FIPS 204 Algorithm 6 (ML-DSA.KeyGen_internal)", fips204}},
+	{RuleKeyGenError, SeverityHigh, "key generation failed",
+		"Make key generation succeed for every 32-byte seed.",
+		[]string{"FIPS 204 Algorithm 6 (ML-DSA.KeyGen_internal)", fips204}},
+	{RuleSigGenMismatch, SeverityCritical, "signature differs from the known answer",
+		"Compare ρ′, the rejection loop and sigEncode with the specification; the mismatch offset names the component.",
+		[]string{"FIPS 204 Algorithm 7 (ML-DSA.Sign_internal)", fips204}},
+	{RuleSigGenError, SeverityCritical, "signature generation failed or produced an invalid signature",
+		"Make signing succeed for every key and message and check its output with a reference verifier.",
+		[]string{"FIPS 204 Algorithm 7 (ML-DSA.Sign_internal)", fips204}},
+	{RuleDeterministicSign, SeverityHigh, "deterministic signature differs from the reference",
+		"With rnd = 0³², signing must be a pure function of key and message; compare with the reference byte for byte.",
+		[]string{"FIPS 204 Algorithm 2 (ML-DSA.Sign), deterministic variant", fips204}},
+	{RuleCrash, SeverityHigh, "implementation crashed",
+		"Fix the crash; a verifier must answer every input, however malformed.",
+		nil},
+	{RuleTimeout, SeverityHigh, "implementation timed out",
+		"Bound the work per call; look for loops driven by attacker-controlled lengths or counters.",
+		nil},
+	{RuleCallError, SeverityHigh, "implementation returned an error on well-formed input",
+		"Accept every well-formed input the interface defines; return errors only for malformed ones.",
+		nil},
+	{RuleTimingValidity, SeverityMedium, "verification time depends on signature validity",
+		"Run the full verification for every input before answering; confirm with a constant-time analysis.",
+		nil},
+	{RuleSlowVerify, SeverityLow, "verification is slower than 1s",
+		"Profile verification; ML-DSA verification normally takes well under a millisecond.",
+		nil},
+	{RuleVectorDecode, SeverityInfo, "test vector could not be decoded",
+		"Check the vector file; the case says nothing about the implementation.",
+		nil},
+
+	// Signature corpora (corpus-analyze).
+	{RuleNonceReuse, SeverityCritical, "signatures on different messages share the commitment w1",
+		"Derive ρ″ from K, rnd and μ as Sign_internal does and draw rnd from an approved RBG; two signatures with the same y give away s1.",
+		[]string{"FIPS 204 Algorithm 7 (ML-DSA.Sign_internal), step 7", fips204}},
+	{RuleChallengeReuse, SeverityCritical, "signatures on different messages share c̃",
+		"Compute c̃ = H(μ || w1Encode(w1), λ/4) with the μ of the message being signed.",
+		[]string{"FIPS 204 Algorithm 7 (ML-DSA.Sign_internal), step 15", fips204}},
+	{RuleDuplicateSig, SeverityHigh, "hedged signer repeated a signature",
+		"Draw a fresh rnd from an approved RBG for every hedged signature; repeats mean the generator is stuck or unseeded.",
+		[]string{"FIPS 204 Algorithm 2 (ML-DSA.Sign), step 5", fips204}},
+	{RuleZDistribution, SeverityHigh, "z coefficients are not uniform over (−(γ1−β), γ1−β)",
+		"Check ExpandMask and the rejection step: y must be uniform in [−γ1+1, γ1] and every z with ‖z‖∞ ≥ γ1−β rejected.",
+		[]string{"FIPS 204 Algorithm 34 (ExpandMask)", "FIPS 204 Algorithm 7 (ML-DSA.Sign_internal), step 23", fips204}},
+	{RuleHintDistribution, SeverityMedium, "hints are not distributed like the reference signer's",
+		"Compare Decompose, MakeHint and the ct0 check with the specification; hints should spread evenly over rows and positions.",
+		[]string{"FIPS 204 Algorithms 36 (Decompose) and 39 (MakeHint)", fips204}},
+	{RuleChallengeDist, SeverityMedium, "c̃ bytes are not uniform",
+		"Take c̃ straight from the SHAKE256 output H(μ || w1Encode(w1), λ/4) without truncating or re-encoding it.",
+		[]string{"FIPS 204 Algorithm 7 (ML-DSA.Sign_internal), step 15", fips204}},
+}
+
+// LookupRule returns the catalogue entry for id.
+func LookupRule(id string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,216 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package diag
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// SARIF 2.1.0 identifiers.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Tool describes the program a SARIF log comes from.
+type Tool struct {
+	Name    string
+	Version string
+	URI     string
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	Help             *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the cases that did not pass as results of a SARIF
+// 2.1.0 log with one run by tool. Failures are errors and cases that could
+// not run are warnings; passed and skipped cases are left out. Each
+// result points at artifact (the vector file, if non-empty) and names its
+// suite and case as a logical location. A case with a RuleID is reported
+// under that catalogue rule, with its remediation as help; other rule IDs
+// combine the status with the verify stage, e.g. "fail-hint". Failures a
+// baseline suppresses stay in the log with an external suppression.
+func WriteSARIF(w io.Writer, tool Tool, artifact string, cases []Case) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: tool.Name, Version: tool.Version, InformationURI: tool.URI, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	seen := map[string]bool{}
+	for _, c := range cases {
+		var level string
+		switch c.Status {
+		case StatusFail:
+			level = "error"
+		case StatusError:
+			level = "warning"
+		default:
+			continue
+		}
+		rule := sarifRuleFor(c)
+		if !seen[rule.ID] {
+			seen[rule.ID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		loc := sarifLocation{LogicalLocations: []sarifLogicalLocation{{
+			Name: c.Name, FullyQualifiedName: c.Suite + "/" + c.Name, Kind: "testcase",
+		}}}
+		if artifact != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: artifact}}
+		}
+		text := c.Suite + " " + c.Name
+		if c.Message != "" {
+			text += ": " + c.Message
+		}
+		props := map[string]string{"suite": c.Suite, "case": c.Name}
+		if c.Stage != "" {
+			props["stage"] = c.Stage
+		}
+		result := sarifResult{
+			RuleID: rule.ID, Level: level, Message: sarifMessage{text}, Locations: []sarifLocation{loc}, Properties: props,
+		}
+		if c.Suppressed {
+			result.Suppressions = []sarifSuppression{{Kind: "external", Justification: "accepted in the baseline"}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: SARIFSchema, Version: SARIFVersion, Runs: []sarifRun{run}})
+}
+
+func sarifRuleFor(c Case) sarifRule {
+	if r, ok := LookupRule(c.RuleID); ok {
+		return sarifRule{ID: r.ID, ShortDescription: sarifMessage{r.Title}, Help: &sarifMessage{r.Remediation}}
+	}
+	id, description := c.Status, "Test case failed"
+	if c.Status == StatusError {
+		description = "Test case could not be run"
+	}
+	if c.Stage != "" {
+		id += "-" + c.Stage
+		description += " (verify stage " + c.Stage + ")"
+	}
+	return sarifRule{ID: id, ShortDescription: sarifMessage{description}}
+}
+
+// SourceRule describes a rule of a static check.
+type SourceRule struct {
+	ID          string
+	Description string
+	Help        string
+}
+
+// SourceResult is a static-check finding at a position in a source file.
+type SourceResult struct {
+	RuleID  string
+	Message string
+	File    string // slash-separated, relative to the repository root where possible
+	Line    int
+	Column  int
+}
+
+// WriteSARIFSource writes static-check results as warnings of a SARIF
+// 2.1.0 log with one run by tool, each pointing at its file, line and
+// column. Only the rules some result uses are listed.
+func WriteSARIFSource(w io.Writer, tool Tool, rules []SourceRule, results []SourceResult) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: tool.Name, Version: tool.Version, InformationURI: tool.URI, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	used := map[string]bool{}
+	for _, r := range results {
+		used[r.RuleID] = true
+		loc := sarifLocation{PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: r.File},
+			Region:           &sarifRegion{StartLine: r.Line, StartColumn: r.Column},
+		}}
+		run.Results = append(run.Results, sarifResult{
+			RuleID: r.RuleID, Level: "warning", Message: sarifMessage{r.Message}, Locations: []sarifLocation{loc},
+		})
+	}
+	for _, r := range rules {
+		if used[r.ID] {
+			rule := sarifRule{ID: r.ID, ShortDescription: sarifMessage{r.Description}}
+			if r.Help != "" {
+				rule.Help = &sarifMessage{r.Help}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: SARIFSchema, Version: SARIFVersion, Runs: []sarifRun{run}})
+}
@@ -0,0 +1,277 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package faultsim assesses ML-DSA signers against fault attacks. It asks
+// a signer for faulty signatures (mldsa.FaultPlan), pairs each with the
+// correct signature for the same inputs, and runs the known leakage
+// checks on the pair: differential key recovery of s₁, out-of-bound z and
+// randomness reuse. For every fault that changed the output it also
+// records whether verify-after-sign or redundant signing would have
+// caught it.
+package faultsim
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// Leakage checks.
+const (
+	CheckKeyRecovery = "key-recovery" // s₁ recovered from the pair and confirmed against pk
+	CheckZBound      = "z-bound"      // the faulty z has coefficients with |z| ≥ γ₁ − β
+	CheckRndReuse    = "rnd-reuse"    // a signature repeats although fresh rnd was requested
+)
+
+// Checks lists the leakage checks in report order.
+var Checks = []string{CheckKeyRecovery, CheckZBound, CheckRndReuse}
+
+// Countermeasures.
+const (
+	// CounterVerify verifies the signature before releasing it.
+	CounterVerify = "verify-after-sign"
+	// CounterRedundant signs twice and releases nothing unless both agree.
+	// Transient faults hit only one of the runs; a stuck generator both.
+	CounterRedundant = "redundant-sign"
+)
+
+// Countermeasures lists the countermeasures in report order.
+var Countermeasures = []string{CounterVerify, CounterRedundant}
+
+// Verdicts.
+const (
+	VerdictLeak     = "leak"
+	VerdictNoLeak   = "no leak"
+	VerdictNoEffect = "no effect"
+)
+
+// ErrSign reports a correct (fault-free) signing call that failed.
+var ErrSign = errors.New("faultsim: signing failed")
+
+// Signer is a signer fault-sim can drive.
+type Signer interface {
+	kats.Signer
+	kats.FaultSigner
+}
+
+// Options tunes Run; zero fields take defaults.
+type Options struct {
+	Messages int   // messages signed per fault (default 4)
+	Attempts int   // rejection-loop iterations a transient fault is swept over (default 8)
+	Seed     int64 // seed for the key, messages and fault positions
+}
+
+// Pair is a correct and a faulty signature on the same message. Byte
+// strings are hex.
+type Pair struct {
+	Message string          `json:"message"`
+	Plan    mldsa.FaultPlan `json:"plan"`
+	Correct string          `json:"correct"`
+	Faulty  string          `json:"faulty"`
+	Leaks   []string        `json:"leaks,omitempty"`
+	Caught  []string        `json:"caught,omitempty"`
+}
+
+// Countermeasure counts the effective faults a countermeasure caught and
+// the leaking pairs it let through.
+type Countermeasure struct {
+	Name        string `json:"name"`
+	Caught      int    `json:"caught"`
+	Of          int    `json:"of"`
+	MissedLeaks int    `json:"missedLeaks"`
+}
+
+// Result is the outcome for one fault kind.
+type Result struct {
+	Fault           mldsa.FaultKind  `json:"fault"`
+	ParameterSet    string           `json:"parameterSet"`
+	Signed          int              `json:"signed"`    // faulty signing calls
+	Effective       int              `json:"effective"` // calls whose output the fault changed
+	Failed          int              `json:"failed"`    // calls where the faulty signer returned an error
+	Leaks           map[string]int   `json:"leaks"`     // leaking pairs per check
+	Countermeasures []Countermeasure `json:"countermeasures"`
+	Verdict         string           `json:"verdict"`
+	Example         *Pair            `json:"example,omitempty"` // the first leaking pair, else the first effective one
+}
+
+// Leak reports whether any check found leakage.
+func (r *Result) Leak() bool { return r.Verdict == VerdictLeak }
+
+// Run injects fault into signatures from s over fresh messages under a key
+// derived from opts.Seed, and checks every faulty signature against the
+// correct one. Transient faults are swept over the first opts.Attempts
+// iterations of the rejection loop with deterministic signing, the setting
+// differential fault attacks need; reuse-rnd pairs a hedged signature with
+// one the stuck generator produced for another rnd.
+func Run(s Signer, params *mldsa.Params, fault mldsa.FaultKind, opts Options) (*Result, error) {
+	if opts.Messages <= 0 {
+		opts.Messages = 4
+	}
+	if opts.Attempts <= 0 {
+		opts.Attempts = 8
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	seed := make([]byte, mldsa.SeedBytes)
+	rng.Read(seed)
+	pk, sk, err := mldsa.KeyGen(params, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Fault: fault, ParameterSet: params.Name, Leaks: map[string]int{}}
+	caught, missed := map[string]int{}, map[string]int{}
+	record := func(p *Pair) {
+		res.Effective++
+		for _, c := range Countermeasures {
+			switch {
+			case contains(p.Caught, c):
+				caught[c]++
+			case len(p.Leaks) > 0:
+				missed[c]++
+			}
+		}
+		for _, l := range p.Leaks {
+			res.Leaks[l]++
+		}
+		if res.Example == nil || (len(p.Leaks) > 0 && len(res.Example.Leaks) == 0) {
+			res.Example = p
+		}
+	}
+
+	for m := 0; m < opts.Messages; m++ {
+		msg := make([]byte, 32)
+		rng.Read(msg)
+
+		if fault == mldsa.FaultReuseRnd {
+			rndA, rndB := randBytes(rng), randBytes(rng)
+			correct, err := s.SignInternal(sk, msg, rndA)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrSign, err)
+			}
+			expected, err := s.SignInternal(sk, msg, rndB)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrSign, err)
+			}
+			plan := mldsa.FaultPlan{Kind: fault, Rnd: rndA}
+			res.Signed++
+			faulty, err := s.SignFaulty(sk, msg, rndB, &plan)
+			if err != nil {
+				res.Failed++
+				continue
+			}
+			if bytes.Equal(faulty, expected) {
+				continue // the fault had no effect
+			}
+			// Redundant signing runs into the same stuck generator.
+			again, err := s.SignFaulty(sk, msg, rndB, &plan)
+			if err != nil {
+				again = nil
+			}
+			p := evaluate(params, pk, msg, plan, correct, faulty, again)
+			if bytes.Equal(correct, faulty) {
+				p.Leaks = append([]string{CheckRndReuse}, p.Leaks...)
+			}
+			record(p)
+			continue
+		}
+
+		rnd := make([]byte, mldsa.RndBytes)
+		correct, err := s.SignInternal(sk, msg, rnd)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSign, err)
+		}
+		for a := 0; a < opts.Attempts; a++ {
+			plan := mldsa.FaultPlan{
+				Kind:    fault,
+				Attempt: a,
+				Poly:    rng.Intn(params.L),
+				Coeff:   rng.Intn(poly.N),
+				Delta:   1,
+				Bit:     rng.Intn(8 * params.CTildeBytes()),
+			}
+			res.Signed++
+			faulty, err := s.SignFaulty(sk, msg, rnd, &plan)
+			if err != nil {
+				res.Failed++
+				continue
+			}
+			if bytes.Equal(faulty, correct) {
+				continue
+			}
+			// A transient fault misses the second run of redundant signing.
+			record(evaluate(params, pk, msg, plan, correct, faulty, correct))
+		}
+	}
+
+	for _, name := range Countermeasures {
+		res.Countermeasures = append(res.Countermeasures, Countermeasure{Name: name, Caught: caught[name], Of: res.Effective, MissedLeaks: missed[name]})
+	}
+	switch {
+	case len(res.Leaks) > 0:
+		res.Verdict = VerdictLeak
+	case res.Effective == 0:
+		res.Verdict = VerdictNoEffect
+	default:
+		res.Verdict = VerdictNoLeak
+	}
+	return res, nil
+}
+
+// evaluate runs the leakage checks and countermeasures on one pair. again
+// is what the second run of redundant signing returned, nil if it failed.
+func evaluate(params *mldsa.Params, pk, msg []byte, plan mldsa.FaultPlan, correct, faulty, again []byte) *Pair {
+	p := &Pair{
+		Message: hex.EncodeToString(msg),
+		Plan:    plan,
+		Correct: hex.EncodeToString(correct),
+		Faulty:  hex.EncodeToString(faulty),
+	}
+	_, cc := traceSignature(pk, msg, correct)
+	valid, cf := traceSignature(pk, msg, faulty)
+
+	var e [][]int32
+	if plan.Kind == mldsa.FaultFlipY {
+		// The attacker knows where the fault lands, so z′ − z − e isolates
+		// (c′ − c)·s₁.
+		e = make([][]int32, params.L)
+		for i := range e {
+			e[i] = make([]int32, poly.N)
+		}
+		e[plan.Poly][plan.Coeff] = plan.YDelta()
+	}
+	if recoverS1(cc, cf, e, params) {
+		p.Leaks = append(p.Leaks, CheckKeyRecovery)
+	}
+	if zOutOfBound(cf, params) > 0 {
+		p.Leaks = append(p.Leaks, CheckZBound)
+	}
+
+	if !valid {
+		p.Caught = append(p.Caught, CounterVerify)
+	}
+	if !bytes.Equal(again, faulty) {
+		p.Caught = append(p.Caught, CounterRedundant)
+	}
+	return p
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func randBytes(rng *rand.Rand) []byte {
+	b := make([]byte, mldsa.RndBytes)
+	rng.Read(b)
+	return b
+}
@@ -0,0 +1,110 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package faultsim
+
+import (
+	"bytes"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+func TestRunBuiltin(t *testing.T) {
+	results := map[mldsa.FaultKind]*Result{}
+	for _, kind := range mldsa.FaultKinds {
+		res, err := Run(kats.Builtin{}, mldsa.ParamsMLDSA44, kind, Options{Seed: 1})
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if !res.Leak() || res.Effective == 0 || res.Example == nil || len(res.Example.Leaks) == 0 ||
+			len(res.Countermeasures) != len(Countermeasures) {
+			t.Errorf("%s: %+v", kind, res)
+		}
+		results[kind] = res
+	}
+
+	counter := func(kind mldsa.FaultKind, name string) Countermeasure {
+		for _, c := range results[kind].Countermeasures {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("%s: no %s", kind, name)
+		return Countermeasure{}
+	}
+	// A corrupted challenge breaks the signature, so verifying catches it;
+	// a shifted mask yields a valid one that still gives away s₁.
+	if r := results[mldsa.FaultCorruptC]; r.Leaks[CheckKeyRecovery] == 0 {
+		t.Errorf("corrupt-c: no key recovery: %+v", r)
+	}
+	if c := counter(mldsa.FaultCorruptC, CounterVerify); c.Caught != c.Of || c.MissedLeaks != 0 {
+		t.Errorf("corrupt-c: %+v", c)
+	}
+	if r := results[mldsa.FaultFlipY]; r.Leaks[CheckKeyRecovery] == 0 {
+		t.Errorf("flip-y: no key recovery: %+v", r)
+	}
+	if c := counter(mldsa.FaultFlipY, CounterVerify); c.Caught != 0 || c.MissedLeaks == 0 {
+		t.Errorf("flip-y: %+v", c)
+	}
+	if r := results[mldsa.FaultSkipNormCheck]; r.Leaks[CheckZBound] == 0 {
+		t.Errorf("skip-norm-check: no z-bound leak: %+v", r)
+	}
+	if r := results[mldsa.FaultReuseRnd]; r.Leaks[CheckRndReuse] != 4 {
+		t.Errorf("reuse-rnd: %+v", r)
+	}
+	if c := counter(mldsa.FaultReuseRnd, CounterRedundant); c.Caught != 0 || c.MissedLeaks != 4 {
+		t.Errorf("reuse-rnd: %+v", c)
+	}
+	for _, kind := range []mldsa.FaultKind{mldsa.FaultCorruptC, mldsa.FaultFlipY, mldsa.FaultSkipNormCheck} {
+		if c := counter(kind, CounterRedundant); c.Caught != c.Of {
+			t.Errorf("%s: redundant signing missed a transient fault: %+v", kind, c)
+		}
+	}
+}
+
+// hardened ignores fault plans, like a signer whose countermeasures
+// suppress every injected fault.
+type hardened struct{ kats.Builtin }
+
+func (hardened) SignFaulty(sk, msg, rnd []byte, plan *mldsa.FaultPlan) ([]byte, error) {
+	return mldsa.Sign(sk, msg, rnd)
+}
+
+func TestRunNoEffect(t *testing.T) {
+	for _, kind := range mldsa.FaultKinds {
+		res, err := Run(hardened{}, mldsa.ParamsMLDSA44, kind, Options{Messages: 2, Attempts: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Verdict != VerdictNoEffect || res.Effective != 0 || res.Example != nil {
+			t.Errorf("%s: %+v", kind, res)
+		}
+	}
+}
+
+func TestRecoverS1NeedsSharedMask(t *testing.T) {
+	params := mldsa.ParamsMLDSA44
+	pk, sk, err := mldsa.KeyGen(params, bytes.Repeat([]byte{3}, mldsa.SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("two honest signatures")
+	a, err := mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := mldsa.Sign(sk, msg, bytes.Repeat([]byte{1}, mldsa.RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ca := traceSignature(pk, msg, a)
+	_, cb := traceSignature(pk, msg, b)
+	if recoverS1(ca, cb, nil, params) {
+		t.Error("recovered s1 from signatures with independent masks")
+	}
+	if zOutOfBound(ca, params) != 0 {
+		t.Error("honest signature has z out of bound")
+	}
+}
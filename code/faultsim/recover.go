@@ -0,0 +1,159 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package faultsim
+
+import (
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// droppedBits is d, the number of low bits Power2Round drops from t.
+const droppedBits = 13
+
+// capture records the verification intermediates the checks need.
+type capture map[mldsa.TraceStep]mldsa.TraceValue
+
+func (c capture) Trace(step mldsa.TraceStep, v mldsa.TraceValue) { c[step] = v }
+
+// traceSignature verifies sig over msg with the reference verifier and
+// returns its verdict with the intermediates it reached.
+func traceSignature(pk, msg, sig []byte) (bool, capture) {
+	c := capture{}
+	ok, err := mldsa.VerifyTraced(pk, msg, sig, c)
+	return ok && err == nil, c
+}
+
+// zOutOfBound counts the coefficients of the traced z with |z| ≥ γ₁ − β,
+// which honest signing never releases: each one ties y and c·s₁ to a
+// narrow range and leaks a linear inequality on s₁.
+func zOutOfBound(c capture, params *mldsa.Params) int {
+	n := 0
+	bound := int32(params.Gamma1 - params.Beta)
+	for _, p := range c[mldsa.TraceZ].Polys {
+		for _, v := range p {
+			if v >= bound || -v >= bound {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// recoverS1 runs the differential fault attack on two signatures that
+// share the mask y up to a known offset e: z′ − z − e = (c′ − c)·s₁, so
+// s₁ = (z′ − z − e)·(c′ − c)⁻¹ in the NTT domain. The candidate is
+// accepted only if it is short and t₁·2^d − A·s₁ is as short as s₂ plus
+// the Power2Round error, which needs nothing but the public key. e may be
+// nil.
+func recoverS1(correct, faulty capture, e [][]int32, params *mldsa.Params) bool {
+	cc, cf := correct[mldsa.TraceC].Polys, faulty[mldsa.TraceC].Polys
+	zc, zf := correct[mldsa.TraceZ].Polys, faulty[mldsa.TraceZ].Polys
+	t1, aHat := faulty[mldsa.TraceT1].Polys, faulty[mldsa.TraceAHat].Polys
+	if len(cc) != 1 || len(cf) != 1 || len(zc) != params.L || len(zf) != params.L ||
+		len(t1) != params.K || len(aHat) != params.K*params.L {
+		return false
+	}
+
+	var dc poly.Poly
+	zero := true
+	for j := range dc.Coeffs {
+		d := cf[0][j] - cc[0][j]
+		zero = zero && d == 0
+		dc.Coeffs[j] = toModQ(int64(d))
+	}
+	if zero {
+		return false // same challenge: the difference carries no s₁
+	}
+	if err := poly.NTT(&dc); err != nil {
+		return false
+	}
+	var inv poly.Poly
+	for j, v := range dc.Coeffs {
+		v = poly.ModQ(v)
+		if v == 0 {
+			return false // c′ − c is not invertible
+		}
+		inv.Coeffs[j] = invModQ(v)
+	}
+
+	s1Hat := make([]*poly.Poly, params.L)
+	for i := 0; i < params.L; i++ {
+		var dz poly.Poly
+		for j := range dz.Coeffs {
+			d := int64(zf[i][j]) - int64(zc[i][j])
+			if e != nil {
+				d -= int64(e[i][j])
+			}
+			dz.Coeffs[j] = toModQ(d)
+		}
+		if err := poly.NTT(&dz); err != nil {
+			return false
+		}
+		s := &poly.Poly{}
+		s.PointwiseMontgomery(&dz, &inv)
+		if err := poly.InvNTT(s); err != nil {
+			return false
+		}
+		for _, v := range s.Coeffs {
+			if abs(poly.Canonical(v)) > int32(params.Eta) {
+				return false
+			}
+		}
+		if err := poly.NTT(s); err != nil {
+			return false
+		}
+		s1Hat[i] = s
+	}
+
+	// t = A·s₁ + s₂ and t₁·2^d = t − t₀ with |t₀| ≤ 2^(d−1).
+	bound := int32(1<<(droppedBits-1) + params.Eta)
+	for r := 0; r < params.K; r++ {
+		row := make([]*poly.Poly, params.L)
+		for i := range row {
+			row[i] = &poly.Poly{}
+			for j, v := range aHat[r*params.L+i] {
+				row[i].Coeffs[j] = uint32(v)
+			}
+		}
+		as1 := &poly.Poly{}
+		poly.PointwiseAccMontgomery(as1, row, s1Hat)
+		if err := poly.InvNTT(as1); err != nil {
+			return false
+		}
+		for j, v := range t1[r] {
+			diff := toModQ(int64(v)<<droppedBits - int64(poly.ModQ(as1.Coeffs[j])))
+			if abs(poly.Canonical(diff)) > bound {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func toModQ(x int64) uint32 {
+	x %= poly.Q
+	if x < 0 {
+		x += poly.Q
+	}
+	return uint32(x)
+}
+
+// invModQ returns x⁻¹ mod q for x in [1, q) by Fermat's little theorem.
+func invModQ(x uint32) uint32 {
+	r, b := uint64(1), uint64(x)
+	for e := uint64(poly.Q - 2); e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = r * b % poly.Q
+		}
+		b = b * b % poly.Q
+	}
+	return uint32(r)
+}
+
+func abs(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
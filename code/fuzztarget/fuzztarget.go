@@ -0,0 +1,374 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package fuzztarget fuzzes an external ML-DSA verifier without coverage
+// feedback. Seeds are mutated at random and each mutated input runs on the
+// target and on a reference verifier. The fuzzer flags crashes, hangs,
+// answers that change between identical calls, and verdicts that differ
+// from the reference. Each finding is minimized and can be saved as a
+// Wycheproof-style case.
+package fuzztarget
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// Finding kinds.
+const (
+	KindCrash            = "crash"
+	KindHang             = "hang"
+	KindNondeterministic = "nondeterministic"
+	KindDisagreement     = "disagreement"
+)
+
+// Outcome values besides "valid=true" and "valid=false".
+const (
+	ValueCrash = "crash"
+	ValueHang  = "hang"
+)
+
+// Errors returned by Run.
+var (
+	ErrNoSeeds     = errors.New("fuzztarget: no seeds")
+	ErrUnbounded   = errors.New("fuzztarget: needs a duration or an iteration count")
+	ErrUnsupported = errors.New("fuzztarget: target does not implement verify-internal")
+)
+
+// VerifyFunc runs ML-DSA.Verify_internal. Targets driven through execsign
+// report crashes and hangs by wrapping execsign.ErrCrashed (or
+// ErrProtocol) and execsign.ErrTimeout.
+type VerifyFunc func(pk, msg, sig []byte) (bool, error)
+
+// Reference is the built-in ML-DSA.Verify_internal to judge targets
+// against. Unlike mldsa.Verify it accepts an empty M′, as FIPS 204 does,
+// so a conformant target is not faulted on the empty edge message.
+func Reference(pk, msg, sig []byte) (bool, error) {
+	return mldsa.VerifyTraced(pk, msg, sig, nil)
+}
+
+// Options bounds and seeds a run.
+type Options struct {
+	Duration    time.Duration // stop after this long; zero leaves it to Iterations
+	Iterations  int           // stop after this many mutated inputs; zero leaves it to Duration
+	Repeat      int           // calls per input to catch non-determinism; zero uses 2
+	MaxFindings int           // distinct findings kept; zero uses 32
+	Seed        int64         // seeds mutation, so runs are reproducible
+}
+
+// Outcome is one target call. Value is "valid=true", "valid=false" (an
+// error that is not a crash or hang counts as a rejection), "crash" or
+// "hang".
+type Outcome struct {
+	Value string `json:"value"`
+	Err   string `json:"error,omitempty"`
+}
+
+// Finding is one distinct problem. Input is the minimized input and
+// Original is set when minimization changed it. Reference is the
+// reference verifier's verdict on Input.
+type Finding struct {
+	Kind         string    `json:"kind"`
+	Seed         string    `json:"seed"`
+	Mutators     []string  `json:"mutators"`
+	ParameterSet string    `json:"parameterSet,omitempty"`
+	Input        Input     `json:"input"`
+	Original     *Input    `json:"original,omitempty"`
+	Reference    string    `json:"reference"`
+	Outcomes     []Outcome `json:"outcomes"`
+}
+
+// Case renders the finding as a Wycheproof-style case whose expected
+// verdict is the reference's, so replaying it against the target fails.
+func (f Finding) Case(id string) kat.SuiteCase {
+	expected := kat.ExpectReject
+	if f.Reference == "valid=true" {
+		expected = kat.ExpectAccept
+	}
+	got := make([]string, len(f.Outcomes))
+	for i, o := range f.Outcomes {
+		got[i] = o.Value
+	}
+	return kat.SuiteCase{
+		ID:          id,
+		Description: fmt.Sprintf("%s from seed %s via %s; target answered %s.", f.Kind, f.Seed, strings.Join(f.Mutators, "+"), strings.Join(got, ", ")),
+		Category:    f.Kind,
+		Reason:      f.Kind,
+		Msg:         hex.EncodeToString(f.Input.Msg),
+		PK:          hex.EncodeToString(f.Input.PK),
+		Sig:         hex.EncodeToString(f.Input.Sig),
+		Expected:    expected,
+	}
+}
+
+// Report is the result of a run.
+type Report struct {
+	Seeds      int            `json:"seeds"`
+	Executions int            `json:"executions"` // inputs judged, seeds included
+	Elapsed    time.Duration  `json:"elapsedNs"`
+	Counts     map[string]int `json:"counts"`     // inputs per finding kind, duplicates included
+	Duplicates int            `json:"duplicates"` // findings dropped as already seen or over MaxFindings
+	Findings   []Finding      `json:"findings"`
+}
+
+// Failed reports whether the run found anything.
+func (r *Report) Failed() bool {
+	return len(r.Findings) > 0
+}
+
+// Budgets for minimizing one finding. Each step is a full judgement, so a
+// hang costs Repeat timeouts per step.
+const (
+	maxMinimizeSteps     = 64
+	maxHangMinimizeSteps = 4
+)
+
+// Run judges every seed, then mutated seeds until opts.Duration passes,
+// opts.Iterations mutated inputs have run or ctx is done.
+func Run(ctx context.Context, target, reference VerifyFunc, seeds []Seed, opts Options) (*Report, error) {
+	if len(seeds) == 0 {
+		return nil, ErrNoSeeds
+	}
+	if opts.Duration <= 0 && opts.Iterations <= 0 {
+		return nil, ErrUnbounded
+	}
+	if opts.Repeat <= 0 {
+		opts.Repeat = 2
+	}
+	if opts.MaxFindings <= 0 {
+		opts.MaxFindings = 32
+	}
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	f := &fuzzer{target: target, reference: reference, repeat: opts.Repeat}
+	r := &Report{Seeds: len(seeds), Counts: map[string]int{}, Findings: []Finding{}}
+	seen := map[string]bool{}
+	start := time.Now()
+	record := func(s Seed, names []string, in Input) error {
+		r.Executions++
+		kind, ref, outcomes, err := f.judge(in)
+		if err != nil || kind == "" {
+			return err
+		}
+		r.Counts[kind]++
+		key := strings.Join([]string{kind, paramSet(in.PK), ref, outcomes[len(outcomes)-1].Value, strings.Join(names, "+")}, "|")
+		if seen[key] || len(r.Findings) >= opts.MaxFindings {
+			r.Duplicates++
+			return nil
+		}
+		seen[key] = true
+		finding := Finding{Kind: kind, Seed: s.Source + " " + s.Label, Mutators: names,
+			ParameterSet: paramSet(in.PK), Input: in, Reference: ref, Outcomes: outcomes}
+		if small, ok := f.minimize(in, Input{PK: s.PK, Msg: s.Msg, Sig: s.Sig}, kind); ok {
+			original := in
+			finding.Input, finding.Original = small, &original
+			_, finding.Reference, finding.Outcomes, _ = f.judge(small)
+		}
+		r.Findings = append(r.Findings, finding)
+		return nil
+	}
+
+	for _, s := range seeds {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := record(s, []string{"none"}, Input{PK: s.PK, Msg: s.Msg, Sig: s.Sig}.clone()); err != nil {
+			return nil, err
+		}
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	for i := 0; (opts.Iterations <= 0 || i < opts.Iterations) && ctx.Err() == nil; i++ {
+		s := seeds[rng.Intn(len(seeds))]
+		in := Input{PK: s.PK, Msg: s.Msg, Sig: s.Sig}.clone()
+		// Mostly one mutation, sometimes a short stack of them.
+		n := 1
+		if rng.Intn(4) == 0 {
+			n = 2 + rng.Intn(2)
+		}
+		names := make([]string, n)
+		for j := range names {
+			m := mutators[rng.Intn(len(mutators))]
+			m.apply(rng, &in, seeds)
+			names[j] = m.name
+		}
+		if err := record(s, names, in); err != nil {
+			return nil, err
+		}
+	}
+	r.Elapsed = time.Since(start)
+	return r, nil
+}
+
+type fuzzer struct {
+	target, reference VerifyFunc
+	repeat            int
+}
+
+// call runs in on the target once.
+func (f *fuzzer) call(in Input) (Outcome, error) {
+	ok, err := f.target(in.PK, in.Msg, in.Sig)
+	var o Outcome
+	switch {
+	case errors.Is(err, kats.ErrUnsupported), errors.Is(err, execsign.ErrUnsupported):
+		return o, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	case errors.Is(err, execsign.ErrTimeout):
+		o.Value = ValueHang
+	case errors.Is(err, execsign.ErrCrashed), errors.Is(err, execsign.ErrProtocol):
+		o.Value = ValueCrash
+	default:
+		o.Value = fmt.Sprintf("valid=%t", ok && err == nil)
+	}
+	if err != nil {
+		o.Err = err.Error()
+	}
+	return o, nil
+}
+
+// judge classifies in. A crash or hang on any call wins; otherwise calls
+// that disagree with each other make it non-deterministic, and a stable
+// answer that differs from the reference is a disagreement. kind is ""
+// for a clean input.
+func (f *fuzzer) judge(in Input) (kind, ref string, outcomes []Outcome, err error) {
+	ok, rerr := f.reference(in.PK, in.Msg, in.Sig)
+	ref = fmt.Sprintf("valid=%t", ok && rerr == nil)
+	for i := 0; i < f.repeat; i++ {
+		o, err := f.call(in)
+		if err != nil {
+			return "", ref, nil, err
+		}
+		outcomes = append(outcomes, o)
+		switch o.Value {
+		case ValueCrash:
+			return KindCrash, ref, outcomes, nil
+		case ValueHang:
+			return KindHang, ref, outcomes, nil
+		}
+	}
+	for _, o := range outcomes[1:] {
+		if o.Value != outcomes[0].Value {
+			return KindNondeterministic, ref, outcomes, nil
+		}
+	}
+	if outcomes[0].Value != ref {
+		return KindDisagreement, ref, outcomes, nil
+	}
+	return "", ref, outcomes, nil
+}
+
+// minimize undoes as much of the mutation as it can while the input keeps
+// producing kind: bytes of pk and sig that differ from the seed are put
+// back in shrinking chunks, then the message is cut down. Non-determinism
+// is not minimized, since a failed check proves nothing.
+func (f *fuzzer) minimize(in, seed Input, kind string) (Input, bool) {
+	budget := maxMinimizeSteps
+	switch kind {
+	case KindNondeterministic:
+		return in, false
+	case KindHang:
+		budget = maxHangMinimizeSteps
+	}
+	still := func(candidate Input) bool {
+		if budget <= 0 {
+			return false
+		}
+		budget--
+		k, _, _, err := f.judge(candidate)
+		return err == nil && k == kind
+	}
+
+	best, changed := in, false
+	for _, field := range []struct {
+		get  func(*Input) *[]byte
+		base []byte
+	}{
+		{func(x *Input) *[]byte { return &x.Sig }, seed.Sig},
+		{func(x *Input) *[]byte { return &x.PK }, seed.PK},
+		{func(x *Input) *[]byte { return &x.Msg }, seed.Msg},
+	} {
+		cur := *field.get(&best)
+		reverted := revert(cur, field.base, func(b []byte) bool {
+			candidate := best
+			*field.get(&candidate) = b
+			return still(candidate)
+		})
+		if reverted != nil {
+			*field.get(&best), changed = reverted, true
+		}
+	}
+
+	// Cut the message down: halve it, then drop one trailing byte.
+	for len(best.Msg) > 0 && budget > 0 {
+		progressed := false
+		for _, n := range []int{len(best.Msg) / 2, len(best.Msg) - 1} {
+			if n >= len(best.Msg) {
+				continue
+			}
+			candidate := best
+			candidate.Msg = best.Msg[:n]
+			if still(candidate) {
+				best, changed, progressed = candidate, true, true
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return best, changed
+}
+
+// revert copies chunks of base back into cur while check holds, halving
+// the chunk size down to one byte. It returns nil when nothing could be
+// reverted or the lengths differ.
+func revert(cur, base []byte, check func([]byte) bool) []byte {
+	if len(cur) != len(base) {
+		return nil
+	}
+	var diff []int
+	for i := range cur {
+		if cur[i] != base[i] {
+			diff = append(diff, i)
+		}
+	}
+	var best []byte
+	for size := len(diff); size >= 1 && len(diff) > 0; size /= 2 {
+		for start := 0; start < len(diff); {
+			end := min(start+size, len(diff))
+			candidate := clone(cur)
+			if best != nil {
+				candidate = clone(best)
+			}
+			for _, i := range diff[start:end] {
+				candidate[i] = base[i]
+			}
+			if check(candidate) {
+				best = candidate
+				diff = append(diff[:start], diff[end:]...)
+				continue
+			}
+			start = end
+		}
+	}
+	return best
+}
+
+func paramSet(pk []byte) string {
+	if params, err := mldsa.FromPublicKeyLength(len(pk)); err == nil {
+		return params.Name
+	}
+	return ""
+}
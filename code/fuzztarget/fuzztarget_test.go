@@ -0,0 +1,206 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package fuzztarget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/corpus"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+func testSeeds(t *testing.T) []Seed {
+	t.Helper()
+	var seeds []Seed
+	for i, params := range parameterSets {
+		s, err := signed(params, []byte("fuzz-target seed message"), byte(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Source, s.Label = "test", params.Name
+		seeds = append(seeds, s)
+	}
+	return seeds
+}
+
+func acceptAll(pk, msg, sig []byte) (bool, error) { return true, nil }
+
+func TestRunFindsDisagreements(t *testing.T) {
+	seeds := testSeeds(t)
+	r, err := Run(context.Background(), acceptAll, mldsa.Verify, seeds, Options{Iterations: 60, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Executions != len(seeds)+60 || r.Counts[KindDisagreement] == 0 || !r.Failed() {
+		t.Fatalf("report %+v", r)
+	}
+	for _, f := range r.Findings {
+		if f.Kind != KindDisagreement || f.Reference != "valid=false" {
+			t.Errorf("finding %+v", f)
+		}
+		// A lone bit flip minimizes to the one byte that matters.
+		if len(f.Mutators) == 1 && f.Mutators[0] == "sig-bitflip" {
+			seed := seedFor(seeds, f.Input.PK)
+			if n := differing(f.Input.Sig, seed.Sig); n != 1 {
+				t.Errorf("minimized sig differs from its seed in %d bytes", n)
+			}
+		}
+
+		// Replaying the reproducer against the reference passes.
+		suite := &kat.Suite{Name: "replay", Cases: []kat.SuiteCase{f.Case("c")}}
+		if res := kat.RunSuite(suite, mldsa.Verify, nil); !res[0].Pass {
+			t.Errorf("reproducer does not replay: %+v", res[0])
+		}
+	}
+}
+
+func TestRunClassifiesTargetFailures(t *testing.T) {
+	calls := 0
+	tests := []struct {
+		name   string
+		target VerifyFunc
+		kind   string
+	}{
+		{"crash", func(pk, msg, sig []byte) (bool, error) {
+			return false, fmt.Errorf("%w: exit status 2", execsign.ErrCrashed)
+		}, KindCrash},
+		{"hang", func(pk, msg, sig []byte) (bool, error) {
+			return false, fmt.Errorf("%w: verify-internal after 5s", execsign.ErrTimeout)
+		}, KindHang},
+		{"flaky", func(pk, msg, sig []byte) (bool, error) {
+			calls++
+			return calls%2 == 0, nil
+		}, KindNondeterministic},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := Run(context.Background(), tc.target, mldsa.Verify, testSeeds(t), Options{Iterations: 5, Seed: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(r.Findings) == 0 {
+				t.Fatal("no findings")
+			}
+			for _, f := range r.Findings {
+				if f.Kind != tc.kind {
+					t.Errorf("kind %q, want %q", f.Kind, tc.kind)
+				}
+			}
+		})
+	}
+}
+
+func TestRunAgreeingTarget(t *testing.T) {
+	r, err := Run(context.Background(), mldsa.Verify, mldsa.Verify, testSeeds(t), Options{Iterations: 40, Seed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Failed() {
+		t.Fatalf("findings for an identical target: %+v", r.Findings)
+	}
+}
+
+// A conformant verifier accepts the signed empty message among the edge
+// seeds; mldsa.Verify does not, so it must not be the reference.
+func TestReferenceAcceptsEdgeSeeds(t *testing.T) {
+	seeds, err := Seeds(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edge []Seed
+	for _, s := range seeds {
+		if s.Source != SourceEdge {
+			continue
+		}
+		if ok, err := Reference(s.PK, s.Msg, s.Sig); !ok || err != nil {
+			t.Errorf("%s: ok=%v err=%v", s.Label, ok, err)
+		}
+		edge = append(edge, s)
+	}
+	if len(edge) == 0 || len(edge[0].Msg) != 0 {
+		t.Fatalf("edge seeds do not start with the empty message")
+	}
+	r, err := Run(context.Background(), Reference, Reference, edge, Options{Iterations: 1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Failed() {
+		t.Fatalf("findings for a conformant target: %+v", r.Findings)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(context.Background(), acceptAll, mldsa.Verify, testSeeds(t), Options{}); !errors.Is(err, ErrUnbounded) {
+		t.Errorf("got %v, want ErrUnbounded", err)
+	}
+	unsupported := func(pk, msg, sig []byte) (bool, error) {
+		return false, execsign.ErrUnsupported
+	}
+	if _, err := Run(context.Background(), unsupported, mldsa.Verify, testSeeds(t), Options{Iterations: 1}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("got %v, want ErrUnsupported", err)
+	}
+}
+
+func TestSeedsReadCorpus(t *testing.T) {
+	dir := t.TempDir()
+	write := func(target, name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, target, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("FuzzVerify", "a", "go test fuzz v1\n[]byte(\"hello\\x00\")\n")
+	write("FuzzDecodePublicKey", "b", "go test fuzz v1\n[]byte(\"\\xff\")\n")
+
+	seeds, err := Seeds(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []Seed
+	for _, s := range seeds {
+		if s.Source == SourceCorpus {
+			entries = append(entries, s)
+		}
+	}
+	if len(entries) != 2 || string(entries[0].Msg) != "hello\x00" || string(entries[1].PK) != "\xff" {
+		t.Fatalf("corpus seeds %+v", entries)
+	}
+	if ok, err := mldsa.Verify(entries[0].PK, entries[0].Msg, entries[0].Sig); !ok || err != nil {
+		t.Errorf("FuzzVerify seed is not validly signed: %v", err)
+	}
+
+	write("FuzzVerify", "c", "go test fuzz v1\nint(3)\n")
+	if _, err := Seeds(dir); !errors.Is(err, corpus.ErrFormat) {
+		t.Errorf("got %v, want corpus.ErrFormat", err)
+	}
+}
+
+func seedFor(seeds []Seed, pk []byte) Seed {
+	for _, s := range seeds {
+		if string(s.PK) == string(pk) {
+			return s
+		}
+	}
+	return Seed{}
+}
+
+func differing(a, b []byte) int {
+	n := 0
+	for i := range a {
+		if i >= len(b) || a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
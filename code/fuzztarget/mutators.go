@@ -0,0 +1,116 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package fuzztarget
+
+import (
+	"math/rand"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+)
+
+// Input is one verify-internal call.
+type Input struct {
+	PK  []byte `json:"pk"`
+	Msg []byte `json:"msg"`
+	Sig []byte `json:"sig"`
+}
+
+func (in Input) clone() Input {
+	return Input{PK: clone(in.PK), Msg: clone(in.Msg), Sig: clone(in.Sig)}
+}
+
+// mutator rewrites in place; seeds lets splicing borrow from other seeds.
+type mutator struct {
+	name  string
+	apply func(rng *rand.Rand, in *Input, seeds []Seed)
+}
+
+// interesting are byte values that tend to sit on encoding boundaries.
+var interesting = []byte{0x00, 0x01, 0x7f, 0x80, 0xfe, 0xff}
+
+// mutators are coverage-free: byte-level havoc on every field plus
+// layout-aware rewrites of whole key and signature components.
+var mutators = []mutator{
+	{"sig-bitflip", func(rng *rand.Rand, in *Input, _ []Seed) { bitflip(rng, in.Sig) }},
+	{"pk-bitflip", func(rng *rand.Rand, in *Input, _ []Seed) { bitflip(rng, in.PK) }},
+	{"msg-bitflip", func(rng *rand.Rand, in *Input, _ []Seed) { bitflip(rng, in.Msg) }},
+	{"sig-interesting-byte", func(rng *rand.Rand, in *Input, _ []Seed) {
+		if len(in.Sig) > 0 {
+			in.Sig[rng.Intn(len(in.Sig))] = interesting[rng.Intn(len(interesting))]
+		}
+	}},
+	{"sig-truncate", func(rng *rand.Rand, in *Input, _ []Seed) { in.Sig = truncate(rng, in.Sig) }},
+	{"sig-extend", func(rng *rand.Rand, in *Input, _ []Seed) { in.Sig = extend(rng, in.Sig) }},
+	{"pk-truncate", func(rng *rand.Rand, in *Input, _ []Seed) { in.PK = truncate(rng, in.PK) }},
+	{"msg-truncate", func(rng *rand.Rand, in *Input, _ []Seed) { in.Msg = truncate(rng, in.Msg) }},
+	{"msg-extend", func(rng *rand.Rand, in *Input, _ []Seed) { in.Msg = extend(rng, in.Msg) }},
+	{"sig-copy-range", func(rng *rand.Rand, in *Input, _ []Seed) {
+		if n := len(in.Sig); n > 1 {
+			size := 1 + rng.Intn(min(n/2, 64))
+			copy(in.Sig[rng.Intn(n-size+1):], in.Sig[rng.Intn(n-size+1):][:size])
+		}
+	}},
+	{"sig-component", func(rng *rand.Rand, in *Input, _ []Seed) {
+		if params, err := mldsa.FromPublicKeyLength(len(in.PK)); err == nil && len(in.Sig) == params.SigBytes {
+			fillComponent(rng, in.Sig, mldsa.SignatureLayout(params))
+		}
+	}},
+	{"pk-component", func(rng *rand.Rand, in *Input, _ []Seed) {
+		if params, err := mldsa.FromPublicKeyLength(len(in.PK)); err == nil {
+			fillComponent(rng, in.PK, mldsa.PublicKeyLayout(params))
+		}
+	}},
+	{"splice-sig", func(rng *rand.Rand, in *Input, seeds []Seed) {
+		in.Sig = clone(seeds[rng.Intn(len(seeds))].Sig)
+	}},
+	{"splice-pk", func(rng *rand.Rand, in *Input, seeds []Seed) {
+		in.PK = clone(seeds[rng.Intn(len(seeds))].PK)
+	}},
+}
+
+func bitflip(rng *rand.Rand, b []byte) {
+	if len(b) > 0 {
+		bit := rng.Intn(8 * len(b))
+		b[bit/8] ^= 1 << (bit % 8)
+	}
+}
+
+func truncate(rng *rand.Rand, b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	return b[:rng.Intn(len(b))]
+}
+
+func extend(rng *rand.Rand, b []byte) []byte {
+	extra := make([]byte, 1+rng.Intn(32))
+	rng.Read(extra)
+	return append(b, extra...)
+}
+
+// fillComponent overwrites one component with random bytes or with one
+// interesting byte repeated.
+func fillComponent(rng *rand.Rand, b []byte, layout []mldsa.Component) {
+	c := layout[rng.Intn(len(layout))]
+	if c.Offset+c.Size > len(b) {
+		return
+	}
+	field := b[c.Offset : c.Offset+c.Size]
+	switch rng.Intn(3) {
+	case 0:
+		rng.Read(field)
+	default:
+		v := interesting[rng.Intn(len(interesting))]
+		for i := range field {
+			field[i] = v
+		}
+	}
+}
+
+func clone(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
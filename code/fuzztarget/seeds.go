@@ -0,0 +1,131 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package fuzztarget
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/corpus"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// Seed sources.
+const (
+	SourceSigVer = "kat-sigver"
+	SourceEdge   = "edge-message"
+	SourceCorpus = "corpus"
+)
+
+// Seed is a verify-internal input the mutators start from.
+type Seed struct {
+	Source string `json:"source"`
+	Label  string `json:"label"`
+	PK     []byte `json:"pk"`
+	Msg    []byte `json:"msg"`
+	Sig    []byte `json:"sig"`
+}
+
+// Seeds collects the seed corpus: the internal-interface cases of the
+// bundled ACVP sigVer vectors, kat.EdgeMsgs signed under every parameter
+// set, and the entries of the Go fuzz corpus in corpusDir. FuzzVerify
+// entries are signed as messages and FuzzDecodePublicKey entries replace
+// the public key of a valid signature. A missing corpusDir is skipped.
+func Seeds(corpusDir string) ([]Seed, error) {
+	var seeds []Seed
+
+	sigVer, err := kats.LoadSigVerVectors("")
+	if err != nil {
+		return nil, err
+	}
+	for _, tg := range sigVer.TestGroups {
+		if tg.ExternalMu || tg.SignatureInterface == kats.InterfaceExternal {
+			continue
+		}
+		for _, tc := range tg.Tests {
+			seeds = append(seeds, Seed{
+				Source: SourceSigVer, Label: fmt.Sprintf("tgId=%d tcId=%d", tg.TargetGroupID, tc.CaseID),
+				PK: unhex(tc.Public), Msg: unhex(tc.Message), Sig: unhex(tc.Signature),
+			})
+		}
+	}
+
+	for i, msg := range kat.EdgeMsgs {
+		for _, params := range parameterSets {
+			s, err := signed(params, msg, byte(i))
+			if err != nil {
+				return nil, err
+			}
+			s.Source, s.Label = SourceEdge, fmt.Sprintf("EdgeMsgs[%d] %s", i, params.Name)
+			seeds = append(seeds, s)
+		}
+	}
+
+	entries, err := corpusSeeds(corpusDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(seeds, entries...), nil
+}
+
+var parameterSets = []*mldsa.Params{mldsa.ParamsMLDSA44, mldsa.ParamsMLDSA65, mldsa.ParamsMLDSA87}
+
+// signed returns a valid signature over msg under a key derived from tag.
+func signed(params *mldsa.Params, msg []byte, tag byte) (Seed, error) {
+	seed := bytes.Repeat([]byte{tag}, mldsa.SeedBytes)
+	pk, sk, err := mldsa.KeyGen(params, seed)
+	if err != nil {
+		return Seed{}, err
+	}
+	sig, err := mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	if err != nil {
+		return Seed{}, err
+	}
+	return Seed{PK: pk, Msg: append([]byte(nil), msg...), Sig: sig}, nil
+}
+
+func corpusSeeds(dir string) ([]Seed, error) {
+	var seeds []Seed
+	for _, target := range corpus.Targets {
+		paths, err := filepath.Glob(filepath.Join(dir, target, "*"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+		for i, path := range paths {
+			values, err := corpus.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) != 1 {
+				return nil, fmt.Errorf("%w: %s: want one []byte value, got %d", corpus.ErrFormat, path, len(values))
+			}
+			params := parameterSets[i%len(parameterSets)]
+			var s Seed
+			if target == corpus.TargetVerify {
+				s, err = signed(params, values[0], byte(i))
+			} else {
+				s, err = signed(params, []byte(target), byte(i))
+				s.PK = values[0]
+			}
+			if err != nil {
+				return nil, err
+			}
+			s.Source, s.Label = SourceCorpus, target+"/"+filepath.Base(path)
+			seeds = append(seeds, s)
+		}
+	}
+	return seeds, nil
+}
+
+// unhex decodes a vector field; the bundled files are well formed.
+func unhex(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
@@ -0,0 +1,162 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package interop
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// Operations use the op names of the kats.Exec protocol, so an Input's
+// Request can be piped straight into an external binary.
+const (
+	OpKeyGen         = "keygen"
+	OpSignInternal   = "sign-internal"
+	OpSignExternal   = "sign-external"
+	OpSignPreHash    = "sign-prehash"
+	OpSignMu         = "sign-mu"
+	OpVerifyInternal = "verify-internal"
+	OpVerifyExternal = "verify-external"
+	OpVerifyPreHash  = "verify-prehash"
+	OpVerifyMu       = "verify-mu"
+)
+
+// Input is one operation fed to every target. Only the fields the
+// operation uses are set.
+type Input struct {
+	Source       string `json:"source"` // kat-keygen, kat-siggen, kat-sigver, mutate or random
+	Label        string `json:"label"`
+	Op           string `json:"op"`
+	ParameterSet string `json:"parameterSet,omitempty"`
+
+	Seed    []byte `json:"seed,omitempty"`
+	PK      []byte `json:"pk,omitempty"`
+	SK      []byte `json:"sk,omitempty"`
+	Msg     []byte `json:"message,omitempty"`
+	Mu      []byte `json:"mu,omitempty"`
+	Ctx     []byte `json:"context,omitempty"`
+	HashAlg string `json:"hashAlg,omitempty"`
+	Rnd     []byte `json:"rnd,omitempty"`
+	Sig     []byte `json:"signature,omitempty"`
+}
+
+// Request renders in as the key=value request kats.Exec would send.
+func (in Input) Request() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "op=%s\n", in.Op)
+	field := func(key string, v []byte) {
+		if v != nil {
+			fmt.Fprintf(&b, "%s=%x\n", key, v)
+		}
+	}
+	if in.Op == OpKeyGen {
+		fmt.Fprintf(&b, "parameterSet=%s\n", in.ParameterSet)
+		field("seed", in.Seed)
+		return b.String()
+	}
+	field("pk", in.PK)
+	field("sk", in.SK)
+	field("message", in.Msg)
+	field("mu", in.Mu)
+	if in.Op == OpSignExternal || in.Op == OpSignPreHash || in.Op == OpVerifyExternal || in.Op == OpVerifyPreHash {
+		fmt.Fprintf(&b, "context=%x\n", in.Ctx)
+	}
+	if in.HashAlg != "" {
+		fmt.Fprintf(&b, "hashAlg=%s\n", in.HashAlg)
+	}
+	field("rnd", in.Rnd)
+	field("signature", in.Sig)
+	return b.String()
+}
+
+// Outcome is what one target returned for an Input. Value is the part that
+// is compared: "valid=true|false" for verification (an error counts as
+// false), digests of the produced bytes for keygen and signing, or "error".
+type Outcome struct {
+	Target      string `json:"target"`
+	Value       string `json:"value"`
+	Err         string `json:"error,omitempty"`
+	Unsupported bool   `json:"unsupported,omitempty"`
+}
+
+// run executes in on impl.
+func (in Input) run(name string, impl kats.Implementation) Outcome {
+	out := Outcome{Target: name}
+	var (
+		valid bool
+		sig   []byte
+		err   error
+	)
+	switch in.Op {
+	case OpKeyGen:
+		var pk, sk []byte
+		if pk, sk, err = impl.KeyGen(in.ParameterSet, in.Seed); err == nil {
+			out.Value = "pk=" + digest(pk) + " sk=" + digest(sk)
+		}
+	case OpSignInternal:
+		sig, err = impl.SignInternal(in.SK, in.Msg, in.Rnd)
+	case OpSignExternal:
+		sig, err = impl.SignExternal(in.SK, in.Msg, in.Ctx, in.Rnd)
+	case OpSignPreHash:
+		sig, err = impl.SignPreHash(in.SK, in.Msg, in.Ctx, in.HashAlg, in.Rnd)
+	case OpSignMu:
+		sig, err = impl.SignExternalMu(in.SK, in.Mu, in.Rnd)
+	case OpVerifyInternal:
+		valid, err = impl.VerifyInternal(in.PK, in.Msg, in.Sig)
+	case OpVerifyExternal:
+		valid, err = impl.VerifyExternal(in.PK, in.Msg, in.Ctx, in.Sig)
+	case OpVerifyPreHash:
+		valid, err = impl.VerifyPreHash(in.PK, in.Msg, in.Ctx, in.HashAlg, in.Sig)
+	case OpVerifyMu:
+		valid, err = impl.VerifyExternalMu(in.PK, in.Mu, in.Sig)
+	default:
+		err = fmt.Errorf("interop: unknown op %q", in.Op)
+	}
+
+	if err != nil {
+		out.Err = err.Error()
+		out.Unsupported = isUnsupported(err)
+	}
+	switch {
+	case strings.HasPrefix(in.Op, "verify-"):
+		out.Value = fmt.Sprintf("valid=%t", valid && err == nil)
+	case err != nil:
+		out.Value = "error"
+	case sig != nil:
+		out.Value = "signature=" + digest(sig)
+	}
+	return out
+}
+
+// digest abbreviates produced bytes: equal digests mean equal output.
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("%d:%s", len(b), hex.EncodeToString(sum[:8]))
+}
+
+// signOp and verifyOp map an ACVP group's interface to the operation.
+func signOp(signatureInterface, preHash string, externalMu bool) string {
+	return "sign-" + route(signatureInterface, preHash, externalMu)
+}
+
+func verifyOp(signatureInterface, preHash string, externalMu bool) string {
+	return "verify-" + route(signatureInterface, preHash, externalMu)
+}
+
+func route(signatureInterface, preHash string, externalMu bool) string {
+	switch {
+	case externalMu:
+		return "mu"
+	case signatureInterface != kats.InterfaceExternal:
+		return "internal"
+	case preHash == kats.PreHashPreHash:
+		return "prehash"
+	default:
+		return "external"
+	}
+}
@@ -0,0 +1,167 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package interop runs differential tests across ML-DSA implementations:
+// the same keygen, sign and verify inputs go to every target, disagreements
+// are reported with a minimized reproducer, and a sign-by-X / verify-by-Y
+// matrix shows which pairs interoperate.
+package interop
+
+import (
+	"errors"
+	"math/rand"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// BuiltinName names the in-process implementation in reports.
+const BuiltinName = "builtin"
+
+// Target is one implementation under comparison.
+type Target struct {
+	Name string
+	Impl kats.Implementation
+}
+
+// Disagreement is an input on which the targets that answered did not all
+// return the same Value. Input is the minimized input; Original is set
+// when minimization changed it.
+type Disagreement struct {
+	Input    Input     `json:"input"`
+	Original *Input    `json:"original,omitempty"`
+	Outcomes []Outcome `json:"outcomes"`
+
+	// Reproducer is Input as a kats.Exec request, ready to pipe into a binary.
+	Reproducer string `json:"reproducer"`
+}
+
+// Report is the result of a differential run.
+type Report struct {
+	Targets       []string       `json:"targets"`
+	Inputs        int            `json:"inputs"`
+	Compared      int            `json:"compared"` // inputs answered by at least two targets
+	Disagreements []Disagreement `json:"disagreements"`
+	Matrices      []Matrix       `json:"matrices,omitempty"`
+}
+
+// Failed reports whether the run found a disagreement or a matrix pair
+// that does not interoperate.
+func (r *Report) Failed() bool {
+	if len(r.Disagreements) > 0 {
+		return true
+	}
+	for _, m := range r.Matrices {
+		if m.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// matrixMessage is the message every matrix signer signs.
+var matrixMessage = []byte("DiliVet interoperability matrix")
+
+// Run builds the inputs opts selects, compares targets on them and adds a
+// matrix per parameter set and interface, keyed from opts.Seed.
+func Run(targets []Target, opts Options) (*Report, error) {
+	inputs, err := Inputs(opts)
+	if err != nil {
+		return nil, err
+	}
+	r := Compare(targets, inputs)
+	seed := make([]byte, mldsa.SeedBytes)
+	rand.New(rand.NewSource(opts.Seed)).Read(seed)
+	for _, params := range ParameterSets {
+		for _, iface := range MatrixInterfaces {
+			r.Matrices = append(r.Matrices, BuildMatrix(targets, params, iface, seed, matrixMessage))
+		}
+	}
+	return r, nil
+}
+
+// maxShrinkSteps bounds the extra calls spent minimizing one disagreement.
+const maxShrinkSteps = 48
+
+// Compare feeds every input to every target and collects disagreements.
+// Targets that report kats.ErrUnsupported for an input are left out of its
+// comparison.
+func Compare(targets []Target, inputs []Input) *Report {
+	r := &Report{Inputs: len(inputs), Disagreements: []Disagreement{}}
+	for _, t := range targets {
+		r.Targets = append(r.Targets, t.Name)
+	}
+	for _, in := range inputs {
+		outcomes, answered, agree := evaluate(targets, in)
+		if answered >= 2 {
+			r.Compared++
+		}
+		if agree {
+			continue
+		}
+		d := Disagreement{Input: in, Outcomes: outcomes}
+		if small, smallOutcomes, ok := shrink(targets, in); ok {
+			original := in
+			d.Input, d.Original, d.Outcomes = small, &original, smallOutcomes
+		}
+		d.Reproducer = d.Input.Request()
+		r.Disagreements = append(r.Disagreements, d)
+	}
+	return r
+}
+
+// evaluate runs in on every target. agree is false when two supported
+// outcomes differ.
+func evaluate(targets []Target, in Input) (outcomes []Outcome, answered int, agree bool) {
+	agree = true
+	first := ""
+	for _, t := range targets {
+		o := in.run(t.Name, t.Impl)
+		outcomes = append(outcomes, o)
+		if o.Unsupported {
+			continue
+		}
+		answered++
+		if answered == 1 {
+			first = o.Value
+		} else if o.Value != first {
+			agree = false
+		}
+	}
+	return outcomes, answered, agree
+}
+
+// shrink looks for a shorter message that still splits the targets, first
+// halving it and then dropping one trailing byte at a time. Only inputs
+// that carry a message are shrunk, and messages stay non-empty.
+func shrink(targets []Target, in Input) (Input, []Outcome, bool) {
+	if len(in.Msg) <= 1 {
+		return in, nil, false
+	}
+	best, shrunk := in, false
+	var bestOutcomes []Outcome
+	for steps := 0; steps < maxShrinkSteps && len(best.Msg) > 1; {
+		progressed := false
+		for _, n := range []int{len(best.Msg) / 2, len(best.Msg) - 1} {
+			if n < 1 || n >= len(best.Msg) {
+				continue
+			}
+			candidate := best
+			candidate.Msg = best.Msg[:n]
+			steps++
+			outcomes, _, agree := evaluate(targets, candidate)
+			if !agree {
+				best, bestOutcomes, shrunk, progressed = candidate, outcomes, true, true
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return best, bestOutcomes, shrunk
+}
+
+func isUnsupported(err error) bool {
+	return errors.Is(err, kats.ErrUnsupported) || errors.Is(err, mldsa.ErrUnsupportedHash)
+}
@@ -0,0 +1,154 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package interop
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// lenient accepts signatures whose only defect is a malformed hint.
+type lenient struct{ kats.Builtin }
+
+func (lenient) VerifyInternal(pk, msg, sig []byte) (bool, error) {
+	ok, err := mldsa.Verify(pk, msg, sig)
+	if errors.Is(err, mldsa.ErrMalformedHint) {
+		return true, nil
+	}
+	return ok, err
+}
+
+// longMessages corrupts signatures over messages longer than 8 bytes.
+type longMessages struct{ kats.Builtin }
+
+func (longMessages) SignInternal(sk, msg, rnd []byte) ([]byte, error) {
+	sig, err := mldsa.Sign(sk, msg, rnd)
+	if err == nil && len(msg) > 8 {
+		sig[0] ^= 1
+	}
+	return sig, err
+}
+
+// verifyOnly implements verification and nothing else.
+type verifyOnly struct{ kats.Builtin }
+
+func (verifyOnly) KeyGen(string, []byte) ([]byte, []byte, error) {
+	return nil, nil, kats.ErrUnsupported
+}
+
+func (verifyOnly) SignInternal([]byte, []byte, []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+
+// externalOnly implements only the external ML-DSA.Sign and ML-DSA.Verify,
+// the usual shape of a vendor library.
+type externalOnly struct{ kats.Builtin }
+
+func (externalOnly) SignInternal([]byte, []byte, []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+
+func (externalOnly) VerifyInternal([]byte, []byte, []byte) (bool, error) {
+	return false, kats.ErrUnsupported
+}
+
+func TestRunAgreeingTargets(t *testing.T) {
+	targets := []Target{{BuiltinName, kats.Builtin{}}, {"copy", kats.Builtin{}}, {"verify-only", verifyOnly{}}}
+	r, err := Run(targets, Options{KATs: true, Mutations: true, Random: 1, PerGroup: 1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Failed() || len(r.Disagreements) != 0 {
+		t.Fatalf("unexpected disagreements: %+v", r.Disagreements)
+	}
+	if r.Compared != r.Inputs {
+		t.Errorf("compared %d of %d inputs", r.Compared, r.Inputs)
+	}
+	if len(r.Matrices) != len(ParameterSets)*len(MatrixInterfaces) {
+		t.Fatalf("got %d matrices", len(r.Matrices))
+	}
+	cells := r.Matrices[0].Cells
+	if cells[0][1].Status != CellOK || cells[2][0].Status != CellNoSignature {
+		t.Errorf("unexpected cells: %+v", cells)
+	}
+}
+
+func TestCompareFindsLenientVerifier(t *testing.T) {
+	inputs, err := Inputs(Options{Mutations: true, Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Compare([]Target{{BuiltinName, kats.Builtin{}}, {"lenient", lenient{}}}, inputs)
+	if len(r.Disagreements) == 0 {
+		t.Fatal("lenient hint decoding went unnoticed")
+	}
+	for _, d := range r.Disagreements {
+		// Zero-extended hints of the swapped parameter set may be malformed too.
+		if !strings.HasPrefix(d.Input.Label, "hint-") && d.Input.Label != "paramset-swapped" {
+			t.Errorf("unexpected disagreement on %s", d.Input.Label)
+		}
+		if d.Outcomes[1].Value != "valid=true" {
+			t.Errorf("%s: lenient outcome %+v", d.Input.Label, d.Outcomes[1])
+		}
+	}
+}
+
+func TestCompareShrinksMessage(t *testing.T) {
+	inputs, err := Inputs(Options{Random: 1, Seed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Compare([]Target{{BuiltinName, kats.Builtin{}}, {"long", longMessages{}}}, inputs)
+
+	var found bool
+	for _, d := range r.Disagreements {
+		if d.Input.Op != OpSignInternal {
+			continue
+		}
+		found = true
+		if len(d.Input.Msg) != 9 || d.Original == nil {
+			t.Errorf("message shrunk to %d bytes, want 9", len(d.Input.Msg))
+		}
+		if !strings.HasPrefix(d.Reproducer, "op=sign-internal\n") || !strings.Contains(d.Reproducer, "rnd=") {
+			t.Errorf("reproducer %q", d.Reproducer)
+		}
+	}
+	if !found {
+		t.Fatal("no sign-internal disagreement")
+	}
+}
+
+func TestBuildMatrixReportsRejection(t *testing.T) {
+	targets := []Target{{BuiltinName, kats.Builtin{}}, {"long", longMessages{}}}
+	m := BuildMatrix(targets, mldsa.ParamsMLDSA44, InterfaceInternal, make([]byte, mldsa.SeedBytes), matrixMessage)
+	if !m.Failed() {
+		t.Fatal("matrix did not fail")
+	}
+	if m.Cells[0][1].Status != CellOK || m.Cells[1][0].Status != CellReject {
+		t.Errorf("unexpected cells: %+v", m.Cells)
+	}
+}
+
+func TestBuildMatrixExternalInterface(t *testing.T) {
+	targets := []Target{{BuiltinName, kats.Builtin{}}, {"vendor", externalOnly{}}}
+	seed := make([]byte, mldsa.SeedBytes)
+	internal := BuildMatrix(targets, mldsa.ParamsMLDSA44, InterfaceInternal, seed, matrixMessage)
+	if internal.Failed() || internal.Cells[0][1].Status != CellUnsupported || internal.Cells[1][0].Status != CellNoSignature {
+		t.Errorf("internal cells: %+v", internal.Cells)
+	}
+	for _, iface := range []string{InterfaceExternal, InterfaceExternalCtx} {
+		m := BuildMatrix(targets, mldsa.ParamsMLDSA44, iface, seed, matrixMessage)
+		for i, row := range m.Cells {
+			for j, c := range row {
+				if c.Status != CellOK {
+					t.Errorf("%s: %s -> %s: %+v", iface, m.Signers[i], m.Verifiers[j], c)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package interop
+
+import (
+	"fmt"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+)
+
+// Matrix cell states.
+const (
+	CellOK          = "ok"           // Y accepted X's signature
+	CellReject      = "reject"       // Y returned false
+	CellError       = "error"        // Y failed with an error
+	CellUnsupported = "unsupported"  // Y does not verify this interface
+	CellNoSignature = "no-signature" // X could not produce a key pair or signature
+)
+
+// Matrix interfaces. Vendor libraries usually expose only the external
+// ML-DSA.Sign and ML-DSA.Verify (FIPS 204 Algorithms 2 and 3), so those are
+// exercised with an empty and a non-empty context next to the internal
+// functions.
+const (
+	InterfaceInternal    = "internal"     // Sign_internal / Verify_internal
+	InterfaceExternal    = "external"     // ML-DSA.Sign / ML-DSA.Verify, empty context
+	InterfaceExternalCtx = "external-ctx" // ML-DSA.Sign / ML-DSA.Verify with matrixContext
+)
+
+// MatrixInterfaces lists the interfaces Run builds a matrix for.
+var MatrixInterfaces = []string{InterfaceInternal, InterfaceExternal, InterfaceExternalCtx}
+
+// matrixContext is the context string of the external-ctx matrix.
+var matrixContext = []byte("DiliVet interop")
+
+// Matrix records, for one parameter set and interface, whether the
+// signature made by Signers[i] verifies under Verifiers[j].
+type Matrix struct {
+	ParameterSet string   `json:"parameterSet"`
+	Interface    string   `json:"interface"`
+	Signers      []string `json:"signers"`
+	Verifiers    []string `json:"verifiers"`
+	Cells        [][]Cell `json:"cells"` // [signer][verifier]
+}
+
+// Cell is one signer/verifier pair.
+type Cell struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Failed reports whether a produced signature was rejected or errored. A
+// signer that cannot sign and unsupported verification are not failures.
+func (m Matrix) Failed() bool {
+	for _, row := range m.Cells {
+		for _, c := range row {
+			if c.Status == CellReject || c.Status == CellError {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BuildMatrix has every target derive a key pair from seed, sign msg
+// through iface with zero rnd, and every target verify the result through
+// the same interface with the signer's public key. Targets that do not
+// implement iface show up as no-signature rows and unsupported cells.
+func BuildMatrix(targets []Target, params *mldsa.Params, iface string, seed, msg []byte) Matrix {
+	m := Matrix{ParameterSet: params.Name, Interface: iface}
+	for _, t := range targets {
+		m.Signers = append(m.Signers, t.Name)
+		m.Verifiers = append(m.Verifiers, t.Name)
+	}
+	for _, signer := range targets {
+		row := make([]Cell, len(targets))
+		pk, sig, err := signWith(signer, params, iface, seed, msg)
+		for j, verifier := range targets {
+			if err != nil {
+				row[j] = Cell{Status: CellNoSignature, Detail: err.Error()}
+				continue
+			}
+			ok, verr := verifyWith(verifier, iface, pk, msg, sig)
+			switch {
+			case verr != nil && isUnsupported(verr):
+				row[j] = Cell{Status: CellUnsupported, Detail: verr.Error()}
+			case verr != nil:
+				row[j] = Cell{Status: CellError, Detail: verr.Error()}
+			case !ok:
+				row[j] = Cell{Status: CellReject}
+			default:
+				row[j] = Cell{Status: CellOK}
+			}
+		}
+		m.Cells = append(m.Cells, row)
+	}
+	return m
+}
+
+func signWith(t Target, params *mldsa.Params, iface string, seed, msg []byte) (pk, sig []byte, err error) {
+	pk, sk, err := t.Impl.KeyGen(params.Name, seed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("keygen: %w", err)
+	}
+	rnd := make([]byte, mldsa.RndBytes)
+	switch iface {
+	case InterfaceInternal:
+		sig, err = t.Impl.SignInternal(sk, msg, rnd)
+	case InterfaceExternal:
+		sig, err = t.Impl.SignExternal(sk, msg, nil, rnd)
+	case InterfaceExternalCtx:
+		sig, err = t.Impl.SignExternal(sk, msg, matrixContext, rnd)
+	default:
+		err = fmt.Errorf("interface %q: %w", iface, kats.ErrUnsupported)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign: %w", err)
+	}
+	return pk, sig, nil
+}
+
+func verifyWith(t Target, iface string, pk, msg, sig []byte) (bool, error) {
+	switch iface {
+	case InterfaceInternal:
+		return t.Impl.VerifyInternal(pk, msg, sig)
+	case InterfaceExternal:
+		return t.Impl.VerifyExternal(pk, msg, nil, sig)
+	case InterfaceExternalCtx:
+		return t.Impl.VerifyExternal(pk, msg, matrixContext, sig)
+	}
+	return false, fmt.Errorf("interface %q: %w", iface, kats.ErrUnsupported)
+}
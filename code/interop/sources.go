@@ -0,0 +1,216 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package interop
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/mutate"
+)
+
+// Input sources.
+const (
+	SourceKeyGen = "kat-keygen"
+	SourceSigGen = "kat-siggen"
+	SourceSigVer = "kat-sigver"
+	SourceMutate = "mutate"
+	SourceRandom = "random"
+)
+
+// Options selects the inputs of a differential run.
+type Options struct {
+	KATs      bool  // bundled ACVP keyGen, sigGen and sigVer vectors
+	Mutations bool  // mutate.Generate cases for one signature per parameter set
+	Random    int   // random keygen/sign/verify inputs per parameter set
+	PerGroup  int   // cap on KAT cases taken from each test group; 0 takes all
+	Seed      int64 // seeds the random inputs, so runs are reproducible
+}
+
+// ParameterSets are the sets random, mutation and matrix inputs cover.
+var ParameterSets = []*mldsa.Params{mldsa.ParamsMLDSA44, mldsa.ParamsMLDSA65, mldsa.ParamsMLDSA87}
+
+// Inputs builds the inputs opts asks for. Keys and signatures for the
+// mutation and random sources come from the built-in implementation.
+func Inputs(opts Options) ([]Input, error) {
+	var inputs []Input
+	if opts.KATs {
+		kat, err := katInputs(opts.PerGroup)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, kat...)
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	if opts.Mutations {
+		for _, params := range ParameterSets {
+			in, err := mutationInputs(params, rng)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, in...)
+		}
+	}
+	for _, params := range ParameterSets {
+		for i := 0; i < opts.Random; i++ {
+			in, err := randomInputs(params, rng, i)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, in...)
+		}
+	}
+	return inputs, nil
+}
+
+func katInputs(perGroup int) ([]Input, error) {
+	var inputs []Input
+	take := func(n int) int {
+		if perGroup > 0 && n > perGroup {
+			return perGroup
+		}
+		return n
+	}
+	label := func(tgID, tcID int) string { return fmt.Sprintf("tgId=%d tcId=%d", tgID, tcID) }
+
+	keyGen, err := kats.LoadKeyGenVectors("")
+	if err != nil {
+		return nil, err
+	}
+	for _, tg := range keyGen.TestGroups {
+		for _, tc := range tg.Tests[:take(len(tg.Tests))] {
+			inputs = append(inputs, Input{
+				Source: SourceKeyGen, Label: label(tg.TargetGroupID, tc.CaseID), Op: OpKeyGen,
+				ParameterSet: tg.ParameterSet, Seed: unhex(tc.Seed),
+			})
+		}
+	}
+
+	sigGen, err := kats.LoadSigGenVectors("")
+	if err != nil {
+		return nil, err
+	}
+	for _, tg := range sigGen.TestGroups {
+		for _, tc := range tg.Tests[:take(len(tg.Tests))] {
+			rnd := unhex(tc.Rnd)
+			switch {
+			case tg.Deterministic:
+				rnd = make([]byte, mldsa.RndBytes)
+			case rnd == nil:
+				continue // hedged without a published rnd: outputs cannot agree
+			}
+			in := Input{
+				Source: SourceSigGen, Label: label(tg.TargetGroupID, tc.CaseID),
+				Op:           signOp(tg.SignatureInterface, tg.PreHash, tg.ExternalMu),
+				ParameterSet: tg.ParameterSet, SK: unhex(tc.Secret), Rnd: rnd,
+				Ctx: unhex(tc.Context), HashAlg: tc.HashAlg,
+			}
+			if in.Op == OpSignMu {
+				in.Mu = unhex(tc.Mu)
+			} else {
+				in.Msg = unhex(tc.Message)
+			}
+			inputs = append(inputs, in)
+		}
+	}
+
+	sigVer, err := kats.LoadSigVerVectors("")
+	if err != nil {
+		return nil, err
+	}
+	for _, tg := range sigVer.TestGroups {
+		for _, tc := range tg.Tests[:take(len(tg.Tests))] {
+			pk := tc.Public
+			if pk == "" {
+				pk = tg.Public
+			}
+			in := Input{
+				Source: SourceSigVer, Label: label(tg.TargetGroupID, tc.CaseID),
+				Op:           verifyOp(tg.SignatureInterface, tg.PreHash, tg.ExternalMu),
+				ParameterSet: tg.ParameterSet, PK: unhex(pk), Sig: unhex(tc.Signature),
+				Ctx: unhex(tc.Context), HashAlg: tc.HashAlg,
+			}
+			if in.Op == OpVerifyMu {
+				in.Mu = unhex(tc.Mu)
+			} else {
+				in.Msg = unhex(tc.Message)
+			}
+			inputs = append(inputs, in)
+		}
+	}
+	return inputs, nil
+}
+
+// mutationInputs signs a random message with a random key and turns every
+// mutate case into a verify-internal input.
+func mutationInputs(params *mldsa.Params, rng *rand.Rand) ([]Input, error) {
+	pk, sk, err := mldsa.KeyGen(params, randBytes(rng, mldsa.SeedBytes))
+	if err != nil {
+		return nil, err
+	}
+	msg := randBytes(rng, 1+rng.Intn(64))
+	sig, err := mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	if err != nil {
+		return nil, err
+	}
+	cases, err := mutate.Generate(pk, msg, sig, mldsa.Verify)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]Input, 0, len(cases))
+	for _, c := range cases {
+		inputs = append(inputs, Input{
+			Source: SourceMutate, Label: c.Name, Op: OpVerifyInternal,
+			ParameterSet: c.ParameterSet, PK: c.PK, Msg: msg, Sig: c.Sig,
+		})
+	}
+	return inputs, nil
+}
+
+// randomInputs draws one key seed, message and rnd, and yields a keygen, a
+// hedged sign-internal, a verify of the resulting signature and a verify of
+// the same signature with one bit flipped.
+func randomInputs(params *mldsa.Params, rng *rand.Rand, n int) ([]Input, error) {
+	seed := randBytes(rng, mldsa.SeedBytes)
+	pk, sk, err := mldsa.KeyGen(params, seed)
+	if err != nil {
+		return nil, err
+	}
+	msg := randBytes(rng, 1+rng.Intn(256))
+	rnd := randBytes(rng, mldsa.RndBytes)
+	sig, err := mldsa.Sign(sk, msg, rnd)
+	if err != nil {
+		return nil, err
+	}
+	flipped := append([]byte(nil), sig...)
+	bit := rng.Intn(8 * len(sig))
+	flipped[bit/8] ^= 1 << (bit % 8)
+
+	label := fmt.Sprintf("#%d", n)
+	return []Input{
+		{Source: SourceRandom, Label: label, Op: OpKeyGen, ParameterSet: params.Name, Seed: seed},
+		{Source: SourceRandom, Label: label, Op: OpSignInternal, ParameterSet: params.Name, SK: sk, Msg: msg, Rnd: rnd},
+		{Source: SourceRandom, Label: label, Op: OpVerifyInternal, ParameterSet: params.Name, PK: pk, Msg: msg, Sig: sig},
+		{Source: SourceRandom, Label: label + fmt.Sprintf(" bit %d flipped", bit), Op: OpVerifyInternal, ParameterSet: params.Name, PK: pk, Msg: msg, Sig: flipped},
+	}, nil
+}
+
+func randBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rng.Read(b)
+	return b
+}
+
+// unhex decodes a vector field; the bundled files are well formed and an
+// empty field yields nil.
+func unhex(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	b, _ := hex.DecodeString(s)
+	return b
+}
@@ -0,0 +1,84 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kat
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+)
+
+// DRBGSeedBytes is the entropy length taken by NewDRBG (the .rsp seed field).
+const DRBGSeedBytes = 48
+
+// ErrDRBGSeed is returned for entropy inputs that are not 48 bytes long.
+var ErrDRBGSeed = errors.New("kat: DRBG seed must be 48 bytes")
+
+// DRBG is the AES-256-CTR generator behind randombytes() in the NIST PQC
+// submission package (rng.c), which the KAT generators seed from each
+// case's seed field.
+//
+// Each Read corresponds to one randombytes call: output blocks are drawn and
+// the state is then updated, so Read(a) followed by Read(b) differs from a
+// single Read(a+b). Callers must request exactly what the reference code does.
+type DRBG struct {
+	key [32]byte
+	v   [16]byte
+}
+
+// NewDRBG implements randombytes_init(entropy, NULL, 256).
+func NewDRBG(entropy []byte) (*DRBG, error) {
+	if len(entropy) != DRBGSeedBytes {
+		return nil, ErrDRBGSeed
+	}
+	d := &DRBG{}
+	d.update(entropy)
+	return d, nil
+}
+
+// Read implements io.Reader as randombytes(p, len(p)). It never fails.
+func (d *DRBG) Read(p []byte) (int, error) {
+	block := d.cipher()
+	var buf [aes.BlockSize]byte
+	for off := 0; off < len(p); off += aes.BlockSize {
+		d.incV()
+		block.Encrypt(buf[:], d.v[:])
+		copy(p[off:], buf[:])
+	}
+	d.update(nil)
+	return len(p), nil
+}
+
+// update is AES256_CTR_DRBG_Update; provided may be nil or 48 bytes.
+func (d *DRBG) update(provided []byte) {
+	block := d.cipher()
+	var temp [48]byte
+	for i := 0; i < 3; i++ {
+		d.incV()
+		block.Encrypt(temp[16*i:], d.v[:])
+	}
+	for i := range provided {
+		temp[i] ^= provided[i]
+	}
+	copy(d.key[:], temp[:32])
+	copy(d.v[:], temp[32:])
+}
+
+// incV increments V as a big-endian 128-bit counter.
+func (d *DRBG) incV() {
+	for i := len(d.v) - 1; i >= 0; i-- {
+		d.v[i]++
+		if d.v[i] != 0 {
+			return
+		}
+	}
+}
+
+func (d *DRBG) cipher() cipher.Block {
+	block, err := aes.NewCipher(d.key[:])
+	if err != nil {
+		panic(err) // unreachable: the key is always 32 bytes
+	}
+	return block
+}
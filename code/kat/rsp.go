@@ -0,0 +1,334 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// Errors reported by the .rsp loader and checker.
+var (
+	ErrRSPFormat   = errors.New("kat: malformed .rsp file")
+	ErrRSPMismatch = errors.New("kat: .rsp mismatch")
+	ErrRSPRound3   = errors.New("kat: Round-3 Dilithium .rsp files are not supported")
+)
+
+// round3SKBytes maps the secret key sizes of the Round-3 Dilithium
+// submission to its parameter sets. Round 3 kept a 32-byte tr where FIPS
+// 204 keeps 64 bytes, so its public keys have the ML-DSA sizes but its
+// secret keys are 32 bytes shorter.
+var round3SKBytes = map[int]struct {
+	scheme string
+	pk     int
+}{
+	2528: {"Dilithium2", 1312},
+	4000: {"Dilithium3", 1952},
+	4864: {"Dilithium5", 2592},
+}
+
+// Round3Scheme reports the Round-3 Dilithium parameter set with pkLen-byte
+// public and skLen-byte secret keys, if there is one.
+func Round3Scheme(pkLen, skLen int) (string, bool) {
+	r, ok := round3SKBytes[skLen]
+	if !ok || r.pk != pkLen {
+		return "", false
+	}
+	return r.scheme, true
+}
+
+// rspFields lists the fields of a NIST PQC signature KAT case in the order
+// PQCgenKAT_sign writes them.
+var rspFields = []string{"count", "seed", "mlen", "msg", "pk", "sk", "smlen", "sm"}
+
+// RSPFile is a parsed NIST PQC signature KAT response file.
+type RSPFile struct {
+	// Scheme is the name from the leading "# name" line, e.g. "Dilithium2".
+	Scheme string
+	Cases  []RSPCase
+}
+
+// RSPCase is one count block of a .rsp file. SM is the signed message
+// sig || msg as produced by crypto_sign.
+type RSPCase struct {
+	Line  int // line of the count field
+	Count int
+	Seed  []byte
+	MLen  int
+	Msg   []byte
+	PK    []byte
+	SK    []byte
+	SMLen int
+	SM    []byte
+}
+
+// Signature returns the signature part of SM.
+func (c *RSPCase) Signature() []byte {
+	return c.SM[:len(c.SM)-len(c.Msg)]
+}
+
+// RSPError locates a format error in a .rsp file.
+type RSPError struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e *RSPError) Error() string {
+	return fmt.Sprintf("kat: %s:%d: %s", e.Path, e.Line, e.Msg)
+}
+
+// Unwrap lets callers match ErrRSPFormat.
+func (e *RSPError) Unwrap() error { return ErrRSPFormat }
+
+// LoadRSP reads a NIST PQC signature .rsp file. Unlike Load it is strict:
+// every case must carry count, seed, mlen, msg, pk, sk, smlen and sm in that
+// order, lengths must agree, counts must be consecutive and sm must end with
+// msg. Errors are *RSPError values carrying the offending line.
+func LoadRSP(path string) (*RSPFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("kat: open %q: %w", path, err)
+	}
+	defer f.Close()
+	return ParseRSP(f, path)
+}
+
+// ParseRSP parses .rsp content from r; name is used in error messages.
+func ParseRSP(r io.Reader, name string) (*RSPFile, error) {
+	var (
+		file    RSPFile
+		current *RSPCase
+		next    int // index into rspFields expected next
+		lineNo  int
+	)
+	fail := func(line int, format string, args ...any) error {
+		return &RSPError{Path: name, Line: line, Msg: fmt.Sprintf(format, args...)}
+	}
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		if next < len(rspFields) {
+			return fail(current.Line, "count = %d: missing %s", current.Count, rspFields[next])
+		}
+		if current.SMLen != len(current.SM) {
+			return fail(current.Line, "count = %d: smlen = %d but sm has %d bytes", current.Count, current.SMLen, len(current.SM))
+		}
+		if !bytes.HasSuffix(current.SM, current.Msg) {
+			return fail(current.Line, "count = %d: sm does not end with msg", current.Count)
+		}
+		file.Cases = append(file.Cases, *current)
+		current, next = nil, 0
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			continue
+		case strings.HasPrefix(line, "#"):
+			if current != nil || len(file.Cases) > 0 || file.Scheme != "" {
+				return nil, fail(lineNo, "comment outside the file header")
+			}
+			file.Scheme = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fail(lineNo, "expected \"name = value\", got %q", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if current == nil {
+			if key != "count" {
+				return nil, fail(lineNo, "case must start with count, got %q", key)
+			}
+			current = &RSPCase{Line: lineNo}
+		}
+		if next >= len(rspFields) || key != rspFields[next] {
+			want := "a blank line"
+			if next < len(rspFields) {
+				want = rspFields[next]
+			}
+			return nil, fail(lineNo, "unexpected field %q, want %s", key, want)
+		}
+		next++
+
+		var err error
+		switch key {
+		case "count":
+			current.Count, err = strconv.Atoi(value)
+			if err == nil && len(file.Cases) > 0 && current.Count != file.Cases[len(file.Cases)-1].Count+1 {
+				err = fmt.Errorf("not consecutive with count = %d", file.Cases[len(file.Cases)-1].Count)
+			}
+		case "mlen":
+			current.MLen, err = strconv.Atoi(value)
+		case "smlen":
+			current.SMLen, err = strconv.Atoi(value)
+		case "seed":
+			if current.Seed, err = hex.DecodeString(value); err == nil && len(current.Seed) != DRBGSeedBytes {
+				err = fmt.Errorf("%d bytes, want %d", len(current.Seed), DRBGSeedBytes)
+			}
+		case "msg":
+			if current.Msg, err = hex.DecodeString(value); err == nil && len(current.Msg) != current.MLen {
+				err = fmt.Errorf("%d bytes but mlen = %d", len(current.Msg), current.MLen)
+			}
+		case "pk":
+			current.PK, err = decodeRSPHex(value)
+		case "sk":
+			current.SK, err = decodeRSPHex(value)
+		case "sm":
+			current.SM, err = decodeRSPHex(value)
+		}
+		if err != nil {
+			return nil, fail(lineNo, "%s: %v", key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("kat: scan %q: %w", name, err)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	if len(file.Cases) == 0 {
+		return nil, fail(lineNo, "no test cases")
+	}
+	return &file, nil
+}
+
+// decodeRSPHex decodes a mandatory, non-empty hex field.
+func decodeRSPHex(value string) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("empty value")
+	}
+	return hex.DecodeString(value)
+}
+
+// RSPKeyGenFunc derives a key pair, drawing its randomness from rng the way
+// the reference crypto_sign_keypair calls randombytes.
+type RSPKeyGenFunc func(rng io.Reader) (pk, sk []byte, err error)
+
+// RSPSignFunc produces the signature part of crypto_sign(msg, sk). rng
+// continues the DRBG stream after key generation, for randomized signing.
+type RSPSignFunc func(sk, msg []byte, rng io.Reader) (sig []byte, err error)
+
+// RSPResult is the outcome of one .rsp case; Err is nil on success and
+// RuleID names the diag rule a failure breaks.
+type RSPResult struct {
+	Count  int
+	Line   int
+	Err    error
+	RuleID string
+}
+
+// RSPDiagCases returns results for diag.WriteJUnit and diag.WriteSARIF
+// under one suite, with each case named after its count.
+func RSPDiagCases(suite string, results []RSPResult) []diag.Case {
+	cases := make([]diag.Case, 0, len(results))
+	for _, r := range results {
+		c := diag.Case{Suite: suite, Name: fmt.Sprintf("count=%d", r.Count), Status: diag.StatusPass}
+		if r.Err != nil {
+			c.Status, c.Message, c.RuleID = diag.StatusFail, fmt.Sprintf("line %d: %v", r.Line, r.Err), r.RuleID
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// RSPFindings aggregates the failed results by rule.
+func RSPFindings(results []RSPResult) []diag.Finding {
+	findings := []diag.Finding{}
+	for _, r := range results {
+		if r.Err != nil {
+			findings = diag.AddFinding(findings, r.RuleID, fmt.Sprintf("count=%d (line %d): %v", r.Count, r.Line, r.Err))
+		}
+	}
+	diag.SortFindings(findings)
+	return findings
+}
+
+// CheckRSP replays every case: it seeds a DRBG from the seed field, derives
+// the key pair and, when sign is non-nil, signs msg with the derived secret
+// key, comparing pk, sk and sm with the file.
+func CheckRSP(file *RSPFile, keygen RSPKeyGenFunc, sign RSPSignFunc) []RSPResult {
+	results := make([]RSPResult, 0, len(file.Cases))
+	for i := range file.Cases {
+		c := &file.Cases[i]
+		rule, err := checkRSPCase(c, keygen, sign)
+		results = append(results, RSPResult{Count: c.Count, Line: c.Line, Err: err, RuleID: rule})
+	}
+	return results
+}
+
+// checkRSPCase returns the case's error and the rule it breaks.
+func checkRSPCase(c *RSPCase, keygen RSPKeyGenFunc, sign RSPSignFunc) (string, error) {
+	rng, err := NewDRBG(c.Seed)
+	if err != nil {
+		return diag.RuleVectorDecode, err
+	}
+	pk, sk, err := keygen(rng)
+	if err != nil {
+		return diag.RuleKeyGenError, fmt.Errorf("keygen: %w", err)
+	}
+	if err := compareRSP("pk", pk, c.PK); err != nil {
+		return diag.RuleKeyGenMismatch, err
+	}
+	if err := compareRSP("sk", sk, c.SK); err != nil {
+		return diag.RuleKeyGenMismatch, err
+	}
+	if sign == nil {
+		return "", nil
+	}
+	sig, err := sign(sk, c.Msg, rng)
+	if err != nil {
+		return diag.RuleSigGenError, fmt.Errorf("sign: %w", err)
+	}
+	if err := compareRSP("sm", append(sig, c.Msg...), c.SM); err != nil {
+		return diag.RuleSigGenMismatch, err
+	}
+	return "", nil
+}
+
+func compareRSP(field string, got, want []byte) error {
+	if len(got) != len(want) {
+		return fmt.Errorf("%w: %s is %d bytes, want %d", ErrRSPMismatch, field, len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return fmt.Errorf("%w: %s differs at byte %d", ErrRSPMismatch, field, i)
+		}
+	}
+	return nil
+}
+
+// WriteTo writes f in the layout PQCgenKAT_sign produces, so a regenerated
+// file can be compared (or hashed) against a published one.
+func (f *RSPFile) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if f.Scheme != "" {
+		fmt.Fprintf(&buf, "# %s\n\n", f.Scheme)
+	}
+	for _, c := range f.Cases {
+		fmt.Fprintf(&buf, "count = %d\nseed = %X\nmlen = %d\nmsg = %X\n", c.Count, c.Seed, c.MLen, c.Msg)
+		fmt.Fprintf(&buf, "pk = %X\nsk = %X\nsmlen = %d\nsm = %X\n\n", c.PK, c.SK, c.SMLen, c.SM)
+	}
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,118 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kat_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// The first seed of every NIST PQC KAT file: randombytes(48) after
+// randombytes_init with entropy 00 01 .. 2F.
+func TestDRBGFirstSeed(t *testing.T) {
+	entropy := make([]byte, kat.DRBGSeedBytes)
+	for i := range entropy {
+		entropy[i] = byte(i)
+	}
+	d, err := kat.NewDRBG(entropy)
+	if err != nil {
+		t.Fatalf("NewDRBG: %v", err)
+	}
+	seed := make([]byte, 48)
+	_, _ = d.Read(seed)
+	want := "061550234d158c5ec95595fe04ef7a25767f2e24cc2bc479d09d86dc9abcfde7056a8c266f9ef97ed08541dbd2e1ffa1"
+	if got := hex.EncodeToString(seed); got != want {
+		t.Fatalf("seed = %s, want %s", got, want)
+	}
+
+	if _, err := kat.NewDRBG(entropy[:32]); !errors.Is(err, kat.ErrDRBGSeed) {
+		t.Fatalf("short entropy: err = %v, want ErrDRBGSeed", err)
+	}
+}
+
+func TestLoadRSPBundled(t *testing.T) {
+	path := "../clean/testdata/mldsa_kat.rsp"
+	file, err := kat.LoadRSP(path)
+	if err != nil {
+		t.Fatalf("LoadRSP: %v", err)
+	}
+	if file.Scheme != "Dilithium2" || len(file.Cases) == 0 {
+		t.Fatalf("scheme %q with %d cases", file.Scheme, len(file.Cases))
+	}
+	c := file.Cases[0]
+	if c.Count != 0 || c.Line != 3 || c.MLen != 33 || len(c.Signature()) != 2420 {
+		t.Fatalf("first case = count %d line %d mlen %d siglen %d", c.Count, c.Line, c.MLen, len(c.Signature()))
+	}
+
+	// Writing the file back reproduces it byte for byte.
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := file.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatal("WriteTo output differs from the loaded file")
+	}
+}
+
+func TestParseRSPReportsLines(t *testing.T) {
+	seed := strings.Repeat("00", kat.DRBGSeedBytes)
+	valid := "count = 0\nseed = " + seed + "\nmlen = 1\nmsg = AB\npk = 01\nsk = 02\nsmlen = 2\nsm = 03AB\n"
+
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		want    string
+	}{
+		{"missing equals", "# x\n\ncount 0\n", 3, "name = value"},
+		{"field order", "count = 0\nmlen = 1\n", 2, `unexpected field "mlen", want seed`},
+		{"short seed", "count = 0\nseed = 00\n", 2, "1 bytes, want 48"},
+		{"bad hex", strings.Replace(valid, "pk = 01", "pk = 0G", 1), 5, "pk:"},
+		{"mlen mismatch", strings.Replace(valid, "mlen = 1", "mlen = 2", 1), 4, "mlen = 2"},
+		{"smlen mismatch", strings.Replace(valid, "smlen = 2", "smlen = 3", 1), 1, "smlen = 3"},
+		{"sm suffix", strings.Replace(valid, "sm = 03AB", "sm = AB03", 1), 1, "does not end with msg"},
+		{"missing sm", "count = 0\nseed = " + seed + "\nmlen = 0\nmsg = \npk = 01\nsk = 02\nsmlen = 1\n\n", 1, "missing sm"},
+		{"count gap", valid + "\n" + strings.Replace(valid, "count = 0", "count = 2", 1), 10, "not consecutive"},
+		{"stray comment", valid + "\n# trailer\n", 10, "comment"},
+		{"empty", "# x\n", 1, "no test cases"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := kat.ParseRSP(strings.NewReader(tt.content), "t.rsp")
+			var rspErr *kat.RSPError
+			if !errors.As(err, &rspErr) || !errors.Is(err, kat.ErrRSPFormat) {
+				t.Fatalf("err = %v, want *RSPError", err)
+			}
+			if rspErr.Line != tt.line || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v (line %d), want line %d mentioning %q", err, rspErr.Line, tt.line, tt.want)
+			}
+		})
+	}
+
+	if _, err := kat.ParseRSP(strings.NewReader(valid), "t.rsp"); err != nil {
+		t.Fatalf("valid case rejected: %v", err)
+	}
+}
+
+func TestRound3Scheme(t *testing.T) {
+	if scheme, ok := kat.Round3Scheme(1312, 2528); !ok || scheme != "Dilithium2" {
+		t.Errorf("Round3Scheme(1312, 2528) = %q, %v", scheme, ok)
+	}
+	// ML-DSA-44 keys and mixed sizes are not Round 3.
+	for _, sizes := range [][2]int{{1312, 2560}, {1952, 2528}} {
+		if scheme, ok := kat.Round3Scheme(sizes[0], sizes[1]); ok {
+			t.Errorf("Round3Scheme(%d, %d) = %q", sizes[0], sizes[1], scheme)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kat
+
+import (
+	"errors"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// Verify stages name how far verification got before it gave its answer.
+// StageOK means the signature was accepted and StageChallenge that
+// everything decoded but c̃ did not match; the rest name the check that
+// failed.
+const (
+	StageOK        = "ok"
+	StageMessage   = "message"    // mldsa.ErrEmptyMessage
+	StagePublicKey = "public-key" // mldsa.ErrInvalidPublicKey or an unknown key length
+	StageSignature = "signature"  // mldsa.ErrInvalidSignature: length or encoding
+	StageHint      = "hint"       // mldsa.ErrMalformedHint
+	StageZRange    = "z-range"    // mldsa.ErrZOutOfRange
+	StageChallenge = "challenge"  // decoded fine, but c̃ did not match
+	StageInternal  = "internal"   // any other error
+)
+
+// VerifyStage classifies the result of a verifier call.
+func VerifyStage(ok bool, err error) string {
+	switch {
+	case err == nil && ok:
+		return StageOK
+	case err == nil:
+		return StageChallenge
+	// ErrMalformedHint and ErrZOutOfRange wrap ErrInvalidSignature, so
+	// they are checked first.
+	case errors.Is(err, mldsa.ErrMalformedHint):
+		return StageHint
+	case errors.Is(err, mldsa.ErrZOutOfRange):
+		return StageZRange
+	case errors.Is(err, mldsa.ErrInvalidSignature):
+		return StageSignature
+	case errors.Is(err, mldsa.ErrInvalidPublicKey):
+		return StagePublicKey
+	case errors.Is(err, mldsa.ErrEmptyMessage):
+		return StageMessage
+	}
+	return StageInternal
+}
+
+// StageRule names the diag rule a verifier breaks by accepting a signature
+// that a correct verifier rejects at stage.
+func StageRule(stage string) string {
+	switch stage {
+	case StageHint:
+		return diag.RuleHintMalformed
+	case StageZRange:
+		return diag.RuleZBound
+	case StageSignature:
+		return diag.RuleSigMalformed
+	case StagePublicKey:
+		return diag.RulePKMalformed
+	case StageMessage:
+		return diag.RuleEmptyMessage
+	case StageChallenge:
+		return diag.RuleChallenge
+	}
+	return diag.RuleAcceptInvalid
+}
@@ -0,0 +1,336 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kat
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// Expected verdicts of a suite case (docs/wycheproof-plan.md). An error
+// verdict is written "error:<code>" with a code from ErrorCodes.
+const (
+	ExpectAccept = "accept"
+	ExpectReject = "reject"
+	expectError  = "error:"
+)
+
+// Errors raised while decoding a case, before the verifier runs. They are
+// part of the taxonomy so serialization traps can name them.
+var (
+	ErrCaseHex   = errors.New("kat: case field is not strict hex")
+	ErrCaseField = errors.New("kat: case has an unknown field")
+)
+
+// ErrSuiteFormat reports a suite file that cannot be run at all.
+var ErrSuiteFormat = errors.New("kat: malformed wycheproof suite")
+
+// ErrorCodes maps each error:<code> verdict to the typed error it stands
+// for. A case expecting error:<code> passes when errors.Is(err, target), so
+// "invalid-signature" also covers the finer malformed-hint and
+// z-out-of-range rejections.
+var ErrorCodes = map[string]error{
+	"invalid-public-key": mldsa.ErrInvalidPublicKey,
+	"invalid-signature":  mldsa.ErrInvalidSignature,
+	"malformed-hint":     mldsa.ErrMalformedHint,
+	"z-out-of-range":     mldsa.ErrZOutOfRange,
+	"empty-message":      mldsa.ErrEmptyMessage,
+	"hex-encoding":       ErrCaseHex,
+	"unknown-field":      ErrCaseField,
+}
+
+// caseFields are the keys a suite case may carry.
+var caseFields = map[string]bool{
+	"id": true, "description": true, "category": true, "reason": true, "rule": true,
+	"msg": true, "pk": true, "sk": true, "sig": true, "expected": true,
+}
+
+// codeRules names the rule a verifier breaks by accepting a case that
+// expects error:<code>.
+var codeRules = map[string]string{
+	"invalid-public-key": diag.RulePKMalformed,
+	"invalid-signature":  diag.RuleSigMalformed,
+	"malformed-hint":     diag.RuleHintMalformed,
+	"z-out-of-range":     diag.RuleZBound,
+	"empty-message":      diag.RuleEmptyMessage,
+}
+
+// Suite is a Wycheproof-style adversarial test suite.
+type Suite struct {
+	Name  string      `json:"suite"`
+	Cases []SuiteCase `json:"cases"`
+}
+
+// SuiteCase is one adversarial case. Hex fields are kept as written so
+// serialization traps survive loading; they are decoded when the case runs.
+type SuiteCase struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	Rule        string `json:"rule,omitempty"` // diag rule broken by accepting the case
+	Msg         string `json:"msg"`
+	PK          string `json:"pk"`
+	SK          string `json:"sk,omitempty"`
+	Sig         string `json:"sig,omitempty"`
+	Expected    string `json:"expected"`
+
+	// Unknown lists keys outside the case format, sorted.
+	Unknown []string `json:"-"`
+}
+
+// LoadSuite reads and validates a suite file. Every case needs a unique id
+// and an expected verdict naming a known code; unknown case keys are kept
+// in SuiteCase.Unknown rather than rejected.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("kat: open %q: %w", path, err)
+	}
+	var raw struct {
+		Name  string            `json:"suite"`
+		Cases []json.RawMessage `json:"cases"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrSuiteFormat, path, err)
+	}
+	if raw.Name == "" || len(raw.Cases) == 0 {
+		return nil, fmt.Errorf("%w: %s: needs a suite name and cases", ErrSuiteFormat, path)
+	}
+
+	suite := &Suite{Name: raw.Name}
+	seen := map[string]bool{}
+	for i, elem := range raw.Cases {
+		var c SuiteCase
+		if err := json.Unmarshal(elem, &c); err != nil {
+			return nil, fmt.Errorf("%w: %s: case %d: %v", ErrSuiteFormat, path, i, err)
+		}
+		var keys map[string]json.RawMessage
+		_ = json.Unmarshal(elem, &keys)
+		for k := range keys {
+			if !caseFields[k] {
+				c.Unknown = append(c.Unknown, k)
+			}
+		}
+		sort.Strings(c.Unknown)
+
+		switch {
+		case c.ID == "":
+			return nil, fmt.Errorf("%w: %s: case %d has no id", ErrSuiteFormat, path, i)
+		case seen[c.ID]:
+			return nil, fmt.Errorf("%w: %s: duplicate id %q", ErrSuiteFormat, path, c.ID)
+		}
+		seen[c.ID] = true
+		if err := checkExpected(c.Expected); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s: %v", ErrSuiteFormat, path, c.ID, err)
+		}
+		if _, ok := diag.LookupRule(c.Rule); c.Rule != "" && !ok {
+			return nil, fmt.Errorf("%w: %s: %s: unknown rule %q", ErrSuiteFormat, path, c.ID, c.Rule)
+		}
+		if c.Sig == "" && c.SK == "" {
+			return nil, fmt.Errorf("%w: %s: %s: needs sig or sk", ErrSuiteFormat, path, c.ID)
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	return suite, nil
+}
+
+func checkExpected(expected string) error {
+	switch {
+	case expected == ExpectAccept, expected == ExpectReject:
+		return nil
+	case strings.HasPrefix(expected, expectError):
+		if _, ok := ErrorCodes[strings.TrimPrefix(expected, expectError)]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown expected verdict %q", expected)
+}
+
+// CheckFunc verifies sig over msg under pk, reporting malformed input through
+// the typed errors listed in ErrorCodes. mldsa.Verify satisfies it.
+type CheckFunc func(pk, msg, sig []byte) (bool, error)
+
+// SuiteResult is the outcome of one suite case.
+type SuiteResult struct {
+	ID       string `json:"id"`
+	Category string `json:"category,omitempty"`
+	Expected string `json:"expected"`
+	Got      string `json:"got"`
+	Pass     bool   `json:"pass"`
+	Stage    string `json:"stage"`             // VerifyStage of the verifier's answer
+	RuleID   string `json:"ruleId,omitempty"` // failures: the diag rule broken
+	Detail   string `json:"detail,omitempty"`
+}
+
+// SuiteDiagCases returns results for diag.WriteJUnit and diag.WriteSARIF,
+// one suite per case category.
+func SuiteDiagCases(suite string, results []SuiteResult) []diag.Case {
+	cases := make([]diag.Case, 0, len(results))
+	for _, r := range results {
+		c := diag.Case{Suite: suite, Name: r.ID, Status: diag.StatusPass, Stage: r.Stage, RuleID: r.RuleID}
+		if r.Category != "" {
+			c.Suite += " " + r.Category
+		}
+		if !r.Pass {
+			c.Status = diag.StatusFail
+			c.Message = fmt.Sprintf("expected %s, got %s", r.Expected, r.Got)
+			if r.Detail != "" {
+				c.Message += ": " + r.Detail
+			}
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// SuiteFindings aggregates the failed results by rule.
+func SuiteFindings(results []SuiteResult) []diag.Finding {
+	findings := []diag.Finding{}
+	for _, r := range results {
+		if r.RuleID != "" {
+			findings = diag.AddFinding(findings, r.RuleID, fmt.Sprintf("%s: expected %s, got %s", r.ID, r.Expected, r.Got))
+		}
+	}
+	diag.SortFindings(findings)
+	return findings
+}
+
+// RunSuite runs every case through verify. Cases without a signature are
+// signed first with sign (pk, sk, msg), as the plan allows for
+// deterministic signers.
+//
+// accept passes only for (true, nil); reject passes for (false, nil) and for
+// any error; error:<code> passes when the error matches ErrorCodes[code].
+func RunSuite(suite *Suite, verify CheckFunc, sign SignFunc) []SuiteResult {
+	results := make([]SuiteResult, 0, len(suite.Cases))
+	for _, c := range suite.Cases {
+		err := runSuiteCase(c, verify, sign)
+		r := SuiteResult{ID: c.ID, Category: c.Category, Expected: c.Expected, Got: verdict(err), Stage: VerifyStage(err == nil, err)}
+		if errors.Is(err, errRejected) {
+			r.Stage = StageChallenge
+		}
+		if err != nil && !errors.Is(err, errRejected) {
+			r.Detail = err.Error()
+		}
+		switch {
+		case c.Expected == ExpectAccept:
+			r.Pass = err == nil
+		case c.Expected == ExpectReject:
+			r.Pass = err != nil
+		default:
+			r.Pass = err != nil && errors.Is(err, ErrorCodes[strings.TrimPrefix(c.Expected, expectError)])
+		}
+		if !r.Pass {
+			r.RuleID = suiteRule(c, err)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// suiteRule names the rule a failed case breaks. Accepting a case that
+// should fail breaks the case's own rule or the one its expected error
+// code stands for; rejecting it with the wrong error is a lesser finding.
+func suiteRule(c SuiteCase, err error) string {
+	switch {
+	case c.Expected == ExpectAccept:
+		return diag.RuleRejectValid
+	case err != nil:
+		return diag.RuleErrorCode
+	case c.Rule != "":
+		return c.Rule
+	}
+	if rule, ok := codeRules[strings.TrimPrefix(c.Expected, expectError)]; ok {
+		return rule
+	}
+	return diag.RuleAcceptInvalid
+}
+
+// errRejected stands for a clean (false, nil) verdict inside RunSuite.
+var errRejected = errors.New("kat: signature rejected")
+
+// runSuiteCase returns nil on accept, errRejected on a clean reject and the
+// verifier's (or decoder's) error otherwise.
+func runSuiteCase(c SuiteCase, verify CheckFunc, sign SignFunc) error {
+	if len(c.Unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrCaseField, strings.Join(c.Unknown, ", "))
+	}
+	var msg, pk, sk, sig []byte
+	for _, f := range []struct {
+		name string
+		in   string
+		out  *[]byte
+	}{{"msg", c.Msg, &msg}, {"pk", c.PK, &pk}, {"sk", c.SK, &sk}, {"sig", c.Sig, &sig}} {
+		b, err := decodeStrictHex(f.in)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrCaseHex, f.name, err)
+		}
+		*f.out = b
+	}
+
+	if c.Sig == "" {
+		if sign == nil {
+			return errors.New("kat: case has no sig and no signer was given")
+		}
+		var err error
+		if sig, err = sign(pk, sk, msg); err != nil {
+			return fmt.Errorf("kat: sign: %w", err)
+		}
+	}
+	ok, err := verify(pk, msg, sig)
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return errRejected
+	}
+	return nil
+}
+
+// verdict renders an outcome the way suites spell expected values.
+func verdict(err error) string {
+	switch {
+	case err == nil:
+		return ExpectAccept
+	case errors.Is(err, errRejected):
+		return ExpectReject
+	}
+	// Report the most specific code: one whose target is not wrapped by
+	// another matching target.
+	var best string
+	for code, target := range ErrorCodes {
+		if !errors.Is(err, target) {
+			continue
+		}
+		if best == "" || errors.Is(target, ErrorCodes[best]) {
+			best = code
+		}
+	}
+	if best == "" {
+		best = "unknown"
+	}
+	return expectError + best
+}
+
+// decodeStrictHex accepts upper-, lower- or mixed-case hex and nothing else:
+// no whitespace, prefixes or odd lengths (unlike decodeHexField).
+func decodeStrictHex(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return nil, errors.New("0x prefix")
+	}
+	if i := bytes.IndexAny([]byte(s), " \t\r\n"); i >= 0 {
+		return nil, fmt.Errorf("whitespace at offset %d", i)
+	}
+	return hex.DecodeString(s)
+}
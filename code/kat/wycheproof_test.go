@@ -0,0 +1,114 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package kat_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+const bundledSuite = "../clean/testdata/wycheproof/mldsa-wycheproof-alpha1.json"
+
+func signInternal(_, sk, msg []byte) ([]byte, error) {
+	return mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+}
+
+func TestRunSuiteBundled(t *testing.T) {
+	suite, err := kat.LoadSuite(bundledSuite)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	categories := map[string]bool{}
+	for _, r := range kat.RunSuite(suite, mldsa.Verify, signInternal) {
+		categories[r.Category] = true
+		if !r.Pass {
+			t.Errorf("%s: expected %s, got %s (%s)", r.ID, r.Expected, r.Got, r.Detail)
+		}
+	}
+	for _, want := range []string{"encoding-traps", "range-violations", "degenerate-keys", "boundary-sizes", "serialization-errors"} {
+		if !categories[want] {
+			t.Errorf("bundled suite has no %s cases", want)
+		}
+	}
+}
+
+// acceptAll is a broken verifier that never rejects anything.
+func acceptAll(pk, msg, sig []byte) (bool, error) { return true, nil }
+
+func TestRunSuiteReportsVerdicts(t *testing.T) {
+	suite, err := kat.LoadSuite(bundledSuite)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	got := map[string]kat.SuiteResult{}
+	for _, r := range kat.RunSuite(suite, mldsa.Verify, signInternal) {
+		got[r.ID] = r
+	}
+	// The most specific code is reported even though malformed-hint also
+	// satisfies error:invalid-signature.
+	if r := got["enc-hint-padding-001"]; r.Got != "error:malformed-hint" || r.Stage != kat.StageHint {
+		t.Errorf("hint padding verdict = %s at stage %s", r.Got, r.Stage)
+	}
+	if r := got["degen-pk-zero-001"]; r.Got != kat.ExpectReject || r.Detail != "" || r.Stage != kat.StageChallenge {
+		t.Errorf("degenerate key verdict = %s (%s) at stage %s", r.Got, r.Detail, r.Stage)
+	}
+
+	failed := 0
+	lenient := kat.RunSuite(suite, acceptAll, signInternal)
+	for _, r := range lenient {
+		if !r.Pass {
+			failed++
+		}
+		if r.Pass != (r.RuleID == "") {
+			t.Errorf("%s: pass=%v with rule %q", r.ID, r.Pass, r.RuleID)
+		}
+		// Decoding traps never reach the verifier.
+		if r.Category == "serialization-errors" && r.Expected != kat.ExpectAccept && !r.Pass {
+			t.Errorf("%s: decode error should not depend on the verifier", r.ID)
+		}
+	}
+	if failed == 0 {
+		t.Fatal("an accept-everything verifier passed the suite")
+	}
+	rules := map[string]int{}
+	total := 0
+	for _, f := range kat.SuiteFindings(lenient) {
+		rules[f.RuleID] = f.Count
+		total += f.Count
+	}
+	if total != failed || rules[diag.RuleHintMalformed] == 0 || rules[diag.RuleZBound] == 0 {
+		t.Errorf("findings %v for %d failures", rules, failed)
+	}
+}
+
+func TestLoadSuiteRejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"no cases":     `{"suite":"x","cases":[]}`,
+		"no id":        `{"suite":"x","cases":[{"msg":"00","pk":"00","sig":"00","expected":"accept"}]}`,
+		"duplicate id": `{"suite":"x","cases":[{"id":"a","sig":"00","expected":"accept"},{"id":"a","sig":"00","expected":"accept"}]}`,
+		"unknown code": `{"suite":"x","cases":[{"id":"a","sig":"00","expected":"error:bogus"}]}`,
+		"bad verdict":  `{"suite":"x","cases":[{"id":"a","sig":"00","expected":"invalid"}]}`,
+		"no sig or sk": `{"suite":"x","cases":[{"id":"a","msg":"00","pk":"00","expected":"accept"}]}`,
+		"unknown rule": `{"suite":"x","cases":[{"id":"a","sig":"00","expected":"reject","rule":"DV-NOPE"}]}`,
+		"not json":     `{"suite":`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "suite.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if _, err := kat.LoadSuite(path); !errors.Is(err, kat.ErrSuiteFormat) {
+				t.Fatalf("err = %v, want ErrSuiteFormat", err)
+			}
+		})
+	}
+}
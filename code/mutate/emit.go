@@ -0,0 +1,107 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mutate
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+// Suite renders cases as a Wycheproof-style suite (docs/wycheproof-plan.md).
+// Suites are checked with ML-DSA.Verify_internal, so the cases must come from
+// a Sign_internal signature.
+func Suite(name string, msg []byte, cases []Case) *kat.Suite {
+	suite := &kat.Suite{Name: name}
+	for _, c := range cases {
+		suite.Cases = append(suite.Cases, kat.SuiteCase{
+			ID:          c.Name,
+			Description: c.Description,
+			Category:    c.Component,
+			Reason:      c.Reason,
+			Rule:        c.Rule,
+			Msg:         hex.EncodeToString(msg),
+			PK:          hex.EncodeToString(c.PK),
+			Sig:         hex.EncodeToString(c.Sig),
+			Expected:    c.Expected,
+		})
+	}
+	return suite
+}
+
+// SigVerVectorSet is an ACVP ML-DSA sigVer vector set in the
+// internalProjection shape kats.LoadSigVerVectors reads.
+type SigVerVectorSet struct {
+	VectorSetID int           `json:"vsId"`
+	Algorithm   string        `json:"algorithm"`
+	Mode        string        `json:"mode"`
+	Revision    string        `json:"revision"`
+	IsSample    bool          `json:"isSample"`
+	TestGroups  []SigVerGroup `json:"testGroups"`
+}
+
+// SigVerGroup holds the cases of one parameter set.
+type SigVerGroup struct {
+	TargetGroupID      int          `json:"tgId"`
+	TestType           string       `json:"testType"`
+	ParameterSet       string       `json:"parameterSet"`
+	SignatureInterface string       `json:"signatureInterface"`
+	PreHash            string       `json:"preHash,omitempty"`
+	ExternalMu         bool         `json:"externalMu"`
+	Tests              []SigVerCase `json:"tests"`
+}
+
+// SigVerCase is one sigVer test with its expected testPassed and reason.
+type SigVerCase struct {
+	CaseID     int    `json:"tcId"`
+	TestPassed bool   `json:"testPassed"`
+	Public     string `json:"pk"`
+	Message    string `json:"message"`
+	Context    string `json:"context,omitempty"`
+	Signature  string `json:"signature"`
+	Reason     string `json:"reason"`
+}
+
+// SigVer renders cases as an ACVP sigVer vector set, one test group per
+// parameter set. With external set the groups use the pure external
+// interface and carry ctx; otherwise they are internal-interface groups.
+// Every case except an accept verdict expects testPassed=false; the finer
+// error code survives only in the reason.
+func SigVer(msg, ctx []byte, external bool, cases []Case) *SigVerVectorSet {
+	vs := &SigVerVectorSet{Algorithm: "ML-DSA", Mode: "sigVer", Revision: "FIPS204", IsSample: true}
+	groups := map[string]int{}
+	tcID := 0
+	for _, c := range cases {
+		gi, ok := groups[c.ParameterSet]
+		if !ok {
+			g := SigVerGroup{
+				TargetGroupID:      len(vs.TestGroups) + 1,
+				TestType:           "AFT",
+				ParameterSet:       c.ParameterSet,
+				SignatureInterface: "internal",
+			}
+			if external {
+				g.SignatureInterface, g.PreHash = "external", "pure"
+			}
+			gi = len(vs.TestGroups)
+			groups[c.ParameterSet] = gi
+			vs.TestGroups = append(vs.TestGroups, g)
+		}
+		tcID++
+		tc := SigVerCase{
+			CaseID:     tcID,
+			TestPassed: c.Expected == ExpectAccept,
+			Public:     strings.ToUpper(hex.EncodeToString(c.PK)),
+			Message:    strings.ToUpper(hex.EncodeToString(msg)),
+			Signature:  strings.ToUpper(hex.EncodeToString(c.Sig)),
+			Reason:     c.Reason + " (" + c.Name + ", expected " + c.Expected + ")",
+		}
+		if external {
+			tc.Context = strings.ToUpper(hex.EncodeToString(ctx))
+		}
+		vs.TestGroups[gi].Tests = append(vs.TestGroups[gi].Tests, tc)
+	}
+	return vs
+}
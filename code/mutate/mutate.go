@@ -0,0 +1,324 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package mutate derives labeled negative test vectors from one valid
+// ML-DSA signature. Mutations are structured: they follow the component
+// layouts of mldsa.SignatureLayout and re-encode z through code/pack, so each
+// case breaks exactly one rule of the encoding or of verification.
+package mutate
+
+import (
+	"errors"
+	"fmt"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/pack"
+)
+
+// Expected verdicts use the kat suite spelling (docs/wycheproof-plan.md).
+const (
+	ExpectAccept           = "accept"
+	ExpectReject           = "reject"
+	ExpectInvalidSignature = "error:invalid-signature"
+	ExpectInvalidPublicKey = "error:invalid-public-key"
+	ExpectMalformedHint    = "error:malformed-hint"
+	ExpectZOutOfRange      = "error:z-out-of-range"
+)
+
+// Errors returned by Generate.
+var (
+	ErrBaseline = errors.New("mutate: base signature does not verify")
+	ErrLayout   = errors.New("mutate: signature does not match the public key's parameter set")
+)
+
+// Case is one mutated (or, for the baseline, untouched) input.
+type Case struct {
+	Name        string // stable identifier, e.g. "ctilde-bitflip-first"
+	Component   string // signature component touched: c_tilde, z, h, length or parameter-set
+	Reason      string // short ACVP-style reason
+	Description string
+	Expected    string // one of the Expect* verdicts
+	Rule        string // diag rule a verifier breaks by accepting the case
+
+	ParameterSet string // parameter set the pk belongs to
+	PK           []byte
+	Sig          []byte
+}
+
+// VerifyFunc checks sig over msg under pk for the interface the signature was
+// produced with; Generate uses it to confirm the baseline.
+type VerifyFunc func(pk, msg, sig []byte) (bool, error)
+
+// Generate returns the baseline case followed by every mutation that applies
+// to sig. The baseline must verify under verify, otherwise the expected
+// verdicts would be meaningless and ErrBaseline is returned. Mutations whose
+// precondition is missing (e.g. reordering needs a polynomial with two hints)
+// are left out.
+func Generate(pk, msg, sig []byte, verify VerifyFunc) ([]Case, error) {
+	params, err := mldsa.FromPublicKeyLength(len(pk))
+	if err != nil {
+		return nil, fmt.Errorf("mutate: public key: %w", err)
+	}
+	if len(sig) != params.SigBytes {
+		return nil, fmt.Errorf("%w: %s signatures are %d bytes, got %d", ErrLayout, params.Name, params.SigBytes, len(sig))
+	}
+	ok, err := verify(pk, msg, sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBaseline, err)
+	}
+	if !ok {
+		return nil, ErrBaseline
+	}
+
+	g := &generator{params: params, pk: pk, sig: sig, layout: map[string]mldsa.Component{}}
+	for _, c := range mldsa.SignatureLayout(params) {
+		g.layout[c.Name] = c
+	}
+	g.add(Case{
+		Name: "baseline", Component: "none", Reason: "unmodified signature",
+		Description: "The input signature, unchanged.", Expected: ExpectAccept,
+	}, clone(sig))
+
+	g.cTilde()
+	if err := g.z(); err != nil {
+		return nil, err
+	}
+	g.hints()
+	g.lengths()
+	g.parameterSwap()
+	return g.cases, nil
+}
+
+type generator struct {
+	params *mldsa.Params
+	pk     []byte
+	sig    []byte
+	layout map[string]mldsa.Component
+	cases  []Case
+}
+
+func (g *generator) add(c Case, sig []byte) {
+	if c.ParameterSet == "" {
+		c.ParameterSet = g.params.Name
+	}
+	if c.PK == nil {
+		c.PK = g.pk
+	}
+	c.Sig = sig
+	g.cases = append(g.cases, c)
+}
+
+// cTilde flips the lowest bit of the first and the highest bit of the last
+// commitment-hash byte. The challenge then differs, so verification fails
+// without any encoding error.
+func (g *generator) cTilde() {
+	ct := g.layout["c_tilde"]
+	for _, m := range []struct {
+		name, where string
+		offset      int
+		bit         byte
+	}{
+		{"first", "bit 0 of byte 0", ct.Offset, 0x01},
+		{"last", fmt.Sprintf("bit 7 of byte %d", ct.Size-1), ct.Offset + ct.Size - 1, 0x80},
+	} {
+		sig := clone(g.sig)
+		sig[m.offset] ^= m.bit
+		g.add(Case{
+			Name: "ctilde-bitflip-" + m.name, Component: "c_tilde",
+			Reason:      "modified signature - c_tilde",
+			Description: fmt.Sprintf("Flip %s of c_tilde; the recomputed challenge no longer matches.", m.where),
+			Expected:    ExpectReject,
+			Rule:        diag.RuleChallenge,
+		}, sig)
+	}
+}
+
+// z sets one coefficient of z[0] to exactly γ1−β (the first rejected value)
+// and to γ1−β−1 (the largest accepted one). The latter passes the norm
+// check and must fail on the challenge instead. A mutation is left out if
+// every coefficient of z[0] already holds its value.
+func (g *generator) z() error {
+	zc := g.layout["z"]
+	bits := g.params.Gamma1Bits
+	size := zc.Size / g.params.L
+	enc, err := pack.UnpackBits(g.sig[zc.Offset:zc.Offset+size], bits, 256)
+	if err != nil {
+		return fmt.Errorf("mutate: unpack z: %w", err)
+	}
+	gamma1, bound := int64(g.params.Gamma1), int64(g.params.Gamma1-g.params.Beta)
+
+	for _, m := range []struct {
+		name, what     string
+		value          int64
+		expected, rule string
+	}{
+		{"z-at-bound", "γ1−β", bound, ExpectZOutOfRange, diag.RuleZBound},
+		{"z-at-bound-negative", "−(γ1−β)", -bound, ExpectZOutOfRange, diag.RuleZBound},
+		{"z-below-bound", "γ1−β−1", bound - 1, ExpectReject, diag.RuleChallenge},
+	} {
+		// z is stored as γ1 − z; pick the first coefficient that changes.
+		target := uint32(gamma1 - m.value)
+		idx, ok := firstOther(enc, target)
+		if !ok {
+			continue
+		}
+		coeffs := append([]uint32(nil), enc...)
+		coeffs[idx] = target
+		packed, err := pack.PackBits(coeffs, bits)
+		if err != nil {
+			return fmt.Errorf("mutate: pack z: %w", err)
+		}
+		sig := clone(g.sig)
+		copy(sig[zc.Offset:], packed)
+		g.add(Case{
+			Name: m.name, Component: "z",
+			Reason:      "modified signature - z",
+			Description: fmt.Sprintf("Set z[0][%d] to %s (%d).", idx, m.what, m.value),
+			Expected:    m.expected,
+			Rule:        m.rule,
+		}, sig)
+	}
+	return nil
+}
+
+// firstOther returns the index of the first coefficient not equal to
+// target; ok is false if every coefficient already is.
+func firstOther(coeffs []uint32, target uint32) (idx int, ok bool) {
+	for i, c := range coeffs {
+		if c != target {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// hints breaks HintBitUnpack in each way FIPS 204 Algorithm 21 rejects:
+// indices out of order, a repeated index, a counter above ω and non-zero
+// padding after the last index.
+func (g *generator) hints() {
+	h := g.layout["h"]
+	omega, k := g.params.Omega, g.params.K
+	base := g.sig[h.Offset : h.Offset+h.Size]
+	counters := base[omega:]
+
+	// First polynomial with at least two hints, for reorder/duplicate.
+	start, pair := 0, -1
+	for i := 0; i < k; i++ {
+		end := int(counters[i])
+		if end-start >= 2 {
+			pair = start
+			break
+		}
+		start = end
+	}
+	if pair >= 0 {
+		sig := clone(g.sig)
+		y := sig[h.Offset:]
+		y[pair], y[pair+1] = y[pair+1], y[pair]
+		g.add(Case{
+			Name: "hint-reordered", Component: "h",
+			Reason:      "modified signature - hint",
+			Description: fmt.Sprintf("Swap hint indices %d and %d so they are no longer strictly increasing.", pair, pair+1),
+			Expected:    ExpectMalformedHint,
+			Rule:        diag.RuleHintOrder,
+		}, sig)
+
+		sig = clone(g.sig)
+		y = sig[h.Offset:]
+		y[pair+1] = y[pair]
+		g.add(Case{
+			Name: "hint-duplicated", Component: "h",
+			Reason:      "modified signature - hint",
+			Description: fmt.Sprintf("Repeat hint index %d at position %d.", y[pair], pair+1),
+			Expected:    ExpectMalformedHint,
+			Rule:        diag.RuleHintOrder,
+		}, sig)
+	}
+
+	sig := clone(g.sig)
+	sig[h.Offset+omega+k-1] = byte(omega + 1)
+	g.add(Case{
+		Name: "hint-exceeds-omega", Component: "h",
+		Reason:      "modified signature - hint",
+		Description: fmt.Sprintf("Set the last hint counter to ω+1 = %d.", omega+1),
+		Expected:    ExpectMalformedHint,
+		Rule:        diag.RuleHintOmega,
+	}, sig)
+
+	if used := int(counters[k-1]); used < omega {
+		sig := clone(g.sig)
+		sig[h.Offset+omega-1] = 0x01
+		g.add(Case{
+			Name: "hint-padding-nonzero", Component: "h",
+			Reason:      "modified signature - hint",
+			Description: fmt.Sprintf("Write 0x01 into hint padding byte %d (only %d of ω = %d indices are used).", omega-1, used, omega),
+			Expected:    ExpectMalformedHint,
+			Rule:        diag.RuleHintPadding,
+		}, sig)
+	}
+}
+
+// lengths truncates and extends the signature by one byte.
+func (g *generator) lengths() {
+	g.add(Case{
+		Name: "sig-truncated", Component: "length",
+		Reason:      "modified signature - length",
+		Description: fmt.Sprintf("Drop the last byte (%d bytes).", len(g.sig)-1),
+		Expected:    ExpectInvalidSignature,
+		Rule:        diag.RuleSigLength,
+	}, clone(g.sig[:len(g.sig)-1]))
+	g.add(Case{
+		Name: "sig-extended", Component: "length",
+		Reason:      "modified signature - length",
+		Description: fmt.Sprintf("Append a zero byte (%d bytes).", len(g.sig)+1),
+		Expected:    ExpectInvalidSignature,
+		Rule:        diag.RuleSigLength,
+	}, append(clone(g.sig), 0))
+}
+
+// parameterSwap presents the material as the next parameter set: first the
+// signature resized to that set's length under the original key, then key
+// and signature both resized so verification runs with the other set.
+func (g *generator) parameterSwap() {
+	other := nextParams(g.params)
+	g.add(Case{
+		Name: "paramset-sig-length", Component: "parameter-set",
+		Reason:      "modified signature - parameter set",
+		Description: fmt.Sprintf("Resize the signature to %s length (%d bytes) under the %s key.", other.Name, other.SigBytes, g.params.Name),
+		Expected:    ExpectInvalidSignature,
+		Rule:        diag.RuleSigLength,
+	}, resize(g.sig, other.SigBytes))
+	g.add(Case{
+		Name: "paramset-swapped", Component: "parameter-set",
+		Reason:      "modified key and signature - parameter set",
+		Description: fmt.Sprintf("Resize key and signature to %s lengths (%d/%d bytes).", other.Name, other.PKBytes, other.SigBytes),
+		Expected:    ExpectReject,
+		Rule:        diag.RuleParamSet,
+
+		ParameterSet: other.Name,
+		PK:           resize(g.pk, other.PKBytes),
+	}, resize(g.sig, other.SigBytes))
+}
+
+func nextParams(p *mldsa.Params) *mldsa.Params {
+	switch p {
+	case mldsa.ParamsMLDSA44:
+		return mldsa.ParamsMLDSA65
+	case mldsa.ParamsMLDSA65:
+		return mldsa.ParamsMLDSA87
+	default:
+		return mldsa.ParamsMLDSA44
+	}
+}
+
+// resize truncates or zero-extends b to n bytes.
+func resize(b []byte, n int) []byte {
+	out := make([]byte, n)
+	copy(out, b)
+	return out
+}
+
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,132 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package mutate
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/kat"
+)
+
+var testMsg = []byte("DiliVet mutate")
+
+func signed(t *testing.T, params *mldsa.Params, ctx []byte) (pk, sig []byte) {
+	t.Helper()
+	pk, sk, err := mldsa.KeyGen(params, make([]byte, mldsa.SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rnd := make([]byte, mldsa.RndBytes)
+	if ctx == nil {
+		sig, err = mldsa.Sign(sk, testMsg, rnd)
+	} else {
+		sig, err = mldsa.SignWithContext(sk, testMsg, ctx, rnd)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk, sig
+}
+
+func TestGenerateSuitePasses(t *testing.T) {
+	for _, params := range []*mldsa.Params{mldsa.ParamsMLDSA44, mldsa.ParamsMLDSA65, mldsa.ParamsMLDSA87} {
+		t.Run(params.Name, func(t *testing.T) {
+			pk, sig := signed(t, params, nil)
+			cases, err := Generate(pk, testMsg, sig, mldsa.Verify)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen := map[string]bool{}
+			for _, c := range cases {
+				seen[c.Name] = true
+				if (c.Rule == "") != (c.Expected == ExpectAccept) {
+					t.Errorf("%s: rule %q for verdict %s", c.Name, c.Rule, c.Expected)
+				}
+			}
+			for _, name := range []string{"baseline", "ctilde-bitflip-first", "z-at-bound", "z-below-bound",
+				"hint-exceeds-omega", "sig-truncated", "sig-extended", "paramset-sig-length", "paramset-swapped"} {
+				if !seen[name] {
+					t.Errorf("missing case %s", name)
+				}
+			}
+
+			suite := Suite("mutations", testMsg, cases)
+			for _, r := range kat.RunSuite(suite, mldsa.Verify, nil) {
+				if !r.Pass {
+					t.Errorf("%s: expected %s, got %s (%s)", r.ID, r.Expected, r.Got, r.Detail)
+				}
+			}
+
+			// A verifier that accepts everything breaks each case's own rule.
+			acceptAll := func(pk, msg, sig []byte) (bool, error) { return true, nil }
+			for i, r := range kat.RunSuite(suite, acceptAll, nil) {
+				if r.RuleID != cases[i].Rule {
+					t.Errorf("%s: rule %q, want %q", r.ID, r.RuleID, cases[i].Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestSigVerLoadsAndPasses(t *testing.T) {
+	ctx := []byte("ctx")
+	pk, sig := signed(t, mldsa.ParamsMLDSA44, ctx)
+	verify := func(pk, msg, sig []byte) (bool, error) { return mldsa.VerifyWithContext(pk, msg, ctx, sig) }
+	cases, err := Generate(pk, testMsg, sig, verify)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(SigVer(testMsg, ctx, true, cases))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "sigver.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vectors, err := kats.LoadSigVerVectors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors.TestGroups) != 2 {
+		t.Fatalf("got %d groups, want the original and the swapped parameter set", len(vectors.TestGroups))
+	}
+	res := kats.RunSigVer(vectors, kats.Builtin{})
+	for _, c := range res.Cases {
+		if c.Outcome != kats.OutcomePass {
+			t.Errorf("tcId %d: %s: %s", c.CaseID, c.Outcome, c.Reason)
+		}
+	}
+	if res.Report.TotalTests != len(cases) {
+		t.Errorf("ran %d cases, want %d", res.Report.TotalTests, len(cases))
+	}
+}
+
+func TestGenerateRejectsInvalidBaseline(t *testing.T) {
+	pk, sig := signed(t, mldsa.ParamsMLDSA44, nil)
+	sig[0] ^= 1
+	if _, err := Generate(pk, testMsg, sig, mldsa.Verify); !errors.Is(err, ErrBaseline) {
+		t.Fatalf("got %v, want ErrBaseline", err)
+	}
+	if _, err := Generate(pk, testMsg, sig[:10], mldsa.Verify); !errors.Is(err, ErrLayout) {
+		t.Fatalf("got %v, want ErrLayout", err)
+	}
+}
+
+func TestFirstOther(t *testing.T) {
+	if idx, ok := firstOther([]uint32{7, 7, 3, 7}, 7); !ok || idx != 2 {
+		t.Errorf("firstOther = %d, %v, want 2, true", idx, ok)
+	}
+	// Nothing left to change: the mutation does not apply.
+	if _, ok := firstOther([]uint32{7, 7, 7}, 7); ok {
+		t.Error("firstOther found a coefficient in a constant slice")
+	}
+}
@@ -49,12 +49,12 @@ func Le2QModQ(x uint32) uint32 {
 }
 
 // Canonical returns the representative of x in signed canonical form (-q/2, q/2].
+//
+//dilivet:secret x
 func Canonical(x uint32) int32 {
 	y := int32(ModQ(x))
-	if y > int32(Q)/2 {
-		y -= int32(Q)
-	}
-	return y
+	mask := (int32(Q)/2 - y) >> 31 // all ones if y > q/2
+	return y - (mask & int32(Q))
 }
 
 // ModQ returns x mod q for any uint32.
@@ -208,6 +208,9 @@ func SamplePolyUniform(p *Poly, seed []byte, nonce uint16) error {
 	return nil
 }
 
+// sampleCBD maps the secret XOF output buf to coefficients in [-eta, eta].
+//
+//dilivet:secret buf
 func sampleCBD(p *Poly, buf []byte, eta int) error {
 	switch eta {
 	case 2:
@@ -222,9 +225,7 @@ func sampleCBD(p *Poly, buf []byte, eta int) error {
 				a := (d >> (4 * j)) & 0x3
 				b := (d >> (4*j + 2)) & 0x3
 				val := int32(a) - int32(b)
-				if val < 0 {
-					val += int32(Q)
-				}
+				val += (val >> 31) & int32(Q)
 				p.Coeffs[8*i+j] = uint32(val)
 			}
 		}
@@ -246,9 +247,7 @@ func sampleCBD(p *Poly, buf []byte, eta int) error {
 				a := (t0 >> (8 * j)) & 0xF
 				b := (t0 >> (8*j + 4)) & 0xF
 				val := int32(a) - int32(b)
-				if val < 0 {
-					val += int32(Q)
-				}
+				val += (val >> 31) & int32(Q)
 				p.Coeffs[8*i+j] = uint32(val)
 			}
 		}
@@ -0,0 +1,95 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package sigcorpus
+
+import "math"
+
+// minExpected is the smallest expected bin count the χ² approximation is
+// trusted with; sparser bins are merged or the test is skipped.
+const minExpected = 5
+
+// ChiSquare runs Pearson's goodness-of-fit test of observed counts against
+// expected counts and returns the statistic, its degrees of freedom and
+// the p-value. Adjacent bins are merged until each expects at least five
+// samples; df is 0 (and p 1) when fewer than two bins remain.
+func ChiSquare(observed, expected []float64) (stat float64, df int, p float64) {
+	var obs, exp []float64
+	var o, e float64
+	for i := range observed {
+		o += observed[i]
+		e += expected[i]
+		if e >= minExpected {
+			obs, exp = append(obs, o), append(exp, e)
+			o, e = 0, 0
+		}
+	}
+	if len(exp) > 0 {
+		obs[len(obs)-1] += o
+		exp[len(exp)-1] += e
+	}
+	if len(exp) < 2 {
+		return 0, 0, 1
+	}
+	for i := range obs {
+		d := obs[i] - exp[i]
+		stat += d * d / exp[i]
+	}
+	df = len(exp) - 1
+	return stat, df, ChiSquareP(stat, df)
+}
+
+// ChiSquareP returns P(X ≥ stat) for X ~ χ²(df), the regularized upper
+// incomplete gamma function Q(df/2, stat/2).
+func ChiSquareP(stat float64, df int) float64 {
+	if df <= 0 || stat <= 0 {
+		return 1
+	}
+	return gammaQ(float64(df)/2, stat/2)
+}
+
+// gammaQ evaluates Q(a, x) by its series for x < a+1 and by Lentz's
+// continued fraction otherwise.
+func gammaQ(a, x float64) float64 {
+	const (
+		eps  = 1e-14
+		tiny = 1e-300
+		iter = 1000
+	)
+	lg, _ := math.Lgamma(a)
+	front := math.Exp(-x + a*math.Log(x) - lg)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < iter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*front)
+	}
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < iter; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return front * h
+}
@@ -0,0 +1,71 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package sigcorpus
+
+import (
+	"fmt"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/pack"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// decoded is a signature split into its sigEncode fields.
+type decoded struct {
+	ctilde []byte
+	z      [][]int32 // centred, in [−γ₁+1, γ₁]
+	hints  [][]int   // hint indices per polynomial of h
+	weight int       // total number of hints
+}
+
+// decode implements sigDecode (FIPS 204 Algorithm 27) with code/pack,
+// keeping z centred and the hints as index lists.
+func decode(sig []byte, params *mldsa.Params) (*decoded, error) {
+	if len(sig) != params.SigBytes {
+		return nil, fmt.Errorf("%w: %d bytes, want %d", ErrDecode, len(sig), params.SigBytes)
+	}
+	layout := mldsa.SignatureLayout(params)
+	ct, zc, hc := layout[0], layout[1], layout[2]
+	d := &decoded{ctilde: sig[ct.Offset : ct.Offset+ct.Size]}
+
+	step := zc.Size / params.L
+	for i := 0; i < params.L; i++ {
+		off := zc.Offset + i*step
+		vals, err := pack.UnpackBits(sig[off:off+step], params.Gamma1Bits, poly.N)
+		if err != nil {
+			return nil, fmt.Errorf("%w: z[%d]: %v", ErrDecode, i, err)
+		}
+		z := make([]int32, poly.N)
+		for j, v := range vals {
+			// BitUnpack(·, γ₁−1, γ₁) stores γ₁ − z.
+			z[j] = int32(params.Gamma1) - int32(v)
+		}
+		d.z = append(d.z, z)
+	}
+
+	// HintBitUnpack (Algorithm 21), rejecting non-canonical encodings.
+	y := sig[hc.Offset : hc.Offset+hc.Size]
+	idx := 0
+	for i := 0; i < params.K; i++ {
+		limit := int(y[params.Omega+i])
+		if limit < idx || limit > params.Omega {
+			return nil, fmt.Errorf("%w: hint counter %d out of range", ErrDecode, i)
+		}
+		var row []int
+		for ; idx < limit; idx++ {
+			if len(row) > 0 && int(y[idx]) <= row[len(row)-1] {
+				return nil, fmt.Errorf("%w: hint indices of h[%d] not strictly increasing", ErrDecode, i)
+			}
+			row = append(row, int(y[idx]))
+		}
+		d.hints = append(d.hints, row)
+		d.weight += len(row)
+	}
+	for i := idx; i < params.Omega; i++ {
+		if y[i] != 0 {
+			return nil, fmt.Errorf("%w: non-zero hint padding", ErrDecode)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,414 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package sigcorpus analyzes many signatures made under one key for the
+// traces of a broken signer: commitments w1 that repeat across messages
+// (reused y or rnd), repeated c̃, duplicate hedged signatures, and z, hint
+// and c̃ distributions that drift from what FIPS 204 signing produces.
+package sigcorpus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+var (
+	// ErrFormat reports a corpus line that is not a msg/sig JSON object.
+	ErrFormat = errors.New("sigcorpus: malformed corpus line")
+	// ErrDecode reports a signature sigDecode rejects.
+	ErrDecode = errors.New("sigcorpus: malformed signature")
+	// ErrEmpty reports a corpus without signatures.
+	ErrEmpty = errors.New("sigcorpus: no signatures")
+)
+
+// Distribution tests.
+const (
+	TestZ             = "z-uniform"      // z coefficients against the uniform range
+	TestCTildeBytes   = "ctilde-bytes"   // c̃ bytes against uniform bytes
+	TestHintPositions = "hint-positions" // hint indices against an even spread over 0..255
+	TestHintWeight    = "hint-weight"    // mean hint weight against reference signatures
+)
+
+// WeightTolerance is how far, relative to the reference, the mean hint
+// weight may drift before it counts. The weight depends a little on the
+// key (through t₀ and s₂), so reference signatures under another key only
+// bound it loosely; a bug in Decompose or MakeHint moves it much further.
+const WeightTolerance = 0.1
+
+// Record is one signature of the corpus. Line is its 1-based line number.
+type Record struct {
+	Msg  []byte
+	Sig  []byte
+	Line int
+}
+
+// ReadJSONL reads one {"msg": hex, "sig": hex} object per line; blank
+// lines are skipped.
+func ReadJSONL(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var raw struct {
+			Msg *string `json:"msg"`
+			Sig *string `json:"sig"`
+		}
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrFormat, line, err)
+		}
+		if raw.Msg == nil || raw.Sig == nil {
+			return nil, fmt.Errorf("%w: line %d: msg and sig are required", ErrFormat, line)
+		}
+		msg, err := hex.DecodeString(*raw.Msg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: msg: %v", ErrFormat, line, err)
+		}
+		sig, err := hex.DecodeString(*raw.Sig)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: sig: %v", ErrFormat, line, err)
+		}
+		records = append(records, Record{Msg: msg, Sig: sig, Line: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("sigcorpus: %w", err)
+	}
+	return records, nil
+}
+
+// Options tunes Analyze; zero fields take defaults.
+type Options struct {
+	// Ctx, if non-nil, verifies with the external ML-DSA.Verify and this
+	// context instead of Verify_internal.
+	Ctx []byte
+	// Deterministic accepts repeated signatures on the same message, which
+	// deterministic signers produce by design.
+	Deterministic bool
+	// Alpha is the significance level of the distribution tests (default 0.001).
+	Alpha float64
+	// Bins is the number of z histogram bins (default 64).
+	Bins int
+	// Reference is how many reference signatures the hint weights are
+	// compared with (default 256).
+	Reference int
+}
+
+// Test is the outcome of one χ² test. Skipped tests had too few samples.
+type Test struct {
+	Name      string  `json:"name"`
+	Samples   int     `json:"samples"`
+	Statistic float64 `json:"statistic"`
+	DF        int     `json:"df"`
+	P         float64 `json:"p"`
+	Skipped   bool    `json:"skipped,omitempty"`
+	Fail      bool    `json:"fail,omitempty"`
+}
+
+// Weights summarizes the hint weights of the decoded signatures.
+type Weights struct {
+	Min   int     `json:"min"`
+	Max   int     `json:"max"`
+	Mean  float64 `json:"mean"`
+	Omega int     `json:"omega"`
+}
+
+// Report is the outcome of Analyze.
+type Report struct {
+	ParameterSet string         `json:"parameterSet"`
+	Signatures   int            `json:"signatures"`
+	Valid        int            `json:"valid"`
+	Invalid      int            `json:"invalid"`    // decoded, but verification rejects them
+	Malformed    int            `json:"malformed"`  // sigDecode rejects them
+	Duplicates   int            `json:"duplicates"` // repeats of an earlier signature on the same message
+	DistinctW1   int            `json:"distinctW1"`
+	HintWeights  Weights        `json:"hintWeights"`
+	Tests        []Test         `json:"tests"`
+	Findings     []diag.Finding `json:"findings"`
+}
+
+// w1Tracer keeps w1Encode(w1′) from a verification.
+type w1Tracer struct{ w1 []byte }
+
+func (t *w1Tracer) Trace(step mldsa.TraceStep, v mldsa.TraceValue) {
+	if step == mldsa.TraceW1Encode {
+		t.w1 = v.Bytes
+	}
+}
+
+// Analyze checks records, all signed under pk. Every signature is decoded
+// and verified; w1 is recomputed as verification does, so it needs nothing
+// secret.
+func Analyze(pk []byte, records []Record, opts Options) (*Report, error) {
+	if len(records) == 0 {
+		return nil, ErrEmpty
+	}
+	params, err := mldsa.FromPublicKeyLength(len(pk))
+	if err != nil {
+		return nil, err
+	}
+	if opts.Alpha <= 0 {
+		opts.Alpha = 0.001
+	}
+	if opts.Bins <= 0 {
+		opts.Bins = 64
+	}
+	if opts.Reference <= 0 {
+		opts.Reference = 256
+	}
+
+	rep := &Report{ParameterSet: params.Name, Signatures: len(records), HintWeights: Weights{Min: -1, Omega: params.Omega}}
+	var findings []diag.Finding
+	add := func(rule, evidence string) { findings = diag.AddFinding(findings, rule, evidence) }
+
+	bound := int32(params.Gamma1 - params.Beta)
+	span := int(2*bound - 1) // z in (−bound, bound)
+	zHist := make([]float64, opts.Bins)
+	zSamples := 0
+	ctHist := make([]float64, 256)
+	positions := make([]float64, 16)
+	var weights []float64
+
+	firstSig := map[string]int{}   // signature → index of its first record
+	byW1 := map[string][]int{}     // w1Encode → indices of valid records
+	byCTilde := map[string][]int{} // c̃ → indices of decoded records
+	var w1Order, ctOrder []string
+
+	for i, rec := range records {
+		// A repeated signature is analyzed once; only a different message
+		// makes it worth verifying again.
+		repeat := false
+		if j, ok := firstSig[string(rec.Sig)]; ok {
+			repeat = true
+			if bytes.Equal(records[j].Msg, rec.Msg) {
+				if !opts.Deterministic {
+					add(diag.RuleDuplicateSig, fmt.Sprintf("lines %d and %d", records[j].Line, rec.Line))
+				}
+				rep.Duplicates++
+				continue
+			}
+		} else {
+			firstSig[string(rec.Sig)] = i
+		}
+
+		d, err := decode(rec.Sig, params)
+		if err != nil {
+			rep.Malformed++
+			add(diag.RuleSigGenError, fmt.Sprintf("line %d: %v", rec.Line, err))
+			continue
+		}
+		key := string(d.ctilde)
+		if _, ok := byCTilde[key]; !ok {
+			ctOrder = append(ctOrder, key)
+		}
+		byCTilde[key] = append(byCTilde[key], i)
+		if !repeat {
+			for _, b := range d.ctilde {
+				ctHist[b]++
+			}
+			for _, z := range d.z {
+				for _, v := range z {
+					if v >= bound || -v >= bound {
+						continue // verification rejects it; counted as invalid below
+					}
+					zHist[int(v+bound-1)*opts.Bins/span]++
+					zSamples++
+				}
+			}
+			for _, row := range d.hints {
+				for _, j := range row {
+					positions[j*len(positions)/poly.N]++
+				}
+			}
+			weights = append(weights, float64(d.weight))
+		}
+
+		tr := &w1Tracer{}
+		var ok bool
+		if opts.Ctx != nil {
+			ok, err = mldsa.VerifyWithContextTraced(pk, rec.Msg, opts.Ctx, rec.Sig, tr)
+		} else {
+			ok, err = mldsa.VerifyTraced(pk, rec.Msg, rec.Sig, tr)
+		}
+		if !ok || err != nil {
+			rep.Invalid++
+			reason := "rejected"
+			if err != nil {
+				reason = err.Error()
+			}
+			add(diag.RuleSigGenError, fmt.Sprintf("line %d: verification %s", rec.Line, reason))
+			continue
+		}
+		rep.Valid++
+		key = string(tr.w1)
+		if _, ok := byW1[key]; !ok {
+			w1Order = append(w1Order, key)
+		}
+		byW1[key] = append(byW1[key], i)
+	}
+	rep.DistinctW1 = len(byW1)
+	mean, variance := moments(weights)
+	rep.HintWeights.Mean = mean
+	for _, w := range weights {
+		if rep.HintWeights.Min < 0 || int(w) < rep.HintWeights.Min {
+			rep.HintWeights.Min = int(w)
+		}
+		rep.HintWeights.Max = max(rep.HintWeights.Max, int(w))
+	}
+	rep.HintWeights.Min = max(rep.HintWeights.Min, 0)
+
+	// A repeated w1 or c̃ only matters across different messages.
+	for _, key := range w1Order {
+		if a, b, ok := differentMessages(records, byW1[key]); ok {
+			add(diag.RuleNonceReuse, fmt.Sprintf("lines %d and %d", a, b))
+		}
+	}
+	for _, key := range ctOrder {
+		if a, b, ok := differentMessages(records, byCTilde[key]); ok {
+			add(diag.RuleChallengeReuse, fmt.Sprintf("lines %d and %d", a, b))
+		}
+	}
+
+	// z is uniform over the span once rejection sampling has run.
+	zExp := make([]float64, opts.Bins)
+	for v := 0; v < span; v++ {
+		zExp[v*opts.Bins/span]++
+	}
+	scale(zExp, float64(zSamples)/float64(span))
+	rep.addTest(TestZ, zSamples, zHist, zExp, opts.Alpha, diag.RuleZDistribution, add)
+
+	ctBytes := len(weights) * params.CTildeBytes()
+	rep.addTest(TestCTildeBytes, ctBytes, ctHist, uniform(256, ctBytes), opts.Alpha, diag.RuleChallengeDist, add)
+
+	// Hint positions are exchangeable under any key; how many hints each
+	// polynomial of h gets is not, so rows are not tested.
+	hints := int(mean * float64(len(weights)))
+	rep.addTest(TestHintPositions, hints, positions, uniform(len(positions), hints), opts.Alpha, diag.RuleHintDistribution, add)
+
+	// The mean weight against reference signatures: z² of the difference
+	// is χ² with one degree of freedom.
+	ref, err := referenceWeights(params, opts.Reference)
+	if err != nil {
+		return nil, err
+	}
+	refMean, refVar := moments(ref)
+	t := Test{Name: TestHintWeight, Samples: len(weights), DF: 1, P: 1, Skipped: len(weights) < 2}
+	if se := variance/float64(len(weights)) + refVar/float64(len(ref)); !t.Skipped && se > 0 {
+		diff := mean - refMean
+		t.Statistic = diff * diff / se
+		t.P = ChiSquareP(t.Statistic, 1)
+		if math.Abs(diff) <= WeightTolerance*refMean {
+			t.P = math.Max(t.P, opts.Alpha) // within tolerance: never a finding
+		}
+	}
+	rep.record(t, opts.Alpha, diag.RuleHintDistribution, add)
+
+	diag.SortFindings(findings)
+	rep.Findings = findings
+	return rep, nil
+}
+
+// addTest runs a goodness-of-fit test and records it.
+func (r *Report) addTest(name string, samples int, observed, expected []float64, alpha float64, rule string, add func(rule, evidence string)) {
+	stat, df, p := ChiSquare(observed, expected)
+	r.record(Test{Name: name, Samples: samples, Statistic: stat, DF: df, P: p, Skipped: df == 0}, alpha, rule, add)
+}
+
+// record appends t and turns a p-value below alpha into a finding.
+func (r *Report) record(t Test, alpha float64, rule string, add func(rule, evidence string)) {
+	if !t.Skipped && t.P < alpha {
+		t.Fail = true
+		add(rule, fmt.Sprintf("%s: χ²=%.1f, df=%d, p=%.2g over %d samples", t.Name, t.Statistic, t.DF, t.P, t.Samples))
+	}
+	r.Tests = append(r.Tests, t)
+}
+
+// referenceWeights returns the hint weights of n signatures the built-in
+// signer makes under a fixed key of params.
+func referenceWeights(params *mldsa.Params, n int) ([]float64, error) {
+	rng := rand.New(rand.NewSource(1))
+	seed := make([]byte, mldsa.SeedBytes)
+	rng.Read(seed)
+	_, sk, err := mldsa.KeyGen(params, seed)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]float64, 0, n)
+	msg := make([]byte, 32)
+	rnd := make([]byte, mldsa.RndBytes)
+	for i := 0; i < n; i++ {
+		rng.Read(msg)
+		rng.Read(rnd)
+		sig, err := mldsa.Sign(sk, msg, rnd)
+		if err != nil {
+			return nil, err
+		}
+		d, err := decode(sig, params)
+		if err != nil {
+			return nil, err
+		}
+		weights = append(weights, float64(d.weight))
+	}
+	return weights, nil
+}
+
+// differentMessages returns the lines of the first record in idx and the
+// first later one with another message.
+func differentMessages(records []Record, idx []int) (int, int, bool) {
+	if len(idx) < 2 {
+		return 0, 0, false
+	}
+	first := records[idx[0]]
+	for _, i := range idx[1:] {
+		if !bytes.Equal(records[i].Msg, first.Msg) {
+			return first.Line, records[i].Line, true
+		}
+	}
+	return 0, 0, false
+}
+
+// moments returns the mean and the sample variance of v.
+func moments(v []float64) (mean, variance float64) {
+	if len(v) == 0 {
+		return 0, 0
+	}
+	for _, x := range v {
+		mean += x
+	}
+	mean /= float64(len(v))
+	if len(v) < 2 {
+		return mean, 0
+	}
+	for _, x := range v {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, variance / float64(len(v)-1)
+}
+
+func uniform(bins, samples int) []float64 {
+	e := make([]float64, bins)
+	for i := range e {
+		e[i] = float64(samples) / float64(bins)
+	}
+	return e
+}
+
+func scale(v []float64, k float64) {
+	for i := range v {
+		v[i] *= k
+	}
+}
@@ -0,0 +1,239 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package sigcorpus
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/hash"
+	"github.com/codethor0/dilivet/code/pack"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+func honestCorpus(t *testing.T, params *mldsa.Params, n int) ([]byte, []Record) {
+	t.Helper()
+	pk, sk, err := mldsa.KeyGen(params, bytes.Repeat([]byte{0x49}, mldsa.SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(7))
+	records := make([]Record, n)
+	for i := range records {
+		msg := make([]byte, 24)
+		rnd := make([]byte, mldsa.RndBytes)
+		rng.Read(msg)
+		rng.Read(rnd)
+		sig, err := mldsa.Sign(sk, msg, rnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[i] = Record{Msg: msg, Sig: sig, Line: i + 1}
+	}
+	return pk, records
+}
+
+func rules(findings []diag.Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.RuleID)
+	}
+	return ids
+}
+
+func TestAnalyzeHonest(t *testing.T) {
+	params := mldsa.ParamsMLDSA44
+	pk, records := honestCorpus(t, params, 200)
+	rep, err := Analyze(pk, records, Options{Reference: 128})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Valid != 200 || rep.DistinctW1 != 200 || rep.Invalid != 0 || rep.Malformed != 0 || len(rep.Findings) != 0 {
+		t.Errorf("report %+v, findings %v", rep, rules(rep.Findings))
+	}
+	if len(rep.Tests) != 4 {
+		t.Fatalf("tests %+v", rep.Tests)
+	}
+	for _, tc := range rep.Tests {
+		if tc.Skipped || tc.Fail || tc.DF == 0 {
+			t.Errorf("%+v", tc)
+		}
+	}
+	if rep.HintWeights.Max > params.Omega || rep.HintWeights.Mean <= 0 {
+		t.Errorf("hint weights %+v", rep.HintWeights)
+	}
+}
+
+func TestAnalyzeDuplicates(t *testing.T) {
+	pk, records := honestCorpus(t, mldsa.ParamsMLDSA44, 4)
+	// A stuck generator repeats the hedged signature for the same message.
+	dup := records[1]
+	dup.Line = 5
+	// A cached signature is released for another message.
+	cached := Record{Msg: []byte("another message"), Sig: records[2].Sig, Line: 6}
+	records = append(records, dup, cached)
+
+	rep, err := Analyze(pk, records, Options{Reference: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(rules(rep.Findings), ",")
+	want := strings.Join([]string{diag.RuleChallengeReuse, diag.RuleSigGenError, diag.RuleDuplicateSig}, ",")
+	if got != want || rep.Invalid != 1 {
+		t.Errorf("findings %s (want %s), invalid %d", got, want, rep.Invalid)
+	}
+
+	// Deterministic signers repeat themselves by design.
+	rep, err = Analyze(pk, records[:5], Options{Reference: 8, Deterministic: true})
+	if err != nil || len(rep.Findings) != 0 {
+		t.Errorf("deterministic: err=%v findings %v", err, rules(rep.Findings))
+	}
+
+	records[0].Sig = records[0].Sig[1:]
+	if rep, err = Analyze(pk, records[:1], Options{Reference: 8}); err != nil || rep.Malformed != 1 {
+		t.Errorf("truncated: err=%v report %+v", err, rep)
+	}
+}
+
+// zeroT1Key returns a public key with t₁ = 0. Under it w1′ = UseHint(h, Az)
+// does not depend on the challenge, so valid signatures can be made for
+// any z without the secret key.
+func zeroT1Key(params *mldsa.Params) []byte {
+	pk := make([]byte, params.PKBytes)
+	copy(pk, bytes.Repeat([]byte{0x17}, mldsa.SeedBytes))
+	return pk
+}
+
+// forge returns a valid signature on msg under zeroT1Key with the given z
+// and no hints.
+func forge(t *testing.T, params *mldsa.Params, pk, msg []byte, z [][]int32) []byte {
+	t.Helper()
+	sig := make([]byte, 0, params.SigBytes)
+	sig = append(sig, make([]byte, params.CTildeBytes())...)
+	for _, p := range z {
+		vals := make([]uint32, poly.N)
+		for j, v := range p {
+			vals[j] = uint32(int32(params.Gamma1) - v)
+		}
+		b, err := pack.PackBits(vals, params.Gamma1Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = append(sig, b...)
+	}
+	sig = append(sig, make([]byte, params.Omega+params.K)...)
+
+	tr := capture{}
+	if _, err := mldsa.VerifyTraced(pk, msg, sig, tr); err != nil {
+		t.Fatal(err)
+	}
+	hash.SumShake256(sig[:params.CTildeBytes()], tr[mldsa.TraceMu].Bytes, tr[mldsa.TraceW1Encode].Bytes)
+	if ok, err := mldsa.Verify(pk, msg, sig); !ok || err != nil {
+		t.Fatalf("forged signature does not verify: %v", err)
+	}
+	return sig
+}
+
+type capture map[mldsa.TraceStep]mldsa.TraceValue
+
+func (c capture) Trace(step mldsa.TraceStep, v mldsa.TraceValue) { c[step] = v }
+
+func TestAnalyzeNonceReuse(t *testing.T) {
+	params := mldsa.ParamsMLDSA44
+	pk := zeroT1Key(params)
+	z := make([][]int32, params.L)
+	for i := range z {
+		z[i] = make([]int32, poly.N)
+		z[i][i] = 5
+	}
+	records := []Record{
+		{Msg: []byte("first"), Sig: forge(t, params, pk, []byte("first"), z), Line: 1},
+		{Msg: []byte("second"), Sig: forge(t, params, pk, []byte("second"), z), Line: 2},
+	}
+	rep, err := Analyze(pk, records, Options{Reference: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Valid != 2 || rep.DistinctW1 != 1 || len(rep.Findings) == 0 || rep.Findings[0].RuleID != diag.RuleNonceReuse ||
+		rep.Findings[0].Evidence[0] != "lines 1 and 2" {
+		t.Errorf("report %+v", rep)
+	}
+}
+
+func TestAnalyzeBiasedZ(t *testing.T) {
+	params := mldsa.ParamsMLDSA44
+	pk := zeroT1Key(params)
+	rng := rand.New(rand.NewSource(3))
+	// A mask sampler that drops the top bit only ever yields half the range.
+	half := int32(params.Gamma1-params.Beta) / 2
+	var records []Record
+	for i := 0; i < 20; i++ {
+		z := make([][]int32, params.L)
+		for p := range z {
+			z[p] = make([]int32, poly.N)
+			for j := range z[p] {
+				z[p][j] = rng.Int31n(2*half) - half
+			}
+		}
+		msg := []byte(fmt.Sprintf("message %d", i))
+		records = append(records, Record{Msg: msg, Sig: forge(t, params, pk, msg, z), Line: i + 1})
+	}
+	rep, err := Analyze(pk, records, Options{Reference: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Tests[0].Name != TestZ || !rep.Tests[0].Fail || rules(rep.Findings)[0] != diag.RuleZDistribution {
+		t.Errorf("tests %+v, findings %v", rep.Tests, rules(rep.Findings))
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	in := fmt.Sprintf("{\"msg\":\"6869\",\"sig\":\"%s\"}\n\n{\"msg\":\"\",\"sig\":\"00\"}\n", hex.EncodeToString([]byte{1, 2}))
+	records, err := ReadJSONL(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || string(records[0].Msg) != "hi" || records[1].Line != 3 || len(records[1].Msg) != 0 {
+		t.Errorf("records %+v", records)
+	}
+	for _, bad := range []string{"{", `{"msg":"00"}`, `{"msg":"zz","sig":"00"}`} {
+		if _, err := ReadJSONL(strings.NewReader(bad)); !errors.Is(err, ErrFormat) {
+			t.Errorf("%q: err = %v", bad, err)
+		}
+	}
+	if _, err := Analyze(nil, nil, Options{}); !errors.Is(err, ErrEmpty) {
+		t.Errorf("empty: err = %v", err)
+	}
+}
+
+func TestChiSquareP(t *testing.T) {
+	// Upper quantiles of the χ² distribution.
+	for _, tc := range []struct {
+		stat float64
+		df   int
+		p    float64
+	}{
+		{3.841, 1, 0.05},
+		{18.307, 10, 0.05},
+		{6.635, 1, 0.01},
+		{82.529, 63, 0.05},
+		{0, 5, 1},
+	} {
+		if got := ChiSquareP(tc.stat, tc.df); math.Abs(got-tc.p) > 1e-3 {
+			t.Errorf("ChiSquareP(%v, %d) = %v, want %v", tc.stat, tc.df, got, tc.p)
+		}
+	}
+	// Sparse bins merge until each expects five samples.
+	if _, df, _ := ChiSquare([]float64{1, 1, 1, 1, 1, 1}, []float64{1, 1, 1, 1, 1, 1}); df != 0 {
+		t.Errorf("df = %d, want 0", df)
+	}
+}
@@ -0,0 +1,247 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package signstats compares the rejection loop of the built-in signer
+// with its theoretical behaviour. The z check passes with a probability
+// that follows exactly from y being uniform (ExpandMask), and the r₀
+// check with one that follows from Decompose of a uniform value mod q;
+// both are computed here from the FIPS 204 definitions, independently of
+// the signer, so a bug in either function shifts the observed rates away
+// from the model.
+package signstats
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/hash"
+	"github.com/codethor0/dilivet/code/poly"
+	"github.com/codethor0/dilivet/code/sigcorpus"
+)
+
+// Tests run on every parameter set.
+const (
+	TestZRate      = "z-rate"     // z-norm rejections among all iterations
+	TestR0Rate     = "r0-rate"    // r0-norm rejections among iterations that passed z
+	TestIterations = "iterations" // iterations per signature against the geometric law
+)
+
+// ErrSign reports a signing call that failed.
+var ErrSign = errors.New("signstats: signing failed")
+
+// Model holds the per-iteration pass probabilities of the z and r₀
+// checks. The ct₀ and hint checks have no closed form; they reject rarely
+// and their rate is taken from the run.
+type Model struct {
+	ZPass  float64 `json:"zPass"`
+	R0Pass float64 `json:"r0Pass"`
+}
+
+// NewModel computes the model for params. A coefficient of y is uniform
+// over 2γ₁ values and passes whatever c·s₁ adds to it for 2(γ₁−β)−1 of
+// them; r₀ = LowBits of a uniform value mod q is counted exactly,
+// including the r − r₀ = q − 1 corner of Decompose. Coefficients are
+// treated as independent.
+func NewModel(params *mldsa.Params) Model {
+	zCoeff := float64(2*(params.Gamma1-params.Beta)-1) / float64(2*params.Gamma1)
+
+	alpha := int64(2 * params.Gamma2)
+	bound := int64(params.Gamma2 - params.Beta)
+	pass := 0
+	for r := int64(0); r < poly.Q; r++ {
+		r0 := r % alpha
+		if r0 > alpha/2 {
+			r0 -= alpha
+		}
+		if r-r0 == poly.Q-1 {
+			r0--
+		}
+		if r0 < bound && -r0 < bound {
+			pass++
+		}
+	}
+	r0Coeff := float64(pass) / poly.Q
+
+	return Model{
+		ZPass:  math.Pow(zCoeff, float64(poly.N*params.L)),
+		R0Pass: math.Pow(r0Coeff, float64(poly.N*params.K)),
+	}
+}
+
+// Options tunes Run; zero fields take defaults.
+type Options struct {
+	Signatures int     // signatures to make (default 10000)
+	Workers    int     // signing goroutines (default runtime.NumCPU())
+	Seed       int64   // seed for the key, messages and rnd
+	Alpha      float64 // significance level (default 0.001)
+}
+
+// Result is the outcome for one parameter set.
+type Result struct {
+	ParameterSet string `json:"parameterSet"`
+	Signatures   int    `json:"signatures"`
+	Iterations   int    `json:"iterations"`
+	// Histogram counts signatures by iterations; index 0 is one iteration.
+	Histogram  []int                      `json:"histogram"`
+	Rejections map[mldsa.RejectReason]int `json:"rejections"`
+	Model      Model                      `json:"model"`
+	// Mean and Expected are the observed and modelled iterations per
+	// signature; Expected uses the observed ct₀ and hint rate.
+	Mean     float64          `json:"mean"`
+	Expected float64          `json:"expected"`
+	Tests    []sigcorpus.Test `json:"tests"`
+}
+
+// Fail reports whether any test failed.
+func (r *Result) Fail() bool {
+	for _, t := range r.Tests {
+		if t.Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// collector accumulates SignStats; each worker has its own.
+type collector struct {
+	hist       []int
+	rejections map[mldsa.RejectReason]int
+}
+
+func (c *collector) ObserveSign(s mldsa.SignStats) {
+	for len(c.hist) < s.Iterations {
+		c.hist = append(c.hist, 0)
+	}
+	c.hist[s.Iterations-1]++
+	for _, r := range s.Rejections {
+		c.rejections[r]++
+	}
+}
+
+// Run signs opts.Signatures messages with the built-in signer under one
+// key and tests the rejection loop against NewModel. The counts do not
+// depend on opts.Workers.
+func Run(params *mldsa.Params, opts Options) (*Result, error) {
+	if opts.Signatures <= 0 {
+		opts.Signatures = 10000
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Alpha <= 0 {
+		opts.Alpha = 0.001
+	}
+	seed := make([]byte, 8)
+	binary.LittleEndian.PutUint64(seed, uint64(opts.Seed))
+	keySeed := make([]byte, mldsa.SeedBytes)
+	hash.SumShake256(keySeed, []byte("signstats key"), seed)
+	_, sk, err := mldsa.KeyGen(params, keySeed)
+	if err != nil {
+		return nil, err
+	}
+
+	collectors := make([]*collector, opts.Workers)
+	errs := make([]error, opts.Workers)
+	var wg sync.WaitGroup
+	for w := range collectors {
+		c := &collector{rejections: map[mldsa.RejectReason]int{}}
+		collectors[w] = c
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			idx := make([]byte, 8)
+			msg := make([]byte, 32)
+			rnd := make([]byte, mldsa.RndBytes)
+			for i := w; i < opts.Signatures; i += opts.Workers {
+				binary.LittleEndian.PutUint64(idx, uint64(i))
+				hash.SumShake256(msg, []byte("signstats msg"), seed, idx)
+				hash.SumShake256(rnd, []byte("signstats rnd"), seed, idx)
+				if _, err := mldsa.SignWithStats(sk, msg, rnd, c); err != nil {
+					errs[w] = fmt.Errorf("%w: signature %d: %w", ErrSign, i, err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{ParameterSet: params.Name, Signatures: opts.Signatures, Rejections: map[mldsa.RejectReason]int{}, Model: NewModel(params)}
+	for _, c := range collectors {
+		for i, n := range c.hist {
+			for len(res.Histogram) <= i {
+				res.Histogram = append(res.Histogram, 0)
+			}
+			res.Histogram[i] += n
+			res.Iterations += n * (i + 1)
+		}
+		for r, n := range c.rejections {
+			res.Rejections[r] += n
+		}
+	}
+	res.Mean = float64(res.Iterations) / float64(res.Signatures)
+	res.evaluate(opts.Alpha)
+	return res, nil
+}
+
+// evaluate runs the tests. The rates are binomial tests written as χ²
+// over two cells; the iteration counts are tested against a geometric
+// law whose rate uses the observed ct₀ and hint rejections, which costs
+// one degree of freedom.
+func (r *Result) evaluate(alpha float64) {
+	zRej := r.Rejections[mldsa.RejectZNorm]
+	r.binomial(TestZRate, zRej, r.Iterations, 1-r.Model.ZPass, alpha)
+	passedZ := r.Iterations - zRej
+	r0Rej := r.Rejections[mldsa.RejectR0Norm]
+	r.binomial(TestR0Rate, r0Rej, passedZ, 1-r.Model.R0Pass, alpha)
+
+	passedR0 := passedZ - r0Rej
+	other := r.Rejections[mldsa.RejectCT0Norm] + r.Rejections[mldsa.RejectHintCount]
+	hintPass := 1.0
+	if passedR0 > 0 {
+		hintPass = 1 - float64(other)/float64(passedR0)
+	}
+	p := r.Model.ZPass * r.Model.R0Pass * hintPass
+	r.Expected = 1 / p
+
+	observed := make([]float64, len(r.Histogram))
+	expected := make([]float64, len(r.Histogram))
+	for i, n := range r.Histogram {
+		observed[i] = float64(n)
+		expected[i] = float64(r.Signatures) * p * math.Pow(1-p, float64(i))
+	}
+	// The last bin takes the whole tail.
+	if k := len(expected) - 1; k >= 0 {
+		expected[k] = float64(r.Signatures) * math.Pow(1-p, float64(k))
+	}
+	stat, df, _ := sigcorpus.ChiSquare(observed, expected)
+	t := sigcorpus.Test{Name: TestIterations, Samples: r.Signatures, Statistic: stat, DF: df - 1, P: 1}
+	t.Skipped = t.DF <= 0
+	if !t.Skipped {
+		t.P = sigcorpus.ChiSquareP(stat, t.DF)
+	}
+	r.record(t, alpha)
+}
+
+// binomial tests k successes out of n trials against probability p.
+func (r *Result) binomial(name string, k, n int, p, alpha float64) {
+	stat, df, pv := sigcorpus.ChiSquare(
+		[]float64{float64(k), float64(n - k)},
+		[]float64{float64(n) * p, float64(n) * (1 - p)},
+	)
+	r.record(sigcorpus.Test{Name: name, Samples: n, Statistic: stat, DF: df, P: pv, Skipped: df == 0}, alpha)
+}
+
+func (r *Result) record(t sigcorpus.Test, alpha float64) {
+	t.Fail = !t.Skipped && t.P < alpha
+	r.Tests = append(r.Tests, t)
+}
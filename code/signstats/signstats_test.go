@@ -0,0 +1,75 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package signstats
+
+import (
+	"math"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+)
+
+func TestNewModel(t *testing.T) {
+	// FIPS 204 Table 1 lists the expected number of repetitions; the ct₀
+	// and hint checks add little on top of the z and r₀ checks.
+	for _, tc := range []struct {
+		params *mldsa.Params
+		want   float64
+	}{
+		{mldsa.ParamsMLDSA44, 4.25},
+		{mldsa.ParamsMLDSA65, 5.1},
+		{mldsa.ParamsMLDSA87, 3.85},
+	} {
+		m := NewModel(tc.params)
+		got := 1 / (m.ZPass * m.R0Pass)
+		if math.Abs(got-tc.want)/tc.want > 0.03 {
+			t.Errorf("%s: expected iterations %.2f, FIPS 204 says %.2f", tc.params.Name, got, tc.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	params := mldsa.ParamsMLDSA44
+	a, err := Run(params, Options{Signatures: 400, Workers: 3, Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Run(params, Options{Signatures: 400, Workers: 1, Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Iterations != b.Iterations || a.Rejections[mldsa.RejectZNorm] != b.Rejections[mldsa.RejectZNorm] {
+		t.Errorf("worker count changed the counts: %d vs %d iterations", a.Iterations, b.Iterations)
+	}
+	rejected := 0
+	for _, n := range a.Rejections {
+		rejected += n
+	}
+	if a.Iterations != a.Signatures+rejected || len(a.Tests) != 3 || a.Fail() {
+		t.Errorf("result %+v", a)
+	}
+	for _, tc := range a.Tests {
+		if tc.Skipped {
+			t.Errorf("%+v", tc)
+		}
+	}
+}
+
+func TestEvaluateFlagsBias(t *testing.T) {
+	// A z check that rejects half again as often as the model allows, as a
+	// mask sampler with a narrowed range would.
+	m := NewModel(mldsa.ParamsMLDSA44)
+	r := &Result{Signatures: 1000, Model: m, Rejections: map[mldsa.RejectReason]int{}}
+	r.Histogram = []int{100, 90, 81, 73, 66, 59, 53, 48, 43, 387}
+	for i, n := range r.Histogram {
+		r.Iterations += n * (i + 1)
+	}
+	rejected := r.Iterations - r.Signatures
+	r.Rejections[mldsa.RejectZNorm] = rejected * 9 / 10
+	r.Rejections[mldsa.RejectR0Norm] = rejected - r.Rejections[mldsa.RejectZNorm]
+	r.evaluate(0.001)
+	if !r.Fail() || !r.Tests[0].Fail || r.Tests[0].Name != TestZRate {
+		t.Errorf("tests %+v", r.Tests)
+	}
+}
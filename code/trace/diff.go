@@ -0,0 +1,132 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// Comparison statuses of a step.
+const (
+	StatusMatch      = "match"
+	StatusDiffers    = "differs"
+	StatusOnlyOurs   = "only-ours"
+	StatusOnlyTheirs = "only-theirs"
+)
+
+// StepDiff is the comparison of one step present in either trace.
+// Location pins the first difference, e.g. "Â row 2 column 1 coefficient
+// 17" or "byte 5"; Ours and Theirs hold the values found there.
+type StepDiff struct {
+	Step     mldsa.TraceStep `json:"step"`
+	Status   string          `json:"status"`
+	Location string          `json:"location,omitempty"`
+	Ours     string          `json:"ours,omitempty"`
+	Theirs   string          `json:"theirs,omitempty"`
+}
+
+// Diff compares two traces step by step, in verification order.
+// Coefficients are compared mod q, so either side may use centred or
+// [0, q) representatives.
+func Diff(ours, theirs *File) []StepDiff {
+	l := 0
+	switch {
+	case ours.ParameterSet != nil:
+		l = ours.ParameterSet.L
+	case theirs.ParameterSet != nil:
+		l = theirs.ParameterSet.L
+	}
+	var out []StepDiff
+	for _, name := range mldsa.TraceSteps {
+		a, inOurs := ours.Lookup(name)
+		b, inTheirs := theirs.Lookup(name)
+		switch {
+		case !inOurs && !inTheirs:
+			continue
+		case !inTheirs:
+			out = append(out, StepDiff{Step: name, Status: StatusOnlyOurs})
+		case !inOurs:
+			out = append(out, StepDiff{Step: name, Status: StatusOnlyTheirs})
+		default:
+			out = append(out, compareStep(a, b, l))
+		}
+	}
+	return out
+}
+
+// FirstDivergence returns the earliest step that differs, or nil when
+// every step present in both traces matches.
+func FirstDivergence(diffs []StepDiff) *StepDiff {
+	for i := range diffs {
+		if diffs[i].Status == StatusDiffers {
+			return &diffs[i]
+		}
+	}
+	return nil
+}
+
+func compareStep(a, b Step, l int) StepDiff {
+	d := StepDiff{Step: a.Step, Status: StatusMatch}
+	if a.Hex != "" || b.Hex != "" {
+		if a.Hex == "" || b.Hex == "" {
+			d.Status, d.Location = StatusDiffers, "bytes in one trace, polynomials in the other"
+			return d
+		}
+		// Both were validated as hex by Load.
+		x, _ := hex.DecodeString(a.Hex)
+		y, _ := hex.DecodeString(b.Hex)
+		i := firstByteDiff(x, y)
+		switch {
+		case i < 0:
+		case i >= len(x) || i >= len(y):
+			d.Status, d.Location = StatusDiffers, fmt.Sprintf("length %d vs %d bytes", len(x), len(y))
+		default:
+			d.Status, d.Location = StatusDiffers, fmt.Sprintf("byte %d", i)
+			d.Ours, d.Theirs = fmt.Sprintf("%02x", x[i]), fmt.Sprintf("%02x", y[i])
+		}
+		return d
+	}
+
+	if len(a.Polys) != len(b.Polys) {
+		d.Status, d.Location = StatusDiffers, fmt.Sprintf("%d vs %d polynomials", len(a.Polys), len(b.Polys))
+		return d
+	}
+	for i := range a.Polys {
+		if len(a.Polys[i]) != len(b.Polys[i]) {
+			d.Status = StatusDiffers
+			d.Location = fmt.Sprintf("%s has %d vs %d coefficients", polyName(a.Step, i, l), len(a.Polys[i]), len(b.Polys[i]))
+			return d
+		}
+		for j := range a.Polys[i] {
+			if modQ(a.Polys[i][j]) != modQ(b.Polys[i][j]) {
+				d.Status = StatusDiffers
+				d.Location = fmt.Sprintf("%s coefficient %d", polyName(a.Step, i, l), j)
+				d.Ours, d.Theirs = fmt.Sprint(a.Polys[i][j]), fmt.Sprint(b.Polys[i][j])
+				return d
+			}
+		}
+	}
+	return d
+}
+
+// polyName names polynomial i of a step; Â entries are named by row and
+// column when l is known.
+func polyName(step mldsa.TraceStep, i, l int) string {
+	if step == mldsa.TraceAHat && l > 0 {
+		return fmt.Sprintf("Â row %d column %d", i/l, i%l)
+	}
+	return fmt.Sprintf("%s[%d]", step, i)
+}
+
+func modQ(c int32) int64 {
+	v := int64(c) % poly.Q
+	if v < 0 {
+		v += poly.Q
+	}
+	return v
+}
@@ -0,0 +1,239 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package trace records the intermediate values of an ML-DSA verification
+// in a JSON file format (docs/trace-format.md) and compares two such
+// traces to locate the first value where implementations diverge.
+package trace
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+// SchemaVersion is the version of the trace file format. Files without a
+// schemaVersion are read as this version.
+const SchemaVersion = 1
+
+// ErrFormat reports a trace file that does not follow the schema.
+var ErrFormat = errors.New("trace: invalid trace file")
+
+// File is a trace of one verification.
+type File struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	ParameterSet  *ParameterSet `json:"parameterSet,omitempty"`
+	Interface     string        `json:"interface,omitempty"`
+	Valid         bool          `json:"valid"`
+	Error         string        `json:"error,omitempty"`
+	Steps         []Step        `json:"steps"`
+}
+
+// ParameterSet annotates a trace with the ML-DSA parameters it ran under.
+type ParameterSet struct {
+	Name     string `json:"name"`
+	K        int    `json:"k"`
+	L        int    `json:"l"`
+	Eta      int    `json:"eta"`
+	Beta     int    `json:"beta"`
+	Gamma1   int    `json:"gamma1"`
+	Gamma2   int    `json:"gamma2"`
+	Tau      int    `json:"tau"`
+	Omega    int    `json:"omega"`
+	Lambda   int    `json:"lambda"`
+	PKBytes  int    `json:"pkBytes"`
+	SigBytes int    `json:"sigBytes"`
+}
+
+// NewParameterSet copies the annotated fields of p.
+func NewParameterSet(p *mldsa.Params) *ParameterSet {
+	return &ParameterSet{p.Name, p.K, p.L, p.Eta, p.Beta, p.Gamma1, p.Gamma2, p.Tau, p.Omega, p.Lambda, p.PKBytes, p.SigBytes}
+}
+
+// Step is one intermediate value: a byte string as Hex, or a vector of
+// polynomials as Polys. Description and Note are for readers only.
+type Step struct {
+	Step        mldsa.TraceStep `json:"step"`
+	Description string          `json:"description,omitempty"`
+	Note        string          `json:"note,omitempty"`
+	Hex         string          `json:"hex,omitempty"`
+	Polys       [][]int32       `json:"polys,omitempty"`
+}
+
+// Lookup returns the step named name.
+func (f *File) Lookup(name mldsa.TraceStep) (Step, bool) {
+	for _, s := range f.Steps {
+		if s.Step == name {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// Load reads a trace file, rejecting unknown or repeated step names,
+// steps holding both or neither of hex and polys, and bad hex.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("trace: open: %w", err)
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrFormat, path, err)
+	}
+	if f.SchemaVersion == 0 {
+		f.SchemaVersion = SchemaVersion
+	}
+	if f.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("%w: %s: schemaVersion %d, want %d", ErrFormat, path, f.SchemaVersion, SchemaVersion)
+	}
+	known := map[mldsa.TraceStep]bool{}
+	for _, s := range mldsa.TraceSteps {
+		known[s] = true
+	}
+	seen := map[mldsa.TraceStep]bool{}
+	for i, s := range f.Steps {
+		switch {
+		case !known[s.Step]:
+			return nil, fmt.Errorf("%w: %s: step %d: unknown step %q", ErrFormat, path, i, s.Step)
+		case seen[s.Step]:
+			return nil, fmt.Errorf("%w: %s: step %q listed twice", ErrFormat, path, s.Step)
+		case (s.Hex == "") == (s.Polys == nil):
+			return nil, fmt.Errorf("%w: %s: step %q needs exactly one of hex and polys", ErrFormat, path, s.Step)
+		}
+		if _, err := hex.DecodeString(s.Hex); err != nil {
+			return nil, fmt.Errorf("%w: %s: step %q: %v", ErrFormat, path, s.Step, err)
+		}
+		seen[s.Step] = true
+	}
+	return &f, nil
+}
+
+// Write encodes f as indented JSON.
+func (f *File) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// Recorder is an mldsa.Tracer that collects annotated steps.
+type Recorder struct {
+	params *mldsa.Params
+	ctilde []byte
+	Steps  []Step
+}
+
+// NewRecorder returns a Recorder annotating values against params.
+func NewRecorder(params *mldsa.Params) *Recorder {
+	return &Recorder{params: params}
+}
+
+// Trace implements mldsa.Tracer.
+func (r *Recorder) Trace(step mldsa.TraceStep, v mldsa.TraceValue) {
+	p := r.params
+	s := Step{Step: step, Polys: v.Polys}
+	if v.Bytes != nil {
+		s.Hex = hex.EncodeToString(v.Bytes)
+	}
+	switch step {
+	case mldsa.TraceMPrime:
+		s.Description = "M′, the formatted message hashed into μ"
+		s.Note = fmt.Sprintf("%d bytes", len(v.Bytes))
+	case mldsa.TraceRho:
+		s.Description = "ρ from pkDecode (FIPS 204 Algorithm 23)"
+	case mldsa.TraceT1:
+		s.Description = fmt.Sprintf("t₁ from pkDecode, k=%d polynomials of %d-bit coefficients", p.K, p.DuBits)
+	case mldsa.TraceCTilde:
+		r.ctilde = v.Bytes
+		s.Description = "c̃ from sigDecode (Algorithm 27)"
+		s.Note = fmt.Sprintf("λ/4 = %d bytes", p.Lambda/4)
+	case mldsa.TraceZ:
+		s.Description = fmt.Sprintf("z from sigDecode, l=%d polynomials, centred", p.L)
+		norm := infNorm(v.Polys)
+		s.Note = fmt.Sprintf("‖z‖∞ = %d, must be < γ1−β = %d", norm, p.Gamma1-p.Beta)
+		if norm >= int32(p.Gamma1-p.Beta) {
+			s.Note += " (out of range)"
+		}
+	case mldsa.TraceHint:
+		s.Description = fmt.Sprintf("h from HintBitUnpack (Algorithm 21), k=%d polynomials of 0/1", p.K)
+		s.Note = fmt.Sprintf("%d ones, at most ω = %d", countNonZero(v.Polys), p.Omega)
+	case mldsa.TraceAHat:
+		s.Description = fmt.Sprintf("Â = ExpandA(ρ) (Algorithm 32), %d×%d row-major, NTT domain", p.K, p.L)
+	case mldsa.TraceTr:
+		s.Description = "tr = H(pk, 64)"
+	case mldsa.TraceMu:
+		s.Description = "μ = H(tr || M′, 64)"
+	case mldsa.TraceC:
+		s.Description = "c = SampleInBall(c̃) (Algorithm 29), centred"
+		s.Note = fmt.Sprintf("%d non-zero coefficients, τ = %d", countNonZero(v.Polys), p.Tau)
+	case mldsa.TraceAz:
+		s.Description = "NTT⁻¹(Â∘NTT(z))"
+	case mldsa.TraceCT1:
+		s.Description = "NTT⁻¹(NTT(c)∘NTT(t₁·2^d)), d = 13"
+	case mldsa.TraceWApprox:
+		s.Description = "w′approx = Az − ct₁·2^d"
+	case mldsa.TraceW1:
+		s.Description = "w′₁ = UseHint(h, w′approx) (Algorithm 40)"
+		s.Note = fmt.Sprintf("coefficients in [0, %d)", (poly.Q-1)/(2*p.Gamma2))
+	case mldsa.TraceW1Encode:
+		s.Description = "w1Encode(w′₁) (Algorithm 28)"
+		s.Note = fmt.Sprintf("%d bytes", len(v.Bytes))
+	case mldsa.TraceCTildePrime:
+		s.Description = "c̃′ = H(μ || w1Encode(w′₁), λ/4)"
+		s.Note = "matches c̃"
+		if i := firstByteDiff(r.ctilde, v.Bytes); i >= 0 {
+			s.Note = fmt.Sprintf("differs from c̃ at byte %d", i)
+		}
+	}
+	r.Steps = append(r.Steps, s)
+}
+
+func infNorm(polys [][]int32) int32 {
+	var norm int32
+	for _, p := range polys {
+		for _, c := range p {
+			if c < 0 {
+				c = -c
+			}
+			if c > norm {
+				norm = c
+			}
+		}
+	}
+	return norm
+}
+
+func countNonZero(polys [][]int32) int {
+	n := 0
+	for _, p := range polys {
+		for _, c := range p {
+			if c != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// firstByteDiff returns the first index at which a and b differ, or -1.
+func firstByteDiff(a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return n
+	}
+	return -1
+}
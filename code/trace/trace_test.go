@@ -0,0 +1,121 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package trace
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/poly"
+)
+
+func record(t *testing.T) *File {
+	t.Helper()
+	params := mldsa.ParamsMLDSA44
+	pk, sk, err := mldsa.KeyGen(params, bytes.Repeat([]byte{0x05}, mldsa.SeedBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("trace")
+	sig, err := mldsa.Sign(sk, msg, make([]byte, mldsa.RndBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := NewRecorder(params)
+	if ok, err := mldsa.VerifyTraced(pk, msg, sig, rec); !ok || err != nil {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	return &File{SchemaVersion: SchemaVersion, ParameterSet: NewParameterSet(params), Valid: true, Steps: rec.Steps}
+}
+
+// roundTrip writes f and loads it back, as a vendor file would arrive.
+func roundTrip(t *testing.T, f *File) *File {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func TestDiff(t *testing.T) {
+	ours := record(t)
+	theirs := roundTrip(t, record(t))
+	if d := FirstDivergence(Diff(ours, theirs)); d != nil {
+		t.Fatalf("identical traces diverge at %+v", d)
+	}
+
+	// A vendor trace with [0, q) representatives, a subset of the steps and
+	// one wrong entry of Â.
+	var steps []Step
+	for _, s := range theirs.Steps {
+		switch s.Step {
+		case mldsa.TraceZ:
+			for _, p := range s.Polys {
+				for j, c := range p {
+					if c < 0 {
+						p[j] = c + poly.Q
+					}
+				}
+			}
+		case mldsa.TraceAHat:
+			s.Polys[2*mldsa.ParamsMLDSA44.L+1][17]++
+		case mldsa.TraceTr, mldsa.TraceW1Encode:
+			continue
+		}
+		steps = append(steps, s)
+	}
+	theirs.Steps = steps
+	theirs.ParameterSet = nil
+
+	diffs := Diff(ours, theirs)
+	status := map[mldsa.TraceStep]string{}
+	for _, d := range diffs {
+		status[d.Step] = d.Status
+	}
+	if status[mldsa.TraceZ] != StatusMatch || status[mldsa.TraceTr] != StatusOnlyOurs || status[mldsa.TraceCTildePrime] != StatusMatch {
+		t.Errorf("statuses %v", status)
+	}
+	first := FirstDivergence(diffs)
+	if first == nil || first.Step != mldsa.TraceAHat || first.Location != "Â row 2 column 1 coefficient 17" {
+		t.Fatalf("first divergence %+v", first)
+	}
+
+	theirs.Steps[0].Hex = "ff" + theirs.Steps[0].Hex[2:]
+	if first := FirstDivergence(Diff(ours, theirs)); first.Step != mldsa.TraceMPrime || first.Location != "byte 0" {
+		t.Errorf("first divergence %+v", first)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	for name, body := range map[string]string{
+		"unknown step": `{"steps":[{"step":"rho2","hex":"00"}]}`,
+		"duplicate":    `{"steps":[{"step":"rho","hex":"00"},{"step":"rho","hex":"00"}]}`,
+		"both":         `{"steps":[{"step":"z","hex":"00","polys":[[1]]}]}`,
+		"neither":      `{"steps":[{"step":"z"}]}`,
+		"bad hex":      `{"steps":[{"step":"rho","hex":"zz"}]}`,
+		"schema":       `{"schemaVersion":7,"steps":[]}`,
+		"syntax":       `{`,
+	} {
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); !errors.Is(err, ErrFormat) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
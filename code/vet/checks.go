@@ -0,0 +1,340 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package vet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	mldsa "github.com/codethor0/dilivet/code/clean"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+	"github.com/codethor0/dilivet/code/kat"
+	"github.com/codethor0/dilivet/code/mutate"
+)
+
+// parameterSets are the sets the edge-message, mutation and timing checks
+// cover.
+var parameterSets = []*mldsa.Params{mldsa.ParamsMLDSA44, mldsa.ParamsMLDSA65, mldsa.ParamsMLDSA87}
+
+// Timing thresholds. The check runs through whatever transport reaches the
+// target (for -impl binaries, a process or pipe round trip per call), so it
+// only catches gross differences; it is no substitute for a constant-time
+// analysis.
+const (
+	timingRatio = 1.5
+	slowVerify  = time.Second
+)
+
+func label(tgID, tcID int) string { return fmt.Sprintf("tgId=%d tcId=%d", tgID, tcID) }
+
+func take(n, perGroup int) int {
+	if perGroup > 0 && n > perGroup {
+		return perGroup
+	}
+	return n
+}
+
+// acvpCases turns kats case outcomes into checks, failing each failed case
+// under the rule kats assigned it. Decode errors concern the bundled
+// vectors rather than the target and are skipped.
+func (c *collector) acvpCases(res *kats.Result) {
+	for _, cr := range res.Cases {
+		switch cr.Outcome {
+		case kats.OutcomePass:
+			c.pass()
+		case kats.OutcomeFail:
+			c.fail(cr.RuleID, label(cr.GroupID, cr.CaseID)+": "+cr.Reason)
+		default:
+			c.skip()
+		}
+	}
+}
+
+func checkKeyGen(c *collector, t *target, opts Options) error {
+	vectors, err := kats.LoadKeyGenVectors("")
+	if err != nil {
+		return err
+	}
+	for i := range vectors.TestGroups {
+		tg := &vectors.TestGroups[i]
+		tg.Tests = tg.Tests[:take(len(tg.Tests), opts.PerGroup)]
+	}
+	c.acvpCases(kats.RunKeyGen(vectors, t))
+	return nil
+}
+
+func checkSigGen(c *collector, t *target, opts Options) error {
+	vectors, err := kats.LoadSigGenVectors("")
+	if err != nil {
+		return err
+	}
+	for i := range vectors.TestGroups {
+		tg := &vectors.TestGroups[i]
+		tg.Tests = tg.Tests[:take(len(tg.Tests), opts.PerGroup)]
+	}
+	c.acvpCases(kats.RunSigGen(vectors, t))
+	return nil
+}
+
+func checkSigVer(c *collector, t *target, opts Options) error {
+	vectors, err := kats.LoadSigVerVectors("")
+	if err != nil {
+		return err
+	}
+	for i := range vectors.TestGroups {
+		tg := &vectors.TestGroups[i]
+		tg.Tests = tg.Tests[:take(len(tg.Tests), opts.PerGroup)]
+	}
+	c.acvpCases(kats.RunSigVer(vectors, t))
+	return nil
+}
+
+// Interfaces the edge, mutation and timing checks sign and verify through.
+const (
+	InterfaceInternal = "internal" // Sign_internal / Verify_internal
+	InterfaceExternal = "external" // ML-DSA.Sign / ML-DSA.Verify with an empty context
+)
+
+// target is the implementation under test with the interface each of its
+// operations answers on.
+type target struct {
+	kats.Implementation
+	sign, verify string
+}
+
+// probe picks the interfaces of impl. Signing and verification use the
+// internal functions unless impl reports them unsupported and answers the
+// external ones, the usual shape of a library that only exposes FIPS 204
+// Algorithms 2 and 3.
+func probe(impl kats.Implementation) (*target, error) {
+	t := &target{Implementation: impl, sign: InterfaceInternal, verify: InterfaceInternal}
+	ref, err := newReference(mldsa.ParamsMLDSA44, 0x5e, []byte("dilivet vet probe"), InterfaceInternal)
+	if err != nil {
+		return nil, err
+	}
+	rnd := make([]byte, mldsa.RndBytes)
+	if _, err := impl.SignInternal(ref.sk, ref.msg, rnd); errors.Is(err, kats.ErrUnsupported) {
+		if _, err := impl.SignExternal(ref.sk, ref.msg, nil, rnd); !errors.Is(err, kats.ErrUnsupported) {
+			t.sign = InterfaceExternal
+		}
+	}
+	if _, err := impl.VerifyInternal(ref.pk, ref.msg, ref.sig); errors.Is(err, kats.ErrUnsupported) {
+		if _, err := impl.VerifyExternal(ref.pk, ref.msg, nil, ref.sig); !errors.Is(err, kats.ErrUnsupported) {
+			t.verify = InterfaceExternal
+		}
+	}
+	return t, nil
+}
+
+func (t *target) signMsg(sk, msg, rnd []byte) ([]byte, error) {
+	if t.sign == InterfaceExternal {
+		return t.SignExternal(sk, msg, nil, rnd)
+	}
+	return t.SignInternal(sk, msg, rnd)
+}
+
+func (t *target) verifyMsg(pk, msg, sig []byte) (bool, error) {
+	if t.verify == InterfaceExternal {
+		return t.VerifyExternal(pk, msg, nil, sig)
+	}
+	return t.VerifyInternal(pk, msg, sig)
+}
+
+// referenceVerify is the built-in verifier for iface.
+func referenceVerify(iface string) mutate.VerifyFunc {
+	if iface == InterfaceExternal {
+		return func(pk, msg, sig []byte) (bool, error) { return mldsa.VerifyWithContext(pk, msg, nil, sig) }
+	}
+	return mldsa.Verify
+}
+
+// reference is a key pair and message signed by the built-in
+// implementation through one interface.
+type reference struct {
+	pk, sk  []byte
+	msg     []byte
+	sig     []byte
+	verdict error // the built-in verifier's error on sig, if any
+}
+
+func newReference(params *mldsa.Params, tag byte, msg []byte, iface string) (*reference, error) {
+	pk, sk, err := mldsa.KeyGen(params, bytes.Repeat([]byte{tag}, mldsa.SeedBytes))
+	if err != nil {
+		return nil, err
+	}
+	rnd := make([]byte, mldsa.RndBytes)
+	var sig []byte
+	if iface == InterfaceExternal {
+		sig, err = mldsa.SignWithContext(sk, msg, nil, rnd)
+	} else {
+		sig, err = mldsa.Sign(sk, msg, rnd)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, verr := referenceVerify(iface)(pk, msg, sig)
+	return &reference{pk: pk, sk: sk, msg: msg, sig: sig, verdict: verr}, nil
+}
+
+// checkEdge has the target sign every edge message deterministically and
+// compares the bytes with the reference, then has it verify the reference
+// signature. Messages the reference verifier itself refuses (the empty
+// message, internally) are not verified.
+func checkEdge(c *collector, t *target, _ Options) error {
+	for _, params := range parameterSets {
+		for i, msg := range kat.EdgeMsgs {
+			tag := byte(0xe0 + i)
+			signRef, err := newReference(params, tag, msg, t.sign)
+			if err != nil {
+				return err
+			}
+			verifyRef := signRef
+			if t.verify != t.sign {
+				if verifyRef, err = newReference(params, tag, msg, t.verify); err != nil {
+					return err
+				}
+			}
+			example := fmt.Sprintf("EdgeMsgs[%d] %s (%d bytes)", i, params.Name, len(msg))
+
+			sig, err := t.signMsg(signRef.sk, msg, make([]byte, mldsa.RndBytes))
+			if c.call(err, "signing", example) {
+				if bytes.Equal(sig, signRef.sig) {
+					c.pass()
+				} else {
+					c.fail(diag.RuleDeterministicSign, example)
+				}
+			}
+
+			if verifyRef.verdict != nil {
+				c.skip()
+				continue
+			}
+			ok, err := t.verifyMsg(verifyRef.pk, msg, verifyRef.sig)
+			if c.call(err, "verification", example) {
+				if ok {
+					c.pass()
+				} else {
+					c.fail(diag.RuleRejectValid, example)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkMutations feeds the target every mutate case for one reference
+// signature per parameter set. Accepting a mutated signature breaks the
+// rule the mutation guards; an error counts as a rejection unless the
+// target crashed or hung.
+func checkMutations(c *collector, t *target, _ Options) error {
+	for i, params := range parameterSets {
+		ref, err := newReference(params, byte(0xa0+i), []byte("dilivet vet mutation message"), t.verify)
+		if err != nil {
+			return err
+		}
+		cases, err := mutate.Generate(ref.pk, ref.msg, ref.sig, referenceVerify(t.verify))
+		if err != nil {
+			return err
+		}
+		for _, mc := range cases {
+			example := mc.Name + " [" + params.Name + "]"
+			ok, err := t.verifyMsg(mc.PK, ref.msg, mc.Sig)
+			if mc.Expected == mutate.ExpectAccept {
+				if !c.call(err, "verification", example) {
+					continue
+				}
+				if ok {
+					c.pass()
+				} else {
+					c.fail(diag.RuleRejectValid, example)
+				}
+				continue
+			}
+			switch {
+			case isCallFailure(err):
+				c.call(err, "verification", example)
+			case ok:
+				c.fail(mc.Rule, example)
+			default:
+				c.pass()
+			}
+		}
+	}
+	return nil
+}
+
+// checkTiming compares the median time the target takes to verify a valid
+// signature with the median for the same signature with c_tilde flipped,
+// which a correct verifier can only reject after the full computation.
+// Samples alternate between the two inputs so drift hits both alike.
+func checkTiming(c *collector, t *target, opts Options) error {
+	if opts.TimingSamples <= 0 {
+		c.skip()
+		return nil
+	}
+	for i, params := range parameterSets {
+		ref, err := newReference(params, byte(0xc0+i), []byte("dilivet vet timing message"), t.verify)
+		if err != nil {
+			return err
+		}
+		bad := append([]byte(nil), ref.sig...)
+		bad[0] ^= 0x01
+		example := params.Name
+
+		var valid, invalid []time.Duration
+		failed := false
+		for n := 0; n < opts.TimingSamples && !failed; n++ {
+			for _, in := range []struct {
+				sig   []byte
+				times *[]time.Duration
+			}{{ref.sig, &valid}, {bad, &invalid}} {
+				start := time.Now()
+				_, err := t.verifyMsg(ref.pk, ref.msg, in.sig)
+				elapsed := time.Since(start)
+				if isCallFailure(err) {
+					c.call(err, "verification", example)
+					failed = true
+					break
+				}
+				*in.times = append(*in.times, elapsed)
+			}
+		}
+		if failed {
+			continue
+		}
+
+		mv, mi := median(valid), median(invalid)
+		detail := fmt.Sprintf("%s: median %s valid, %s invalid over %d samples", example, mv, mi, len(valid))
+		if slow, fast := max(mv, mi), min(mv, mi); fast > 0 && float64(slow)/float64(fast) > timingRatio {
+			c.fail(diag.RuleTimingValidity, detail)
+		} else {
+			c.pass()
+		}
+		if max(mv, mi) > slowVerify {
+			c.note(diag.RuleSlowVerify, detail)
+		}
+	}
+	return nil
+}
+
+// isCallFailure reports whether err means the target did not answer at
+// all (a crash, a timeout or an unsupported entry point) rather than
+// refusing the input.
+func isCallFailure(err error) bool {
+	return errors.Is(err, kats.ErrUnsupported) || errors.Is(err, execsign.ErrCrashed) || errors.Is(err, execsign.ErrTimeout)
+}
+
+func median(d []time.Duration) time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+	s := append([]time.Duration(nil), d...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s[len(s)/2]
+}
@@ -0,0 +1,93 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package vet
+
+import (
+	"html/template"
+	"io"
+)
+
+// WriteHTML renders s as a self-contained HTML page: styles are inline and
+// nothing is loaded from elsewhere, so the file can be mailed or archived
+// as is.
+func WriteHTML(w io.Writer, s *Scorecard) error {
+	return htmlReport.Execute(w, s)
+}
+
+var htmlReport = template.Must(template.New("vet").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>DiliVet scorecard: {{.Target}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+h1 { font-size: 1.5em; }
+.grade { display: inline-block; font-size: 3em; font-weight: bold; width: 1.6em; height: 1.6em; line-height: 1.6em; text-align: center; border-radius: 0.2em; color: #fff; margin-right: 0.4em; vertical-align: middle; }
+.grade-A, .grade-B { background: #2e7d32; }
+.grade-C, .grade-D { background: #ef6c00; }
+.grade-F { background: #c62828; }
+.grade-I { background: #757575; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0 2em; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+td.num { text-align: right; }
+.status-pass { color: #2e7d32; font-weight: bold; }
+.status-fail { color: #c62828; font-weight: bold; }
+.status-skip { color: #757575; }
+.sev { font-weight: bold; text-transform: uppercase; font-size: 0.85em; }
+.sev-critical { color: #b71c1c; }
+.sev-high { color: #e65100; }
+.sev-medium { color: #f9a825; }
+.sev-low, .sev-info { color: #546e7a; }
+code { font-size: 0.85em; word-break: break-all; }
+ul { margin: 0; padding-left: 1.2em; }
+.fix { margin: 0.3em 0 0; color: #455a64; font-size: 0.9em; }
+.refs { color: #757575; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>DiliVet scorecard</h1>
+<p><span class="grade grade-{{.Grade}}">{{.Grade}}</span>
+<strong>{{.Target}}</strong> scored {{.Score}}/100</p>
+{{- if .Incomplete}}
+<p class="status-fail">Incomplete: the target skipped every check of {{range $i, $c := .Incomplete}}{{if $i}}, {{end}}{{$c}}{{end}}, so it cannot be graded.</p>
+{{- end}}
+<p>Signing interface: {{.SignInterface}}; verification interface: {{.VerifyInterface}}.</p>
+
+<h2>Categories</h2>
+<table>
+<tr><th>Category</th><th>Status</th><th>Passed</th><th>Failed</th><th>Skipped</th><th>Suppressed</th><th>Penalty</th></tr>
+{{- range .Categories}}
+<tr><td><strong>{{.Name}}</strong><br>{{.Description}}</td><td class="status-{{.Status}}">{{.Status}}</td>
+<td class="num">{{.Passed}}/{{.Total}}</td><td class="num">{{.Failed}}</td><td class="num">{{.Skipped}}</td><td class="num">{{.Suppressed}}</td><td class="num">{{.Penalty}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Findings</h2>
+{{- if .Findings}}
+<table>
+<tr><th>Severity</th><th>Rule</th><th>Category</th><th>Finding</th><th>Cases</th><th>Evidence</th></tr>
+{{- range .Findings}}
+<tr><td class="sev sev-{{.Severity}}">{{.Severity}}</td><td><code>{{.RuleID}}</code></td><td>{{.Category}}</td>
+<td>{{.Title}}{{if .Remediation}}<p class="fix">{{.Remediation}}</p>{{end}}{{if .References}}<ul class="refs">{{range .References}}<li>{{.}}</li>{{end}}</ul>{{end}}</td>
+<td class="num">{{.Count}}</td>
+<td><ul>{{range .Evidence}}<li><code>{{.}}</code></li>{{end}}</ul></td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No findings.</p>
+{{- end}}
+{{- if .Suppressed}}
+
+<h2>Suppressed by baseline</h2>
+<table>
+<tr><th>Severity</th><th>Rule</th><th>Category</th><th>Finding</th><th>Cases</th></tr>
+{{- range .Suppressed}}
+<tr><td class="sev sev-{{.Severity}}">{{.Severity}}</td><td><code>{{.RuleID}}</code></td><td>{{.Category}}</td><td>{{.Title}}</td><td class="num">{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
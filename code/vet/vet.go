@@ -0,0 +1,288 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+// Package vet grades an ML-DSA implementation. It runs the bundled ACVP
+// keyGen, sigGen and sigVer vectors, kat.EdgeMsgs, mutation-derived
+// negatives and a coarse timing check against the target and condenses the
+// results into a scorecard: diag findings, pass/fail per category and an
+// overall grade.
+package vet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+// Categories, in the order they run.
+const (
+	CategoryKeyGen   = "acvp-keygen"
+	CategorySigGen   = "acvp-siggen"
+	CategorySigVer   = "acvp-sigver"
+	CategoryEdge     = "edge-messages"
+	CategoryMutation = "mutations"
+	CategoryTiming   = "timing"
+)
+
+// Finding severities, most severe first; they are the diag ones.
+const (
+	SeverityCritical = diag.SeverityCritical
+	SeverityHigh     = diag.SeverityHigh
+	SeverityMedium   = diag.SeverityMedium
+	SeverityLow      = diag.SeverityLow
+	SeverityInfo     = diag.SeverityInfo
+)
+
+// Category statuses.
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// GradeIncomplete is the grade of a target that skipped a required
+// category: it was not exercised, so it cannot be certified.
+const GradeIncomplete = "I"
+
+// requiredCategories must not end up skipped. A target that answers none
+// of the ACVP keyGen, sigGen or sigVer vectors has not been vetted, however
+// clean the rest of its scorecard looks.
+var requiredCategories = []string{CategoryKeyGen, CategorySigGen, CategorySigVer}
+
+// Severities lists the severities from most to least severe.
+var Severities = diag.Severities
+
+// penalty is the score deducted per distinct finding of a severity.
+var penalty = map[string]int{
+	SeverityCritical: 40,
+	SeverityHigh:     20,
+	SeverityMedium:   8,
+	SeverityLow:      3,
+}
+
+// maxCategoryPenalty caps what one category can deduct, so a single broken
+// area does not hide how the others fared.
+const maxCategoryPenalty = 40
+
+// Finding is a diag finding of one category.
+type Finding struct {
+	Category string `json:"category"`
+	diag.Finding
+}
+
+// CategoryResult counts the checks of one category.
+type CategoryResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Total       int    `json:"total"`
+	Passed      int    `json:"passed"`
+	Failed      int    `json:"failed"`
+	Skipped     int    `json:"skipped"`
+	Suppressed  int    `json:"suppressed"` // failed checks whose rule the baseline accepts
+	Penalty     int    `json:"penalty"`
+}
+
+// Scorecard is the outcome of Run.
+type Scorecard struct {
+	Target string `json:"target"`
+	Score  int    `json:"score"`
+	Grade  string `json:"grade"`
+	// Incomplete lists the required categories the target skipped; any
+	// entry makes the grade GradeIncomplete.
+	Incomplete []string `json:"incomplete,omitempty"`
+	// SignInterface and VerifyInterface are the interfaces the edge,
+	// mutation and timing checks used (InterfaceInternal or
+	// InterfaceExternal).
+	SignInterface   string           `json:"signInterface"`
+	VerifyInterface string           `json:"verifyInterface"`
+	Categories      []CategoryResult `json:"categories"`
+	Findings        []Finding        `json:"findings"`
+	Suppressed      []Finding        `json:"suppressed,omitempty"` // findings the baseline accepts
+}
+
+// Failed reports whether any category failed or the run is incomplete.
+func (s *Scorecard) Failed() bool {
+	if len(s.Incomplete) > 0 {
+		return true
+	}
+	for _, c := range s.Categories {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Options tunes a run.
+type Options struct {
+	PerGroup      int // cap on ACVP cases taken from each test group; 0 takes all
+	TimingSamples int // verifications timed per parameter set and input; 0 skips the timing category
+
+	// Baseline lists accepted rules. Their findings move to
+	// Scorecard.Suppressed and neither fail a category nor cost points.
+	Baseline *diag.Baseline
+}
+
+// Run vets impl and returns its scorecard. name names the implementation
+// in the report. Entry points impl reports as kats.ErrUnsupported are
+// skipped rather than failed, and a category with nothing but skipped
+// checks is not scored; if it is a required one, the grade is
+// GradeIncomplete. The edge, mutation and timing checks fall back to the
+// external interface with an empty context where impl lacks the internal
+// one.
+func Run(name string, impl kats.Implementation, opts Options) (*Scorecard, error) {
+	t, err := probe(impl)
+	if err != nil {
+		return nil, fmt.Errorf("vet: probe: %w", err)
+	}
+	s := &Scorecard{Target: name, SignInterface: t.sign, VerifyInterface: t.verify, Findings: []Finding{}}
+	checks := []struct {
+		name, description string
+		run               func(*collector, *target, Options) error
+	}{
+		{CategoryKeyGen, "ACVP keyGen vectors: keys derived from the published seeds", checkKeyGen},
+		{CategorySigGen, "ACVP sigGen vectors: signatures over the published inputs", checkSigGen},
+		{CategorySigVer, "ACVP sigVer vectors: verdicts on valid and invalid signatures", checkSigVer},
+		{CategoryEdge, "kat.EdgeMsgs signed and verified in both directions against the reference", checkEdge},
+		{CategoryMutation, "Mutated signatures (see mutate) that must be rejected", checkMutations},
+		{CategoryTiming, "Coarse timing sanity: valid versus invalid signature verification", checkTiming},
+	}
+	score := 100
+	for _, check := range checks {
+		c := &collector{result: CategoryResult{Name: check.name, Description: check.description}, baseline: opts.Baseline}
+		if err := check.run(c, t, opts); err != nil {
+			return nil, fmt.Errorf("vet: %s: %w", check.name, err)
+		}
+		c.finish()
+		score -= c.result.Penalty
+		s.Categories = append(s.Categories, c.result)
+		s.Findings = append(s.Findings, inCategory(check.name, c.findings)...)
+		s.Suppressed = append(s.Suppressed, inCategory(check.name, c.suppressed)...)
+	}
+	if score < 0 {
+		score = 0
+	}
+	s.Score = score
+	for _, c := range s.Categories {
+		for _, required := range requiredCategories {
+			if c.Name == required && c.Status == StatusSkip {
+				s.Incomplete = append(s.Incomplete, c.Name)
+			}
+		}
+	}
+	s.Grade = grade(score, s.Findings, len(s.Incomplete) > 0)
+	return s, nil
+}
+
+// grade maps a score to a letter. Any critical finding fails the target
+// outright, whatever the score; short of that, an incomplete run gets
+// GradeIncomplete.
+func grade(score int, findings []Finding, incomplete bool) string {
+	for _, f := range findings {
+		if f.Severity == SeverityCritical {
+			return "F"
+		}
+	}
+	if incomplete {
+		return GradeIncomplete
+	}
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
+
+// collector gathers the checks and findings of one category.
+type collector struct {
+	result     CategoryResult
+	baseline   *diag.Baseline
+	findings   []diag.Finding
+	suppressed []diag.Finding
+}
+
+func inCategory(category string, list []diag.Finding) []Finding {
+	out := make([]Finding, 0, len(list))
+	for _, f := range list {
+		out = append(out, Finding{Category: category, Finding: f})
+	}
+	return out
+}
+
+func (c *collector) pass() {
+	c.result.Total++
+	c.result.Passed++
+}
+
+func (c *collector) skip() {
+	c.result.Total++
+	c.result.Skipped++
+}
+
+// fail counts a failed check and records it as a finding of ruleID, or as
+// suppressed if the baseline accepts the rule.
+func (c *collector) fail(ruleID, evidence string) {
+	c.result.Total++
+	if c.baseline.Suppresses(ruleID) {
+		c.result.Suppressed++
+	} else {
+		c.result.Failed++
+	}
+	c.note(ruleID, evidence)
+}
+
+// note records a finding without failing a check.
+func (c *collector) note(ruleID, evidence string) {
+	if c.baseline.Suppresses(ruleID) {
+		c.suppressed = diag.AddFinding(c.suppressed, ruleID, evidence)
+		return
+	}
+	c.findings = diag.AddFinding(c.findings, ruleID, evidence)
+}
+
+// call records the outcome of an implementation call that should have
+// succeeded; it returns true if the caller should go on checking the
+// answer.
+func (c *collector) call(err error, what, example string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, kats.ErrUnsupported):
+		c.skip()
+	case errors.Is(err, execsign.ErrCrashed):
+		c.fail(diag.RuleCrash, example+": "+what+": "+err.Error())
+	case errors.Is(err, execsign.ErrTimeout):
+		c.fail(diag.RuleTimeout, example+": "+what+": "+err.Error())
+	default:
+		c.fail(diag.RuleCallError, example+": "+what+": "+err.Error())
+	}
+	return false
+}
+
+func (c *collector) finish() {
+	r := &c.result
+	switch {
+	case r.Total == r.Skipped:
+		r.Status = StatusSkip
+	case r.Failed > 0:
+		r.Status = StatusFail
+	default:
+		r.Status = StatusPass
+	}
+	for _, f := range c.findings {
+		r.Penalty += penalty[f.Severity]
+	}
+	if r.Penalty > maxCategoryPenalty {
+		r.Penalty = maxCategoryPenalty
+	}
+}
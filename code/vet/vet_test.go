@@ -0,0 +1,224 @@
+// DiliVet – ML-DSA diagnostics and vetting toolkit
+// Author: Thor "Thor Thor" (codethor@gmail.com, https://www.linkedin.com/in/thor-thor0)
+
+package vet
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codethor0/dilivet/code/adapter/execsign"
+	"github.com/codethor0/dilivet/code/clean/kats"
+	"github.com/codethor0/dilivet/code/diag"
+)
+
+func TestRunBuiltinGradesA(t *testing.T) {
+	s, err := Run("builtin", kats.Builtin{}, Options{PerGroup: 1, TimingSamples: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Failed() || s.Grade != "A" || s.Score != 100 {
+		t.Fatalf("grade %s (%d), findings %+v", s.Grade, s.Score, s.Findings)
+	}
+	if len(s.Categories) != 6 {
+		t.Fatalf("got %d categories", len(s.Categories))
+	}
+	for _, c := range s.Categories {
+		if c.Status != StatusPass || c.Passed == 0 {
+			t.Errorf("category %+v", c)
+		}
+	}
+}
+
+// lenient accepts every signature it is asked about.
+type lenient struct{ kats.Builtin }
+
+func (lenient) VerifyInternal(pk, msg, sig []byte) (bool, error) { return true, nil }
+
+func TestRunLenientVerifierFails(t *testing.T) {
+	s, err := Run("lenient", lenient{}, Options{PerGroup: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Failed() || s.Grade != "F" {
+		t.Fatalf("grade %s (%d)", s.Grade, s.Score)
+	}
+	status := map[string]string{}
+	for _, c := range s.Categories {
+		status[c.Name] = c.Status
+	}
+	want := map[string]string{
+		CategoryKeyGen: StatusPass, CategorySigGen: StatusPass, CategoryMutation: StatusFail, CategoryTiming: StatusSkip,
+	}
+	for name, st := range want {
+		if status[name] != st {
+			t.Errorf("%s: status %q, want %q", name, status[name], st)
+		}
+	}
+	rules := map[string]bool{}
+	for _, f := range s.Findings {
+		if f.Category == CategoryMutation && f.Severity == SeverityCritical {
+			rules[f.RuleID] = len(f.Evidence) > 0 && len(f.Evidence) <= diag.MaxEvidence && f.Remediation != ""
+		}
+	}
+	for _, id := range []string{diag.RuleZBound, diag.RuleHintOrder, diag.RuleHintOmega, diag.RuleChallenge, diag.RuleSigLength} {
+		if !rules[id] {
+			t.Errorf("no %s mutation finding in %+v", id, s.Findings)
+		}
+	}
+}
+
+func TestRunBaselineSuppresses(t *testing.T) {
+	first, err := Run("lenient", lenient{}, Options{PerGroup: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []diag.Finding
+	for _, f := range first.Findings {
+		all = append(all, f.Finding)
+	}
+	s, err := Run("lenient", lenient{}, Options{PerGroup: 1, Baseline: diag.NewBaseline(all)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Failed() || len(s.Findings) != 0 || s.Score != 100 {
+		t.Fatalf("grade %s (%d), findings %+v", s.Grade, s.Score, s.Findings)
+	}
+	if len(s.Suppressed) != len(first.Findings) {
+		t.Errorf("suppressed %d findings, want %d", len(s.Suppressed), len(first.Findings))
+	}
+	for _, c := range s.Categories {
+		if c.Name == CategoryMutation && (c.Suppressed == 0 || c.Failed != 0) {
+			t.Errorf("mutations %+v", c)
+		}
+	}
+}
+
+// crashing fails every verification as a crashed process would.
+type crashing struct{ kats.Builtin }
+
+func (crashing) VerifyInternal(pk, msg, sig []byte) (bool, error) {
+	return false, fmt.Errorf("%w: exit status 2", execsign.ErrCrashed)
+}
+
+func TestRunCountsCrashes(t *testing.T) {
+	s, err := Run("crashing", crashing{}, Options{PerGroup: 1, TimingSamples: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range s.Categories {
+		if c.Name == CategoryMutation && (c.Status != StatusFail || c.Passed != 0) {
+			t.Errorf("mutations %+v", c)
+		}
+	}
+	for _, f := range s.Findings {
+		if f.Category == CategoryTiming && f.RuleID != diag.RuleCrash {
+			t.Errorf("timing finding %+v", f)
+		}
+	}
+	if s.Grade != "F" || s.Score >= 60 {
+		t.Errorf("grade %s (%d)", s.Grade, s.Score)
+	}
+}
+
+// unsupported answers nothing.
+type unsupported struct{ kats.Builtin }
+
+func (unsupported) KeyGen(string, []byte) ([]byte, []byte, error) {
+	return nil, nil, kats.ErrUnsupported
+}
+func (unsupported) SignInternal(sk, msg, rnd []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+func (unsupported) SignExternal(sk, msg, ctx, rnd []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+func (unsupported) SignPreHash(sk, msg, ctx []byte, hashAlg string, rnd []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+func (unsupported) SignExternalMu(sk, mu, rnd []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+func (unsupported) VerifyInternal(pk, msg, sig []byte) (bool, error) {
+	return false, kats.ErrUnsupported
+}
+func (unsupported) VerifyExternal(pk, msg, ctx, sig []byte) (bool, error) {
+	return false, kats.ErrUnsupported
+}
+func (unsupported) VerifyPreHash(pk, msg, ctx []byte, hashAlg string, sig []byte) (bool, error) {
+	return false, kats.ErrUnsupported
+}
+func (unsupported) VerifyExternalMu(pk, mu, sig []byte) (bool, error) {
+	return false, kats.ErrUnsupported
+}
+
+func TestRunUnsupportedIsIncomplete(t *testing.T) {
+	s, err := Run("stub", unsupported{}, Options{PerGroup: 1, TimingSamples: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Failed() || s.Grade != GradeIncomplete {
+		t.Fatalf("grade %s (%d), failed %v", s.Grade, s.Score, s.Failed())
+	}
+	if got := strings.Join(s.Incomplete, ","); got != "acvp-keygen,acvp-siggen,acvp-sigver" {
+		t.Errorf("incomplete = %q", got)
+	}
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if html := buf.String(); !strings.Contains(html, `class="grade grade-I"`) || !strings.Contains(html, "Incomplete") {
+		t.Error("HTML report does not mark the run incomplete")
+	}
+}
+
+// externalOnly has no internal sign or verify entry point.
+type externalOnly struct{ kats.Builtin }
+
+func (externalOnly) SignInternal(sk, msg, rnd []byte) ([]byte, error) {
+	return nil, kats.ErrUnsupported
+}
+func (externalOnly) VerifyInternal(pk, msg, sig []byte) (bool, error) {
+	return false, kats.ErrUnsupported
+}
+
+func TestRunFallsBackToExternal(t *testing.T) {
+	s, err := Run("external", externalOnly{}, Options{PerGroup: 1, TimingSamples: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SignInterface != InterfaceExternal || s.VerifyInterface != InterfaceExternal {
+		t.Fatalf("interfaces sign %s, verify %s", s.SignInterface, s.VerifyInterface)
+	}
+	if s.Failed() || s.Grade != "A" {
+		t.Fatalf("grade %s (%d), incomplete %v, findings %+v", s.Grade, s.Score, s.Incomplete, s.Findings)
+	}
+	for _, c := range s.Categories {
+		if c.Status != StatusPass || c.Passed == 0 {
+			t.Errorf("category %+v", c)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	s := &Scorecard{
+		Target: "<vendor>", Score: 60, Grade: "D",
+		Categories: []CategoryResult{{Name: CategoryTiming, Status: StatusFail, Total: 1, Failed: 1}},
+		Findings:   []Finding{{Category: CategoryTiming, Finding: diag.AddFinding(nil, diag.RuleTimingValidity, "ML-DSA-44")[0]}},
+	}
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{"&lt;vendor&gt;", `class="grade grade-D"`, "sev-medium", "ML-DSA-44", "DV-TIMING-VALIDITY", "<style>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML lacks %q", want)
+		}
+	}
+	if strings.Contains(html, "<script") || strings.Contains(html, "href=") || strings.Contains(html, "src=") {
+		t.Error("HTML report loads external resources")
+	}
+}
@@ -11,11 +11,15 @@ echo "[dilivet] Go version:"
 go version || { echo "[dilivet] go is not installed or not on PATH"; exit 1; }
 
 echo
-echo "[dilivet] Step 1/5: go vet ./..."
+echo "[dilivet] Step 1/6: go vet ./..."
 go vet ./...
 
 echo
-echo "[dilivet] Step 2/5: golangci-lint (if available)"
+echo "[dilivet] Step 2/6: dilivet-ctlint ./... (secret-dependent branches and indexing)"
+go run ./cmd/dilivet-ctlint ./...
+
+echo
+echo "[dilivet] Step 3/6: golangci-lint (if available)"
 if command -v golangci-lint >/dev/null 2>&1; then
   golangci-lint run --timeout=5m
 else
@@ -23,11 +27,11 @@ else
 fi
 
 echo
-echo "[dilivet] Step 3/5: go test -race ./..."
+echo "[dilivet] Step 4/6: go test -race ./..."
 go test -race -p 4 ./...
 
 echo
-echo "[dilivet] Step 4/5: fuzz smoke tests (short runs; safe to skip if too slow)"
+echo "[dilivet] Step 5/6: fuzz smoke tests (short runs; safe to skip if too slow)"
 if go test -c ./fuzz >/dev/null 2>&1; then
   # These fuzz targets are listed in the README; if they do not exist, errors are ignored.
   go test ./fuzz -run='^$' -fuzz=FuzzDecodePublicKey -fuzztime=30s || echo "[dilivet] FuzzDecodePublicKey fuzz run failed or not present; continuing."
@@ -37,7 +41,7 @@ else
 fi
 
 echo
-echo "[dilivet] Step 5/5: cross-build matrix (no install, just build into dist/check-build)"
+echo "[dilivet] Step 6/6: cross-build matrix (no install, just build into dist/check-build)"
 mkdir -p dist/check-build
 for os in linux darwin windows; do
   for arch in amd64 arm64; do
@@ -10,6 +10,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	mldsa "github.com/codethor0/dilivet/code/clean"
@@ -42,6 +43,7 @@ func main() {
 	mux.HandleFunc("/api/health", handleHealth)
 	mux.HandleFunc("/api/verify", handleVerify)
 	mux.HandleFunc("/api/kat-verify", handleKATVerify)
+	mux.HandleFunc("/api/kat-keygen", handleKATKeyGen)
 
 	// Serve static files from web/ui/dist if they exist
 	staticDir := "./web/ui/dist"
@@ -184,15 +186,20 @@ type katVerifyResponse struct {
 	Passed         int               `json:"passed,omitempty"`
 	Failed         int               `json:"failed,omitempty"`
 	DecodeFailures int               `json:"decodeFailures,omitempty"`
+	Skipped        int               `json:"skipped,omitempty"`
 	Error          string            `json:"error,omitempty"`
 	Details        []katVerifyDetail `json:"details,omitempty"`
+	Report         *diag.Report      `json:"report,omitempty"`
 }
 
 type katVerifyDetail struct {
-	CaseID       int    `json:"caseId"`
-	Passed       bool   `json:"passed"`
-	ParameterSet string `json:"parameterSet,omitempty"`
-	Reason       string `json:"reason,omitempty"`
+	CaseID       int            `json:"caseId"`
+	GroupID      int            `json:"groupId,omitempty"`
+	Passed       bool           `json:"passed"`
+	Skipped      bool           `json:"skipped,omitempty"`
+	ParameterSet string         `json:"parameterSet,omitempty"`
+	Reason       string         `json:"reason,omitempty"`
+	Mismatch     *kats.Mismatch `json:"mismatch,omitempty"`
 }
 
 func handleKATVerify(w http.ResponseWriter, r *http.Request) {
@@ -209,7 +216,7 @@ func handleKATVerify(w http.ResponseWriter, r *http.Request) {
 
 	vectorsPath := req.VectorsPath
 	if vectorsPath == "" {
-		vectorsPath = "code/clean/testdata/kats/ml-dsa/ML-DSA-sigVer-FIPS204-internalProjection.json"
+		vectorsPath = filepath.Join(kats.DefaultRoot, kats.DefaultSigVerVectors)
 	}
 
 	vectors, err := kats.LoadSigVerVectors(vectorsPath)
@@ -221,86 +228,94 @@ func handleKATVerify(w http.ResponseWriter, r *http.Request) {
 
 	logSecurityEvent("kat_start", "/api/kat-verify", fmt.Sprintf("vectorsPath=%s", vectorsPath))
 
-	report := &diag.Report{}
-	var details []katVerifyDetail
-
-	for _, tg := range vectors.TestGroups {
-		for _, tc := range tg.Tests {
-			report.TotalTests++
-
-			pk, err := hex.DecodeString(tc.Public)
-			if err != nil {
-				report.DecodeFailures++
-				details = append(details, katVerifyDetail{
-					CaseID:       tc.CaseID,
-					Passed:       false,
-					ParameterSet: tg.ParameterSet,
-					Reason:       fmt.Sprintf("Failed to decode public key: %v", err),
-				})
-				continue
-			}
+	result := kats.RunSigVer(vectors, kats.Builtin{})
+	writeKATResult(w, "/api/kat-verify", vectorsPath, result)
+}
 
-			sig, err := hex.DecodeString(tc.Signature)
-			if err != nil {
-				report.DecodeFailures++
-				details = append(details, katVerifyDetail{
-					CaseID:       tc.CaseID,
-					Passed:       false,
-					ParameterSet: tg.ParameterSet,
-					Reason:       fmt.Sprintf("Failed to decode signature: %v", err),
-				})
-				continue
-			}
+// handleKATKeyGen runs the ACVP keyGen vectors against the built-in
+// implementation. External binaries are deliberately not reachable from
+// the web API.
+func handleKATKeyGen(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-			msg, err := hex.DecodeString(tc.Message)
-			if err != nil {
-				report.DecodeFailures++
-				details = append(details, katVerifyDetail{
-					CaseID:       tc.CaseID,
-					Passed:       false,
-					ParameterSet: tg.ParameterSet,
-					Reason:       fmt.Sprintf("Failed to decode message: %v", err),
-				})
-				continue
-			}
+	var req katVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		// Empty body is OK, use default path
+		req.VectorsPath = ""
+	}
 
-			ok, verr := mldsa.Verify(pk, msg, sig)
-			if verr == nil && ok {
-				report.StrictPasses++
-				details = append(details, katVerifyDetail{
-					CaseID:       tc.CaseID,
-					Passed:       true,
-					ParameterSet: tg.ParameterSet,
-				})
-			} else {
-				report.StructuralFailures++
-				reason := "Signature verification failed"
-				if verr != nil {
-					reason = fmt.Sprintf("Verification error: %v", verr)
-				}
-				details = append(details, katVerifyDetail{
-					CaseID:       tc.CaseID,
-					Passed:       false,
-					ParameterSet: tg.ParameterSet,
-					Reason:       reason,
-				})
-			}
-		}
+	vectorsPath := req.VectorsPath
+	if vectorsPath == "" {
+		vectorsPath = filepath.Join(kats.DefaultRoot, kats.DefaultKeyGenVectors)
+	}
+
+	vectors, err := kats.LoadKeyGenVectors(vectorsPath)
+	if err != nil {
+		logSecurityEvent("kat_error", "/api/kat-keygen", sanitizeError(err))
+		respondError(w, http.StatusInternalServerError, "Failed to load test vectors")
+		return
+	}
+
+	logSecurityEvent("kat_start", "/api/kat-keygen", fmt.Sprintf("vectorsPath=%s", vectorsPath))
+
+	result := kats.RunKeyGen(vectors, kats.Builtin{})
+	writeKATResult(w, "/api/kat-keygen", vectorsPath, result)
+}
+
+// writeKATResult encodes a KAT run as a katVerifyResponse. The full
+// diag.Report rides along; its environment names the vector file by base
+// name only, so server paths are not disclosed.
+func writeKATResult(w http.ResponseWriter, endpoint, vectorsPath string, result *kats.Result) {
+	result.Report.Environment = katEnvironment(vectorsPath)
+	report := result.Report
+
+	details := make([]katVerifyDetail, 0, len(result.Cases))
+	for _, c := range result.Cases {
+		details = append(details, katVerifyDetail{
+			CaseID:       c.CaseID,
+			GroupID:      c.GroupID,
+			Passed:       c.Outcome == kats.OutcomePass,
+			Skipped:      c.Outcome == kats.OutcomeSkipped,
+			ParameterSet: c.ParameterSet,
+			Reason:       c.Reason,
+			Mismatch:     c.Mismatch,
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(katVerifyResponse{
 		OK:             true,
 		TotalVectors:   report.TotalTests,
-		Passed:         report.StrictPasses,
+		Passed:         report.StrictPasses + report.StructuralWarnings,
 		Failed:         report.StructuralFailures + report.DecodeFailures,
 		DecodeFailures: report.DecodeFailures,
+		Skipped:        report.Skipped,
 		Details:        details,
+		Report:         &report,
 	})
 
 	// Log KAT completion (metadata only)
-	logSecurityEvent("kat_complete", "/api/kat-verify",
-		fmt.Sprintf("total=%d passed=%d failed=%d", report.TotalTests, report.StrictPasses, report.StructuralFailures+report.DecodeFailures))
+	logSecurityEvent("kat_complete", endpoint,
+		fmt.Sprintf("total=%d passed=%d failed=%d skipped=%d", report.TotalTests, report.StrictPasses+report.StructuralWarnings, report.StructuralFailures+report.DecodeFailures, report.Skipped))
+}
+
+// katEnvironment describes the server and the vector file it ran. A file
+// that cannot be hashed is left out rather than failing the request.
+func katEnvironment(vectorsPath string) *diag.Environment {
+	path, err := kats.ResolvePath(vectorsPath)
+	if err == nil {
+		if env, err := diag.NewEnvironment(version, path); err == nil {
+			for i := range env.VectorFiles {
+				env.VectorFiles[i].Path = filepath.Base(env.VectorFiles[i].Path)
+			}
+			return env
+		}
+	}
+	env, _ := diag.NewEnvironment(version)
+	return env
 }
 
 func decodeHex(s, fieldName string) ([]byte, error) {
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/codethor0/dilivet/code/diag"
 )
 
 func TestHandleHealth(t *testing.T) {
@@ -532,3 +534,43 @@ func TestHandleKATVerify_Concurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleKATKeyGen(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/kat-keygen", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handleKATKeyGen(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var resp katVerifyResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !resp.OK || resp.TotalVectors == 0 {
+		t.Fatalf("unexpected response: ok=%v total=%d", resp.OK, resp.TotalVectors)
+	}
+	if resp.Passed != resp.TotalVectors {
+		t.Errorf("Passed = %d, want all %d vectors", resp.Passed, resp.TotalVectors)
+	}
+	r := resp.Report
+	if r == nil || r.SchemaVersion != diag.SchemaVersion || len(r.ByParameterSet) == 0 || r.Durations == nil {
+		t.Fatalf("report = %+v", r)
+	}
+	if env := r.Environment; env == nil || len(env.VectorFiles) != 1 || len(env.VectorFiles[0].SHA256) != 64 ||
+		strings.Contains(env.VectorFiles[0].Path, "/") {
+		t.Errorf("environment = %+v", env)
+	}
+}
+
+func TestHandleKATKeyGen_WrongMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/kat-keygen", nil)
+	w := httptest.NewRecorder()
+	handleKATKeyGen(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}